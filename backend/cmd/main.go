@@ -70,8 +70,8 @@ func main() {
 
 	// Initialize event bus
 	logger.Info().Msg("Initializing event bus")
-	eventBus, err := events.NewNATSEventBus(
-		cfg.MessageBus.URL,
+	eventBus, err := events.NewEventBus(
+		&cfg.MessageBus,
 		&logger,
 		eventMetrics,
 		"main-service",
@@ -92,7 +92,7 @@ func main() {
 		Msg("Stripe configuration loaded")
 
 	// Start echo server
-	s := api.NewServer(cfg, db, eventBus, &logger)
+	s := api.NewServer(cfg, db, eventBus, eventMetrics, &logger)
 	s.Start(cfg.App.Port)
 }
 
@@ -7,6 +7,7 @@ import (
 	"os"
 	"strconv"
 	"strings"
+	"time"
 	"unicode"
 
 	"github.com/joho/godotenv"
@@ -19,6 +20,10 @@ type Config struct {
 	Stripe     StripeConfig
 	JWT        JWTConfig
 	MessageBus MessageBusConfig
+	Dunning    DunningConfig
+	Inventory  InventoryConfig
+	Outbox     OutboxConfig
+	Reconcile  ReconciliationConfig
 }
 
 // AppConfig holds application-specific configuration
@@ -45,6 +50,13 @@ type DBConfig struct {
 type StripeConfig struct {
 	SecretKey     string
 	WebhookSecret string
+	APIBaseURL    string // Overrides api.stripe.com, e.g. to point at a local stripe-mock
+	SuccessURL    string // Where Checkout redirects after payment; may contain {CHECKOUT_SESSION_ID}
+	CancelURL     string // Where Checkout redirects when the customer backs out
+
+	WebhookPartitions int  // Webhook event partitions; events for one Stripe object stay in order within theirs
+	WebhookWorkers    bool // Whether this instance processes webhook events or only receives them
+	CatalogSync       bool // Whether this instance pushes local catalog edits to Stripe
 }
 
 // JWTConfig holds JWT authentication configuration
@@ -53,7 +65,36 @@ type JWTConfig struct {
 	Expiration string
 }
 
+// DunningConfig controls how failed subscription payments are chased
+type DunningConfig struct {
+	ReminderDays   []int         // Days after the first failure to send each reminder
+	FinalAfterDays int           // Days after the first failure to give up
+	FinalAction    string        // cancel or pause
+	CheckInterval  time.Duration // How often due reminders are processed
+}
+
+// InventoryConfig controls stock reservations held during checkout
+type InventoryConfig struct {
+	ReservationTTL   time.Duration // Checkout session lifetime
+	ReservationGrace time.Duration // How long stock stays held after the session expires
+	SweepInterval    time.Duration // How often expired holds are released
+}
+
+// OutboxConfig controls the relay publishing outbox events to the message bus
+type OutboxConfig struct {
+	RelayInterval time.Duration // How often pending events are published
+	BatchSize     int           // Events published per pass
+	Retention     time.Duration // How long published events are kept
+}
+
+// ReconciliationConfig controls the scheduled catalog reconciliation report
+type ReconciliationConfig struct {
+	Interval time.Duration // How often Postgres and Stripe are compared; 0 disables the schedule
+}
+
 type MessageBusConfig struct {
+	Driver    string // nats, or memory to run without a NATS server
+	Async     bool   // memory driver: dispatch on background goroutines instead of inline
 	URL       string
 	Username  string
 	Password  string
@@ -83,17 +124,45 @@ func Load() (*Config, error) {
 		Stripe: StripeConfig{
 			SecretKey:     getEnv("STRIPE_SECRET_KEY", ""),
 			WebhookSecret: getEnv("STRIPE_WEBHOOK_SECRET", ""),
+			APIBaseURL:    getEnv("STRIPE_API_BASE_URL", ""),
+			SuccessURL:    getEnv("STRIPE_CHECKOUT_SUCCESS_URL", "http://localhost:3000/checkout/success?session_id={CHECKOUT_SESSION_ID}"),
+			CancelURL:     getEnv("STRIPE_CHECKOUT_CANCEL_URL", "http://localhost:3000/cart"),
+
+			WebhookPartitions: getEnvAsInt("STRIPE_WEBHOOK_PARTITIONS", 8),
+			WebhookWorkers:    getEnvAsBool("STRIPE_WEBHOOK_WORKERS", true),
+			CatalogSync:       getEnvAsBool("STRIPE_CATALOG_SYNC", true),
 		},
 		JWT: JWTConfig{
 			Secret:     getEnv("JWT_SECRET", "your_jwt_secret_key"),
 			Expiration: getEnv("JWT_EXPIRATION", "24h"),
 		},
 		MessageBus: MessageBusConfig{
+			Driver:    getEnv("EVENT_BUS_DRIVER", "nats"),
+			Async:     getEnvAsBool("EVENT_BUS_ASYNC", true),
 			URL:       getEnv("NATS_URL", "nats://localhost:4222"),
 			Username:  getEnv("NATS_USERNAME", ""),
 			Password:  getEnv("NATS_PASSWORD", ""),
 			Namespace: getEnv("NATS_NAMESPACE", "walkingdrum"),
 		},
+		Dunning: DunningConfig{
+			ReminderDays:   getEnvAsIntSlice("DUNNING_REMINDER_DAYS", []int{1, 3, 7}),
+			FinalAfterDays: getEnvAsInt("DUNNING_FINAL_AFTER_DAYS", 10),
+			FinalAction:    getEnv("DUNNING_FINAL_ACTION", "cancel"),
+			CheckInterval:  time.Duration(getEnvAsInt("DUNNING_CHECK_INTERVAL_MINUTES", 60)) * time.Minute,
+		},
+		Inventory: InventoryConfig{
+			ReservationTTL:   time.Duration(getEnvAsInt("STOCK_RESERVATION_TTL_MINUTES", 31)) * time.Minute,
+			ReservationGrace: time.Duration(getEnvAsInt("STOCK_RESERVATION_GRACE_MINUTES", 10)) * time.Minute,
+			SweepInterval:    time.Duration(getEnvAsInt("STOCK_RESERVATION_SWEEP_SECONDS", 60)) * time.Second,
+		},
+		Outbox: OutboxConfig{
+			RelayInterval: time.Duration(getEnvAsInt("OUTBOX_RELAY_INTERVAL_MS", 500)) * time.Millisecond,
+			BatchSize:     getEnvAsInt("OUTBOX_BATCH_SIZE", 100),
+			Retention:     time.Duration(getEnvAsInt("OUTBOX_RETENTION_HOURS", 72)) * time.Hour,
+		},
+		Reconcile: ReconciliationConfig{
+			Interval: time.Duration(getEnvAsInt("RECONCILIATION_INTERVAL_HOURS", 0)) * time.Hour,
+		},
 	}
 
 	// Validate required configuration
@@ -137,6 +206,55 @@ func (c *Config) validate() error {
 		}
 	}
 
+	if c.Dunning.FinalAction != "cancel" && c.Dunning.FinalAction != "pause" {
+		return fmt.Errorf("DUNNING_FINAL_ACTION must be cancel or pause, got '%s'", c.Dunning.FinalAction)
+	}
+	for i, day := range c.Dunning.ReminderDays {
+		if day < 0 || (i > 0 && day <= c.Dunning.ReminderDays[i-1]) {
+			return errors.New("DUNNING_REMINDER_DAYS must be increasing, non-negative day numbers")
+		}
+	}
+	if n := len(c.Dunning.ReminderDays); n > 0 && c.Dunning.FinalAfterDays <= c.Dunning.ReminderDays[n-1] {
+		return errors.New("DUNNING_FINAL_AFTER_DAYS must be later than the last reminder day")
+	}
+	if c.Dunning.CheckInterval <= 0 {
+		return errors.New("DUNNING_CHECK_INTERVAL_MINUTES must be positive")
+	}
+
+	// Stripe only accepts Checkout session lifetimes between 30 minutes and 24
+	// hours of its receiving the request, so the minimum leaves a minute for latency
+	if c.Inventory.ReservationTTL < 31*time.Minute || c.Inventory.ReservationTTL > 24*time.Hour {
+		return errors.New("STOCK_RESERVATION_TTL_MINUTES must be between 31 and 1440")
+	}
+	if c.Inventory.ReservationGrace < 0 {
+		return errors.New("STOCK_RESERVATION_GRACE_MINUTES must not be negative")
+	}
+	if c.Inventory.SweepInterval <= 0 {
+		return errors.New("STOCK_RESERVATION_SWEEP_SECONDS must be positive")
+	}
+
+	// Changing the partition count can reorder events queued under the old count
+	if c.Stripe.WebhookPartitions < 1 || c.Stripe.WebhookPartitions > 64 {
+		return errors.New("STRIPE_WEBHOOK_PARTITIONS must be between 1 and 64")
+	}
+
+	if c.MessageBus.Driver != "nats" && c.MessageBus.Driver != "memory" {
+		return fmt.Errorf("EVENT_BUS_DRIVER must be nats or memory, got '%s'", c.MessageBus.Driver)
+	}
+
+	if c.Outbox.RelayInterval <= 0 {
+		return errors.New("OUTBOX_RELAY_INTERVAL_MS must be positive")
+	}
+	if c.Outbox.BatchSize <= 0 {
+		return errors.New("OUTBOX_BATCH_SIZE must be positive")
+	}
+	if c.Outbox.Retention <= 0 {
+		return errors.New("OUTBOX_RETENTION_HOURS must be positive")
+	}
+	if c.Reconcile.Interval < 0 {
+		return errors.New("RECONCILIATION_INTERVAL_HOURS must not be negative")
+	}
+
 	// Verify that the provided database name is valid
 	valid, msg := isValidPostgresIdentifier(c.DB.Name)
 	if !valid {
@@ -162,6 +280,24 @@ func getEnvAsInt(key string, defaultValue int) int {
 	return defaultValue
 }
 
+// getEnvAsIntSlice parses a comma-separated list such as "1,3,7"
+func getEnvAsIntSlice(key string, defaultValue []int) []int {
+	valueStr := getEnv(key, "")
+	if valueStr == "" {
+		return defaultValue
+	}
+
+	var values []int
+	for _, part := range strings.Split(valueStr, ",") {
+		value, err := strconv.Atoi(strings.TrimSpace(part))
+		if err != nil {
+			return defaultValue
+		}
+		values = append(values, value)
+	}
+	return values
+}
+
 func getEnvAsBool(key string, defaultValue bool) bool {
 	valueStr := getEnv(key, "")
 	if value, err := strconv.ParseBool(valueStr); err == nil {
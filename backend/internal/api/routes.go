@@ -8,7 +8,7 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-func RegisterRoutes(e *echo.Echo, productHandler handler.ProductHandler, variantHandler handler.VariantHandler, priceHandler handler.PriceHandler, stripeWebhookHandler handler.StripeWebhookHandler, adminHandler handler.AdminHandler) error {
+func RegisterRoutes(e *echo.Echo, productHandler handler.ProductHandler, variantHandler handler.VariantHandler, priceHandler handler.PriceHandler, stripeWebhookHandler handler.StripeWebhookHandler, adminHandler handler.AdminHandler, customerHandler handler.CustomerHandler, addressHandler handler.AddressHandler, subscriptionHandler handler.SubscriptionHandler, orderHandler handler.OrderHandler, checkoutHandler handler.CheckoutHandler, cartHandler handler.CartHandler) error {
 
 	e.GET("/", func(c echo.Context) error {
 		return c.String(http.StatusOK, "Hello, World!")
@@ -26,10 +26,15 @@ func RegisterRoutes(e *echo.Echo, productHandler handler.ProductHandler, variant
 	products.PUT("/:id", productHandler.Update)
 	products.DELETE("/:id", productHandler.Delete)
 	products.GET("/:id/variants", variantHandler.ListByProduct)
+	products.GET("/:id/variants/regeneration", variantHandler.PreviewRegeneration)
+	products.POST("/:id/variants/regenerate", variantHandler.Regenerate)
 	products.POST("/:id/archive", productHandler.Archive)
 
 	// Add product prices route
 	products.GET("/:id/prices", priceHandler.GetByProduct)
+	products.GET("/:id/pricing-rule", priceHandler.GetPricingRule)
+	products.PUT("/:id/pricing-rule", priceHandler.SetPricingRule)
+	products.POST("/:id/pricing-rule/apply", priceHandler.ApplyPricingRule)
 
 	// Add price routes
 	prices := v1.Group("/prices")
@@ -41,12 +46,86 @@ func RegisterRoutes(e *echo.Echo, productHandler handler.ProductHandler, variant
 
 	// Add variant price assignment route
 	variants := v1.Group("/variants")
+	variants.POST("", variantHandler.Create)
+	variants.GET("/:id", variantHandler.Get)
+	variants.PUT("/:id", variantHandler.Update)
+	variants.DELETE("/:id", variantHandler.Delete)
+	variants.PUT("/:id/stock", variantHandler.UpdateStockLevel)
 	variants.POST("/:id/assign-price", priceHandler.AssignToVariant)
 
+	// Inventory ledger routes
+	variants.GET("/:id/stock-movements", variantHandler.StockHistory)
+	variants.POST("/:id/stock-movements", variantHandler.CreateStockMovement)
+	variants.PUT("/:id/reorder-threshold", variantHandler.SetReorderThreshold)
+
+	// Inventory routes
+	inventory := v1.Group("/inventory")
+	inventory.GET("/low-stock", variantHandler.LowStock)
+
+	// Customer routes
+	customers := v1.Group("/customers")
+	customers.GET("", customerHandler.List)
+	customers.POST("", customerHandler.Create)
+	customers.GET("/lookup", customerHandler.Lookup)
+	customers.GET("/:id", customerHandler.Get)
+	customers.PUT("/:id", customerHandler.Update)
+	customers.DELETE("/:id", customerHandler.Delete)
+	customers.POST("/:id/deactivate", customerHandler.Deactivate)
+
+	// Customer address book routes
+	customers.GET("/:id/addresses", addressHandler.List)
+	customers.POST("/:id/addresses", addressHandler.Create)
+	customers.GET("/:id/addresses/:address_id", addressHandler.Get)
+	customers.PUT("/:id/addresses/:address_id", addressHandler.Update)
+	customers.DELETE("/:id/addresses/:address_id", addressHandler.Delete)
+
+	// Subscription routes
+	subscriptions := v1.Group("/subscriptions")
+	subscriptions.GET("", subscriptionHandler.List)
+	subscriptions.POST("", subscriptionHandler.Create)
+	subscriptions.GET("/:id", subscriptionHandler.Get)
+	subscriptions.POST("/:id/pause", subscriptionHandler.Pause)
+	subscriptions.POST("/:id/resume", subscriptionHandler.Resume)
+	subscriptions.POST("/:id/skip", subscriptionHandler.Skip)
+	subscriptions.POST("/:id/cancel", subscriptionHandler.Cancel)
+
+	// Order routes
+	orders := v1.Group("/orders")
+	orders.GET("", orderHandler.List)
+	orders.POST("", orderHandler.Create)
+	orders.GET("/:id", orderHandler.Get)
+	orders.POST("/:id/status", orderHandler.UpdateStatus)
+	orders.POST("/:id/cancel", orderHandler.Cancel)
+	orders.GET("/:id/history", orderHandler.History)
+
+	// Checkout routes
+	checkout := v1.Group("/checkout")
+	checkout.POST("/sessions", checkoutHandler.CreateSession)
+
+	// Cart routes
+	carts := v1.Group("/carts")
+	carts.POST("", cartHandler.Create)
+	carts.GET("/:id", cartHandler.Get)
+	carts.POST("/:id/items", cartHandler.AddItem)
+	carts.PUT("/:id/items/:variant_id", cartHandler.UpdateItem)
+	carts.DELETE("/:id/items/:variant_id", cartHandler.RemoveItem)
+	carts.POST("/:id/merge", cartHandler.Merge)
+
 	// Admin routes (add these)
 	admin := v1.Group("/admin")
 	admin.GET("/health", adminHandler.HealthCheck)
 	admin.POST("/sync-stripe-ids", adminHandler.SyncStripeProductIDs)
+	admin.GET("/reconciliation", adminHandler.Reconcile)
+	admin.POST("/reconciliation/apply", adminHandler.ApplyReconciliation)
+
+	// Sync hash dashboard
+	admin.GET("/sync/status", adminHandler.SyncStatus)
+	admin.GET("/sync/history/:variant_id", adminHandler.SyncHistory)
+
+	// Stripe webhook event store
+	admin.GET("/webhook-events", stripeWebhookHandler.ListEvents)
+	admin.POST("/webhook-events/replay-failed", stripeWebhookHandler.ReplayFailed)
+	admin.POST("/webhook-events/:id/replay", stripeWebhookHandler.ReplayEvent)
 
 	return nil
 }
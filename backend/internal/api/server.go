@@ -1,11 +1,13 @@
 package api
 
 import (
+	"context"
 	"fmt"
 
 	"github.com/dukerupert/coffee-commerce/config"
 	"github.com/dukerupert/coffee-commerce/internal/events"
 	"github.com/dukerupert/coffee-commerce/internal/handler"
+	"github.com/dukerupert/coffee-commerce/internal/metrics"
 	custommiddleware "github.com/dukerupert/coffee-commerce/internal/middleware"
 	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
 	"github.com/dukerupert/coffee-commerce/internal/service"
@@ -19,29 +21,75 @@ type server struct {
 	e *echo.Echo
 }
 
-func NewServer(cfg *config.Config, db *postgres.DB, eventBus *events.NATSEventBus, logger *zerolog.Logger) *server {
+func NewServer(cfg *config.Config, db *postgres.DB, eventBus events.EventBus, eventMetrics *metrics.EventMetrics, logger *zerolog.Logger) *server {
 
 	// Initialize repositories
 	productRepo := postgres.NewProductRepository(db, logger)
 	variantRepo := postgres.NewVariantRepository(db, logger)
 	priceRepo := postgres.NewPriceRepository(db, logger)
 	syncRepo := postgres.NewSyncHashRepository(db, logger)
+	customerRepo := postgres.NewCustomerRepository(db, logger)
+	addressRepo := postgres.NewAddressRepository(db, logger)
+	subscriptionRepo := postgres.NewSubscriptionRepository(db, logger)
+	orderRepo := postgres.NewOrderRepository(db, logger)
+	cartRepo := postgres.NewCartRepository(db, logger)
+	invoiceRepo := postgres.NewInvoiceRepository(db, logger)
+	dunningRepo := postgres.NewDunningRepository(db, logger)
+	reservationRepo := postgres.NewStockReservationRepository(db, logger)
+	pricingRuleRepo := postgres.NewPricingRuleRepository(db, logger)
+	outboxRepo := postgres.NewOutboxRepository(db, logger)
+	webhookEventRepo := postgres.NewWebhookEventRepository(db, logger)
 
 	// Initialize services
 	stripeService := stripe.NewStripeService(logger, &cfg.Stripe)
 	productService := service.NewProductService(logger, eventBus, productRepo)
-	priceService := service.NewPriceService(logger, eventBus, priceRepo, productRepo, variantRepo, stripeService)
-	_, err := service.NewVariantService(logger, eventBus, variantRepo, productRepo, priceRepo, stripeService)
+	priceService := service.NewPriceService(logger, eventBus, priceRepo, productRepo, variantRepo, pricingRuleRepo, stripeService)
+	customerService := service.NewCustomerService(logger, eventBus, customerRepo, stripeService)
+	addressService := service.NewAddressService(logger, addressRepo, customerRepo)
+	subscriptionService := service.NewSubscriptionService(logger, eventBus, subscriptionRepo, customerRepo, addressRepo, priceRepo, productRepo, stripeService)
+	variantService, err := service.NewVariantService(logger, eventBus, variantRepo, productRepo, priceRepo, reservationRepo, pricingRuleRepo, stripeService)
 	if err != nil {
 		logger.Fatal().Err(err).Msg("Failed to initialize variant service")
 	}
+	checkoutService := service.NewCheckoutService(logger, &cfg.Stripe, &cfg.Inventory, variantRepo, priceRepo, productRepo, customerRepo, variantService, stripeService)
+	dunningService := service.NewDunningService(logger, &cfg.Dunning, eventBus, dunningRepo, subscriptionRepo, invoiceRepo, stripeService)
+	reconciliationService := service.NewReconciliationService(logger, &cfg.Reconcile, productRepo, variantRepo, priceRepo, syncRepo, stripeService)
+	syncStatusService := service.NewSyncStatusService(logger, syncRepo, variantRepo, stripeService)
+	catalogSyncService := service.NewCatalogSyncService(logger, eventBus, productRepo, variantRepo, priceRepo, syncRepo, stripeService)
+	cartService := service.NewCartService(logger, cartRepo, variantRepo, productRepo, customerRepo, priceService)
+	outboxRelay := service.NewOutboxRelay(logger, &cfg.Outbox, eventBus, outboxRepo, eventMetrics)
+	orderService := service.NewOrderService(logger, eventBus, orderRepo, customerRepo, addressRepo, variantRepo, priceRepo, productRepo, variantService)
+
+	// Start background workers
+	go outboxRelay.Run(context.Background())
+	go dunningService.Run(context.Background())
+	go reconciliationService.Run(context.Background())
+	go variantService.RunReservationSweeper(context.Background(), cfg.Inventory.SweepInterval)
+
+	// Pushing edits needs a real Stripe account; the disabled client only returns mocks
+	if cfg.Stripe.CatalogSync && cfg.Stripe.SecretKey != "" {
+		if err := catalogSyncService.Start(); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to start Stripe catalog sync")
+		}
+	}
 
 	// Initialize handlers
 	productHandler := handler.NewProductHandler(logger, productService, variantRepo, priceRepo)
-	variantHandler := handler.NewVariantHandler(logger, variantRepo, productRepo)
+	variantHandler := handler.NewVariantHandler(logger, variantService, variantRepo, productRepo)
 	priceHandler := handler.NewPriceHandler(logger, priceService, productRepo, variantRepo)
-	stripeWebhookHandler := handler.NewStripeWebhookHandler(logger, &cfg.Stripe, eventBus, productRepo, priceRepo, variantRepo, syncRepo)
-	adminHandler := handler.NewAdminHandler(logger, priceService, productRepo)
+	stripeWebhookHandler := handler.NewStripeWebhookHandler(logger, &cfg.Stripe, eventBus, productRepo, priceRepo, variantRepo, syncRepo, webhookEventRepo, customerRepo, addressRepo, subscriptionRepo, invoiceRepo, stripeService, variantService, orderService, dunningService)
+	if cfg.Stripe.WebhookWorkers {
+		if err := stripeWebhookHandler.StartWorkers(); err != nil {
+			logger.Fatal().Err(err).Msg("Failed to start Stripe webhook workers")
+		}
+	}
+	adminHandler := handler.NewAdminHandler(logger, priceService, reconciliationService, syncStatusService, productRepo)
+	customerHandler := handler.NewCustomerHandler(logger, customerService)
+	addressHandler := handler.NewAddressHandler(logger, addressService)
+	subscriptionHandler := handler.NewSubscriptionHandler(logger, subscriptionService)
+	orderHandler := handler.NewOrderHandler(logger, orderService)
+	checkoutHandler := handler.NewCheckoutHandler(logger, checkoutService)
+	cartHandler := handler.NewCartHandler(logger, cartService)
 
 	// Start echo server
 	e := echo.New()
@@ -61,7 +109,7 @@ func NewServer(cfg *config.Config, db *postgres.DB, eventBus *events.NATSEventBu
 	}
 	e.Use(custommiddleware.SetupCORS(corsConfig))
 
-	RegisterRoutes(e, productHandler, variantHandler, priceHandler, *stripeWebhookHandler, adminHandler)
+	RegisterRoutes(e, productHandler, variantHandler, priceHandler, *stripeWebhookHandler, adminHandler, customerHandler, addressHandler, subscriptionHandler, orderHandler, checkoutHandler, cartHandler)
 
 	return &server{
 		e: e,
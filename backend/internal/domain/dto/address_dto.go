@@ -0,0 +1,227 @@
+// internal/domain/dto/address_dto.go
+package dto
+
+import (
+	"context"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// Postal code formats for the countries we ship to, keyed by ISO 3166-1 alpha-2 code
+var postalCodePatterns = map[string]*regexp.Regexp{
+	"US": regexp.MustCompile(`^\d{5}(-\d{4})?$`),
+	"CA": regexp.MustCompile(`^[A-Z]\d[A-Z] ?\d[A-Z]\d$`),
+	"GB": regexp.MustCompile(`^[A-Z]{1,2}\d[A-Z\d]? ?\d[A-Z]{2}$`),
+	"IE": regexp.MustCompile(`^[A-Z]\d[\dW] ?[A-Z\d]{4}$`),
+	"AU": regexp.MustCompile(`^\d{4}$`),
+	"NZ": regexp.MustCompile(`^\d{4}$`),
+	"DE": regexp.MustCompile(`^\d{5}$`),
+	"FR": regexp.MustCompile(`^\d{5}$`),
+	"NL": regexp.MustCompile(`^\d{4} ?[A-Z]{2}$`),
+	"JP": regexp.MustCompile(`^\d{3}-?\d{4}$`),
+}
+
+// Countries where a state or province is part of the postal address
+var stateRequiredCountries = map[string]bool{
+	"US": true,
+	"CA": true,
+	"AU": true,
+}
+
+// validateCountryAndPostalCode checks the country is one we ship to and the
+// postal code matches its format. Values are normalized in place
+func validateCountryAndPostalCode(country, postalCode *string, problems map[string]string) {
+	*country = strings.ToUpper(strings.TrimSpace(*country))
+	*postalCode = strings.ToUpper(strings.TrimSpace(*postalCode))
+
+	pattern, supported := postalCodePatterns[*country]
+	if *country == "" {
+		problems["country"] = "country is required"
+	} else if len(*country) != 2 {
+		problems["country"] = "country must be a 2-letter ISO code (e.g., US, CA)"
+	} else if !supported {
+		problems["country"] = "we do not currently ship to this country"
+	}
+
+	if *postalCode == "" {
+		problems["postal_code"] = "postal code is required"
+	} else if supported && !pattern.MatchString(*postalCode) {
+		problems["postal_code"] = "postal code is not valid for " + *country
+	}
+}
+
+// AddressCreateDTO represents the data needed to add an address to a customer
+type AddressCreateDTO struct {
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2"`
+	City       string `json:"city"`
+	State      string `json:"state"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	IsDefault  bool   `json:"is_default"`
+}
+
+// Valid validates the AddressCreateDTO
+func (a *AddressCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if strings.TrimSpace(a.Line1) == "" {
+		problems["line1"] = "address line 1 is required"
+	} else if len(a.Line1) > 255 {
+		problems["line1"] = "must not exceed 255 characters"
+	}
+
+	if len(a.Line2) > 255 {
+		problems["line2"] = "must not exceed 255 characters"
+	}
+
+	if strings.TrimSpace(a.City) == "" {
+		problems["city"] = "city is required"
+	} else if len(a.City) > 255 {
+		problems["city"] = "must not exceed 255 characters"
+	}
+
+	validateCountryAndPostalCode(&a.Country, &a.PostalCode, problems)
+
+	if stateRequiredCountries[a.Country] && strings.TrimSpace(a.State) == "" {
+		problems["state"] = "state is required for " + a.Country
+	}
+
+	return problems
+}
+
+// ToModel converts AddressCreateDTO to an Address model
+func (a *AddressCreateDTO) ToModel(customerID uuid.UUID) *model.Address {
+	return &model.Address{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		Line1:      strings.TrimSpace(a.Line1),
+		Line2:      strings.TrimSpace(a.Line2),
+		City:       strings.TrimSpace(a.City),
+		State:      strings.TrimSpace(a.State),
+		PostalCode: a.PostalCode,
+		Country:    a.Country,
+		IsDefault:  a.IsDefault,
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+}
+
+// AddressUpdateDTO represents the data needed to update an address
+// Using pointers for all fields to differentiate between zero values and absence
+type AddressUpdateDTO struct {
+	Line1      *string `json:"line1"`
+	Line2      *string `json:"line2"`
+	City       *string `json:"city"`
+	State      *string `json:"state"`
+	PostalCode *string `json:"postal_code"`
+	Country    *string `json:"country"`
+	IsDefault  *bool   `json:"is_default"`
+}
+
+// Valid performs validation on the AddressUpdateDTO fields. Country and postal
+// code can only be validated as a pair, so they must be updated together
+func (dto *AddressUpdateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if dto.Line1 != nil {
+		if strings.TrimSpace(*dto.Line1) == "" {
+			problems["line1"] = "must not be empty"
+		} else if len(*dto.Line1) > 255 {
+			problems["line1"] = "must not exceed 255 characters"
+		}
+	}
+
+	if dto.Line2 != nil && len(*dto.Line2) > 255 {
+		problems["line2"] = "must not exceed 255 characters"
+	}
+
+	if dto.City != nil {
+		if strings.TrimSpace(*dto.City) == "" {
+			problems["city"] = "must not be empty"
+		} else if len(*dto.City) > 255 {
+			problems["city"] = "must not exceed 255 characters"
+		}
+	}
+
+	switch {
+	case dto.Country != nil && dto.PostalCode != nil:
+		validateCountryAndPostalCode(dto.Country, dto.PostalCode, problems)
+
+		if stateRequiredCountries[*dto.Country] && (dto.State == nil || strings.TrimSpace(*dto.State) == "") {
+			problems["state"] = "state is required for " + *dto.Country
+		}
+	case dto.Country != nil:
+		problems["postal_code"] = "postal code must be provided when changing country"
+	case dto.PostalCode != nil:
+		problems["country"] = "country must be provided when changing postal code"
+	}
+
+	if dto.State != nil && dto.Country == nil && strings.TrimSpace(*dto.State) == "" {
+		problems["state"] = "must not be empty"
+	}
+
+	return problems
+}
+
+// ApplyToModel applies the non-nil fields from the DTO to the address model
+func (dto *AddressUpdateDTO) ApplyToModel(address *model.Address) {
+	if dto.Line1 != nil {
+		address.Line1 = strings.TrimSpace(*dto.Line1)
+	}
+	if dto.Line2 != nil {
+		address.Line2 = strings.TrimSpace(*dto.Line2)
+	}
+	if dto.City != nil {
+		address.City = strings.TrimSpace(*dto.City)
+	}
+	if dto.State != nil {
+		address.State = strings.TrimSpace(*dto.State)
+	}
+	if dto.PostalCode != nil {
+		address.PostalCode = *dto.PostalCode
+	}
+	if dto.Country != nil {
+		address.Country = *dto.Country
+	}
+	if dto.IsDefault != nil {
+		address.IsDefault = *dto.IsDefault
+	}
+	address.UpdatedAt = time.Now()
+}
+
+// AddressResponseDTO represents the address data returned to the client
+type AddressResponseDTO struct {
+	ID         string `json:"id"`
+	CustomerID string `json:"customer_id"`
+	Line1      string `json:"line1"`
+	Line2      string `json:"line2,omitempty"`
+	City       string `json:"city"`
+	State      string `json:"state,omitempty"`
+	PostalCode string `json:"postal_code"`
+	Country    string `json:"country"`
+	IsDefault  bool   `json:"is_default"`
+	CreatedAt  string `json:"created_at"`
+	UpdatedAt  string `json:"updated_at"`
+}
+
+// AddressResponseDTOFromModel converts an Address model to AddressResponseDTO
+func AddressResponseDTOFromModel(address *model.Address) AddressResponseDTO {
+	return AddressResponseDTO{
+		ID:         address.ID.String(),
+		CustomerID: address.CustomerID.String(),
+		Line1:      address.Line1,
+		Line2:      address.Line2,
+		City:       address.City,
+		State:      address.State,
+		PostalCode: address.PostalCode,
+		Country:    address.Country,
+		IsDefault:  address.IsDefault,
+		CreatedAt:  address.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:  address.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -1,5 +1,12 @@
 package dto
 
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+)
+
 // SyncResult represents the result of syncing a single product
 type SyncResult struct {
 	ProductID      string `json:"product_id"`
@@ -23,3 +30,132 @@ type SyncStripeProductIDsResult struct {
 		Updated    int `json:"updated"`
 	} `json:"summary"`
 }
+
+// Reconciliation finding categories
+const (
+	ReconcileMissingLocally  = "missing_locally"   // Stripe object with our metadata that no local record links to
+	ReconcileMissingInStripe = "missing_in_stripe" // Local record whose Stripe object doesn't exist
+	ReconcileDrift           = "drift"             // Linked on both sides but with differing fields
+	ReconcileOrphan          = "orphan"            // Active Stripe object that can't be traced to any local record
+)
+
+// Sources of truth a reconciliation can apply to a category
+const (
+	ReconcileSourceLocal  = "local"  // Change Stripe to match Postgres
+	ReconcileSourceStripe = "stripe" // Change Postgres to match Stripe
+)
+
+// ReconciliationApplyDTO picks the source of truth for each category to fix.
+// Categories left out are reported but not changed
+type ReconciliationApplyDTO struct {
+	Policy map[string]string `json:"policy"` // Category -> local or stripe
+}
+
+// Valid checks that every category and source of truth is known
+func (r *ReconciliationApplyDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if len(r.Policy) == 0 {
+		problems["policy"] = "at least one category is required"
+	}
+
+	for category, source := range r.Policy {
+		switch category {
+		case ReconcileMissingLocally, ReconcileMissingInStripe, ReconcileDrift, ReconcileOrphan:
+		default:
+			problems["policy."+category] = "category must be one of missing_locally, missing_in_stripe, drift, orphan"
+			continue
+		}
+		if source != ReconcileSourceLocal && source != ReconcileSourceStripe {
+			problems["policy."+category] = "source of truth must be local or stripe"
+		}
+	}
+
+	return problems
+}
+
+// FieldDiff is a field whose value differs between Postgres and Stripe
+type FieldDiff struct {
+	Field  string `json:"field"`
+	Local  string `json:"local"`
+	Stripe string `json:"stripe"`
+}
+
+// ReconciliationFinding is one discrepancy between Postgres and Stripe
+type ReconciliationFinding struct {
+	Category string      `json:"category"`
+	Kind     string      `json:"kind"` // product, variant or price
+	LocalID  string      `json:"local_id,omitempty"`
+	StripeID string      `json:"stripe_id,omitempty"`
+	Name     string      `json:"name,omitempty"`
+	Detail   string      `json:"detail,omitempty"`
+	Diffs    []FieldDiff `json:"diffs,omitempty"`
+
+	// Set when the finding was applied
+	Action string `json:"action,omitempty"`
+	Error  string `json:"error,omitempty"`
+}
+
+// ReconciliationCounts is how many records each side holds
+type ReconciliationCounts struct {
+	LocalProducts  int `json:"local_products"`
+	LocalVariants  int `json:"local_variants"`
+	LocalPrices    int `json:"local_prices"`
+	StripeProducts int `json:"stripe_products"`
+	StripePrices   int `json:"stripe_prices"`
+}
+
+// ReconciliationReport lists every discrepancy found between Postgres and
+// Stripe. Applied is false for a report-only run
+type ReconciliationReport struct {
+	StartedAt   time.Time               `json:"started_at"`
+	CompletedAt time.Time               `json:"completed_at"`
+	Applied     bool                    `json:"applied"`
+	Policy      map[string]string       `json:"policy,omitempty"`
+	Counts      ReconciliationCounts    `json:"counts"`
+	Summary     map[string]int          `json:"summary"` // Findings per category
+	Findings    []ReconciliationFinding `json:"findings"`
+	Fixed       int                     `json:"fixed"`
+	Failed      int                     `json:"failed"`
+}
+
+// Sync states of a variant, comparing the last recorded hash with the live Stripe product
+const (
+	SyncStateInSync          = "in_sync"           // Stripe product still hashes to the recorded value
+	SyncStateDrifted         = "drifted"           // Stripe product changed since the hash was recorded
+	SyncStateNeverSynced     = "never_synced"      // No hash recorded for the variant's Stripe product
+	SyncStateMissingInStripe = "missing_in_stripe" // Variant links to a Stripe product that doesn't exist
+	SyncStateUnlinked        = "unlinked"          // Variant has no Stripe product
+)
+
+// VariantSyncStatus is one variant's row on the sync dashboard
+type VariantSyncStatus struct {
+	VariantID       string            `json:"variant_id"`
+	ProductID       string            `json:"product_id"`
+	ProductName     string            `json:"product_name"`
+	Options         map[string]string `json:"options"`
+	Active          bool              `json:"active"`
+	StripeProductID string            `json:"stripe_product_id,omitempty"`
+	State           string            `json:"state"`
+	LastHash        string            `json:"last_hash,omitempty"`
+	LastSyncSource  string            `json:"last_sync_source,omitempty"`
+	LastSyncedAt    *time.Time        `json:"last_synced_at,omitempty"`
+	AgeSeconds      int64             `json:"age_seconds,omitempty"` // Time since the last sync
+	CurrentHash     string            `json:"current_hash,omitempty"`
+}
+
+// SyncStatusReport lists the sync state of every variant
+type SyncStatusReport struct {
+	GeneratedAt time.Time           `json:"generated_at"`
+	Summary     map[string]int      `json:"summary"` // Variants per state, before filtering
+	Variants    []VariantSyncStatus `json:"variants"`
+}
+
+// SyncHistoryReport is the hash timeline of one variant, newest first
+type SyncHistoryReport struct {
+	VariantID       string            `json:"variant_id"`
+	StripeProductID string            `json:"stripe_product_id,omitempty"`
+	State           string            `json:"state"`
+	CurrentHash     string            `json:"current_hash,omitempty"`
+	History         []*model.SyncHash `json:"history"`
+}
@@ -0,0 +1,85 @@
+// internal/domain/dto/cart_dto.go
+package dto
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// maxCartQuantity caps a single cart line
+const maxCartQuantity = 99
+
+// CartCreateDTO represents a request to start a cart
+type CartCreateDTO struct {
+	CustomerID *uuid.UUID `json:"customer_id,omitempty"` // Omitted for guest carts
+}
+
+// Valid validates the CartCreateDTO
+func (c *CartCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if c.CustomerID != nil && *c.CustomerID == uuid.Nil {
+		problems["customer_id"] = "customer ID must not be empty"
+	}
+
+	return problems
+}
+
+// CartItemDTO adds a variant line to a cart
+type CartItemDTO struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// Valid validates the CartItemDTO
+func (c *CartItemDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if c.VariantID == uuid.Nil {
+		problems["variant_id"] = "variant ID is required"
+	}
+
+	if c.Quantity < 1 {
+		problems["quantity"] = "quantity must be at least 1"
+	} else if c.Quantity > maxCartQuantity {
+		problems["quantity"] = "quantity must not exceed 99"
+	}
+
+	return problems
+}
+
+// CartItemUpdateDTO sets the quantity of an existing cart line.
+// A quantity of zero removes the line
+type CartItemUpdateDTO struct {
+	Quantity int `json:"quantity"`
+}
+
+// Valid validates the CartItemUpdateDTO
+func (c *CartItemUpdateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if c.Quantity < 0 {
+		problems["quantity"] = "quantity cannot be negative"
+	} else if c.Quantity > maxCartQuantity {
+		problems["quantity"] = "quantity must not exceed 99"
+	}
+
+	return problems
+}
+
+// CartMergeDTO folds a guest cart into a customer's cart after login
+type CartMergeDTO struct {
+	CustomerID uuid.UUID `json:"customer_id"`
+}
+
+// Valid validates the CartMergeDTO
+func (c *CartMergeDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if c.CustomerID == uuid.Nil {
+		problems["customer_id"] = "customer ID is required"
+	}
+
+	return problems
+}
@@ -0,0 +1,65 @@
+// internal/domain/dto/checkout_dto.go
+package dto
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// CheckoutItemDTO is a cart line to be purchased
+type CheckoutItemDTO struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// CheckoutSessionCreateDTO represents a request to start a hosted checkout
+type CheckoutSessionCreateDTO struct {
+	CustomerID *uuid.UUID        `json:"customer_id,omitempty"` // Known customer, if logged in
+	Email      string            `json:"email,omitempty"`       // Prefills the email of a guest checkout
+	Items      []CheckoutItemDTO `json:"items"`
+}
+
+// Valid validates the CheckoutSessionCreateDTO
+func (c *CheckoutSessionCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if len(c.Items) == 0 {
+		problems["items"] = "at least one item is required"
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for i, item := range c.Items {
+		if item.VariantID == uuid.Nil {
+			problems[fmt.Sprintf("items[%d].variant_id", i)] = "variant ID is required"
+		} else if seen[item.VariantID] {
+			problems[fmt.Sprintf("items[%d].variant_id", i)] = "variant appears more than once"
+		}
+		seen[item.VariantID] = true
+
+		if item.Quantity < 1 {
+			problems[fmt.Sprintf("items[%d].quantity", i)] = "quantity must be at least 1"
+		} else if item.Quantity > 99 {
+			problems[fmt.Sprintf("items[%d].quantity", i)] = "quantity must not exceed 99"
+		}
+	}
+
+	if c.Email != "" {
+		if _, err := mail.ParseAddress(c.Email); err != nil {
+			problems["email"] = "invalid email format"
+		}
+	}
+
+	return problems
+}
+
+// CheckoutSessionResponseDTO is returned after a checkout session is created
+type CheckoutSessionResponseDTO struct {
+	SessionID string    `json:"session_id"`
+	URL       string    `json:"url"`
+	Mode      string    `json:"mode"` // payment or subscription
+	ExpiresAt time.Time `json:"expires_at"`
+}
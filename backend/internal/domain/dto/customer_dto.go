@@ -0,0 +1,157 @@
+// internal/domain/dto/customer_dto.go
+package dto
+
+import (
+	"context"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// CustomerCreateDTO represents the data needed to create a new customer
+type CustomerCreateDTO struct {
+	Email       string `json:"email"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	PhoneNumber string `json:"phone_number"`
+}
+
+// Valid validates the CustomerCreateDTO
+func (c *CustomerCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	c.Email = strings.TrimSpace(c.Email)
+	if c.Email == "" {
+		problems["email"] = "email is required"
+	} else if _, err := mail.ParseAddress(c.Email); err != nil {
+		problems["email"] = "must be a valid email address"
+	}
+
+	if c.FirstName == "" {
+		problems["first_name"] = "first name is required"
+	} else if len(c.FirstName) > 100 {
+		problems["first_name"] = "must not exceed 100 characters"
+	}
+
+	if c.LastName == "" {
+		problems["last_name"] = "last name is required"
+	} else if len(c.LastName) > 100 {
+		problems["last_name"] = "must not exceed 100 characters"
+	}
+
+	if len(c.PhoneNumber) > 50 {
+		problems["phone_number"] = "must not exceed 50 characters"
+	}
+
+	return problems
+}
+
+// ToModel converts CustomerCreateDTO to a Customer model
+func (c *CustomerCreateDTO) ToModel() *model.Customer {
+	return &model.Customer{
+		ID:          uuid.New(),
+		Email:       strings.ToLower(c.Email),
+		FirstName:   c.FirstName,
+		LastName:    c.LastName,
+		PhoneNumber: c.PhoneNumber,
+		Active:      true,
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+// CustomerUpdateDTO represents the data needed to update a customer
+// Using pointers for all fields to differentiate between zero values and absence
+type CustomerUpdateDTO struct {
+	Email       *string `json:"email"`
+	FirstName   *string `json:"first_name"`
+	LastName    *string `json:"last_name"`
+	PhoneNumber *string `json:"phone_number"`
+	Active      *bool   `json:"active"`
+}
+
+// Valid performs validation on the CustomerUpdateDTO fields
+func (dto *CustomerUpdateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if dto.Email != nil {
+		email := strings.TrimSpace(*dto.Email)
+		if _, err := mail.ParseAddress(email); err != nil {
+			problems["email"] = "must be a valid email address"
+		}
+		dto.Email = &email
+	}
+
+	if dto.FirstName != nil {
+		if *dto.FirstName == "" {
+			problems["first_name"] = "must not be empty"
+		} else if len(*dto.FirstName) > 100 {
+			problems["first_name"] = "must not exceed 100 characters"
+		}
+	}
+
+	if dto.LastName != nil {
+		if *dto.LastName == "" {
+			problems["last_name"] = "must not be empty"
+		} else if len(*dto.LastName) > 100 {
+			problems["last_name"] = "must not exceed 100 characters"
+		}
+	}
+
+	if dto.PhoneNumber != nil && len(*dto.PhoneNumber) > 50 {
+		problems["phone_number"] = "must not exceed 50 characters"
+	}
+
+	return problems
+}
+
+// ApplyToModel applies the non-nil fields from the DTO to the customer model
+func (dto *CustomerUpdateDTO) ApplyToModel(customer *model.Customer) {
+	if dto.Email != nil {
+		customer.Email = strings.ToLower(*dto.Email)
+	}
+	if dto.FirstName != nil {
+		customer.FirstName = *dto.FirstName
+	}
+	if dto.LastName != nil {
+		customer.LastName = *dto.LastName
+	}
+	if dto.PhoneNumber != nil {
+		customer.PhoneNumber = *dto.PhoneNumber
+	}
+	if dto.Active != nil {
+		customer.Active = *dto.Active
+	}
+	customer.UpdatedAt = time.Now()
+}
+
+// CustomerResponseDTO represents the customer data returned to the client
+type CustomerResponseDTO struct {
+	ID          string `json:"id"`
+	Email       string `json:"email"`
+	FirstName   string `json:"first_name"`
+	LastName    string `json:"last_name"`
+	PhoneNumber string `json:"phone_number,omitempty"`
+	StripeID    string `json:"stripe_id"`
+	Active      bool   `json:"active"`
+	CreatedAt   string `json:"created_at"`
+	UpdatedAt   string `json:"updated_at"`
+}
+
+// CustomerResponseDTOFromModel converts a Customer model to CustomerResponseDTO
+func CustomerResponseDTOFromModel(customer *model.Customer) CustomerResponseDTO {
+	return CustomerResponseDTO{
+		ID:          customer.ID.String(),
+		Email:       customer.Email,
+		FirstName:   customer.FirstName,
+		LastName:    customer.LastName,
+		PhoneNumber: customer.PhoneNumber,
+		StripeID:    customer.StripeID,
+		Active:      customer.Active,
+		CreatedAt:   customer.CreatedAt.Format(time.RFC3339),
+		UpdatedAt:   customer.UpdatedAt.Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,129 @@
+// internal/domain/dto/order_dto.go
+package dto
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// OrderItemDTO is a requested order line
+type OrderItemDTO struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Quantity  int       `json:"quantity"`
+}
+
+// OrderCreateDTO represents the data needed to place an order
+type OrderCreateDTO struct {
+	CustomerID     uuid.UUID         `json:"customer_id"`
+	AddressID      *uuid.UUID        `json:"address_id,omitempty"`      // Defaults to the customer's default address
+	SubscriptionID *uuid.UUID        `json:"subscription_id,omitempty"` // Set for subscription deliveries
+	Items          []OrderItemDTO    `json:"items"`
+	ShippingAmount int64             `json:"shipping_amount,omitempty"`
+	Metadata       map[string]string `json:"metadata,omitempty"`
+}
+
+// Valid validates the OrderCreateDTO
+func (o *OrderCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if o.CustomerID == uuid.Nil {
+		problems["customer_id"] = "customer ID is required"
+	}
+
+	if len(o.Items) == 0 {
+		problems["items"] = "at least one item is required"
+	}
+
+	seen := make(map[uuid.UUID]bool)
+	for i, item := range o.Items {
+		if item.VariantID == uuid.Nil {
+			problems[fmt.Sprintf("items[%d].variant_id", i)] = "variant ID is required"
+		} else if seen[item.VariantID] {
+			problems[fmt.Sprintf("items[%d].variant_id", i)] = "variant appears more than once"
+		}
+		seen[item.VariantID] = true
+
+		if item.Quantity < 1 {
+			problems[fmt.Sprintf("items[%d].quantity", i)] = "quantity must be at least 1"
+		}
+	}
+
+	if o.ShippingAmount < 0 {
+		problems["shipping_amount"] = "shipping amount cannot be negative"
+	}
+
+	return problems
+}
+
+// OrderStatusUpdateDTO represents a request to move an order to a new status
+type OrderStatusUpdateDTO struct {
+	Status         string `json:"status"`
+	Note           string `json:"note,omitempty"`
+	Carrier        string `json:"carrier,omitempty"`         // Used when shipping
+	TrackingNumber string `json:"tracking_number,omitempty"` // Used when shipping
+}
+
+// Valid validates the OrderStatusUpdateDTO
+func (o *OrderStatusUpdateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	switch o.Status {
+	case model.OrderStatusPaid, model.OrderStatusRoasting, model.OrderStatusPacked,
+		model.OrderStatusShipped, model.OrderStatusDelivered,
+		model.OrderStatusCanceled, model.OrderStatusRefunded:
+	case "":
+		problems["status"] = "status is required"
+	default:
+		problems["status"] = "unknown order status"
+	}
+
+	if o.Status == model.OrderStatusShipped && o.TrackingNumber == "" {
+		problems["tracking_number"] = "tracking number is required when shipping"
+	}
+
+	if len(o.Note) > 500 {
+		problems["note"] = "must not exceed 500 characters"
+	}
+
+	return problems
+}
+
+// CheckoutOrderItemDTO is a line of a completed Stripe Checkout session
+// already resolved to one of our variants
+type CheckoutOrderItemDTO struct {
+	VariantID  uuid.UUID
+	Quantity   int
+	UnitAmount int64 // What Stripe actually charged per unit
+}
+
+// CheckoutOrderDTO carries a completed Stripe Checkout session into an order
+type CheckoutOrderDTO struct {
+	CustomerID              uuid.UUID
+	StripeCheckoutSessionID string
+	StripePaymentIntentID   string
+	Paid                    bool // false while an async payment method is still settling
+	Currency                string
+	ShippingAmount          int64
+	TaxAmount               int64
+	Total                   int64
+	Items                   []CheckoutOrderItemDTO
+	Metadata                map[string]string
+}
+
+// RenewalOrderDTO carries a paid subscription invoice into the delivery order
+// for that billing period
+type RenewalOrderDTO struct {
+	CustomerID            uuid.UUID
+	SubscriptionID        uuid.UUID
+	AddressID             *uuid.UUID
+	StripeInvoiceID       string
+	StripePaymentIntentID string
+	Currency              string
+	TaxAmount             int64
+	Total                 int64
+	Items                 []CheckoutOrderItemDTO
+	Metadata              map[string]string
+}
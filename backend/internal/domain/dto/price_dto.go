@@ -354,3 +354,80 @@ func (v *VariantPriceAssignmentDTO) Valid(ctx context.Context) map[string]string
 
 	return problems
 }
+
+// PricingRuleDTO represents a product's variant pricing rule
+type PricingRuleDTO struct {
+	Currency     string                      `json:"currency"`      // Default: USD
+	BasePrice    int64                       `json:"base_price"`    // Price in cents when the weight has none of its own
+	WeightPrices map[string]int64            `json:"weight_prices"` // e.g. {"12oz": 1800, "5lb": 6500}
+	Surcharges   map[string]map[string]int64 `json:"surcharges"`    // e.g. {"grind": {"Drip Ground": 100}}
+}
+
+// Valid validates the PricingRuleDTO
+func (p *PricingRuleDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if p.Currency == "" {
+		p.Currency = "USD" // Default currency
+	} else {
+		p.Currency = strings.ToUpper(p.Currency)
+		if len(p.Currency) != 3 {
+			problems["currency"] = "currency must be a 3-letter code (e.g., USD, EUR)"
+		}
+	}
+
+	if p.BasePrice <= 0 {
+		problems["base_price"] = "base price must be greater than 0"
+	}
+
+	for weight, amount := range p.WeightPrices {
+		if amount <= 0 {
+			problems["weight_prices."+weight] = "price must be greater than 0"
+		}
+	}
+
+	for key, values := range p.Surcharges {
+		if key == "weight" {
+			problems["surcharges.weight"] = "weights are priced with weight_prices"
+		}
+		for value, amount := range values {
+			if amount < 0 {
+				problems["surcharges."+key+"."+value] = "surcharge cannot be negative"
+			}
+		}
+	}
+
+	return problems
+}
+
+// ToModel converts PricingRuleDTO to a PricingRule for a product
+func (p *PricingRuleDTO) ToModel(productID uuid.UUID) *model.PricingRule {
+	weightPrices := p.WeightPrices
+	if weightPrices == nil {
+		weightPrices = make(map[string]int64)
+	}
+
+	surcharges := p.Surcharges
+	if surcharges == nil {
+		surcharges = make(map[string]map[string]int64)
+	}
+
+	now := time.Now()
+	return &model.PricingRule{
+		ProductID:    productID,
+		Currency:     strings.ToUpper(p.Currency),
+		BasePrice:    p.BasePrice,
+		WeightPrices: weightPrices,
+		Surcharges:   surcharges,
+		CreatedAt:    now,
+		UpdatedAt:    now,
+	}
+}
+
+// PricingRuleApplyResult reports the variants repriced by a pricing rule
+type PricingRuleApplyResult struct {
+	ProductID uuid.UUID        `json:"product_id"`
+	Repriced  []*model.Variant `json:"repriced"`
+	Unchanged int              `json:"unchanged"`
+	Errors    []string         `json:"errors,omitempty"`
+}
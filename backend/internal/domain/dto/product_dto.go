@@ -39,7 +39,8 @@ type ProductCreateDTO struct {
 	ImageURL          string              `json:"image_url"`
 	Active            bool                `json:"active"`
 	StockLevel        int                 `json:"stock_level"`
-	Weight            int                 `json:"weight"` // Weight in grams
+	ReorderThreshold  int                 `json:"reorder_threshold"` // Low-stock alert level; 0 disables
+	Weight            int                 `json:"weight"`            // Weight in grams
 	Origin            string              `json:"origin"`
 	RoastLevel        string              `json:"roast_level"`
 	FlavorNotes       string              `json:"flavor_notes"`
@@ -65,6 +66,10 @@ func (p *ProductCreateDTO) Valid(ctx context.Context) map[string]string {
 		problems["stock_level"] = "stock level cannot be negative"
 	}
 
+	if p.ReorderThreshold < 0 {
+		problems["reorder_threshold"] = "reorder threshold cannot be negative"
+	}
+
 	// Validate roast level if provided
 	if p.RoastLevel != "" && !validRoastLevels[strings.ToLower(p.RoastLevel)] {
 		problems["roast_level"] = "must be one of: light, medium, dark"
@@ -122,6 +127,7 @@ func (p *ProductCreateDTO) ToModel() *model.Product {
 		ImageURL:          p.ImageURL,
 		Active:            p.Active,
 		StockLevel:        p.StockLevel,
+		ReorderThreshold:  p.ReorderThreshold,
 		Weight:            p.Weight,
 		Origin:            p.Origin,
 		RoastLevel:        p.RoastLevel,
@@ -142,7 +148,8 @@ type ProductUpdateDTO struct {
 	ImageURL          *string              `json:"image_url"`
 	Active            *bool                `json:"active"`
 	StockLevel        *int                 `json:"stock_level"`
-	Weight            *int                 `json:"weight"` // Weight in grams
+	ReorderThreshold  *int                 `json:"reorder_threshold"` // Low-stock alert level; 0 disables
+	Weight            *int                 `json:"weight"`            // Weight in grams
 	Origin            *string              `json:"origin"`
 	RoastLevel        *string              `json:"roast_level"`
 	FlavorNotes       *string              `json:"flavor_notes"`
@@ -184,6 +191,11 @@ func (dto *ProductUpdateDTO) Valid(ctx context.Context) map[string]string {
 		problems["stock_level"] = "must not be negative"
 	}
 
+	// ReorderThreshold validation
+	if dto.ReorderThreshold != nil && *dto.ReorderThreshold < 0 {
+		problems["reorder_threshold"] = "must not be negative"
+	}
+
 	// Weight validation
 	if dto.Weight != nil && *dto.Weight < 1 {
 		problems["weight"] = "must be at least 1 gram"
@@ -252,6 +264,9 @@ func (dto *ProductUpdateDTO) ApplyToModel(product *model.Product) {
 	if dto.StockLevel != nil {
 		product.StockLevel = *dto.StockLevel
 	}
+	if dto.ReorderThreshold != nil {
+		product.ReorderThreshold = *dto.ReorderThreshold
+	}
 	if dto.Weight != nil {
 		product.Weight = *dto.Weight
 	}
@@ -0,0 +1,56 @@
+// internal/domain/dto/subscription_dto.go
+package dto
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// SubscriptionCreateDTO represents the data needed to start a subscription
+type SubscriptionCreateDTO struct {
+	CustomerID uuid.UUID  `json:"customer_id"`
+	PriceID    uuid.UUID  `json:"price_id"`             // Must be an active recurring price
+	AddressID  *uuid.UUID `json:"address_id,omitempty"` // Defaults to the customer's default address
+	Quantity   int        `json:"quantity"`
+}
+
+// Valid validates the SubscriptionCreateDTO
+func (s *SubscriptionCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if s.CustomerID == uuid.Nil {
+		problems["customer_id"] = "customer ID is required"
+	}
+
+	if s.PriceID == uuid.Nil {
+		problems["price_id"] = "price ID is required"
+	}
+
+	if s.Quantity == 0 {
+		s.Quantity = 1 // Default quantity
+	} else if s.Quantity < 0 {
+		problems["quantity"] = "quantity must be at least 1"
+	} else if s.Quantity > 20 {
+		problems["quantity"] = "quantity must not exceed 20"
+	}
+
+	return problems
+}
+
+// SubscriptionCancelDTO represents a cancellation request
+type SubscriptionCancelDTO struct {
+	AtPeriodEnd bool   `json:"at_period_end"` // Keep delivering until the paid period ends
+	Reason      string `json:"reason,omitempty"`
+}
+
+// Valid validates the SubscriptionCancelDTO
+func (s *SubscriptionCancelDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if len(s.Reason) > 500 {
+		problems["reason"] = "must not exceed 500 characters"
+	}
+
+	return problems
+}
@@ -3,21 +3,23 @@ package dto
 
 import (
 	"context"
+	"strings"
 	"time"
 
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
 	"github.com/google/uuid"
 )
 
-// VariantCreateDTO represents the data needed to create a new variant
+// VariantCreateDTO represents the data needed to create a new variant.
+// The variant's Stripe product is created by the service
 type VariantCreateDTO struct {
-	ProductID       uuid.UUID `json:"product_id"`
-	PriceID         uuid.UUID `json:"price_id"`
-	StripeProductID string    `json:"stripe_product_id"`
-	StripePriceID   string    `json:"stripe_price_id"`
-	Weight          string    `json:"weight"`
-	Grind           string    `json:"grind"`
-	Active          bool      `json:"active"`
-	StockLevel      int       `json:"stock_level"`
+	ProductID        uuid.UUID `json:"product_id"`
+	PriceID          uuid.UUID `json:"price_id"`
+	Weight           string    `json:"weight"`
+	Grind            string    `json:"grind"`
+	Active           bool      `json:"active"`
+	StockLevel       int       `json:"stock_level"`
+	ReorderThreshold *int      `json:"reorder_threshold,omitempty"`
 }
 
 // Valid validates the VariantCreateDTO
@@ -32,14 +34,6 @@ func (v *VariantCreateDTO) Valid(ctx context.Context) map[string]string {
 		problems["price_id"] = "price ID is required"
 	}
 
-	if v.StripeProductID == "" {
-		problems["stripe_product_id"] = "Stripe product ID is required"
-	}
-
-	if v.StripePriceID == "" {
-		problems["stripe_price_id"] = "Stripe price ID is required"
-	}
-
 	if v.Weight == "" {
 		problems["weight"] = "weight is required"
 	} else if !isValidWeight(v.Weight) {
@@ -56,41 +50,30 @@ func (v *VariantCreateDTO) Valid(ctx context.Context) map[string]string {
 		problems["stock_level"] = "stock level cannot be negative"
 	}
 
+	if v.ReorderThreshold != nil && *v.ReorderThreshold < 0 {
+		problems["reorder_threshold"] = "reorder threshold cannot be negative"
+	}
+
 	return problems
 }
 
-// VariantUpdateDTO represents the data needed to update an existing variant
+// VariantUpdateDTO represents the data needed to update an existing variant.
+// Stock changes go through the inventory ledger instead
 type VariantUpdateDTO struct {
-	ProductID       *uuid.UUID `json:"product_id,omitempty"`
-	PriceID         *uuid.UUID `json:"price_id,omitempty"`
-	StripeProductID *string    `json:"stripe_product_id,omitempty"`
-	StripePriceID   *string    `json:"stripe_price_id,omitempty"`
-	Weight          *string    `json:"weight,omitempty"`
-	Grind           *string    `json:"grind,omitempty"`
-	Active          *bool      `json:"active,omitempty"`
-	StockLevel      *int       `json:"stock_level,omitempty"`
+	PriceID *uuid.UUID `json:"price_id,omitempty"`
+	Weight  *string    `json:"weight,omitempty"`
+	Grind   *string    `json:"grind,omitempty"`
+	Active  *bool      `json:"active,omitempty"`
 }
 
 // Valid validates the VariantUpdateDTO
 func (v *VariantUpdateDTO) Valid(ctx context.Context) map[string]string {
 	problems := make(map[string]string)
 
-	if v.ProductID != nil && *v.ProductID == uuid.Nil {
-		problems["product_id"] = "product ID cannot be empty when provided"
-	}
-
 	if v.PriceID != nil && *v.PriceID == uuid.Nil {
 		problems["price_id"] = "price ID cannot be empty when provided"
 	}
 
-	if v.StripeProductID != nil && *v.StripeProductID == "" {
-		problems["stripe_product_id"] = "Stripe product ID cannot be empty when provided"
-	}
-
-	if v.StripePriceID != nil && *v.StripePriceID == "" {
-		problems["stripe_price_id"] = "Stripe price ID cannot be empty when provided"
-	}
-
 	if v.Weight != nil {
 		if *v.Weight == "" {
 			problems["weight"] = "weight cannot be empty when provided"
@@ -107,7 +90,21 @@ func (v *VariantUpdateDTO) Valid(ctx context.Context) map[string]string {
 		}
 	}
 
-	if v.StockLevel != nil && *v.StockLevel < 0 {
+	return problems
+}
+
+// VariantStockLevelDTO sets a variant's stock level outright
+type VariantStockLevelDTO struct {
+	StockLevel *int `json:"stock_level"`
+}
+
+// Valid validates the VariantStockLevelDTO
+func (v *VariantStockLevelDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if v.StockLevel == nil {
+		problems["stock_level"] = "stock level is required"
+	} else if *v.StockLevel < 0 {
 		problems["stock_level"] = "stock level cannot be negative"
 	}
 
@@ -168,3 +165,79 @@ type VariantOptionsResponse struct {
 	Weights []string `json:"weights"`
 	Grinds  []string `json:"grinds"`
 }
+
+// StockMovementCreateDTO records a manual change to a variant's stock.
+// Send either a signed quantity or, for adjustments, the counted stock level
+type StockMovementCreateDTO struct {
+	Type         string `json:"type"`
+	Quantity     *int   `json:"quantity,omitempty"`
+	CountedLevel *int   `json:"counted_level,omitempty"` // Physical count; the ledger records the difference
+	Reason       string `json:"reason"`
+	Reference    string `json:"reference,omitempty"`
+}
+
+// Valid validates the StockMovementCreateDTO
+func (s *StockMovementCreateDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	// Sales and reservations are only recorded by orders
+	switch s.Type {
+	case model.MovementTypeReceipt, model.MovementTypeAdjustment, model.MovementTypeSpoilage, model.MovementTypeReturn:
+	case "":
+		problems["type"] = "type is required"
+	default:
+		problems["type"] = "type must be receipt, adjustment, spoilage or return"
+	}
+
+	if strings.TrimSpace(s.Reason) == "" {
+		problems["reason"] = "reason is required"
+	}
+
+	switch {
+	case s.Quantity != nil && s.CountedLevel != nil:
+		problems["quantity"] = "send either quantity or counted_level, not both"
+	case s.CountedLevel != nil:
+		if s.Type != model.MovementTypeAdjustment {
+			problems["counted_level"] = "counted level is only allowed for adjustments"
+		} else if *s.CountedLevel < 0 {
+			problems["counted_level"] = "counted level cannot be negative"
+		}
+	case s.Quantity == nil:
+		problems["quantity"] = "quantity or counted_level is required"
+	case *s.Quantity == 0:
+		problems["quantity"] = "quantity cannot be zero"
+	case (s.Type == model.MovementTypeReceipt || s.Type == model.MovementTypeReturn) && *s.Quantity < 0:
+		problems["quantity"] = "quantity must be positive for " + s.Type
+	case s.Type == model.MovementTypeSpoilage && *s.Quantity > 0:
+		problems["quantity"] = "quantity must be negative for spoilage"
+	}
+
+	return problems
+}
+
+// VariantReorderThresholdDTO sets a variant's own reorder threshold.
+// A null threshold falls back to the product's
+type VariantReorderThresholdDTO struct {
+	ReorderThreshold *int `json:"reorder_threshold"`
+}
+
+// Valid validates the VariantReorderThresholdDTO
+func (v *VariantReorderThresholdDTO) Valid(ctx context.Context) map[string]string {
+	problems := make(map[string]string)
+
+	if v.ReorderThreshold != nil && *v.ReorderThreshold < 0 {
+		problems["reorder_threshold"] = "reorder threshold cannot be negative"
+	}
+
+	return problems
+}
+
+// VariantRegenerationPlan lists how a product's variants line up with its
+// current options. Applied is false for a dry run
+type VariantRegenerationPlan struct {
+	ProductID    uuid.UUID           `json:"product_id"`
+	ToCreate     []map[string]string `json:"to_create"`     // Option combinations with no variant yet
+	ToKeep       []*model.Variant    `json:"to_keep"`       // Variants matching a combination; inactive ones are reactivated
+	ToDeactivate []*model.Variant    `json:"to_deactivate"` // Active variants no combination matches
+	Applied      bool                `json:"applied"`
+}
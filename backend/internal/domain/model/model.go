@@ -1,6 +1,7 @@
 package model
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/google/uuid"
@@ -19,25 +20,27 @@ type Product struct {
 	Archived          bool                `json:"archived"`
 	AllowSubscription bool                `json:"allow_subscription"` // Flag to indicate if product can be subscribed to
 	StockLevel        int                 `json:"stock_level"`
-	Weight            int                 `json:"weight"`  // Base weight in grams
-	Options           map[string][]string `json:"options"` // Product options (e.g., weight, grind)
+	ReorderThreshold  int                 `json:"reorder_threshold"` // Low stock at or below this level; 0 disables alerts
+	Weight            int                 `json:"weight"`            // Base weight in grams
+	Options           map[string][]string `json:"options"`           // Product options (e.g., weight, grind)
 	CreatedAt         time.Time           `json:"created_at"`
 	UpdatedAt         time.Time           `json:"updated_at"`
 }
 
 // Variant represents a specific product variant (combination of product options)
 type Variant struct {
-	ID              uuid.UUID         `json:"id"`
-	ProductID       uuid.UUID         `json:"product_id"`
-	PriceID         uuid.UUID         `json:"price_id"`
-	StripeProductID string            `json:"stripe_product_id"`
-	StripePriceID   string            `json:"stripe_price_id"`
-	Active          bool              `json:"active"`
-	StockLevel      int               `json:"stock_level"`
-	Weight          int               `json:"weight"`  // Base weight in grams
-	Options         map[string]string `json:"options"` // Map of option key to selected value
-	CreatedAt       time.Time         `json:"created_at"`
-	UpdatedAt       time.Time         `json:"updated_at"`
+	ID               uuid.UUID         `json:"id"`
+	ProductID        uuid.UUID         `json:"product_id"`
+	PriceID          uuid.UUID         `json:"price_id"`
+	StripeProductID  string            `json:"stripe_product_id"`
+	StripePriceID    string            `json:"stripe_price_id"`
+	Active           bool              `json:"active"`
+	StockLevel       int               `json:"stock_level"`
+	ReorderThreshold *int              `json:"reorder_threshold,omitempty"` // Overrides the product's threshold when set
+	Weight           int               `json:"weight"`                      // Base weight in grams
+	Options          map[string]string `json:"options"`                     // Map of option key to selected value
+	CreatedAt        time.Time         `json:"created_at"`
+	UpdatedAt        time.Time         `json:"updated_at"`
 }
 
 // Price represents the pricing options for subscriptions or one-time purchases
@@ -56,6 +59,18 @@ type Price struct {
 	UpdatedAt     time.Time `json:"updated_at"`
 }
 
+// PricingRule computes variant prices for a product from their option values:
+// the base price for the variant's weight plus a surcharge for each other option
+type PricingRule struct {
+	ProductID    uuid.UUID                   `json:"product_id"`
+	Currency     string                      `json:"currency"`
+	BasePrice    int64                       `json:"base_price"`    // In cents; used when the weight has no price of its own
+	WeightPrices map[string]int64            `json:"weight_prices"` // Weight option value -> base price in cents
+	Surcharges   map[string]map[string]int64 `json:"surcharges"`    // Option key -> option value -> cents added
+	CreatedAt    time.Time                   `json:"created_at"`
+	UpdatedAt    time.Time                   `json:"updated_at"`
+}
+
 // Customer represents a subscriber in the system
 type Customer struct {
 	ID          uuid.UUID `json:"id"`
@@ -128,6 +143,10 @@ type Subscription struct {
 	Metadata  map[string]string `json:"metadata,omitempty"` // Additional data
 	CreatedAt time.Time         `json:"created_at"`
 	UpdatedAt time.Time         `json:"updated_at"`
+
+	// Set when a new subscription's first invoice awaits payment, not stored
+	PaymentClientSecret string `json:"payment_client_secret,omitempty"` // Confirms the invoice's PaymentIntent with Stripe.js
+	HostedInvoiceURL    string `json:"hosted_invoice_url,omitempty"`    // Stripe-hosted page to pay the invoice
 }
 
 // SubscriptionWithDetails includes related entity details for API responses
@@ -142,6 +161,266 @@ type SubscriptionWithDetails struct {
 	Currency      string `json:"currency"`       // USD, EUR, etc.
 }
 
+// Order status constants
+const (
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusRoasting  = "roasting"
+	OrderStatusPacked    = "packed"
+	OrderStatusShipped   = "shipped"
+	OrderStatusDelivered = "delivered"
+	OrderStatusCanceled  = "canceled"
+	OrderStatusRefunded  = "refunded"
+)
+
+// Order represents a one-off purchase or a subscription delivery
+type Order struct {
+	ID             uuid.UUID  `json:"id"`
+	CustomerID     uuid.UUID  `json:"customer_id"`
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty"` // Set for subscription deliveries
+	AddressID      *uuid.UUID `json:"address_id,omitempty"`
+	Status         string     `json:"status"`
+
+	// Amounts in cents, snapshotted when the order is placed
+	Currency       string `json:"currency"`
+	Subtotal       int64  `json:"subtotal"`
+	ShippingAmount int64  `json:"shipping_amount"`
+	TaxAmount      int64  `json:"tax_amount"`
+	Total          int64  `json:"total"`
+
+	// Stripe references
+	StripeCheckoutSessionID string `json:"stripe_checkout_session_id,omitempty"`
+	StripePaymentIntentID   string `json:"stripe_payment_intent_id,omitempty"`
+	StripeInvoiceID         string `json:"stripe_invoice_id,omitempty"`
+
+	// Fulfillment details
+	Carrier        string `json:"carrier,omitempty"`
+	TrackingNumber string `json:"tracking_number,omitempty"`
+
+	Items    []*OrderItem      `json:"items"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+
+	PaidAt      *time.Time `json:"paid_at,omitempty"`
+	ShippedAt   *time.Time `json:"shipped_at,omitempty"`
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+	CanceledAt  *time.Time `json:"canceled_at,omitempty"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// OrderItem is a line on an order. Price and options are copied from the
+// variant at order time so later catalogue changes don't rewrite history
+type OrderItem struct {
+	ID          uuid.UUID         `json:"id"`
+	OrderID     uuid.UUID         `json:"order_id"`
+	VariantID   uuid.UUID         `json:"variant_id"`
+	ProductID   uuid.UUID         `json:"product_id"`
+	PriceID     uuid.UUID         `json:"price_id"`
+	ProductName string            `json:"product_name"`
+	Options     map[string]string `json:"options"`
+	Quantity    int               `json:"quantity"`
+	UnitAmount  int64             `json:"unit_amount"`
+	TotalAmount int64             `json:"total_amount"`
+	CreatedAt   time.Time         `json:"created_at"`
+}
+
+// OrderStatusChange records a single transition in an order's lifecycle
+type OrderStatusChange struct {
+	ID         uuid.UUID `json:"id"`
+	OrderID    uuid.UUID `json:"order_id"`
+	FromStatus string    `json:"from_status,omitempty"` // Empty for the initial status
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
+
+// Invoice status constants, mirroring Stripe's invoice statuses
+const (
+	InvoiceStatusDraft         = "draft"
+	InvoiceStatusOpen          = "open"
+	InvoiceStatusPaid          = "paid"
+	InvoiceStatusUncollectible = "uncollectible"
+	InvoiceStatusVoid          = "void"
+)
+
+// Invoice is a Stripe invoice, usually for one billing period of a subscription
+type Invoice struct {
+	ID             uuid.UUID  `json:"id"`
+	CustomerID     uuid.UUID  `json:"customer_id"`
+	SubscriptionID *uuid.UUID `json:"subscription_id,omitempty"`
+	OrderID        *uuid.UUID `json:"order_id,omitempty"` // The delivery created when the invoice was paid
+
+	StripeID      string `json:"stripe_id"`
+	Status        string `json:"status"`
+	BillingReason string `json:"billing_reason"` // e.g. subscription_create, subscription_cycle
+
+	// Amounts in cents
+	Currency   string `json:"currency"`
+	AmountDue  int64  `json:"amount_due"`
+	AmountPaid int64  `json:"amount_paid"`
+
+	// Billing period the invoice pays for
+	PeriodStart time.Time `json:"period_start"`
+	PeriodEnd   time.Time `json:"period_end"`
+
+	HostedInvoiceURL   string     `json:"hosted_invoice_url,omitempty"`
+	AttemptCount       int        `json:"attempt_count"`
+	NextPaymentAttempt *time.Time `json:"next_payment_attempt,omitempty"`
+
+	PaidAt    *time.Time `json:"paid_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+	UpdatedAt time.Time  `json:"updated_at"`
+}
+
+// Dunning case status constants
+const (
+	DunningStatusOpen      = "open"
+	DunningStatusRecovered = "recovered" // The customer paid
+	DunningStatusCanceled  = "canceled"  // Gave up and canceled the subscription
+	DunningStatusPaused    = "paused"    // Gave up and paused the subscription
+)
+
+// Dunning step constants, recorded in order as a case progresses
+const (
+	DunningStepStarted          = "started"
+	DunningStepPaymentFailed    = "payment_failed"
+	DunningStepDeliveryHeld     = "delivery_held"
+	DunningStepReminder         = "reminder"
+	DunningStepRecovered        = "recovered"
+	DunningStepDeliveryReleased = "delivery_released"
+	DunningStepExhausted        = "exhausted"
+)
+
+// DunningCase tracks the recovery of one unpaid subscription invoice
+type DunningCase struct {
+	ID               uuid.UUID  `json:"id"`
+	SubscriptionID   uuid.UUID  `json:"subscription_id"`
+	CustomerID       uuid.UUID  `json:"customer_id"`
+	InvoiceID        uuid.UUID  `json:"invoice_id"`
+	StripeInvoiceID  string     `json:"stripe_invoice_id"`
+	Status           string     `json:"status"`
+	AttemptCount     int        `json:"attempt_count"` // Failed payment attempts reported by Stripe
+	RemindersSent    int        `json:"reminders_sent"`
+	HeldDeliveryDate *time.Time `json:"held_delivery_date,omitempty"` // Delivery whose order waits for the invoice to be paid
+	NextActionAt     *time.Time `json:"next_action_at,omitempty"`     // Next reminder or final action
+	StartedAt        time.Time  `json:"started_at"`
+	ResolvedAt       *time.Time `json:"resolved_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+	UpdatedAt        time.Time  `json:"updated_at"`
+}
+
+// DunningStep is an entry in a dunning case's audit trail
+type DunningStep struct {
+	ID            uuid.UUID `json:"id"`
+	DunningCaseID uuid.UUID `json:"dunning_case_id"`
+	Step          string    `json:"step"`
+	Detail        string    `json:"detail,omitempty"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// Inventory movement type constants
+const (
+	MovementTypeReceipt     = "receipt"     // Roasted stock put on the shelf
+	MovementTypeSale        = "sale"        // Taken by an order
+	MovementTypeReservation = "reservation" // Set aside outside of an order
+	MovementTypeAdjustment  = "adjustment"  // Correction, usually after a physical count
+	MovementTypeSpoilage    = "spoilage"    // Stale or damaged beans written off
+	MovementTypeReturn      = "return"      // Put back from a canceled, refunded or returned order
+)
+
+// InventoryMovement is one append-only entry in a variant's stock ledger.
+// A variant's stock level is the sum of its movements
+type InventoryMovement struct {
+	ID           uuid.UUID `json:"id"`
+	VariantID    uuid.UUID `json:"variant_id"`
+	Type         string    `json:"type"`
+	Quantity     int       `json:"quantity"`      // Signed change in stock
+	BalanceAfter int       `json:"balance_after"` // Stock level once applied
+	Reason       string    `json:"reason"`
+	Reference    string    `json:"reference,omitempty"` // e.g. an order ID
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// LowStockVariant is a variant at or below its effective reorder threshold
+type LowStockVariant struct {
+	VariantID        uuid.UUID         `json:"variant_id"`
+	ProductID        uuid.UUID         `json:"product_id"`
+	ProductName      string            `json:"product_name"`
+	Options          map[string]string `json:"options"`
+	StockLevel       int               `json:"stock_level"`
+	ReorderThreshold int               `json:"reorder_threshold"` // Variant's own threshold, else the product's
+}
+
+// Stock reservation status constants
+const (
+	ReservationStatusHeld      = "held"      // Counts against available stock
+	ReservationStatusConfirmed = "confirmed" // Paid; the order has taken the stock
+	ReservationStatusReleased  = "released"  // Checkout abandoned or failed
+	ReservationStatusExpired   = "expired"   // Released by the sweeper
+)
+
+// StockReservation holds variant stock for a checkout until it is paid or expires.
+// Reservations made together share a Reference
+type StockReservation struct {
+	ID        uuid.UUID `json:"id"`
+	VariantID uuid.UUID `json:"variant_id"`
+	Reference string    `json:"reference"`
+	Quantity  int       `json:"quantity"`
+	Status    string    `json:"status"`
+	ExpiresAt time.Time `json:"expires_at"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Cart status constants
+const (
+	CartStatusActive    = "active"
+	CartStatusMerged    = "merged"    // A guest cart folded into a customer cart
+	CartStatusConverted = "converted" // Checked out
+)
+
+// Cart is a server-side shopping cart. Guest carts have no customer
+type Cart struct {
+	ID         uuid.UUID   `json:"id"`
+	CustomerID *uuid.UUID  `json:"customer_id,omitempty"`
+	Status     string      `json:"status"`
+	Items      []*CartItem `json:"items"`
+	CreatedAt  time.Time   `json:"created_at"`
+	UpdatedAt  time.Time   `json:"updated_at"`
+
+	// Computed when the cart is priced, not stored
+	Currency    string           `json:"currency,omitempty"`
+	Subtotal    int64            `json:"subtotal"`
+	Adjustments []CartAdjustment `json:"adjustments,omitempty"`
+}
+
+// CartItem is a variant line in a cart
+type CartItem struct {
+	ID         uuid.UUID `json:"id"`
+	CartID     uuid.UUID `json:"cart_id"`
+	VariantID  uuid.UUID `json:"variant_id"`
+	PriceID    uuid.UUID `json:"price_id"`
+	Quantity   int       `json:"quantity"`
+	UnitAmount int64     `json:"unit_amount"` // Price at the last repricing
+	CreatedAt  time.Time `json:"created_at"`
+	UpdatedAt  time.Time `json:"updated_at"`
+
+	// Filled in when the cart is priced, not stored
+	ProductID   uuid.UUID         `json:"product_id"`
+	ProductName string            `json:"product_name"`
+	Options     map[string]string `json:"options,omitempty"`
+	TotalAmount int64             `json:"total_amount"`
+}
+
+// CartAdjustment tells the shopper why a cart changed since they last saw it
+type CartAdjustment struct {
+	VariantID uuid.UUID `json:"variant_id"`
+	Type      string    `json:"type"` // removed or repriced
+	Reason    string    `json:"reason"`
+	OldAmount int64     `json:"old_amount,omitempty"`
+	NewAmount int64     `json:"new_amount,omitempty"`
+}
+
 // SyncHash represents a content hash for tracking sync state between systems
 type SyncHash struct {
 	ID              uuid.UUID `json:"id"`
@@ -154,6 +433,51 @@ type SyncHash struct {
 	UpdatedAt       time.Time `json:"updated_at"`
 }
 
+// VariantSyncState is a variant with the sync hash recorded for its current
+// Stripe product. SyncHash is nil when the pair has never been synced
+type VariantSyncState struct {
+	VariantID       uuid.UUID         `json:"variant_id"`
+	ProductID       uuid.UUID         `json:"product_id"`
+	ProductName     string            `json:"product_name"`
+	Options         map[string]string `json:"options"`
+	StripeProductID string            `json:"stripe_product_id"`
+	Active          bool              `json:"active"`
+	SyncHash        *SyncHash         `json:"sync_hash,omitempty"`
+}
+
+// OutboxEvent is a domain event stored in the same transaction as the change
+// it describes, waiting for the relay to publish it
+type OutboxEvent struct {
+	ID        uuid.UUID       `json:"id"` // Also the published event ID, so consumers can dedupe
+	Topic     string          `json:"topic"`
+	Payload   json.RawMessage `json:"payload"`
+	Attempts  int             `json:"attempts"`
+	LastError string          `json:"last_error,omitempty"`
+	CreatedAt time.Time       `json:"created_at"`
+	SentAt    *time.Time      `json:"sent_at,omitempty"`
+}
+
+// WebhookEvent is a verified Stripe webhook event and the outcome of processing it
+type WebhookEvent struct {
+	ID          string          `json:"id"` // Stripe event ID
+	Type        string          `json:"type"`
+	Payload     json.RawMessage `json:"payload"` // The event exactly as Stripe sent it
+	Status      string          `json:"status"`
+	Attempts    int             `json:"attempts"`
+	LastError   string          `json:"last_error,omitempty"`
+	ReceivedAt  time.Time       `json:"received_at"`
+	UpdatedAt   time.Time       `json:"updated_at"`
+	ProcessedAt *time.Time      `json:"processed_at,omitempty"`
+}
+
+// Webhook event status constants
+const (
+	WebhookEventStatusPending    = "pending"
+	WebhookEventStatusProcessing = "processing"
+	WebhookEventStatusProcessed  = "processed"
+	WebhookEventStatusFailed     = "failed"
+)
+
 // Sync source constants for consistent usage
 const (
 	SyncSourceStripeWebhook = "stripe_webhook"
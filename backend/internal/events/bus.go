@@ -4,10 +4,11 @@ package events
 import (
 	"encoding/json"
 	"fmt"
+	"sync"
 	"time"
 
+	"github.com/dukerupert/coffee-commerce/config"
 	"github.com/dukerupert/coffee-commerce/internal/metrics"
-	"github.com/google/uuid"
 	"github.com/nats-io/nats.go"
 	"github.com/rs/zerolog"
 )
@@ -17,16 +18,34 @@ type EventBus interface {
 	// Publish sends an event to the specified topic
 	Publish(topic string, payload interface{}) error
 
+	// PublishEvent sends a prepared event, keeping its ID so redeliveries can be deduplicated
+	PublishEvent(event Event) error
+
 	// PublishPersistent publishes an event that will be stored in JetStream
 	PublishPersistent(topic string, payload interface{}) error
 
-	// Subscribe registers a handler for events on the specified topic
-	Subscribe(topic string, handler func([]byte)) (*nats.Subscription, error)
+	// Subscribe registers a handler for events on the specified topic. Topics
+	// may use NATS wildcards: * matches one token, > matches the rest
+	Subscribe(topic string, handler func([]byte)) (Subscription, error)
+
+	// SubscribeDurable registers a handler on a durable consumer. Messages are
+	// redelivered with backoff until the handler returns nil, then dead-lettered
+	SubscribeDurable(topic, durable string, handler DurableHandler) (Subscription, error)
+
+	// SubscribeOrdered is SubscribeDurable with one message in flight at a time:
+	// a failing message holds back the ones behind it until it succeeds or is
+	// dead-lettered, so messages are handled in publish order
+	SubscribeOrdered(topic, durable string, handler DurableHandler) (Subscription, error)
 
 	// Close closes the connection to the message bus
 	Close()
 }
 
+// Subscription is a registered handler that can be removed
+type Subscription interface {
+	Unsubscribe() error
+}
+
 // NATSEventBus implements EventBus using NATS
 type NATSEventBus struct {
 	conn          *nats.Conn
@@ -35,14 +54,12 @@ type NATSEventBus struct {
 	metrics       *metrics.EventMetrics
 	serviceName   string
 	subscriptions map[string][]*nats.Subscription
-}
 
-// Event represents a message in the event bus
-type Event struct {
-	ID        string      `json:"id"`
-	Topic     string      `json:"topic"`
-	Timestamp time.Time   `json:"timestamp"`
-	Payload   interface{} `json:"payload"`
+	// Subjects of the durable stream; topics matching them are published with JetStream acks
+	mu               sync.RWMutex
+	durableTopics    map[string]bool
+	durableRefreshed time.Time
+	done             chan struct{}
 }
 
 // NewNATSEventBus creates a new NATS-based event bus
@@ -81,24 +98,54 @@ func NewNATSEventBus(natsURL string, logger *zerolog.Logger, metrics *metrics.Ev
 
 	subLogger.Info().Msg("Successfully connected to NATS")
 
-	return &NATSEventBus{
+	bus := &NATSEventBus{
 		conn:          nc,
 		jetStream:     js,
 		logger:        subLogger,
 		metrics:       metrics,
 		serviceName:   serviceName,
 		subscriptions: make(map[string][]*nats.Subscription),
-	}, nil
+		durableTopics: make(map[string]bool),
+		done:          make(chan struct{}),
+	}
+
+	// Learn which topics other instances made durable before publishing any
+	bus.mu.Lock()
+	bus.refreshDurableTopics()
+	bus.mu.Unlock()
+
+	return bus, nil
+}
+
+// NewEventBus creates the event bus selected by the message bus config
+func NewEventBus(cfg *config.MessageBusConfig, logger *zerolog.Logger, metrics *metrics.EventMetrics, serviceName string) (EventBus, error) {
+	switch cfg.Driver {
+	case "memory":
+		return NewMemoryEventBus(logger, metrics, serviceName, cfg.Async), nil
+	case "nats":
+		return NewNATSEventBus(cfg.URL, logger, metrics, serviceName)
+	default:
+		return nil, fmt.Errorf("unknown event bus driver '%s'", cfg.Driver)
+	}
 }
 
 // Publish sends an event to the specified topic
 func (n *NATSEventBus) Publish(topic string, payload interface{}) error {
-	// Create an event with metadata
-	event := Event{
-		ID:        uuid.New().String(),
-		Topic:     topic,
-		Timestamp: time.Now(),
-		Payload:   payload,
+	event, err := NewEvent(topic, payload)
+	if err != nil {
+		n.reportRejected(topic, err)
+		return err
+	}
+	return n.PublishEvent(event)
+}
+
+// PublishEvent validates a prepared event and sends it to its topic
+func (n *NATSEventBus) PublishEvent(event Event) error {
+	topic := event.Topic
+
+	if err := event.prepare(n.serviceName); err != nil {
+		n.reportRejected(topic, err)
+		return err
 	}
 
 	// Marshal the event to JSON
@@ -113,12 +160,18 @@ func (n *NATSEventBus) Publish(topic string, payload interface{}) error {
 	n.logger.Debug().
 		Str("topic", topic).
 		Str("event_id", event.ID).
+		Str("correlation_id", event.CorrelationID).
 		Int("data_size", len(data)).
 		Msg("Publishing event")
 
-	// Publish the event
-	err = n.conn.Publish(topic, data)
-	
+	// Publish the event, waiting for the stream to store it if the topic is durable.
+	// The message ID lets JetStream drop republished copies of the same event
+	if n.isDurable(topic) {
+		_, err = n.jetStream.Publish(topic, data, nats.MsgId(event.ID))
+	} else {
+		err = n.conn.Publish(topic, data)
+	}
+
 	// Record metrics
 	if n.metrics != nil {
 		if err != nil {
@@ -127,18 +180,19 @@ func (n *NATSEventBus) Publish(topic string, payload interface{}) error {
 			n.metrics.EventsPublished.WithLabelValues(topic, n.serviceName).Inc()
 		}
 	}
-	
+
 	return err
 }
 
 // PublishPersistent publishes an event that will be stored in JetStream
 func (n *NATSEventBus) PublishPersistent(topic string, payload interface{}) error {
-	// Create an event with metadata
-	event := Event{
-		ID:        uuid.New().String(),
-		Topic:     topic,
-		Timestamp: time.Now(),
-		Payload:   payload,
+	event, err := NewEvent(topic, payload)
+	if err == nil {
+		err = event.prepare(n.serviceName)
+	}
+	if err != nil {
+		n.reportRejected(topic, err)
+		return err
 	}
 
 	// Marshal the event to JSON
@@ -190,8 +244,16 @@ func (n *NATSEventBus) PublishPersistent(topic string, payload interface{}) erro
 	return err
 }
 
+// reportRejected logs and counts an event that failed envelope or payload validation
+func (n *NATSEventBus) reportRejected(topic string, err error) {
+	n.logger.Error().Err(err).Str("topic", topic).Msg("Rejected event")
+	if n.metrics != nil {
+		n.metrics.EventsErrorCount.WithLabelValues(topic, n.serviceName, rejectionType(err)).Inc()
+	}
+}
+
 // Subscribe registers a handler for events on the specified topic
-func (n *NATSEventBus) Subscribe(topic string, handler func([]byte)) (*nats.Subscription, error) {
+func (n *NATSEventBus) Subscribe(topic string, handler func([]byte)) (Subscription, error) {
 	n.logger.Debug().
 		Str("topic", topic).
 		Msg("Subscribing to topic")
@@ -242,6 +304,9 @@ func (n *NATSEventBus) Subscribe(topic string, handler func([]byte)) (*nats.Subs
 func (n *NATSEventBus) Close() {
 	if n.conn != nil {
 		n.logger.Debug().Msg("Closing NATS connection")
+
+		// Stop durable consumer loops
+		close(n.done)
 		
 		// Update subscriber metrics
 		if n.metrics != nil {
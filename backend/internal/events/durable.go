@@ -0,0 +1,400 @@
+// internal/events/durable.go
+package events
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strconv"
+	"time"
+
+	"github.com/nats-io/nats.go"
+)
+
+// DurableHandler processes a message from a durable consumer. Returning an
+// error redelivers the message later; wrap ErrNoRetry to dead-letter it at once
+type DurableHandler func(data []byte) error
+
+// ErrNoRetry marks a handler error that redelivery can't fix, e.g. a malformed payload
+var ErrNoRetry = errors.New("event cannot be retried")
+
+// RetryAfterError is a handler error that asks for redelivery after a set delay
+// instead of the usual backoff, e.g. when waiting for a lock to expire
+type RetryAfterError struct {
+	Err   error
+	Delay time.Duration
+}
+
+// RetryAfter wraps a handler error so the message is redelivered after delay
+func RetryAfter(err error, delay time.Duration) error {
+	return &RetryAfterError{Err: err, Delay: delay}
+}
+
+func (e *RetryAfterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *RetryAfterError) Unwrap() error {
+	return e.Err
+}
+
+// retryDelay is how long to wait before redelivering a message whose handler
+// failed with err on its nth delivery
+func retryDelay(err error, delivered uint64) time.Duration {
+	var retryAfter *RetryAfterError
+	if errors.As(err, &retryAfter) && retryAfter.Delay > 0 {
+		return retryAfter.Delay
+	}
+	return durableBackoff(delivered)
+}
+
+const (
+	// durableStreamName is the stream capturing every topic with a durable consumer
+	durableStreamName = "DOMAIN_EVENTS"
+	durableStreamAge  = 7 * 24 * time.Hour
+
+	// Messages that exhaust their deliveries are published to dlq.<topic>
+	deadLetterStreamName = "DEAD_LETTER"
+	deadLetterPrefix     = "dlq."
+	deadLetterStreamAge  = 30 * 24 * time.Hour
+
+	durableMaxDeliver  = 8
+	durableAckWait     = time.Minute
+	durableBaseBackoff = 2 * time.Second
+	durableMaxBackoff  = 5 * time.Minute
+	durableFetchBatch  = 10
+	durableFetchWait   = 5 * time.Second
+
+	// durableStreamRefresh limits how often publishing an unknown topic reloads
+	// the durable stream's subjects
+	durableStreamRefresh = 10 * time.Second
+)
+
+// SubscribeDurable registers a handler on a JetStream pull consumer for the
+// topic. Consumers sharing a durable name split the messages between them
+func (n *NATSEventBus) SubscribeDurable(topic, durable string, handler DurableHandler) (Subscription, error) {
+	return n.subscribeDurable(topic, durable, handler, false)
+}
+
+// SubscribeOrdered registers a handler on a JetStream pull consumer that allows
+// a single unacknowledged message, so redeliveries keep their place in line
+func (n *NATSEventBus) SubscribeOrdered(topic, durable string, handler DurableHandler) (Subscription, error) {
+	return n.subscribeDurable(topic, durable, handler, true)
+}
+
+// subscribeDurable creates the streams and pull consumer and starts consuming
+func (n *NATSEventBus) subscribeDurable(topic, durable string, handler DurableHandler, ordered bool) (Subscription, error) {
+	n.logger.Debug().
+		Str("topic", topic).
+		Str("durable", durable).
+		Bool("ordered", ordered).
+		Msg("Subscribing durable consumer to topic")
+
+	if err := n.ensureDurableStream(topic); err != nil {
+		if n.metrics != nil {
+			n.metrics.EventsErrorCount.WithLabelValues(topic, n.serviceName, "create_stream_error").Inc()
+		}
+		return nil, err
+	}
+
+	if err := n.ensureDeadLetterStream(); err != nil {
+		if n.metrics != nil {
+			n.metrics.EventsErrorCount.WithLabelValues(topic, n.serviceName, "create_stream_error").Inc()
+		}
+		return nil, err
+	}
+
+	opts := []nats.SubOpt{
+		nats.BindStream(durableStreamName),
+		nats.ManualAck(),
+		nats.AckExplicit(),
+		nats.AckWait(durableAckWait),
+		nats.MaxDeliver(durableMaxDeliver),
+	}
+	batch := durableFetchBatch
+	if ordered {
+		opts = append(opts, nats.MaxAckPending(1))
+		batch = 1
+	}
+
+	sub, err := n.jetStream.PullSubscribe(topic, durable, opts...)
+	if err != nil {
+		if n.metrics != nil {
+			n.metrics.EventsErrorCount.WithLabelValues(topic, n.serviceName, "subscribe_error").Inc()
+		}
+		return nil, fmt.Errorf("failed to create durable consumer %s: %w", durable, err)
+	}
+
+	// Track subscriber count
+	if n.metrics != nil {
+		n.subscriptions[topic] = append(n.subscriptions[topic], sub)
+		n.metrics.ActiveSubscribers.WithLabelValues(topic).Inc()
+	}
+
+	go n.consume(sub, topic, durable, batch, handler)
+
+	return sub, nil
+}
+
+// isDurable reports whether a topic is captured by the durable stream. The
+// answer comes from the stream's subjects, not this process's subscriptions,
+// so an instance that only publishes still stores events for consumers
+// running elsewhere. Unknown topics refresh the subjects at most once per
+// durableStreamRefresh
+func (n *NATSEventBus) isDurable(topic string) bool {
+	n.mu.RLock()
+	durable := n.matchesDurableTopic(topic)
+	stale := time.Since(n.durableRefreshed) >= durableStreamRefresh
+	n.mu.RUnlock()
+
+	if durable || !stale {
+		return durable
+	}
+
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	if time.Since(n.durableRefreshed) >= durableStreamRefresh {
+		n.refreshDurableTopics()
+	}
+	return n.matchesDurableTopic(topic)
+}
+
+// matchesDurableTopic reports whether a topic matches a durable stream
+// subject; the caller holds the lock
+func (n *NATSEventBus) matchesDurableTopic(topic string) bool {
+	if n.durableTopics[topic] {
+		return true
+	}
+	for pattern := range n.durableTopics {
+		if subjectMatches(pattern, topic) {
+			return true
+		}
+	}
+	return false
+}
+
+// refreshDurableTopics reloads the durable stream's subjects; the caller holds
+// the lock. On failure the known subjects are kept
+func (n *NATSEventBus) refreshDurableTopics() {
+	n.durableRefreshed = time.Now()
+
+	info, err := n.jetStream.StreamInfo(durableStreamName)
+	switch {
+	case errors.Is(err, nats.ErrStreamNotFound):
+		n.durableTopics = make(map[string]bool)
+		return
+	case err != nil:
+		n.logger.Warn().Err(err).Str("stream", durableStreamName).Msg("Failed to load durable stream subjects")
+		return
+	}
+
+	topics := make(map[string]bool, len(info.Config.Subjects))
+	for _, subject := range info.Config.Subjects {
+		topics[subject] = true
+	}
+	n.durableTopics = topics
+}
+
+// ensureDurableStream creates the durable stream or adds the topic to its subjects
+func (n *NATSEventBus) ensureDurableStream(topic string) error {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+
+	if n.durableTopics[topic] {
+		return nil
+	}
+
+	info, err := n.jetStream.StreamInfo(durableStreamName)
+	switch {
+	case errors.Is(err, nats.ErrStreamNotFound):
+		n.logger.Info().Str("stream", durableStreamName).Msg("Creating durable events stream")
+		_, err = n.jetStream.AddStream(&nats.StreamConfig{
+			Name:     durableStreamName,
+			Subjects: []string{topic},
+			Storage:  nats.FileStorage,
+			MaxAge:   durableStreamAge,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create stream %s: %w", durableStreamName, err)
+		}
+
+	case err != nil:
+		return fmt.Errorf("failed to get stream %s: %w", durableStreamName, err)
+
+	case !slices.Contains(info.Config.Subjects, topic):
+		config := info.Config
+		config.Subjects = append(config.Subjects, topic)
+		if _, err := n.jetStream.UpdateStream(&config); err != nil {
+			return fmt.Errorf("failed to add %s to stream %s: %w", topic, durableStreamName, err)
+		}
+	}
+
+	n.durableTopics[topic] = true
+	return nil
+}
+
+// ensureDeadLetterStream creates the stream holding dead-lettered messages
+func (n *NATSEventBus) ensureDeadLetterStream() error {
+	_, err := n.jetStream.StreamInfo(deadLetterStreamName)
+	if err == nil {
+		return nil
+	}
+	if !errors.Is(err, nats.ErrStreamNotFound) {
+		return fmt.Errorf("failed to get stream %s: %w", deadLetterStreamName, err)
+	}
+
+	n.logger.Info().Str("stream", deadLetterStreamName).Msg("Creating dead letter stream")
+	_, err = n.jetStream.AddStream(&nats.StreamConfig{
+		Name:     deadLetterStreamName,
+		Subjects: []string{deadLetterPrefix + ">"},
+		Storage:  nats.FileStorage,
+		MaxAge:   deadLetterStreamAge,
+	})
+	if err != nil && !errors.Is(err, nats.ErrStreamNameAlreadyInUse) {
+		return fmt.Errorf("failed to create stream %s: %w", deadLetterStreamName, err)
+	}
+
+	return nil
+}
+
+// consume fetches batches from a pull consumer until the bus is closed
+func (n *NATSEventBus) consume(sub *nats.Subscription, topic, durable string, batch int, handler DurableHandler) {
+	for {
+		select {
+		case <-n.done:
+			return
+		default:
+		}
+
+		msgs, err := sub.Fetch(batch, nats.MaxWait(durableFetchWait))
+		if err != nil {
+			if errors.Is(err, nats.ErrTimeout) || errors.Is(err, context.DeadlineExceeded) {
+				continue
+			}
+			if errors.Is(err, nats.ErrConnectionClosed) || errors.Is(err, nats.ErrBadSubscription) {
+				return
+			}
+
+			n.logger.Error().Err(err).
+				Str("topic", topic).
+				Str("durable", durable).
+				Msg("Failed to fetch messages")
+
+			select {
+			case <-n.done:
+				return
+			case <-time.After(durableFetchWait):
+			}
+			continue
+		}
+
+		for _, msg := range msgs {
+			n.handleDurable(msg, topic, durable, handler)
+		}
+	}
+}
+
+// handleDurable runs the handler for one message and acks, naks with backoff
+// or dead-letters it depending on the outcome
+func (n *NATSEventBus) handleDurable(msg *nats.Msg, topic, durable string, handler DurableHandler) {
+	startTime := time.Now()
+
+	var delivered uint64 = 1
+	if meta, err := msg.Metadata(); err == nil {
+		delivered = meta.NumDelivered
+	}
+
+	n.logger.Debug().
+		Str("topic", topic).
+		Str("durable", durable).
+		Uint64("delivery", delivered).
+		Int("data_size", len(msg.Data)).
+		Msg("Received durable message")
+
+	if n.metrics != nil {
+		n.metrics.EventsReceived.WithLabelValues(topic, n.serviceName).Inc()
+	}
+
+	err := handler(msg.Data)
+
+	if n.metrics != nil {
+		processingTime := time.Since(startTime).Seconds()
+		n.metrics.EventProcessTime.WithLabelValues(topic, n.serviceName).Observe(processingTime)
+	}
+
+	if err == nil {
+		if ackErr := msg.Ack(); ackErr != nil {
+			n.logger.Error().Err(ackErr).Str("topic", topic).Msg("Failed to ack message")
+		}
+		return
+	}
+
+	if n.metrics != nil {
+		n.metrics.EventsErrorCount.WithLabelValues(topic, n.serviceName, "handler_error").Inc()
+	}
+
+	if errors.Is(err, ErrNoRetry) || delivered >= durableMaxDeliver {
+		n.deadLetter(msg, topic, durable, delivered, err)
+		return
+	}
+
+	delay := retryDelay(err, delivered)
+	n.logger.Warn().Err(err).
+		Str("topic", topic).
+		Str("durable", durable).
+		Uint64("delivery", delivered).
+		Dur("retry_in", delay).
+		Msg("Handler failed, message will be redelivered")
+
+	if nakErr := msg.NakWithDelay(delay); nakErr != nil {
+		n.logger.Error().Err(nakErr).Str("topic", topic).Msg("Failed to nak message")
+	}
+}
+
+// deadLetter publishes a failed message to dlq.<topic> and stops its redelivery.
+// If the dead letter can't be stored the message is nak'd so it isn't dropped
+// while deliveries remain
+func (n *NATSEventBus) deadLetter(msg *nats.Msg, topic, durable string, delivered uint64, cause error) {
+	dlq := nats.NewMsg(deadLetterPrefix + topic)
+	dlq.Data = msg.Data
+	dlq.Header.Set("Original-Subject", topic)
+	dlq.Header.Set("Durable", durable)
+	dlq.Header.Set("Deliveries", strconv.FormatUint(delivered, 10))
+	dlq.Header.Set("Error", cause.Error())
+
+	if _, err := n.jetStream.PublishMsg(dlq); err != nil {
+		n.logger.Error().Err(err).
+			Str("topic", topic).
+			Str("durable", durable).
+			Msg("Failed to publish message to dead letter stream")
+		msg.NakWithDelay(durableMaxBackoff)
+		return
+	}
+
+	if n.metrics != nil {
+		n.metrics.EventsErrorCount.WithLabelValues(topic, n.serviceName, "dead_lettered").Inc()
+	}
+
+	n.logger.Error().Err(cause).
+		Str("topic", topic).
+		Str("durable", durable).
+		Uint64("deliveries", delivered).
+		Msg("Message dead-lettered")
+
+	if err := msg.Term(); err != nil {
+		n.logger.Error().Err(err).Str("topic", topic).Msg("Failed to terminate message")
+	}
+}
+
+// durableBackoff doubles the redelivery delay with each attempt, up to durableMaxBackoff
+func durableBackoff(delivered uint64) time.Duration {
+	delay := durableBaseBackoff
+	for i := uint64(1); i < delivered; i++ {
+		delay *= 2
+		if delay >= durableMaxBackoff {
+			return durableMaxBackoff
+		}
+	}
+	return delay
+}
@@ -0,0 +1,122 @@
+// internal/events/envelope.go
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Metadata is the envelope information carried by every event
+type Metadata struct {
+	ID            string    `json:"id"`
+	Topic         string    `json:"topic"`
+	SchemaVersion int       `json:"schema_version"`
+	Source        string    `json:"source"`                   // Service that published the event
+	CorrelationID string    `json:"correlation_id,omitempty"` // ID of the event that started the chain
+	CausationID   string    `json:"causation_id,omitempty"`   // ID of the event this one was published in response to
+	Timestamp     time.Time `json:"timestamp"`
+}
+
+// Event is a message on the event bus with its payload still encoded
+type Event struct {
+	Metadata
+	Payload json.RawMessage `json:"payload"`
+}
+
+// TypedEvent is an event whose payload has been decoded and validated against
+// the payload registered for its topic
+type TypedEvent[T any] struct {
+	Metadata
+	Payload T `json:"payload"`
+}
+
+// NewEvent wraps a payload in an envelope at the topic's current schema version.
+// The event starts its own correlation chain unless CausedBy is applied
+func NewEvent(topic string, payload interface{}) (Event, error) {
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return Event{}, fmt.Errorf("failed to marshal %s payload: %w", topic, err)
+	}
+
+	return Event{
+		Metadata: Metadata{
+			ID:            uuid.New().String(),
+			Topic:         topic,
+			SchemaVersion: SchemaVersion(topic),
+			Timestamp:     time.Now(),
+		},
+		Payload: data,
+	}, nil
+}
+
+// CausedBy links the event to the event it was published in response to
+func (e Event) CausedBy(parent Metadata) Event {
+	e.CausationID = parent.ID
+	e.CorrelationID = parent.CorrelationID
+	if e.CorrelationID == "" {
+		e.CorrelationID = parent.ID
+	}
+	return e
+}
+
+// Validate checks the payload against the payload registered for the topic,
+// for events that are stored before they are published
+func (e Event) Validate() error {
+	version := e.SchemaVersion
+	if version == 0 {
+		version = SchemaVersion(e.Topic)
+	}
+	_, err := validatePayload(e.Topic, version, e.Payload, true)
+	return err
+}
+
+// prepare fills in envelope defaults and validates the payload before publishing
+func (e *Event) prepare(source string) error {
+	if e.ID == "" {
+		e.ID = uuid.New().String()
+	}
+	if e.Timestamp.IsZero() {
+		e.Timestamp = time.Now()
+	}
+	if e.SchemaVersion == 0 {
+		e.SchemaVersion = SchemaVersion(e.Topic)
+	}
+	if e.Source == "" {
+		e.Source = source
+	}
+	if e.CorrelationID == "" {
+		e.CorrelationID = e.ID
+	}
+
+	_, err := validatePayload(e.Topic, e.SchemaVersion, e.Payload, true)
+	return err
+}
+
+// Decode parses an event and its payload, rejecting topics without a
+// registered payload, unsupported schema versions and malformed payloads
+func Decode[T any](data []byte) (TypedEvent[T], error) {
+	var event Event
+	if err := json.Unmarshal(data, &event); err != nil {
+		return TypedEvent[T]{}, fmt.Errorf("%w: %v", ErrInvalidPayload, err)
+	}
+
+	// Events published before the envelope was versioned have no schema_version
+	if event.SchemaVersion == 0 {
+		event.SchemaVersion = 1
+	}
+
+	payload, err := validatePayload(event.Topic, event.SchemaVersion, event.Payload, false)
+	if err != nil {
+		return TypedEvent[T]{}, err
+	}
+
+	typed, ok := payload.(*T)
+	if !ok {
+		return TypedEvent[T]{}, fmt.Errorf("%w: %s carries %T, not %T", ErrInvalidPayload, event.Topic, payload, typed)
+	}
+
+	return TypedEvent[T]{Metadata: event.Metadata, Payload: *typed}, nil
+}
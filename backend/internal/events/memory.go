@@ -0,0 +1,440 @@
+// internal/events/memory.go
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/metrics"
+	"github.com/rs/zerolog"
+)
+
+// memoryQueueSize is the number of undelivered messages an async subscription buffers
+const memoryQueueSize = 1024
+
+// ErrBusClosed is returned when publishing or subscribing on a closed bus
+var ErrBusClosed = errors.New("event bus is closed")
+
+// MemoryEventBus implements EventBus in process, for tests and running the
+// backend as a single binary without NATS. Events use the same envelope and
+// subject matching as NATS; nothing survives a restart
+type MemoryEventBus struct {
+	logger      zerolog.Logger
+	metrics     *metrics.EventMetrics
+	serviceName string
+	async       bool
+
+	mu          sync.RWMutex
+	subs        []*memorySubscription
+	durableNext map[string]int // Round-robin position per durable consumer
+	closed      bool
+	done        chan struct{} // Closed by Close to cut short ordered retries
+	wg          sync.WaitGroup
+}
+
+// memorySubscription is a handler registered on a MemoryEventBus
+type memorySubscription struct {
+	bus            *MemoryEventBus
+	pattern        string
+	durable        string
+	ordered        bool // Retries block the messages behind them
+	handler        func([]byte)
+	durableHandler DurableHandler
+	queue          chan memoryMessage // Async dispatch only
+}
+
+// memoryMessage is a message waiting for an async subscription
+type memoryMessage struct {
+	subject   string
+	data      []byte
+	delivered uint64
+}
+
+// NewMemoryEventBus creates an in-process event bus. With async set, each
+// subscription handles its messages in order on its own goroutine; otherwise
+// handlers run inline before Publish returns, which keeps tests deterministic
+func NewMemoryEventBus(logger *zerolog.Logger, metrics *metrics.EventMetrics, serviceName string, async bool) *MemoryEventBus {
+	subLogger := logger.With().Str("component", "memory_event_bus").Logger()
+	subLogger.Info().Bool("async", async).Msg("Using in-memory event bus")
+
+	return &MemoryEventBus{
+		logger:      subLogger,
+		metrics:     metrics,
+		serviceName: serviceName,
+		async:       async,
+		durableNext: make(map[string]int),
+		done:        make(chan struct{}),
+	}
+}
+
+// Publish sends an event to the specified topic
+func (m *MemoryEventBus) Publish(topic string, payload interface{}) error {
+	event, err := NewEvent(topic, payload)
+	if err != nil {
+		m.reportRejected(topic, err)
+		return err
+	}
+	return m.PublishEvent(event)
+}
+
+// PublishEvent validates a prepared event and sends it to its topic
+func (m *MemoryEventBus) PublishEvent(event Event) error {
+	if err := event.prepare(m.serviceName); err != nil {
+		m.reportRejected(event.Topic, err)
+		return err
+	}
+	return m.publish(event.Topic, event)
+}
+
+// PublishPersistent sends an event to events.<topic>, matching the subject the
+// NATS bus stores persistent events under
+func (m *MemoryEventBus) PublishPersistent(topic string, payload interface{}) error {
+	event, err := NewEvent(topic, payload)
+	if err == nil {
+		err = event.prepare(m.serviceName)
+	}
+	if err != nil {
+		m.reportRejected(topic, err)
+		return err
+	}
+	return m.publish("events."+topic, event)
+}
+
+// reportRejected logs and counts an event that failed envelope or payload validation
+func (m *MemoryEventBus) reportRejected(topic string, err error) {
+	m.logger.Error().Err(err).Str("topic", topic).Msg("Rejected event")
+	if m.metrics != nil {
+		m.metrics.EventsErrorCount.WithLabelValues(topic, m.serviceName, rejectionType(err)).Inc()
+	}
+}
+
+// publish marshals the event like the NATS bus does and dispatches it
+func (m *MemoryEventBus) publish(subject string, event Event) error {
+	data, err := json.Marshal(event)
+	if err != nil {
+		if m.metrics != nil {
+			m.metrics.EventsErrorCount.WithLabelValues(event.Topic, m.serviceName, "marshal_error").Inc()
+		}
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	m.logger.Debug().
+		Str("topic", subject).
+		Str("event_id", event.ID).
+		Int("data_size", len(data)).
+		Msg("Publishing event")
+
+	if err := m.dispatch(subject, data); err != nil {
+		if m.metrics != nil {
+			m.metrics.EventsErrorCount.WithLabelValues(event.Topic, m.serviceName, "publish_error").Inc()
+		}
+		return err
+	}
+
+	if m.metrics != nil {
+		m.metrics.EventsPublished.WithLabelValues(event.Topic, m.serviceName).Inc()
+	}
+
+	return nil
+}
+
+// dispatch hands a message to every matching subscription, and to one member
+// of each matching durable consumer
+func (m *MemoryEventBus) dispatch(subject string, data []byte) error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return ErrBusClosed
+	}
+
+	// Durable consumers sharing a name split the messages between them
+	var picked []*memorySubscription
+	var durables []string
+	groups := make(map[string][]*memorySubscription)
+	for _, sub := range m.subs {
+		if !subjectMatches(sub.pattern, subject) {
+			continue
+		}
+		if sub.durable == "" {
+			picked = append(picked, sub)
+			continue
+		}
+		if groups[sub.durable] == nil {
+			durables = append(durables, sub.durable)
+		}
+		groups[sub.durable] = append(groups[sub.durable], sub)
+	}
+	for _, durable := range durables {
+		members := groups[durable]
+		if members[0].ordered {
+			picked = append(picked, members[0])
+			continue
+		}
+		next := m.durableNext[durable] % len(members)
+		m.durableNext[durable] = next + 1
+		picked = append(picked, members[next])
+	}
+
+	if m.async {
+		// Sends happen under the lock so Close can't close a queue mid-send
+		defer m.mu.Unlock()
+		for _, sub := range picked {
+			sub.queue <- memoryMessage{subject: subject, data: data, delivered: 1}
+		}
+		return nil
+	}
+
+	m.mu.Unlock()
+	for _, sub := range picked {
+		sub.deliver(memoryMessage{subject: subject, data: data, delivered: 1})
+	}
+	return nil
+}
+
+// Subscribe registers a handler for events on the specified topic
+func (m *MemoryEventBus) Subscribe(topic string, handler func([]byte)) (Subscription, error) {
+	return m.subscribe(&memorySubscription{
+		bus:     m,
+		pattern: topic,
+		handler: handler,
+	})
+}
+
+// SubscribeDurable registers a handler that is retried with backoff until it
+// succeeds, then dead-lettered to dlq.<topic>. Redeliveries are immediate when
+// dispatch is synchronous
+func (m *MemoryEventBus) SubscribeDurable(topic, durable string, handler DurableHandler) (Subscription, error) {
+	return m.subscribe(&memorySubscription{
+		bus:            m,
+		pattern:        topic,
+		durable:        durable,
+		durableHandler: handler,
+	})
+}
+
+// SubscribeOrdered registers a durable handler whose retries wait in place, so
+// later messages aren't handled until the failing one succeeds or is dead-lettered.
+// Members of an ordered durable consumer don't split messages; the first gets them all
+func (m *MemoryEventBus) SubscribeOrdered(topic, durable string, handler DurableHandler) (Subscription, error) {
+	return m.subscribe(&memorySubscription{
+		bus:            m,
+		pattern:        topic,
+		durable:        durable,
+		ordered:        true,
+		durableHandler: handler,
+	})
+}
+
+// subscribe registers a subscription and starts its goroutine in async mode
+func (m *MemoryEventBus) subscribe(sub *memorySubscription) (Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.closed {
+		return nil, ErrBusClosed
+	}
+
+	m.logger.Debug().
+		Str("topic", sub.pattern).
+		Str("durable", sub.durable).
+		Msg("Subscribing to topic")
+
+	if m.async {
+		sub.queue = make(chan memoryMessage, memoryQueueSize)
+		m.wg.Add(1)
+		go func() {
+			defer m.wg.Done()
+			for msg := range sub.queue {
+				sub.deliver(msg)
+			}
+		}()
+	}
+
+	m.subs = append(m.subs, sub)
+
+	if m.metrics != nil {
+		m.metrics.ActiveSubscribers.WithLabelValues(sub.pattern).Inc()
+	}
+
+	return sub, nil
+}
+
+// Unsubscribe removes the subscription. Messages already queued are still handled
+func (s *memorySubscription) Unsubscribe() error {
+	m := s.bus
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for i, sub := range m.subs {
+		if sub != s {
+			continue
+		}
+		m.subs = append(m.subs[:i], m.subs[i+1:]...)
+		if s.queue != nil && !m.closed {
+			close(s.queue)
+		}
+		if m.metrics != nil {
+			m.metrics.ActiveSubscribers.WithLabelValues(s.pattern).Dec()
+		}
+		return nil
+	}
+
+	return nil
+}
+
+// deliver runs the subscription's handler for one message
+func (s *memorySubscription) deliver(msg memoryMessage) {
+	m := s.bus
+	startTime := time.Now()
+
+	if m.metrics != nil {
+		m.metrics.EventsReceived.WithLabelValues(msg.subject, m.serviceName).Inc()
+	}
+	defer func() {
+		if m.metrics != nil {
+			m.metrics.EventProcessTime.WithLabelValues(msg.subject, m.serviceName).Observe(time.Since(startTime).Seconds())
+		}
+	}()
+
+	if s.durableHandler == nil {
+		s.handler(msg.data)
+		return
+	}
+
+	for {
+		err := s.durableHandler(msg.data)
+		if err == nil {
+			return
+		}
+
+		if m.metrics != nil {
+			m.metrics.EventsErrorCount.WithLabelValues(msg.subject, m.serviceName, "handler_error").Inc()
+		}
+
+		if errors.Is(err, ErrNoRetry) || msg.delivered >= durableMaxDeliver {
+			s.deadLetter(msg, err)
+			return
+		}
+
+		if !m.async {
+			msg.delivered++
+			continue
+		}
+
+		delay := retryDelay(err, msg.delivered)
+		if s.ordered {
+			// Hold up the queue until the message succeeds or is dead-lettered
+			m.logger.Warn().Err(err).
+				Str("topic", msg.subject).
+				Str("durable", s.durable).
+				Uint64("delivery", msg.delivered).
+				Dur("retry_in", delay).
+				Msg("Handler failed, ordered message will be retried")
+
+			select {
+			case <-m.done:
+				return
+			case <-time.After(delay):
+			}
+			msg.delivered++
+			continue
+		}
+
+		m.logger.Warn().Err(err).
+			Str("topic", msg.subject).
+			Str("durable", s.durable).
+			Uint64("delivery", msg.delivered).
+			Dur("retry_in", delay).
+			Msg("Handler failed, message will be redelivered")
+
+		retry := msg
+		retry.delivered++
+		time.AfterFunc(delay, func() {
+			m.mu.RLock()
+			defer m.mu.RUnlock()
+			if m.closed || !s.subscribed() {
+				return
+			}
+			s.queue <- retry
+		})
+		return
+	}
+}
+
+// subscribed reports whether the subscription is still registered; the caller holds the bus lock
+func (s *memorySubscription) subscribed() bool {
+	for _, sub := range s.bus.subs {
+		if sub == s {
+			return true
+		}
+	}
+	return false
+}
+
+// deadLetter republishes a failed message to dlq.<subject>
+func (s *memorySubscription) deadLetter(msg memoryMessage, cause error) {
+	m := s.bus
+
+	if m.metrics != nil {
+		m.metrics.EventsErrorCount.WithLabelValues(msg.subject, m.serviceName, "dead_lettered").Inc()
+	}
+
+	m.logger.Error().Err(cause).
+		Str("topic", msg.subject).
+		Str("durable", s.durable).
+		Uint64("deliveries", msg.delivered).
+		Msg("Message dead-lettered")
+
+	if err := m.dispatch(deadLetterPrefix+msg.subject, msg.data); err != nil && !errors.Is(err, ErrBusClosed) {
+		m.logger.Error().Err(err).Str("topic", msg.subject).Msg("Failed to dispatch dead letter")
+	}
+}
+
+// Close stops dispatch and waits for async subscriptions to drain their queues
+func (m *MemoryEventBus) Close() {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return
+	}
+	m.closed = true
+	close(m.done)
+
+	for _, sub := range m.subs {
+		if sub.queue != nil {
+			close(sub.queue)
+		}
+		if m.metrics != nil {
+			m.metrics.ActiveSubscribers.WithLabelValues(sub.pattern).Dec()
+		}
+	}
+	m.subs = nil
+	m.mu.Unlock()
+
+	m.wg.Wait()
+}
+
+// subjectMatches reports whether a subject matches a NATS subscription pattern:
+// * matches exactly one token and a trailing > matches one or more
+func subjectMatches(pattern, subject string) bool {
+	patternTokens := strings.Split(pattern, ".")
+	subjectTokens := strings.Split(subject, ".")
+
+	for i, token := range patternTokens {
+		if token == ">" {
+			return len(subjectTokens) > i
+		}
+		if i >= len(subjectTokens) {
+			return false
+		}
+		if token != "*" && token != subjectTokens[i] {
+			return false
+		}
+	}
+
+	return len(patternTokens) == len(subjectTokens)
+}
@@ -2,6 +2,7 @@
 package events
 
 import (
+	"errors"
 	"time"
 )
 
@@ -31,6 +32,13 @@ type ProductCreatedPayload struct {
 	CreatedAt time.Time `json:"created_at"`
 }
 
+// Validate checks the fields consumers rely on
+func (p *ProductCreatedPayload) Validate() error {
+	if p.ProductID == "" {
+		return errors.New("product_id is required")
+	}
+	return nil
+}
 
 // ProductUpdatedPayload represents the data in a product.updated event
 type ProductUpdatedPayload struct {
@@ -61,14 +69,32 @@ type ProductUpdatedPayload struct {
 	ShouldCreateVariants bool      `json:"should_create_variants"` // Whether this update should trigger variant creation
 }
 
-// ProductStockUpdatedPayload represents the data in a product.stock_updated event
+// Validate checks the fields consumers rely on
+func (p *ProductUpdatedPayload) Validate() error {
+	if p.ProductID == "" {
+		return errors.New("product_id is required")
+	}
+	return nil
+}
+
+// ProductDeletedPayload represents the data in a product.deleted event
+type ProductDeletedPayload struct {
+	ProductID string    `json:"product_id"`
+	Name      string    `json:"name"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// ProductStockUpdatedPayload represents the data in a product.stock_updated
+// or product.low_stock event
 type ProductStockUpdatedPayload struct {
-	ProductID     string    `json:"product_id"`
-	Name          string    `json:"name"`
-	OldStockLevel int       `json:"old_stock_level"`
-	NewStockLevel int       `json:"new_stock_level"`
-	IsLowStock    bool      `json:"is_low_stock"`
-	UpdatedAt     time.Time `json:"updated_at"`
+	ProductID        string    `json:"product_id"`
+	VariantID        string    `json:"variant_id,omitempty"` // Empty when the product's own stock changed
+	Name             string    `json:"name"`
+	OldStockLevel    int       `json:"old_stock_level"`
+	NewStockLevel    int       `json:"new_stock_level"`
+	ReorderThreshold int       `json:"reorder_threshold"`
+	IsLowStock       bool      `json:"is_low_stock"`
+	UpdatedAt        time.Time `json:"updated_at"`
 }
 
 // VariantQueuedPayload represents the data needed to create a variant
@@ -92,6 +118,20 @@ type VariantQueuedPayload struct {
 	QueuedAt time.Time `json:"queued_at"`
 }
 
+// Validate checks the fields needed to create the variant
+func (p *VariantQueuedPayload) Validate() error {
+	if p.ProductID == "" {
+		return errors.New("product_id is required")
+	}
+	if p.DefaultPrice <= 0 {
+		return errors.New("default_price must be positive")
+	}
+	if p.Currency == "" {
+		return errors.New("currency is required")
+	}
+	return nil
+}
+
 // VariantCreatedPayload represents the data in a variant.created event
 type VariantCreatedPayload struct {
 	// IDs
@@ -138,7 +178,240 @@ type VariantUpdatedPayload struct {
 	Interval      string `json:"interval,omitempty"`       // week, month, year (for recurring)
 	IntervalCount int    `json:"interval_count,omitempty"` // Number of intervals (for recurring)
 
+	// Variant details
+	Active       bool              `json:"active"`
+	OptionValues map[string]string `json:"option_values,omitempty"`
+
 	// Metadata
 	UpdatedAt    time.Time `json:"updated_at"`
 	UpdateSource string    `json:"update_source"` // e.g., "stripe_webhook", "api", "admin"
 }
+
+// VariantDeletedPayload represents the data in a variant.deleted event
+type VariantDeletedPayload struct {
+	VariantID       string    `json:"variant_id"`
+	ProductID       string    `json:"product_id"`
+	ProductName     string    `json:"product_name,omitempty"`
+	StripeProductID string    `json:"stripe_product_id"`
+	StripePriceID   string    `json:"stripe_price_id,omitempty"`
+	DeleteSource    string    `json:"delete_source,omitempty"` // e.g., "stripe_webhook" when only deactivated
+	DeletedAt       time.Time `json:"deleted_at"`
+}
+
+// VariantPriceAssignedPayload represents the data in a variants.price_assigned event
+type VariantPriceAssignedPayload struct {
+	VariantID     string    `json:"variant_id"`
+	ProductID     string    `json:"product_id"`
+	NewPriceID    string    `json:"new_price_id"`
+	OldPriceID    string    `json:"old_price_id"`
+	StripePriceID string    `json:"stripe_price_id"`
+	AssignedAt    time.Time `json:"assigned_at"`
+}
+
+// PriceCreatedPayload represents the data in a prices.created event
+type PriceCreatedPayload struct {
+	PriceID       string    `json:"price_id"`
+	ProductID     string    `json:"product_id"`
+	Name          string    `json:"name"`
+	Amount        int64     `json:"amount"`
+	Currency      string    `json:"currency"`
+	Type          string    `json:"type"`
+	Interval      string    `json:"interval"`
+	IntervalCount int       `json:"interval_count"`
+	Active        bool      `json:"active"`
+	StripeID      string    `json:"stripe_id"`
+	CreatedAt     time.Time `json:"created_at"`
+}
+
+// PriceUpdatedPayload represents the data in a prices.updated event
+type PriceUpdatedPayload struct {
+	PriceID        string    `json:"price_id"`
+	ProductID      string    `json:"product_id"`
+	Name           string    `json:"name"`
+	Amount         int64     `json:"amount"`
+	OriginalAmount int64     `json:"original_amount"`
+	Currency       string    `json:"currency"`
+	Type           string    `json:"type"`
+	Interval       string    `json:"interval"`
+	IntervalCount  int       `json:"interval_count"`
+	Active         bool      `json:"active"`
+	OriginalActive bool      `json:"original_active"`
+	StripeID       string    `json:"stripe_id"`
+	UpdatedAt      time.Time `json:"updated_at"`
+}
+
+// PriceDeletedPayload represents the data in a prices.deleted event
+type PriceDeletedPayload struct {
+	PriceID   string    `json:"price_id"`
+	ProductID string    `json:"product_id"`
+	Name      string    `json:"name"`
+	StripeID  string    `json:"stripe_id"`
+	DeletedAt time.Time `json:"deleted_at"`
+}
+
+// CustomerCreatedPayload represents the data in a customer.created event
+type CustomerCreatedPayload struct {
+	CustomerID  string    `json:"customer_id"`
+	StripeID    string    `json:"stripe_id"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"first_name"`
+	LastName    string    `json:"last_name"`
+	PhoneNumber string    `json:"phone_number,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// CustomerUpdatedPayload represents the data in a customer.updated event
+type CustomerUpdatedPayload struct {
+	CustomerID  string    `json:"customer_id"`
+	StripeID    string    `json:"stripe_id"`
+	Email       string    `json:"email"`
+	FirstName   string    `json:"first_name"`
+	LastName    string    `json:"last_name"`
+	PhoneNumber string    `json:"phone_number,omitempty"`
+	Active      bool      `json:"active"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// CustomerDeletedPayload represents the data in a customer.deleted event
+type CustomerDeletedPayload struct {
+	CustomerID string    `json:"customer_id"`
+	StripeID   string    `json:"stripe_id"`
+	Email      string    `json:"email"`
+	HardDelete bool      `json:"hard_delete"` // false when the customer was only deactivated
+	DeletedAt  time.Time `json:"deleted_at"`
+}
+
+// SubscriptionCreatedPayload represents the data in a subscription.created event
+type SubscriptionCreatedPayload struct {
+	SubscriptionID   string    `json:"subscription_id"`
+	CustomerID       string    `json:"customer_id"`
+	ProductID        string    `json:"product_id"`
+	PriceID          string    `json:"price_id"`
+	AddressID        string    `json:"address_id,omitempty"`
+	StripeID         string    `json:"stripe_id"`
+	Quantity         int       `json:"quantity"`
+	Status           string    `json:"status"`
+	NextDeliveryDate time.Time `json:"next_delivery_date"`
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// SubscriptionUpdatedPayload represents the data in a subscription.updated event
+type SubscriptionUpdatedPayload struct {
+	SubscriptionID      string     `json:"subscription_id"`
+	CustomerID          string     `json:"customer_id"`
+	StripeID            string     `json:"stripe_id"`
+	Status              string     `json:"status"`
+	NextDeliveryDate    time.Time  `json:"next_delivery_date"`
+	Change              string     `json:"change"`                          // e.g., "delivery_skipped"
+	SkippedDeliveryDate *time.Time `json:"skipped_delivery_date,omitempty"` // Set when a delivery was skipped
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// SubscriptionPausedPayload represents the data in a subscription.paused event
+type SubscriptionPausedPayload struct {
+	SubscriptionID string    `json:"subscription_id"`
+	CustomerID     string    `json:"customer_id"`
+	StripeID       string    `json:"stripe_id"`
+	Reason         string    `json:"reason,omitempty"`
+	PausedAt       time.Time `json:"paused_at"`
+}
+
+// SubscriptionResumedPayload represents the data in a subscription.resumed event
+type SubscriptionResumedPayload struct {
+	SubscriptionID   string    `json:"subscription_id"`
+	CustomerID       string    `json:"customer_id"`
+	StripeID         string    `json:"stripe_id"`
+	NextDeliveryDate time.Time `json:"next_delivery_date"`
+	ResumedAt        time.Time `json:"resumed_at"`
+}
+
+// SubscriptionCanceledPayload represents the data in a subscription.canceled event
+type SubscriptionCanceledPayload struct {
+	SubscriptionID    string    `json:"subscription_id"`
+	CustomerID        string    `json:"customer_id"`
+	StripeID          string    `json:"stripe_id"`
+	CancelAtPeriodEnd bool      `json:"cancel_at_period_end"` // true when the subscription runs until the period ends
+	Reason            string    `json:"reason,omitempty"`
+	CanceledAt        time.Time `json:"canceled_at"`
+}
+
+// SubscriptionRenewedPayload represents the data in a subscription.renewed event
+type SubscriptionRenewedPayload struct {
+	SubscriptionID   string    `json:"subscription_id"`
+	CustomerID       string    `json:"customer_id"`
+	StripeID         string    `json:"stripe_id"`
+	StripeInvoiceID  string    `json:"stripe_invoice_id"`
+	OrderID          string    `json:"order_id,omitempty"` // Empty when no line could be matched to a variant
+	AmountPaid       int64     `json:"amount_paid"`
+	Currency         string    `json:"currency"`
+	PeriodStart      time.Time `json:"period_start"`
+	PeriodEnd        time.Time `json:"period_end"`
+	NextDeliveryDate time.Time `json:"next_delivery_date"`
+	RenewedAt        time.Time `json:"renewed_at"`
+}
+
+// DunningStepPayload represents the data in every dunning.* event
+type DunningStepPayload struct {
+	DunningCaseID    string     `json:"dunning_case_id"`
+	SubscriptionID   string     `json:"subscription_id"`
+	CustomerID       string     `json:"customer_id"`
+	StripeInvoiceID  string     `json:"stripe_invoice_id"`
+	Step             string     `json:"step"`
+	Status           string     `json:"status"`
+	AttemptCount     int        `json:"attempt_count"`
+	RemindersSent    int        `json:"reminders_sent"`
+	AmountDue        int64      `json:"amount_due"`
+	Currency         string     `json:"currency"`
+	HostedInvoiceURL string     `json:"hosted_invoice_url,omitempty"` // Where the customer can update payment
+	HeldDeliveryDate *time.Time `json:"held_delivery_date,omitempty"`
+	NextActionAt     *time.Time `json:"next_action_at,omitempty"`
+	Detail           string     `json:"detail,omitempty"`
+	OccurredAt       time.Time  `json:"occurred_at"`
+}
+
+// OrderItemPayload represents a line item in order events
+type OrderItemPayload struct {
+	VariantID   string `json:"variant_id"`
+	ProductID   string `json:"product_id"`
+	ProductName string `json:"product_name"`
+	Quantity    int    `json:"quantity"`
+	UnitAmount  int64  `json:"unit_amount"`
+}
+
+// OrderCreatedPayload represents the data in an orders.created event
+type OrderCreatedPayload struct {
+	OrderID        string             `json:"order_id"`
+	CustomerID     string             `json:"customer_id"`
+	SubscriptionID string             `json:"subscription_id,omitempty"`
+	Status         string             `json:"status"`
+	Currency       string             `json:"currency"`
+	Total          int64              `json:"total"`
+	Items          []OrderItemPayload `json:"items"`
+	CreatedAt      time.Time          `json:"created_at"`
+}
+
+// OrderStatusUpdatedPayload represents the data in an orders.status_updated event
+type OrderStatusUpdatedPayload struct {
+	OrderID    string    `json:"order_id"`
+	CustomerID string    `json:"customer_id"`
+	FromStatus string    `json:"from_status"`
+	ToStatus   string    `json:"to_status"`
+	Note       string    `json:"note,omitempty"`
+	UpdatedAt  time.Time `json:"updated_at"`
+}
+
+// OrderShippedPayload represents the data in an orders.shipped event
+type OrderShippedPayload struct {
+	OrderID        string    `json:"order_id"`
+	CustomerID     string    `json:"customer_id"`
+	Carrier        string    `json:"carrier,omitempty"`
+	TrackingNumber string    `json:"tracking_number"`
+	ShippedAt      time.Time `json:"shipped_at"`
+}
+
+// OrderDeliveredPayload represents the data in an orders.delivered event
+type OrderDeliveredPayload struct {
+	OrderID     string    `json:"order_id"`
+	CustomerID  string    `json:"customer_id"`
+	DeliveredAt time.Time `json:"delivered_at"`
+}
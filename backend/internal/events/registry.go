@@ -0,0 +1,190 @@
+// internal/events/registry.go
+package events
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+var (
+	// ErrUnknownTopic is returned for events on a topic with no registered payload
+	ErrUnknownTopic = errors.New("no payload registered for topic")
+
+	// ErrUnsupportedVersion is returned for events newer than the registered schema
+	ErrUnsupportedVersion = errors.New("unsupported schema version")
+
+	// ErrInvalidPayload is returned when a payload doesn't decode or fails validation
+	ErrInvalidPayload = errors.New("invalid event payload")
+)
+
+// Validator is implemented by payloads with rules beyond what decoding checks
+type Validator interface {
+	Validate() error
+}
+
+// payloadSchema is the registered payload type and current version of a topic
+type payloadSchema struct {
+	version     int
+	payloadType reflect.Type
+}
+
+// schemas maps each topic to its payload. Bump a topic's version when its
+// payload changes incompatibly; consumers reject versions they don't know
+var schemas = map[string]payloadSchema{}
+
+// families maps topic prefixes to the payload shared by every topic under them,
+// for topics built at runtime such as webhook partitions
+var families = map[string]payloadSchema{}
+
+func init() {
+	register[ProductCreatedPayload](TopicProductCreated, 1)
+	register[ProductUpdatedPayload](TopicProductUpdated, 1)
+	register[ProductDeletedPayload](TopicProductDeleted, 1)
+	register[ProductStockUpdatedPayload](TopicProductStockUpdated, 1)
+	register[ProductStockUpdatedPayload](TopicProductLowStock, 1)
+
+	register[VariantQueuedPayload](TopicVariantQueued, 1)
+	register[VariantCreatedPayload](TopicVariantCreated, 1)
+	register[VariantUpdatedPayload](TopicVariantUpdated, 1)
+	register[VariantDeletedPayload](TopicVariantDeleted, 1)
+	register[VariantPriceAssignedPayload](TopicVariantPriceAssigned, 1)
+
+	register[PriceCreatedPayload](TopicPriceCreated, 1)
+	register[PriceUpdatedPayload](TopicPriceUpdated, 1)
+	register[PriceDeletedPayload](TopicPriceDeleted, 1)
+
+	register[CustomerCreatedPayload](TopicCustomerCreated, 1)
+	register[CustomerUpdatedPayload](TopicCustomerUpdated, 1)
+	register[CustomerDeletedPayload](TopicCustomerDeleted, 1)
+
+	register[SubscriptionCreatedPayload](TopicSubscriptionCreated, 1)
+	register[SubscriptionUpdatedPayload](TopicSubscriptionUpdated, 1)
+	register[SubscriptionCanceledPayload](TopicSubscriptionCanceled, 1)
+	register[SubscriptionPausedPayload](TopicSubscriptionPaused, 1)
+	register[SubscriptionResumedPayload](TopicSubscriptionResumed, 1)
+	register[SubscriptionRenewedPayload](TopicSubscriptionRenewed, 1)
+
+	for _, topic := range []string{
+		TopicDunningStarted, TopicDunningPaymentFailed, TopicDunningDeliveryHeld, TopicDunningReminder,
+		TopicDunningRecovered, TopicDunningDeliveryReleased, TopicDunningExhausted,
+	} {
+		register[DunningStepPayload](topic, 1)
+	}
+
+	register[OrderCreatedPayload](TopicOrderCreated, 1)
+	register[OrderStatusUpdatedPayload](TopicOrderStatusUpdated, 1)
+	register[OrderShippedPayload](TopicOrderShipped, 1)
+	register[OrderDeliveredPayload](TopicOrderDelivered, 1)
+
+	register[StripeProductEventPayload](TopicStripeProductCreated, 1)
+	register[StripeProductEventPayload](TopicStripeProductUpdated, 1)
+	register[StripeProductEventPayload](TopicStripeProductDeleted, 1)
+	register[StripePriceEventPayload](TopicStripePriceCreated, 1)
+	register[StripePriceEventPayload](TopicStripePriceUpdated, 1)
+	register[StripePriceEventPayload](TopicStripePriceDeleted, 1)
+	register[StripeCustomerEventPayload](TopicStripeCustomerCreated, 1)
+	register[StripeCustomerEventPayload](TopicStripeCustomerUpdated, 1)
+	register[StripeCustomerEventPayload](TopicStripeCustomerDeleted, 1)
+	register[StripeSubscriptionEventPayload](TopicStripeSubscriptionCreated, 1)
+	register[StripeSubscriptionEventPayload](TopicStripeSubscriptionUpdated, 1)
+	register[StripeSubscriptionEventPayload](TopicStripeSubscriptionCanceled, 1)
+	register[StripeCheckoutEventPayload](TopicStripeCheckoutCompleted, 1)
+	register[StripeInvoiceEventPayload](TopicStripeInvoicePaid, 1)
+	register[StripeInvoiceEventPayload](TopicStripeInvoicePaymentFailed, 1)
+
+	registerFamily[StripeWebhookReceivedPayload](TopicStripeWebhookPrefix, 1)
+}
+
+// register records the payload type and schema version of a topic
+func register[T any](topic string, version int) {
+	if _, exists := schemas[topic]; exists {
+		panic(fmt.Sprintf("events: payload already registered for %s", topic))
+	}
+	schemas[topic] = payloadSchema{version: version, payloadType: reflect.TypeFor[T]()}
+}
+
+// registerFamily records the payload type and schema version of every topic under a prefix
+func registerFamily[T any](prefix string, version int) {
+	if _, exists := families[prefix]; exists {
+		panic(fmt.Sprintf("events: payload already registered for %s*", prefix))
+	}
+	families[prefix] = payloadSchema{version: version, payloadType: reflect.TypeFor[T]()}
+}
+
+// schemaFor looks up a topic's payload, falling back to its topic family. A
+// wildcard subscription topic under a family prefix resolves to the family
+func schemaFor(topic string) (payloadSchema, bool) {
+	if schema, ok := schemas[topic]; ok {
+		return schema, true
+	}
+	for prefix, schema := range families {
+		if strings.HasPrefix(topic, prefix) {
+			return schema, true
+		}
+	}
+	return payloadSchema{}, false
+}
+
+// SchemaVersion returns the current schema version of a topic's payload, or 0 if unregistered
+func SchemaVersion(topic string) int {
+	schema, _ := schemaFor(topic)
+	return schema.version
+}
+
+// checkRegistered verifies that a typed subscription matches the topic's registered payload
+func checkRegistered[T any](topic string) error {
+	schema, ok := schemaFor(topic)
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrUnknownTopic, topic)
+	}
+	if want := reflect.TypeFor[T](); schema.payloadType != want {
+		return fmt.Errorf("%s carries %s, not %s", topic, schema.payloadType, want)
+	}
+	return nil
+}
+
+// validatePayload decodes a payload into the topic's registered type and runs
+// its Validate method. Publishing is strict about unknown fields so ad-hoc
+// payloads are caught at the source; consumers tolerate them
+func validatePayload(topic string, version int, payload json.RawMessage, strict bool) (interface{}, error) {
+	schema, ok := schemaFor(topic)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", ErrUnknownTopic, topic)
+	}
+	if version < 1 || version > schema.version {
+		return nil, fmt.Errorf("%w: %s v%d (current v%d)", ErrUnsupportedVersion, topic, version, schema.version)
+	}
+
+	decoded := reflect.New(schema.payloadType).Interface()
+	decoder := json.NewDecoder(bytes.NewReader(payload))
+	if strict {
+		decoder.DisallowUnknownFields()
+	}
+	if err := decoder.Decode(decoded); err != nil {
+		return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPayload, topic, err)
+	}
+
+	if v, ok := decoded.(Validator); ok {
+		if err := v.Validate(); err != nil {
+			return nil, fmt.Errorf("%w: %s: %v", ErrInvalidPayload, topic, err)
+		}
+	}
+
+	return decoded, nil
+}
+
+// rejectionType is the error_type metric label for a rejected event
+func rejectionType(err error) string {
+	switch {
+	case errors.Is(err, ErrUnknownTopic):
+		return "unknown_topic"
+	case errors.Is(err, ErrUnsupportedVersion):
+		return "unsupported_version"
+	default:
+		return "invalid_payload"
+	}
+}
@@ -2,6 +2,7 @@
 package events
 
 import (
+	"errors"
 	"time"
 )
 
@@ -124,5 +125,28 @@ type StripeInvoiceEventPayload struct {
 	Currency      string    `json:"currency"`
 	Paid          bool      `json:"paid"`
 	Attempted     bool      `json:"attempted"`
+	AttemptCount  int64     `json:"attempt_count"`
+	BillingReason string    `json:"billing_reason,omitempty"`
+	HostedInvoiceURL string `json:"hosted_invoice_url,omitempty"`
 	Created       time.Time `json:"created"`
-}
\ No newline at end of file
+}
+
+// StripeWebhookReceivedPayload points a webhook worker at a stored Stripe event.
+// The event itself stays in the webhook event store
+type StripeWebhookReceivedPayload struct {
+	EventID    string    `json:"event_id"`
+	EventType  string    `json:"event_type"`
+	ObjectID   string    `json:"object_id,omitempty"` // Stripe object the event is about
+	ReceivedAt time.Time `json:"received_at"`
+}
+
+// Validate checks that the payload identifies a stored event
+func (p StripeWebhookReceivedPayload) Validate() error {
+	if p.EventID == "" {
+		return errors.New("event_id is required")
+	}
+	if p.EventType == "" {
+		return errors.New("event_type is required")
+	}
+	return nil
+}
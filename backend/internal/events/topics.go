@@ -1,6 +1,8 @@
 // internal/events/topics.go
 package events
 
+import "strconv"
+
 // Product-related topics
 const (
 	TopicProductCreated      = "products.created"
@@ -16,6 +18,15 @@ const (
 	TopicVariantQueued  = "variants.queued" // Event when a variant needs to be created
 	TopicVariantUpdated = "variants.updated"
 	TopicVariantDeleted = "variants.deleted"
+
+	TopicVariantPriceAssigned = "variants.price_assigned"
+)
+
+// Price-related topics
+const (
+	TopicPriceCreated = "prices.created"
+	TopicPriceUpdated = "prices.updated"
+	TopicPriceDeleted = "prices.deleted"
 )
 
 // Customer-related topics
@@ -35,6 +46,17 @@ const (
 	TopicSubscriptionRenewed  = "subscriptions.renewed"
 )
 
+// Dunning topics, one per recorded step of chasing a failed subscription payment
+const (
+	TopicDunningStarted          = "dunning.started"
+	TopicDunningPaymentFailed    = "dunning.payment_failed"
+	TopicDunningDeliveryHeld     = "dunning.delivery_held"
+	TopicDunningReminder         = "dunning.reminder"
+	TopicDunningRecovered        = "dunning.recovered"
+	TopicDunningDeliveryReleased = "dunning.delivery_released"
+	TopicDunningExhausted        = "dunning.exhausted"
+)
+
 // Order-related topics
 const (
 	TopicOrderCreated       = "orders.created"
@@ -65,3 +87,14 @@ const (
 	TopicStripeInvoicePaid = "stripe.invoice.paid"
 	TopicStripeInvoicePaymentFailed = "stripe.invoice.payment_failed"
 )
+
+// Inbound Stripe webhooks are dispatched to stripe.webhooks.<partition>. Events
+// about the same Stripe object share a partition, so they're handled in order
+const (
+	TopicStripeWebhookPrefix = "stripe.webhooks."
+)
+
+// StripeWebhookTopic returns the topic of a webhook partition
+func StripeWebhookTopic(partition int) string {
+	return TopicStripeWebhookPrefix + strconv.Itoa(partition)
+}
@@ -0,0 +1,69 @@
+// internal/events/typed.go
+package events
+
+import (
+	"fmt"
+)
+
+// rejectionReporter is implemented by buses that log and count rejected events
+type rejectionReporter interface {
+	reportRejected(topic string, err error)
+}
+
+// Subscribe registers a handler that receives decoded payloads. Events that
+// don't match the topic's registered payload are dropped and counted
+func Subscribe[T any](bus EventBus, topic string, handler func(event TypedEvent[T])) (Subscription, error) {
+	if err := checkRegistered[T](topic); err != nil {
+		return nil, err
+	}
+
+	return bus.Subscribe(topic, func(data []byte) {
+		event, err := Decode[T](data)
+		if err != nil {
+			reject(bus, topic, err)
+			return
+		}
+		handler(event)
+	})
+}
+
+// SubscribeDurable registers a durable handler that receives decoded payloads.
+// Events that don't match the topic's registered payload are dead-lettered
+// without retrying
+func SubscribeDurable[T any](bus EventBus, topic, durable string, handler func(event TypedEvent[T]) error) (Subscription, error) {
+	if err := checkRegistered[T](topic); err != nil {
+		return nil, err
+	}
+
+	return bus.SubscribeDurable(topic, durable, decodeDurable(bus, topic, handler))
+}
+
+// SubscribeOrdered registers an ordered durable handler that receives decoded
+// payloads. Events that don't match the topic's registered payload are
+// dead-lettered without retrying
+func SubscribeOrdered[T any](bus EventBus, topic, durable string, handler func(event TypedEvent[T]) error) (Subscription, error) {
+	if err := checkRegistered[T](topic); err != nil {
+		return nil, err
+	}
+
+	return bus.SubscribeOrdered(topic, durable, decodeDurable(bus, topic, handler))
+}
+
+// decodeDurable adapts a typed handler to a DurableHandler
+func decodeDurable[T any](bus EventBus, topic string, handler func(event TypedEvent[T]) error) DurableHandler {
+	return func(data []byte) error {
+		event, err := Decode[T](data)
+		if err != nil {
+			reject(bus, topic, err)
+			return fmt.Errorf("%w: %v", ErrNoRetry, err)
+		}
+		return handler(event)
+	}
+}
+
+// reject reports an event that failed decoding to the bus, if it can record it
+func reject(bus EventBus, topic string, err error) {
+	if reporter, ok := bus.(rejectionReporter); ok {
+		reporter.reportRejected(topic, err)
+	}
+}
@@ -0,0 +1,340 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
+	"github.com/google/uuid"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type AddressHandler interface {
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+// addressHandler handles HTTP requests for customer addresses
+type addressHandler struct {
+	logger         zerolog.Logger
+	addressService interfaces.AddressService
+}
+
+// NewAddressHandler creates a new address handler
+func NewAddressHandler(logger *zerolog.Logger, addressService interfaces.AddressService) *addressHandler {
+	sublogger := logger.With().Str("component", "address_handler").Logger()
+	return &addressHandler{
+		logger:         sublogger,
+		addressService: addressService,
+	}
+}
+
+// parseIDs extracts the customer ID and, when present, the address ID from the URL.
+// On failure the 400 response has already been written and ok is false
+func (h *addressHandler) parseIDs(c echo.Context, requestID string, withAddress bool) (customerID, addressID uuid.UUID, ok bool, err error) {
+	customerID, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		h.logger.Warn().
+			Err(parseErr).
+			Str("request_id", requestID).
+			Str("id_param", c.Param("id")).
+			Msg("Invalid customer ID format")
+
+		return customerID, addressID, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid customer ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	if withAddress {
+		addressID, parseErr = uuid.Parse(c.Param("address_id"))
+		if parseErr != nil {
+			h.logger.Warn().
+				Err(parseErr).
+				Str("request_id", requestID).
+				Str("address_id_param", c.Param("address_id")).
+				Msg("Invalid address ID format")
+
+			return customerID, addressID, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid address ID format",
+				Code:    "INVALID_ID_FORMAT",
+			})
+		}
+	}
+
+	return customerID, addressID, true, nil
+}
+
+// errorResponse maps service errors to HTTP responses
+func (h *addressHandler) errorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Customer or address not found",
+			Code:    "ADDRESS_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	case errors.Is(err, postgres.ErrDatabaseConnection):
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Status:  http.StatusServiceUnavailable,
+			Message: "Service temporarily unavailable, please try again later",
+			Code:    "SERVICE_UNAVAILABLE",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action + " address",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+}
+
+// List handles GET /api/v1/customers/:id/addresses
+func (h *addressHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "AddressHandler.List").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling address listing request")
+
+	customerID, _, ok, err := h.parseIDs(c, requestID, false)
+	if !ok {
+		return err
+	}
+
+	addresses, err := h.addressService.ListByCustomer(ctx, customerID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to list addresses")
+		return h.errorResponse(c, err, "list")
+	}
+
+	addressResponses := make([]dto.AddressResponseDTO, 0, len(addresses))
+	for _, address := range addresses {
+		addressResponses = append(addressResponses, dto.AddressResponseDTOFromModel(address))
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"addresses": addressResponses,
+	})
+}
+
+// Create handles POST /api/v1/customers/:id/addresses
+func (h *addressHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "AddressHandler.Create").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling address creation request")
+
+	customerID, _, ok, err := h.parseIDs(c, requestID, false)
+	if !ok {
+		return err
+	}
+
+	var addressDTO dto.AddressCreateDTO
+	if err := c.Bind(&addressDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := addressDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Address validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	address, err := h.addressService.Create(ctx, customerID, &addressDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to create address")
+		return h.errorResponse(c, err, "create")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Address created successfully",
+		"address": dto.AddressResponseDTOFromModel(address),
+	})
+}
+
+// Get handles GET /api/v1/customers/:id/addresses/:address_id
+func (h *addressHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "AddressHandler.Get").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling get address request")
+
+	customerID, addressID, ok, err := h.parseIDs(c, requestID, true)
+	if !ok {
+		return err
+	}
+
+	address, err := h.addressService.GetByID(ctx, customerID, addressID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("address_id", addressID.String()).
+			Msg("Failed to retrieve address")
+		return h.errorResponse(c, err, "retrieve")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"address": dto.AddressResponseDTOFromModel(address),
+	})
+}
+
+// Update handles PUT /api/v1/customers/:id/addresses/:address_id
+func (h *addressHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "AddressHandler.Update").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling address update request")
+
+	customerID, addressID, ok, err := h.parseIDs(c, requestID, true)
+	if !ok {
+		return err
+	}
+
+	var addressUpdateDTO dto.AddressUpdateDTO
+	if err := c.Bind(&addressUpdateDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := addressUpdateDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Address update validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	address, err := h.addressService.Update(ctx, customerID, addressID, &addressUpdateDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("address_id", addressID.String()).
+			Msg("Failed to update address")
+		return h.errorResponse(c, err, "update")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Address updated successfully",
+		"address": dto.AddressResponseDTOFromModel(address),
+	})
+}
+
+// Delete handles DELETE /api/v1/customers/:id/addresses/:address_id
+func (h *addressHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "AddressHandler.Delete").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling address delete request")
+
+	customerID, addressID, ok, err := h.parseIDs(c, requestID, true)
+	if !ok {
+		return err
+	}
+
+	err = h.addressService.Delete(ctx, customerID, addressID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("address_id", addressID.String()).
+			Msg("Failed to delete address")
+		return h.errorResponse(c, err, "delete")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Address deleted successfully",
+	})
+}
@@ -2,8 +2,15 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
+	"strconv"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
 	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
+	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
 )
@@ -11,26 +18,36 @@ import (
 type AdminHandler interface {
 	SyncStripeProductIDs(c echo.Context) error
 	HealthCheck(c echo.Context) error
+	Reconcile(c echo.Context) error
+	ApplyReconciliation(c echo.Context) error
+	SyncStatus(c echo.Context) error
+	SyncHistory(c echo.Context) error
 }
 
 // adminHandler handles administrative operations
 type adminHandler struct {
-	logger       zerolog.Logger
-	priceService interfaces.PriceService
-	productRepo  interfaces.ProductRepository
+	logger                zerolog.Logger
+	priceService          interfaces.PriceService
+	reconciliationService interfaces.ReconciliationService
+	syncStatusService     interfaces.SyncStatusService
+	productRepo           interfaces.ProductRepository
 }
 
 // NewAdminHandler creates a new admin handler
 func NewAdminHandler(
 	logger *zerolog.Logger,
 	priceService interfaces.PriceService,
+	reconciliationService interfaces.ReconciliationService,
+	syncStatusService interfaces.SyncStatusService,
 	productRepo interfaces.ProductRepository,
 ) *adminHandler {
 	sublogger := logger.With().Str("component", "admin_handler").Logger()
 	return &adminHandler{
-		logger:       sublogger,
-		priceService: priceService,
-		productRepo:  productRepo,
+		logger:                sublogger,
+		priceService:          priceService,
+		reconciliationService: reconciliationService,
+		syncStatusService:     syncStatusService,
+		productRepo:           productRepo,
 	}
 }
 
@@ -99,4 +116,191 @@ func (h *adminHandler) HealthCheck(c echo.Context) error {
 		"database":      "connected",
 		"timestamp":     ctx.Value("timestamp"),
 	})
-}
\ No newline at end of file
+}
+
+// Reconcile handles GET /api/v1/admin/reconciliation
+func (h *adminHandler) Reconcile(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "AdminHandler.Reconcile").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling catalog reconciliation request")
+
+	report, err := h.reconciliationService.Reconcile(ctx)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to reconcile catalog")
+
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to reconcile catalog with Stripe",
+			Code:    "RECONCILIATION_FAILED",
+		})
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// ApplyReconciliation handles POST /api/v1/admin/reconciliation/apply
+func (h *adminHandler) ApplyReconciliation(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "AdminHandler.ApplyReconciliation").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling apply catalog reconciliation request")
+
+	var applyDTO dto.ReconciliationApplyDTO
+	if err := c.Bind(&applyDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := applyDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Reconciliation policy validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	report, err := h.reconciliationService.Apply(ctx, applyDTO.Policy)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to apply catalog reconciliation")
+
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to apply catalog reconciliation",
+			Code:    "RECONCILIATION_FAILED",
+		})
+	}
+
+	h.logger.Info().
+		Str("request_id", requestID).
+		Int("fixed", report.Fixed).
+		Int("failed", report.Failed).
+		Msg("Catalog reconciliation applied")
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// SyncStatus handles GET /api/v1/admin/sync/status
+func (h *adminHandler) SyncStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "AdminHandler.SyncStatus").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling sync status request")
+
+	report, err := h.syncStatusService.Status(ctx, c.QueryParam("state"))
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to retrieve sync status")
+		return h.syncErrorResponse(c, err, "retrieve sync status")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// SyncHistory handles GET /api/v1/admin/sync/history/:variant_id
+func (h *adminHandler) SyncHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "AdminHandler.SyncHistory").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("variant_id", c.Param("variant_id")).
+		Msg("Handling sync history request")
+
+	variantID, err := uuid.Parse(c.Param("variant_id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid variant ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit < 1 || limit > 100 {
+		limit = 20
+	}
+
+	report, err := h.syncStatusService.History(ctx, variantID, limit)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", variantID.String()).
+			Msg("Failed to retrieve sync history")
+		return h.syncErrorResponse(c, err, "retrieve sync history")
+	}
+
+	return c.JSON(http.StatusOK, report)
+}
+
+// syncErrorResponse maps sync status errors to HTTP responses
+func (h *adminHandler) syncErrorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Variant not found",
+			Code:    "VARIANT_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action,
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
+	"github.com/google/uuid"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type CartHandler interface {
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	AddItem(c echo.Context) error
+	UpdateItem(c echo.Context) error
+	RemoveItem(c echo.Context) error
+	Merge(c echo.Context) error
+}
+
+// cartHandler handles HTTP requests for shopping carts
+type cartHandler struct {
+	logger      zerolog.Logger
+	cartService interfaces.CartService
+}
+
+// NewCartHandler creates a new cart handler
+func NewCartHandler(logger *zerolog.Logger, cartService interfaces.CartService) *cartHandler {
+	sublogger := logger.With().Str("component", "cart_handler").Logger()
+	return &cartHandler{
+		logger:      sublogger,
+		cartService: cartService,
+	}
+}
+
+// parseID extracts a UUID path parameter.
+// On failure the 400 response has already been written and ok is false
+func (h *cartHandler) parseID(c echo.Context, requestID, param, label string) (id uuid.UUID, ok bool, err error) {
+	id, parseErr := uuid.Parse(c.Param(param))
+	if parseErr != nil {
+		h.logger.Warn().
+			Err(parseErr).
+			Str("request_id", requestID).
+			Str("id_param", c.Param(param)).
+			Msg("Invalid " + label + " ID format")
+
+		return id, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid " + label + " ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	return id, true, nil
+}
+
+// invalidFormat writes the response for a request body that could not be bound
+func (h *cartHandler) invalidFormat(c echo.Context, err error, requestID string) error {
+	h.logger.Warn().
+		Err(err).
+		Str("request_id", requestID).
+		Msg("Failed to parse request body")
+
+	return c.JSON(http.StatusBadRequest, ErrorResponse{
+		Status:  http.StatusBadRequest,
+		Message: "Invalid request format",
+		Code:    "INVALID_FORMAT",
+	})
+}
+
+// validationFailed writes the response for a request body that failed validation
+func (h *cartHandler) validationFailed(c echo.Context, validationErrors map[string]string, requestID string) error {
+	h.logger.Warn().
+		Interface("validation_errors", validationErrors).
+		Str("request_id", requestID).
+		Msg("Cart validation failed")
+
+	return c.JSON(http.StatusBadRequest, ErrorResponse{
+		Status:           http.StatusBadRequest,
+		Message:          "Validation failed",
+		ValidationErrors: validationErrors,
+		Code:             "VALIDATION_ERROR",
+	})
+}
+
+// errorResponse maps service errors to HTTP responses
+func (h *cartHandler) errorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Cart not found",
+			Code:    "CART_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidState):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Status:  http.StatusConflict,
+			Message: err.Error(),
+			Code:    "INVALID_CART_STATE",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	case errors.Is(err, postgres.ErrDatabaseConnection):
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Status:  http.StatusServiceUnavailable,
+			Message: "Service temporarily unavailable, please try again later",
+			Code:    "SERVICE_UNAVAILABLE",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action + " cart",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+}
+
+// Create handles POST /api/v1/carts
+func (h *cartHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CartHandler.Create").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling cart creation request")
+
+	var cartDTO dto.CartCreateDTO
+	if err := c.Bind(&cartDTO); err != nil {
+		return h.invalidFormat(c, err, requestID)
+	}
+
+	if validationErrors := cartDTO.Valid(ctx); len(validationErrors) > 0 {
+		return h.validationFailed(c, validationErrors, requestID)
+	}
+
+	cart, err := h.cartService.Create(ctx, &cartDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to create cart")
+		return h.errorResponse(c, err, "create")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Cart created successfully",
+		"cart":    cart,
+	})
+}
+
+// Get handles GET /api/v1/carts/:id
+func (h *cartHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CartHandler.Get").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling get cart request")
+
+	id, ok, err := h.parseID(c, requestID, "id", "cart")
+	if !ok {
+		return err
+	}
+
+	cart, err := h.cartService.GetByID(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("cart_id", id.String()).
+			Msg("Failed to retrieve cart")
+		return h.errorResponse(c, err, "retrieve")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"cart": cart,
+	})
+}
+
+// AddItem handles POST /api/v1/carts/:id/items
+func (h *cartHandler) AddItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CartHandler.AddItem").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling add cart item request")
+
+	id, ok, err := h.parseID(c, requestID, "id", "cart")
+	if !ok {
+		return err
+	}
+
+	var itemDTO dto.CartItemDTO
+	if err := c.Bind(&itemDTO); err != nil {
+		return h.invalidFormat(c, err, requestID)
+	}
+
+	if validationErrors := itemDTO.Valid(ctx); len(validationErrors) > 0 {
+		return h.validationFailed(c, validationErrors, requestID)
+	}
+
+	cart, err := h.cartService.AddItem(ctx, id, &itemDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("cart_id", id.String()).
+			Str("variant_id", itemDTO.VariantID.String()).
+			Msg("Failed to add cart item")
+		return h.errorResponse(c, err, "update")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Item added to cart",
+		"cart":    cart,
+	})
+}
+
+// UpdateItem handles PUT /api/v1/carts/:id/items/:variant_id
+func (h *cartHandler) UpdateItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CartHandler.UpdateItem").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling update cart item request")
+
+	id, ok, err := h.parseID(c, requestID, "id", "cart")
+	if !ok {
+		return err
+	}
+
+	variantID, ok, err := h.parseID(c, requestID, "variant_id", "variant")
+	if !ok {
+		return err
+	}
+
+	var itemDTO dto.CartItemUpdateDTO
+	if err := c.Bind(&itemDTO); err != nil {
+		return h.invalidFormat(c, err, requestID)
+	}
+
+	if validationErrors := itemDTO.Valid(ctx); len(validationErrors) > 0 {
+		return h.validationFailed(c, validationErrors, requestID)
+	}
+
+	cart, err := h.cartService.UpdateItem(ctx, id, variantID, &itemDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("cart_id", id.String()).
+			Str("variant_id", variantID.String()).
+			Msg("Failed to update cart item")
+		return h.errorResponse(c, err, "update")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Cart item updated",
+		"cart":    cart,
+	})
+}
+
+// RemoveItem handles DELETE /api/v1/carts/:id/items/:variant_id
+func (h *cartHandler) RemoveItem(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CartHandler.RemoveItem").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling remove cart item request")
+
+	id, ok, err := h.parseID(c, requestID, "id", "cart")
+	if !ok {
+		return err
+	}
+
+	variantID, ok, err := h.parseID(c, requestID, "variant_id", "variant")
+	if !ok {
+		return err
+	}
+
+	cart, err := h.cartService.RemoveItem(ctx, id, variantID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("cart_id", id.String()).
+			Str("variant_id", variantID.String()).
+			Msg("Failed to remove cart item")
+		return h.errorResponse(c, err, "update")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Item removed from cart",
+		"cart":    cart,
+	})
+}
+
+// Merge handles POST /api/v1/carts/:id/merge
+func (h *cartHandler) Merge(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CartHandler.Merge").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling cart merge request")
+
+	id, ok, err := h.parseID(c, requestID, "id", "cart")
+	if !ok {
+		return err
+	}
+
+	var mergeDTO dto.CartMergeDTO
+	if err := c.Bind(&mergeDTO); err != nil {
+		return h.invalidFormat(c, err, requestID)
+	}
+
+	if validationErrors := mergeDTO.Valid(ctx); len(validationErrors) > 0 {
+		return h.validationFailed(c, validationErrors, requestID)
+	}
+
+	cart, err := h.cartService.Merge(ctx, id, &mergeDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("cart_id", id.String()).
+			Str("customer_id", mergeDTO.CustomerID.String()).
+			Msg("Failed to merge cart")
+		return h.errorResponse(c, err, "merge")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Cart merged successfully",
+		"cart":    cart,
+	})
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type CheckoutHandler interface {
+	CreateSession(c echo.Context) error
+}
+
+// checkoutHandler handles HTTP requests for starting a checkout
+type checkoutHandler struct {
+	logger          zerolog.Logger
+	checkoutService interfaces.CheckoutService
+}
+
+// NewCheckoutHandler creates a new checkout handler
+func NewCheckoutHandler(logger *zerolog.Logger, checkoutService interfaces.CheckoutService) *checkoutHandler {
+	sublogger := logger.With().Str("component", "checkout_handler").Logger()
+	return &checkoutHandler{
+		logger:          sublogger,
+		checkoutService: checkoutService,
+	}
+}
+
+// CreateSession handles POST /api/v1/checkout/sessions
+func (h *checkoutHandler) CreateSession(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CheckoutHandler.CreateSession").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling checkout session creation request")
+
+	var checkoutDTO dto.CheckoutSessionCreateDTO
+	if err := c.Bind(&checkoutDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := checkoutDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Checkout validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	session, err := h.checkoutService.CreateSession(ctx, &checkoutDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to create checkout session")
+
+		switch {
+		case errors.Is(err, postgres.ErrResourceNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Customer not found",
+				Code:    "CUSTOMER_NOT_FOUND",
+			})
+
+		case errors.Is(err, service.ErrInvalidInput):
+			return c.JSON(http.StatusUnprocessableEntity, ErrorResponse{
+				Status:  http.StatusUnprocessableEntity,
+				Message: err.Error(),
+				Code:    "CART_NOT_PURCHASABLE",
+			})
+
+		case errors.Is(err, service.ErrServiceUnavailable):
+			return c.JSON(http.StatusBadGateway, ErrorResponse{
+				Status:  http.StatusBadGateway,
+				Message: "Payment provider unavailable, please try again later",
+				Code:    "PAYMENT_PROVIDER_ERROR",
+			})
+
+		case errors.Is(err, postgres.ErrDatabaseConnection):
+			return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+				Status:  http.StatusServiceUnavailable,
+				Message: "Service temporarily unavailable, please try again later",
+				Code:    "SERVICE_UNAVAILABLE",
+			})
+
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: "Failed to create checkout session",
+				Code:    "INTERNAL_ERROR",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Checkout session created successfully",
+		"session": session,
+	})
+}
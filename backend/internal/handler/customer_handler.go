@@ -0,0 +1,554 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type CustomerHandler interface {
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	List(c echo.Context) error
+	Lookup(c echo.Context) error
+	Update(c echo.Context) error
+	Deactivate(c echo.Context) error
+	Delete(c echo.Context) error
+}
+
+// customerHandler handles HTTP requests for customers
+type customerHandler struct {
+	logger          zerolog.Logger
+	customerService interfaces.CustomerService
+}
+
+// NewCustomerHandler creates a new customer handler
+func NewCustomerHandler(logger *zerolog.Logger, customerService interfaces.CustomerService) *customerHandler {
+	sublogger := logger.With().Str("component", "customer_handler").Logger()
+	return &customerHandler{
+		logger:          sublogger,
+		customerService: customerService,
+	}
+}
+
+// Create handles POST /api/v1/customers
+func (h *customerHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.Create").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling customer creation request")
+
+	var customerDTO dto.CustomerCreateDTO
+	if err := c.Bind(&customerDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := customerDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Customer validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	customer, err := h.customerService.Create(ctx, &customerDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to create customer")
+
+		switch {
+		case errors.Is(err, postgres.ErrDuplicateEmail):
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "A customer with this email already exists",
+				Code:    "DUPLICATE_CUSTOMER",
+				ValidationErrors: map[string]string{
+					"email": "This email is already in use",
+				},
+			})
+
+		case errors.Is(err, postgres.ErrDatabaseConnection):
+			return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+				Status:  http.StatusServiceUnavailable,
+				Message: "Service temporarily unavailable, please try again later",
+				Code:    "SERVICE_UNAVAILABLE",
+			})
+
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: "Failed to create customer",
+				Code:    "INTERNAL_ERROR",
+			})
+		}
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":  "Customer created successfully",
+		"customer": dto.CustomerResponseDTOFromModel(customer),
+	})
+}
+
+// Get handles GET /api/v1/customers/:id
+func (h *customerHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	idParam := c.Param("id")
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.Get").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("id_param", idParam).
+		Msg("Handling get customer by ID request")
+
+	customerID, err := uuid.Parse(idParam)
+	if err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Str("id_param", idParam).
+			Msg("Invalid customer ID format")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid customer ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	customer, err := h.customerService.GetByID(ctx, customerID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to retrieve customer")
+
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Customer not found",
+				Code:    "CUSTOMER_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to retrieve customer",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"customer": dto.CustomerResponseDTOFromModel(customer),
+	})
+}
+
+// List handles GET /api/v1/customers
+func (h *customerHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "CustomerHandler.List").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling customer listing request")
+
+	params := NewParams(c)
+	includeInactive := c.QueryParam("include_inactive") == "true"
+
+	customers, total, err := h.customerService.List(ctx, params.Offset, params.PerPage, includeInactive)
+	if err != nil {
+		h.logger.Error().
+			Str("handler", "CustomerHandler.List").
+			Str("request_id", requestID).
+			Err(err).
+			Int("offset", params.Offset).
+			Int("per_page", params.PerPage).
+			Bool("include_inactive", includeInactive).
+			Msg("Failed to retrieve customers from service")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve customers")
+	}
+
+	customerResponses := make([]dto.CustomerResponseDTO, 0, len(customers))
+	for _, customer := range customers {
+		customerResponses = append(customerResponses, dto.CustomerResponseDTOFromModel(customer))
+	}
+
+	meta := NewMeta(params, total)
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.List").
+		Str("request_id", requestID).
+		Int("customers_count", len(customers)).
+		Int("total_count", total).
+		Int("page", params.Page).
+		Int("per_page", params.PerPage).
+		Msg("Customer listing successfully returned")
+
+	return c.JSON(http.StatusOK, Response(customerResponses, meta))
+}
+
+// Lookup handles GET /api/v1/customers/lookup?email=...|stripe_id=...
+func (h *customerHandler) Lookup(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	email := c.QueryParam("email")
+	stripeID := c.QueryParam("stripe_id")
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.Lookup").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("email", email).
+		Str("stripe_id", stripeID).
+		Msg("Handling customer lookup request")
+
+	var customer *model.Customer
+	var err error
+
+	switch {
+	case email != "":
+		customer, err = h.customerService.GetByEmail(ctx, email)
+	case stripeID != "":
+		customer, err = h.customerService.GetByStripeID(ctx, stripeID)
+	default:
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Either email or stripe_id query parameter is required",
+			Code:    "MISSING_LOOKUP_KEY",
+		})
+	}
+
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to look up customer")
+
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Customer not found",
+				Code:    "CUSTOMER_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to look up customer",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"customer": dto.CustomerResponseDTOFromModel(customer),
+	})
+}
+
+// Update handles PUT /api/v1/customers/:id
+func (h *customerHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	idParam := c.Param("id")
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.Update").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("id_param", idParam).
+		Msg("Handling customer update by ID request")
+
+	customerID, err := uuid.Parse(idParam)
+	if err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Str("id_param", idParam).
+			Msg("Invalid customer ID format")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid customer ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	var customerUpdateDTO dto.CustomerUpdateDTO
+	if err := c.Bind(&customerUpdateDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := customerUpdateDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Customer update validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	updatedCustomer, err := h.customerService.Update(ctx, customerID, &customerUpdateDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to update customer")
+
+		switch {
+		case errors.Is(err, postgres.ErrResourceNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Customer not found",
+				Code:    "CUSTOMER_NOT_FOUND",
+			})
+
+		case errors.Is(err, postgres.ErrDuplicateEmail):
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "A customer with this email already exists",
+				Code:    "DUPLICATE_CUSTOMER",
+				ValidationErrors: map[string]string{
+					"email": "This email is already in use",
+				},
+			})
+
+		case errors.Is(err, postgres.ErrDatabaseConnection):
+			return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+				Status:  http.StatusServiceUnavailable,
+				Message: "Service temporarily unavailable, please try again later",
+				Code:    "SERVICE_UNAVAILABLE",
+			})
+
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: "Failed to update customer",
+				Code:    "INTERNAL_ERROR",
+			})
+		}
+	}
+
+	h.logger.Info().
+		Str("request_id", requestID).
+		Str("customer_id", customerID.String()).
+		Msg("Customer updated successfully")
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":  "Customer updated successfully",
+		"customer": dto.CustomerResponseDTOFromModel(updatedCustomer),
+	})
+}
+
+// Deactivate handles POST /api/v1/customers/:id/deactivate
+func (h *customerHandler) Deactivate(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	idParam := c.Param("id")
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.Deactivate").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("id_param", idParam).
+		Msg("Handling customer deactivate by ID request")
+
+	customerID, err := uuid.Parse(idParam)
+	if err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Str("id_param", idParam).
+			Msg("Invalid customer ID format")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid customer ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	err = h.customerService.Deactivate(ctx, customerID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to deactivate customer")
+
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Customer not found",
+				Code:    "CUSTOMER_NOT_FOUND",
+			})
+		}
+
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to deactivate customer",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Customer deactivated successfully",
+	})
+}
+
+// Delete handles DELETE /api/v1/customers/:id
+// Without hard_delete=true this deactivates the customer instead
+func (h *customerHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	hardDelete := c.QueryParam("hard_delete") == "true"
+	idParam := c.Param("id")
+
+	h.logger.Info().
+		Str("handler", "CustomerHandler.Delete").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("id_param", idParam).
+		Bool("hard_delete", hardDelete).
+		Msg("Handling customer delete by ID request")
+
+	customerID, err := uuid.Parse(idParam)
+	if err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Str("id_param", idParam).
+			Msg("Invalid customer ID format")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid customer ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	if hardDelete {
+		// TODO: Add proper permission checking here
+		err = h.customerService.Delete(ctx, customerID)
+	} else {
+		err = h.customerService.Deactivate(ctx, customerID)
+	}
+
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", customerID.String()).
+			Bool("hard_delete", hardDelete).
+			Msg("Failed to remove customer")
+
+		switch {
+		case errors.Is(err, postgres.ErrResourceNotFound):
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Customer not found",
+				Code:    "CUSTOMER_NOT_FOUND",
+			})
+
+		case errors.Is(err, postgres.ErrDatabaseConnection):
+			return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+				Status:  http.StatusServiceUnavailable,
+				Message: "Service temporarily unavailable, please try again later",
+				Code:    "SERVICE_UNAVAILABLE",
+			})
+
+		case errors.Is(err, postgres.ErrResourceInUse):
+			return c.JSON(http.StatusConflict, ErrorResponse{
+				Status:  http.StatusConflict,
+				Message: "This customer cannot be hard deleted because it has associated records. Use deactivate instead.",
+				Code:    "FOREIGN_KEY_CONSTRAINT",
+			})
+
+		default:
+			return c.JSON(http.StatusInternalServerError, ErrorResponse{
+				Status:  http.StatusInternalServerError,
+				Message: "Failed to remove customer",
+				Code:    "INTERNAL_ERROR",
+			})
+		}
+	}
+
+	operation := "deactivated"
+	if hardDelete {
+		operation = "deleted"
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": fmt.Sprintf("Customer successfully %s", operation),
+	})
+}
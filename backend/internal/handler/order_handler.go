@@ -0,0 +1,391 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
+	"github.com/google/uuid"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type OrderHandler interface {
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	UpdateStatus(c echo.Context) error
+	Cancel(c echo.Context) error
+	History(c echo.Context) error
+}
+
+// orderHandler handles HTTP requests for orders
+type orderHandler struct {
+	logger       zerolog.Logger
+	orderService interfaces.OrderService
+}
+
+// NewOrderHandler creates a new order handler
+func NewOrderHandler(logger *zerolog.Logger, orderService interfaces.OrderService) *orderHandler {
+	sublogger := logger.With().Str("component", "order_handler").Logger()
+	return &orderHandler{
+		logger:       sublogger,
+		orderService: orderService,
+	}
+}
+
+// parseID extracts the order ID from the URL.
+// On failure the 400 response has already been written and ok is false
+func (h *orderHandler) parseID(c echo.Context, requestID string) (id uuid.UUID, ok bool, err error) {
+	id, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		h.logger.Warn().
+			Err(parseErr).
+			Str("request_id", requestID).
+			Str("id_param", c.Param("id")).
+			Msg("Invalid order ID format")
+
+		return id, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid order ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	return id, true, nil
+}
+
+// errorResponse maps service errors to HTTP responses
+func (h *orderHandler) errorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Order not found",
+			Code:    "ORDER_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidState):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Status:  http.StatusConflict,
+			Message: err.Error(),
+			Code:    "INVALID_ORDER_STATE",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	case errors.Is(err, postgres.ErrDatabaseConnection):
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Status:  http.StatusServiceUnavailable,
+			Message: "Service temporarily unavailable, please try again later",
+			Code:    "SERVICE_UNAVAILABLE",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action + " order",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+}
+
+// List handles GET /api/v1/orders?customer_id=...&status=...
+func (h *orderHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "OrderHandler.List").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling order listing request")
+
+	params := NewParams(c)
+	status := c.QueryParam("status")
+
+	var customerID *uuid.UUID
+	if customerParam := c.QueryParam("customer_id"); customerParam != "" {
+		id, err := uuid.Parse(customerParam)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid customer ID format",
+				Code:    "INVALID_ID_FORMAT",
+			})
+		}
+		customerID = &id
+	}
+
+	orders, total, err := h.orderService.List(ctx, params.Offset, params.PerPage, customerID, status)
+	if err != nil {
+		h.logger.Error().
+			Str("handler", "OrderHandler.List").
+			Str("request_id", requestID).
+			Err(err).
+			Int("offset", params.Offset).
+			Int("per_page", params.PerPage).
+			Msg("Failed to retrieve orders from service")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve orders")
+	}
+
+	meta := NewMeta(params, total)
+
+	h.logger.Info().
+		Str("handler", "OrderHandler.List").
+		Str("request_id", requestID).
+		Int("orders_count", len(orders)).
+		Int("total_count", total).
+		Int("page", params.Page).
+		Int("per_page", params.PerPage).
+		Msg("Order listing successfully returned")
+
+	return c.JSON(http.StatusOK, Response(orders, meta))
+}
+
+// Create handles POST /api/v1/orders
+func (h *orderHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "OrderHandler.Create").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling order creation request")
+
+	var orderDTO dto.OrderCreateDTO
+	if err := c.Bind(&orderDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := orderDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Order validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	order, err := h.orderService.Create(ctx, &orderDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", orderDTO.CustomerID.String()).
+			Msg("Failed to create order")
+		return h.errorResponse(c, err, "create")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Order created successfully",
+		"order":   order,
+	})
+}
+
+// Get handles GET /api/v1/orders/:id
+func (h *orderHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "OrderHandler.Get").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling get order request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	order, err := h.orderService.GetByID(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("order_id", id.String()).
+			Msg("Failed to retrieve order")
+		return h.errorResponse(c, err, "retrieve")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"order": order,
+	})
+}
+
+// UpdateStatus handles POST /api/v1/orders/:id/status
+func (h *orderHandler) UpdateStatus(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "OrderHandler.UpdateStatus").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling order status update request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	var statusDTO dto.OrderStatusUpdateDTO
+	if err := c.Bind(&statusDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := statusDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Order status update validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	order, err := h.orderService.UpdateStatus(ctx, id, &statusDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("order_id", id.String()).
+			Str("status", statusDTO.Status).
+			Msg("Failed to update order status")
+		return h.errorResponse(c, err, "update")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Order status updated successfully",
+		"order":   order,
+	})
+}
+
+// Cancel handles POST /api/v1/orders/:id/cancel
+func (h *orderHandler) Cancel(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "OrderHandler.Cancel").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling order cancel request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	// The body is optional and only carries a reason
+	var body struct {
+		Reason string `json:"reason"`
+	}
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&body); err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid request format",
+				Code:    "INVALID_FORMAT",
+			})
+		}
+	}
+
+	order, err := h.orderService.Cancel(ctx, id, body.Reason)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("order_id", id.String()).
+			Msg("Failed to cancel order")
+		return h.errorResponse(c, err, "cancel")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Order canceled successfully",
+		"order":   order,
+	})
+}
+
+// History handles GET /api/v1/orders/:id/history
+func (h *orderHandler) History(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "OrderHandler.History").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling order history request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	history, err := h.orderService.GetStatusHistory(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("order_id", id.String()).
+			Msg("Failed to retrieve order history")
+		return h.errorResponse(c, err, "retrieve history for")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"history": history,
+	})
+}
@@ -22,6 +22,9 @@ type PriceHandler interface {
 	Delete(c echo.Context) error
 	AssignToVariant(c echo.Context) error
 	GetVariantsByPrice(c echo.Context) error
+	GetPricingRule(c echo.Context) error
+	SetPricingRule(c echo.Context) error
+	ApplyPricingRule(c echo.Context) error
 }
 
 // priceHandler handles HTTP requests for prices
@@ -609,4 +612,182 @@ func (h *priceHandler) GetVariantsByPrice(c echo.Context) error {
 		"variants": variants,
 		"count":    len(variants),
 	})
-}
\ No newline at end of file
+}
+// parseProductID parses the product ID path parameter, writing a 400 response when it's invalid
+func (h *priceHandler) parseProductID(c echo.Context, requestID string) (id uuid.UUID, ok bool, err error) {
+	id, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		h.logger.Warn().
+			Err(parseErr).
+			Str("request_id", requestID).
+			Str("product_id", c.Param("id")).
+			Msg("Invalid product ID format")
+
+		return id, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid product ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	return id, true, nil
+}
+
+// pricingRuleErrorResponse maps pricing rule service errors to HTTP responses
+func (h *priceHandler) pricingRuleErrorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Pricing rule not found",
+			Code:    "PRICING_RULE_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action,
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+}
+
+// GetPricingRule handles GET /api/v1/products/:id/pricing-rule
+func (h *priceHandler) GetPricingRule(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "PriceHandler.GetPricingRule").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("product_id", c.Param("id")).
+		Msg("Handling get pricing rule request")
+
+	productID, ok, err := h.parseProductID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	rule, err := h.priceService.GetPricingRule(ctx, productID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("product_id", productID.String()).
+			Msg("Failed to retrieve pricing rule")
+
+		return h.pricingRuleErrorResponse(c, err, "retrieve pricing rule")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"pricing_rule": rule,
+	})
+}
+
+// SetPricingRule handles PUT /api/v1/products/:id/pricing-rule
+func (h *priceHandler) SetPricingRule(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "PriceHandler.SetPricingRule").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("product_id", c.Param("id")).
+		Msg("Handling set pricing rule request")
+
+	productID, ok, err := h.parseProductID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	var ruleDTO dto.PricingRuleDTO
+	if err := c.Bind(&ruleDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := ruleDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Pricing rule validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	rule, err := h.priceService.SetPricingRule(ctx, productID, &ruleDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("product_id", productID.String()).
+			Msg("Failed to save pricing rule")
+
+		return h.pricingRuleErrorResponse(c, err, "save pricing rule")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Pricing rule saved successfully",
+		"pricing_rule": rule,
+	})
+}
+
+// ApplyPricingRule handles POST /api/v1/products/:id/pricing-rule/apply
+func (h *priceHandler) ApplyPricingRule(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "PriceHandler.ApplyPricingRule").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Str("product_id", c.Param("id")).
+		Msg("Handling apply pricing rule request")
+
+	productID, ok, err := h.parseProductID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	result, err := h.priceService.ApplyPricingRule(ctx, productID)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("product_id", productID.String()).
+			Msg("Failed to apply pricing rule")
+
+		return h.pricingRuleErrorResponse(c, err, "apply pricing rule")
+	}
+
+	return c.JSON(http.StatusOK, result)
+}
@@ -4,7 +4,9 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"hash/fnv"
 	"io"
 	"net/http"
 	"strconv"
@@ -12,9 +14,12 @@ import (
 	"time"
 
 	"github.com/dukerupert/coffee-commerce/config"
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
 	"github.com/dukerupert/coffee-commerce/internal/domain/model"
 	"github.com/dukerupert/coffee-commerce/internal/events"
 	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
 	"github.com/dukerupert/coffee-commerce/internal/sync"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
@@ -31,13 +36,28 @@ type StripeWebhookHandler struct {
 	priceRepo    interfaces.PriceRepository
 	variantRepo  interfaces.VariantRepository
 	syncRepo     interfaces.SyncHashRepository
+	webhookRepo  interfaces.WebhookEventRepository
+
+	customerRepo     interfaces.CustomerRepository
+	addressRepo      interfaces.AddressRepository
+	subscriptionRepo interfaces.SubscriptionRepository
+	invoiceRepo      interfaces.InvoiceRepository
+
+	stripeService  interfaces.StripeService
+	variantService interfaces.VariantService
+	orderService   interfaces.OrderService
+	dunningService interfaces.DunningService
 }
 
 func NewStripeWebhookHandler(
 	logger *zerolog.Logger,
 	stripeConfig *config.StripeConfig,
 	eventBus events.EventBus, productRepo interfaces.ProductRepository, priceRepo interfaces.PriceRepository,
-	variantRepo interfaces.VariantRepository, syncRepo interfaces.SyncHashRepository) *StripeWebhookHandler {
+	variantRepo interfaces.VariantRepository, syncRepo interfaces.SyncHashRepository, webhookRepo interfaces.WebhookEventRepository,
+	customerRepo interfaces.CustomerRepository, addressRepo interfaces.AddressRepository,
+	subscriptionRepo interfaces.SubscriptionRepository, invoiceRepo interfaces.InvoiceRepository,
+	stripeService interfaces.StripeService, variantService interfaces.VariantService,
+	orderService interfaces.OrderService, dunningService interfaces.DunningService) *StripeWebhookHandler {
 
 	return &StripeWebhookHandler{
 		logger:       logger.With().Str("component", "stripe_webhook_handler").Logger(),
@@ -47,6 +67,17 @@ func NewStripeWebhookHandler(
 		priceRepo:    priceRepo,
 		variantRepo:  variantRepo,
 		syncRepo:     syncRepo,
+		webhookRepo:  webhookRepo,
+
+		customerRepo:     customerRepo,
+		addressRepo:      addressRepo,
+		subscriptionRepo: subscriptionRepo,
+		invoiceRepo:      invoiceRepo,
+
+		stripeService:  stripeService,
+		variantService: variantService,
+		orderService:   orderService,
+		dunningService: dunningService,
 	}
 }
 
@@ -92,24 +123,371 @@ func (h *StripeWebhookHandler) HandleWebhook(c echo.Context) error {
 		Str("event_type", string(event.Type)).
 		Msg("Received Stripe webhook event")
 
-	// Process the event based on its type
-	err = h.processEvent(event)
+	// Store the event before acknowledging it. If it can't be stored, let Stripe retry it
+	ctx := c.Request().Context()
+	record := &model.WebhookEvent{
+		ID:      event.ID,
+		Type:    string(event.Type),
+		Payload: body,
+	}
+	inserted, err := h.webhookRepo.Save(ctx, record)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("event_id", event.ID).
+			Msg("Failed to store webhook event")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to store webhook event",
+		})
+	}
+
+	// Stripe delivers at least once; events already handled aren't dispatched again
+	if !inserted {
+		stored, err := h.webhookRepo.GetByID(ctx, event.ID)
+		if err == nil && stored != nil && stored.Status == model.WebhookEventStatusProcessed {
+			h.logger.Info().
+				Str("event_id", event.ID).
+				Msg("Webhook event already processed, skipping")
+			return c.JSON(http.StatusOK, map[string]string{
+				"status": "duplicate",
+			})
+		}
+		if stored != nil {
+			record = stored
+		}
+	}
+
+	// Processing happens on the webhook workers, keeping the response inside Stripe's timeout
+	if err := h.dispatchEvent(event, record.ReceivedAt); err != nil {
+		h.logger.Error().Err(err).
+			Str("event_id", event.ID).
+			Msg("Failed to dispatch webhook event")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to dispatch webhook event",
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"status": "accepted",
+	})
+}
+
+// dispatchEvent publishes a stored event to the partition of the Stripe object it's about
+func (h *StripeWebhookHandler) dispatchEvent(event stripe.Event, receivedAt time.Time) error {
+	objectID := webhookObjectID(event)
+	payload := events.StripeWebhookReceivedPayload{
+		EventID:    event.ID,
+		EventType:  string(event.Type),
+		ObjectID:   objectID,
+		ReceivedAt: receivedAt,
+	}
+
+	topic := events.StripeWebhookTopic(webhookPartition(objectID, event.ID, h.stripeConfig.WebhookPartitions))
+	dispatched, err := events.NewEvent(topic, payload)
+	if err != nil {
+		return err
+	}
+
+	// Reusing the Stripe event ID lets JetStream drop repeated dispatches
+	dispatched.ID = event.ID
+
+	return h.eventBus.PublishEvent(dispatched)
+}
+
+// webhookObjectID returns the ID of the Stripe object an event is about, if it has one
+func webhookObjectID(event stripe.Event) string {
+	if event.Data == nil {
+		return ""
+	}
+	id, _ := event.Data.Object["id"].(string)
+	return id
+}
+
+// webhookPartition hashes the object ID to a partition, falling back to the
+// event ID for events without an object
+func webhookPartition(objectID, eventID string, partitions int) int {
+	key := objectID
+	if key == "" {
+		key = eventID
+	}
+
+	hash := fnv.New32a()
+	hash.Write([]byte(key))
+	return int(hash.Sum32() % uint32(partitions))
+}
+
+// StartWorkers subscribes an ordered durable consumer to each webhook partition.
+// Instances that only receive webhooks leave this to dedicated workers
+func (h *StripeWebhookHandler) StartWorkers() error {
+	for partition := 0; partition < h.stripeConfig.WebhookPartitions; partition++ {
+		topic := events.StripeWebhookTopic(partition)
+		durable := fmt.Sprintf("stripe-webhook-worker-%d", partition)
+
+		if _, err := events.SubscribeOrdered(h.eventBus, topic, durable, h.handleDispatchedEvent); err != nil {
+			h.logger.Error().Err(err).Str("topic", topic).Msg("Failed to subscribe webhook worker")
+			return err
+		}
+	}
+
+	h.logger.Info().
+		Int("partitions", h.stripeConfig.WebhookPartitions).
+		Msg("Started Stripe webhook workers")
+
+	return nil
+}
+
+// handleDispatchedEvent processes a stored event dispatched by HandleWebhook.
+// Returning an error redelivers it; the stored failure can also be replayed
+func (h *StripeWebhookHandler) handleDispatchedEvent(dispatched events.TypedEvent[events.StripeWebhookReceivedPayload]) error {
+	ctx := context.Background()
+	eventID := dispatched.Payload.EventID
+
+	record, err := h.webhookRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("%w: webhook event %s is not stored", events.ErrNoRetry, eventID)
+	}
+
+	event, err := parseStoredEvent(record)
+	if err != nil {
+		return fmt.Errorf("%w: %v", events.ErrNoRetry, err)
+	}
+
+	err = h.runStoredEvent(ctx, event)
+	if errors.Is(err, errWebhookEventClaimed) {
+		return h.claimedEventOutcome(ctx, eventID)
+	}
+
+	return err
+}
+
+// claimedEventOutcome decides what to do with a dispatch whose event couldn't
+// be claimed. Only a processed event is acked: one still processing may belong
+// to a worker that died, so it is redelivered once its claim goes stale
+func (h *StripeWebhookHandler) claimedEventOutcome(ctx context.Context, eventID string) error {
+	record, err := h.webhookRepo.GetByID(ctx, eventID)
+	if err != nil {
+		return err
+	}
+	if record == nil {
+		return fmt.Errorf("%w: webhook event %s is not stored", events.ErrNoRetry, eventID)
+	}
+
+	switch record.Status {
+	case model.WebhookEventStatusProcessed:
+		h.logger.Debug().
+			Str("event_id", eventID).
+			Msg("Webhook event already processed, skipping")
+		return nil
+
+	case model.WebhookEventStatusProcessing:
+		retryIn := time.Until(record.UpdatedAt.Add(webhookProcessingTimeout)) + time.Second
+		h.logger.Debug().
+			Str("event_id", eventID).
+			Dur("retry_in", retryIn).
+			Msg("Webhook event in progress elsewhere, will check again")
+		return events.RetryAfter(errWebhookEventClaimed, retryIn)
+
+	default:
+		// Released since the claim failed; the next delivery can claim it
+		return errWebhookEventClaimed
+	}
+}
+
+// webhookProcessingTimeout is how long an event can stay in processing before
+// its processor is assumed to have died and the event can be claimed again
+const webhookProcessingTimeout = 5 * time.Minute
+
+// errWebhookEventClaimed is returned for events that were already processed or
+// are being processed by another request
+var errWebhookEventClaimed = errors.New("webhook event already processed or in progress")
+
+// runStoredEvent claims a stored event, processes it and records the outcome
+func (h *StripeWebhookHandler) runStoredEvent(ctx context.Context, event stripe.Event) error {
+	claimed, err := h.webhookRepo.Claim(ctx, event.ID, time.Now().Add(-webhookProcessingTimeout))
 	if err != nil {
+		h.logger.Error().Err(err).
+			Str("event_id", event.ID).
+			Msg("Failed to claim webhook event")
+		return err
+	}
+	if !claimed {
+		return errWebhookEventClaimed
+	}
+
+	if err := h.processEvent(event); err != nil {
 		h.logger.Error().Err(err).
 			Str("event_id", event.ID).
 			Str("event_type", string(event.Type)).
 			Msg("Error processing webhook event")
 
-		// Return 200 even if processing fails to prevent Stripe from retrying
-		// We'll handle the error internally through logging and monitoring
+		if markErr := h.webhookRepo.MarkFailed(ctx, event.ID, err.Error()); markErr != nil {
+			h.logger.Error().Err(markErr).
+				Str("event_id", event.ID).
+				Msg("Failed to record webhook event failure")
+		}
+		return err
 	}
 
-	// Always return a 200 OK to prevent Stripe from retrying
-	return c.JSON(http.StatusOK, map[string]string{
-		"status": "success",
+	// If this fails the event is claimed again once it goes stale, and handlers
+	// tolerate being run twice
+	if err := h.webhookRepo.MarkProcessed(ctx, event.ID); err != nil {
+		h.logger.Error().Err(err).
+			Str("event_id", event.ID).
+			Msg("Failed to mark webhook event processed")
+	}
+
+	return nil
+}
+
+// ListEvents handles GET /api/v1/admin/webhook-events
+func (h *StripeWebhookHandler) ListEvents(c echo.Context) error {
+	ctx := c.Request().Context()
+	params := NewParams(c)
+
+	status := c.QueryParam("status")
+	switch status {
+	case "", model.WebhookEventStatusPending, model.WebhookEventStatusProcessing,
+		model.WebhookEventStatusProcessed, model.WebhookEventStatusFailed:
+	default:
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid webhook event status",
+			ValidationErrors: map[string]string{
+				"status": "must be one of pending, processing, processed, failed",
+			},
+		})
+	}
+
+	webhookEvents, total, err := h.webhookRepo.List(ctx, status, params.Offset, params.PerPage)
+	if err != nil {
+		h.logger.Error().Err(err).Str("status", status).Msg("Failed to list webhook events")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to list webhook events",
+		})
+	}
+
+	return c.JSON(http.StatusOK, Response(webhookEvents, NewMeta(params, total)))
+}
+
+// ReplayEvent handles POST /api/v1/admin/webhook-events/:id/replay
+func (h *StripeWebhookHandler) ReplayEvent(c echo.Context) error {
+	ctx := c.Request().Context()
+	id := c.Param("id")
+
+	record, err := h.webhookRepo.GetByID(ctx, id)
+	if err != nil {
+		h.logger.Error().Err(err).Str("event_id", id).Msg("Failed to retrieve webhook event")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to retrieve webhook event",
+		})
+	}
+	if record == nil {
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Webhook event not found",
+		})
+	}
+
+	err = h.replayStoredEvent(ctx, record)
+	if errors.Is(err, errWebhookEventClaimed) {
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Status:  http.StatusConflict,
+			Message: "Webhook event has already been processed or is in progress",
+			Code:    "WEBHOOK_EVENT_NOT_REPLAYABLE",
+		})
+	}
+
+	// Return the stored outcome, including the error if the replay failed again
+	updated, getErr := h.webhookRepo.GetByID(ctx, id)
+	if getErr != nil || updated == nil {
+		updated = record
+	}
+
+	if err != nil {
+		return c.JSON(http.StatusUnprocessableEntity, map[string]interface{}{
+			"message": "Webhook event failed again",
+			"event":   updated,
+		})
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Webhook event replayed successfully",
+		"event":   updated,
+	})
+}
+
+// ReplayFailed handles POST /api/v1/admin/webhook-events/replay-failed. It
+// replays a page of failed events, oldest failures last
+func (h *StripeWebhookHandler) ReplayFailed(c echo.Context) error {
+	ctx := c.Request().Context()
+	params := NewParams(c)
+
+	failed, _, err := h.webhookRepo.List(ctx, model.WebhookEventStatusFailed, 0, params.PerPage)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to list failed webhook events")
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to list failed webhook events",
+		})
+	}
+
+	results := map[string]string{}
+	succeeded := 0
+	for _, record := range failed {
+		switch err := h.replayStoredEvent(ctx, record); {
+		case errors.Is(err, errWebhookEventClaimed):
+			results[record.ID] = "skipped"
+		case err != nil:
+			results[record.ID] = err.Error()
+		default:
+			results[record.ID] = "processed"
+			succeeded++
+		}
+	}
+
+	h.logger.Info().
+		Int("replayed", len(failed)).
+		Int("succeeded", succeeded).
+		Msg("Replayed failed webhook events")
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"replayed":  len(failed),
+		"succeeded": succeeded,
+		"results":   results,
 	})
 }
 
+// parseStoredEvent decodes the Stripe event kept in the webhook event store
+func parseStoredEvent(record *model.WebhookEvent) (stripe.Event, error) {
+	var event stripe.Event
+	if err := json.Unmarshal(record.Payload, &event); err != nil {
+		return event, fmt.Errorf("failed to parse stored webhook event %s: %w", record.ID, err)
+	}
+	return event, nil
+}
+
+// replayStoredEvent runs a stored event through processEvent again
+func (h *StripeWebhookHandler) replayStoredEvent(ctx context.Context, record *model.WebhookEvent) error {
+	event, err := parseStoredEvent(record)
+	if err != nil {
+		return err
+	}
+
+	h.logger.Info().
+		Str("event_id", record.ID).
+		Str("event_type", record.Type).
+		Int("attempts", record.Attempts).
+		Msg("Replaying webhook event")
+
+	return h.runStoredEvent(ctx, event)
+}
+
 // processEvent handles different Stripe event types
 func (h *StripeWebhookHandler) processEvent(event stripe.Event) error {
 	switch event.Type {
@@ -170,11 +548,11 @@ func (h *StripeWebhookHandler) processEvent(event stripe.Event) error {
 		return h.handleCustomerUpdated(event)
 	case "customer.deleted":
 		return h.handleCustomerDeleted(event)
-	case "subscription.created":
+	case "customer.subscription.created":
 		return h.handleSubscriptionCreated(event)
-	case "subscription.updated":
+	case "customer.subscription.updated":
 		return h.handleSubscriptionUpdated(event)
-	case "subscription.deleted":
+	case "customer.subscription.deleted":
 		return h.handleSubscriptionDeleted(event)
 	case "invoice.created":
 		return h.handleInvoiceCreated(event)
@@ -194,24 +572,396 @@ func (h *StripeWebhookHandler) processEvent(event stripe.Event) error {
 // Event handlers - stub implementations for all required events
 
 // Checkout session handlers
+
+// handleCheckoutSessionAsyncPaymentFailed cancels the pending order of a
+// session whose delayed payment method (e.g. a bank debit) failed, which
+// returns its stock
 func (h *StripeWebhookHandler) handleCheckoutSessionAsyncPaymentFailed(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing checkout.session.async_payment_failed")
-	return nil
+	var checkoutSession stripe.CheckoutSession
+	err := json.Unmarshal(event.Data.Raw, &checkoutSession)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe checkout session data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("session_id", checkoutSession.ID).
+		Msg("Processing Stripe checkout.session.async_payment_failed event")
+
+	return h.cancelCheckoutOrder(checkoutSession.ID, "async payment failed")
 }
 
+// handleCheckoutSessionAsyncPaymentSucceeded marks the pending order of a
+// session paid once its delayed payment method settles
 func (h *StripeWebhookHandler) handleCheckoutSessionAsyncPaymentSucceeded(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing checkout.session.async_payment_succeeded")
+	var checkoutSession stripe.CheckoutSession
+	err := json.Unmarshal(event.Data.Raw, &checkoutSession)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe checkout session data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("session_id", checkoutSession.ID).
+		Msg("Processing Stripe checkout.session.async_payment_succeeded event")
+
+	ctx := context.Background()
+	order, err := h.orderService.GetByCheckoutSessionID(ctx, checkoutSession.ID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			// The completed event hasn't been processed yet, so create the order as paid
+			return h.handleCheckoutSessionCompleted(event)
+		}
+		return err
+	}
+
+	if order.Status != model.OrderStatusPending {
+		h.logger.Info().
+			Str("session_id", checkoutSession.ID).
+			Str("order_id", order.ID.String()).
+			Str("status", order.Status).
+			Msg("Order is no longer pending, nothing to do")
+		return nil
+	}
+
+	_, err = h.orderService.UpdateStatus(ctx, order.ID, &dto.OrderStatusUpdateDTO{
+		Status: model.OrderStatusPaid,
+		Note:   "async payment succeeded",
+	})
+	return err
+}
+
+// handleCheckoutSessionCompleted turns a completed Checkout session into an order
+func (h *StripeWebhookHandler) handleCheckoutSessionCompleted(event stripe.Event) error {
+	var checkoutSession stripe.CheckoutSession
+	err := json.Unmarshal(event.Data.Raw, &checkoutSession)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe checkout session data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("session_id", checkoutSession.ID).
+		Str("mode", string(checkoutSession.Mode)).
+		Str("payment_status", string(checkoutSession.PaymentStatus)).
+		Msg("Processing Stripe checkout.session.completed event")
+
+	if checkoutSession.Mode == stripe.CheckoutSessionModeSetup {
+		h.logger.Info().
+			Str("session_id", checkoutSession.ID).
+			Msg("Setup mode session has nothing to fulfill")
+		return nil
+	}
+
+	ctx := context.Background()
+
+	customer, err := h.findOrCreateCheckoutCustomer(ctx, &checkoutSession)
+	if err != nil {
+		return err
+	}
+
+	lineItems, err := h.stripeService.ListCheckoutSessionLineItems(checkoutSession.ID)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("session_id", checkoutSession.ID).
+			Msg("Failed to retrieve checkout session line items")
+		return err
+	}
+
+	checkoutOrder := &dto.CheckoutOrderDTO{
+		CustomerID:              customer.ID,
+		StripeCheckoutSessionID: checkoutSession.ID,
+		Paid:                    checkoutSession.PaymentStatus != stripe.CheckoutSessionPaymentStatusUnpaid,
+		Currency:                string(checkoutSession.Currency),
+		Total:                   checkoutSession.AmountTotal,
+		Metadata:                checkoutSession.Metadata,
+	}
+	if checkoutSession.PaymentIntent != nil {
+		checkoutOrder.StripePaymentIntentID = checkoutSession.PaymentIntent.ID
+	}
+	if checkoutSession.TotalDetails != nil {
+		checkoutOrder.ShippingAmount = checkoutSession.TotalDetails.AmountShipping
+		checkoutOrder.TaxAmount = checkoutSession.TotalDetails.AmountTax
+	}
+
+	payloadItems := make([]events.StripeLineItem, 0, len(lineItems))
+	for _, lineItem := range lineItems {
+		if lineItem.Price == nil || lineItem.Price.Product == nil {
+			h.logger.Warn().
+				Str("session_id", checkoutSession.ID).
+				Str("line_item_id", lineItem.ID).
+				Msg("Line item has no product, skipping")
+			continue
+		}
+
+		payloadItems = append(payloadItems, events.StripeLineItem{
+			PriceID:   lineItem.Price.ID,
+			ProductID: lineItem.Price.Product.ID,
+			Quantity:  lineItem.Quantity,
+			Amount:    lineItem.AmountTotal,
+			Currency:  string(lineItem.Currency),
+		})
+
+		// Stripe products map to our variants
+		variant, err := h.variantRepo.GetByStripeID(ctx, lineItem.Price.Product.ID)
+		if err != nil {
+			h.logger.Error().Err(err).
+				Str("stripe_product_id", lineItem.Price.Product.ID).
+				Msg("Error looking up variant")
+			return err
+		}
+		if variant == nil {
+			h.logger.Warn().
+				Str("session_id", checkoutSession.ID).
+				Str("stripe_product_id", lineItem.Price.Product.ID).
+				Msg("Variant not found for line item, skipping")
+			continue
+		}
+
+		unitAmount := lineItem.Price.UnitAmount
+		if lineItem.Quantity > 0 {
+			unitAmount = lineItem.AmountSubtotal / lineItem.Quantity
+		}
+
+		checkoutOrder.Items = append(checkoutOrder.Items, dto.CheckoutOrderItemDTO{
+			VariantID:  variant.ID,
+			Quantity:   int(lineItem.Quantity),
+			UnitAmount: unitAmount,
+		})
+	}
+
+	if len(checkoutOrder.Items) == 0 {
+		h.logger.Warn().
+			Str("session_id", checkoutSession.ID).
+			Msg("No line items could be matched to variants, order not created")
+	} else {
+		order, err := h.orderService.CreateFromCheckout(ctx, checkoutOrder)
+		if err != nil {
+			h.logger.Error().Err(err).
+				Str("session_id", checkoutSession.ID).
+				Msg("Failed to create order from checkout session")
+			return err
+		}
+
+		h.logger.Info().
+			Str("session_id", checkoutSession.ID).
+			Str("order_id", order.ID.String()).
+			Str("status", order.Status).
+			Msg("Order created from checkout session")
+	}
+
+	// The order now owns the stock, so the checkout hold is settled
+	if reference := checkoutSession.Metadata["reservation_ref"]; reference != "" {
+		if err := h.variantService.ConfirmStock(ctx, reference); err != nil {
+			h.logger.Error().Err(err).
+				Str("session_id", checkoutSession.ID).
+				Str("reservation_ref", reference).
+				Msg("Failed to confirm stock reservation")
+			return err
+		}
+	}
+
+	payload := events.StripeCheckoutEventPayload{
+		StripeID:      checkoutSession.ID,
+		CustomerID:    customer.ID.String(),
+		CustomerEmail: customer.Email,
+		PaymentStatus: string(checkoutSession.PaymentStatus),
+		Mode:          string(checkoutSession.Mode),
+		LineItems:     payloadItems,
+		Metadata:      checkoutSession.Metadata,
+		CreatedAt:     time.Unix(checkoutSession.Created, 0),
+	}
+	if details := checkoutSession.CustomerDetails; details != nil {
+		payload.CustomerDetails = &events.StripeCustomerDetails{
+			Email: details.Email,
+			Name:  details.Name,
+			Phone: details.Phone,
+		}
+		if details.Address != nil {
+			payload.CustomerDetails.Address = &events.StripeAddress{
+				Line1:      details.Address.Line1,
+				Line2:      details.Address.Line2,
+				City:       details.Address.City,
+				State:      details.Address.State,
+				PostalCode: details.Address.PostalCode,
+				Country:    details.Address.Country,
+			}
+		}
+	}
+
+	err = h.eventBus.Publish(events.TopicStripeCheckoutCompleted, payload)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("session_id", checkoutSession.ID).
+			Msg("Failed to publish checkout completed event")
+		// Don't return error since the order is already saved
+	}
+
+	h.logger.Info().
+		Str("topic", events.TopicStripeCheckoutCompleted).
+		Str("session_id", checkoutSession.ID).
+		Msg("Published checkout completed event")
+
 	return nil
 }
 
-func (h *StripeWebhookHandler) handleCheckoutSessionCompleted(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing checkout.session.completed")
-	return nil
-}
+// handleCheckoutSessionExpired releases anything held for an abandoned session
+func (h *StripeWebhookHandler) handleCheckoutSessionExpired(event stripe.Event) error {
+	var checkoutSession stripe.CheckoutSession
+	err := json.Unmarshal(event.Data.Raw, &checkoutSession)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe checkout session data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("session_id", checkoutSession.ID).
+		Msg("Processing Stripe checkout.session.expired event")
+
+	if reference := checkoutSession.Metadata["reservation_ref"]; reference != "" {
+		if err := h.variantService.ReleaseStock(context.Background(), reference); err != nil {
+			h.logger.Error().Err(err).
+				Str("session_id", checkoutSession.ID).
+				Str("reservation_ref", reference).
+				Msg("Failed to release stock reservation")
+			return err
+		}
+	}
+
+	return h.cancelCheckoutOrder(checkoutSession.ID, "checkout session expired")
+}
+
+// cancelCheckoutOrder cancels the still-pending order of a checkout session,
+// if it has one. Canceling a pending order puts its stock back
+func (h *StripeWebhookHandler) cancelCheckoutOrder(sessionID, reason string) error {
+	ctx := context.Background()
+
+	order, err := h.orderService.GetByCheckoutSessionID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			h.logger.Info().
+				Str("session_id", sessionID).
+				Msg("No order for checkout session, nothing to release")
+			return nil
+		}
+		return err
+	}
+
+	if order.Status != model.OrderStatusPending {
+		h.logger.Info().
+			Str("session_id", sessionID).
+			Str("order_id", order.ID.String()).
+			Str("status", order.Status).
+			Msg("Order is no longer pending, leaving it untouched")
+		return nil
+	}
+
+	_, err = h.orderService.Cancel(ctx, order.ID, reason)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("session_id", sessionID).
+			Str("order_id", order.ID.String()).
+			Msg("Failed to cancel checkout order")
+		return err
+	}
+
+	h.logger.Info().
+		Str("session_id", sessionID).
+		Str("order_id", order.ID.String()).
+		Str("reason", reason).
+		Msg("Canceled checkout order and released its stock")
+
+	return nil
+}
+
+// findOrCreateCheckoutCustomer resolves the customer of a checkout session by
+// Stripe customer ID, then by email, creating a local customer if neither matches
+func (h *StripeWebhookHandler) findOrCreateCheckoutCustomer(ctx context.Context, checkoutSession *stripe.CheckoutSession) (*model.Customer, error) {
+	stripeCustomerID := ""
+	if checkoutSession.Customer != nil {
+		stripeCustomerID = checkoutSession.Customer.ID
+	}
+
+	if stripeCustomerID != "" {
+		customer, err := h.customerRepo.GetByStripeID(ctx, stripeCustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to look up customer by Stripe ID: %w", err)
+		}
+		if customer != nil {
+			return customer, nil
+		}
+	}
+
+	details := checkoutSession.CustomerDetails
+	email := checkoutSession.CustomerEmail
+	if details != nil && details.Email != "" {
+		email = details.Email
+	}
+	if email == "" {
+		return nil, fmt.Errorf("checkout session %s has no customer or email", checkoutSession.ID)
+	}
+	email = strings.ToLower(email)
+
+	customer, err := h.customerRepo.GetByEmail(ctx, email)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up customer by email: %w", err)
+	}
+	if customer != nil {
+		// Link the existing customer to the Stripe customer created at checkout
+		if customer.StripeID == "" && stripeCustomerID != "" {
+			customer.StripeID = stripeCustomerID
+			if err := h.customerRepo.Update(ctx, customer); err != nil {
+				h.logger.Warn().Err(err).
+					Str("customer_id", customer.ID.String()).
+					Msg("Failed to link customer to Stripe customer")
+			}
+		}
+		return customer, nil
+	}
+
+	customer = &model.Customer{
+		ID:        uuid.New(),
+		Email:     email,
+		StripeID:  stripeCustomerID,
+		Active:    true,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}
+	if details != nil {
+		customer.PhoneNumber = details.Phone
+		firstName, lastName, _ := strings.Cut(strings.TrimSpace(details.Name), " ")
+		customer.FirstName = firstName
+		customer.LastName = strings.TrimSpace(lastName)
+	}
+
+	if err := h.customerRepo.Create(ctx, customer); err != nil {
+		h.logger.Error().Err(err).
+			Str("email", email).
+			Msg("Failed to create customer from checkout session")
+		return nil, err
+	}
+
+	payload := events.CustomerCreatedPayload{
+		CustomerID:  customer.ID.String(),
+		StripeID:    customer.StripeID,
+		Email:       customer.Email,
+		FirstName:   customer.FirstName,
+		LastName:    customer.LastName,
+		PhoneNumber: customer.PhoneNumber,
+		CreatedAt:   customer.CreatedAt,
+	}
+	if err := h.eventBus.Publish(events.TopicCustomerCreated, payload); err != nil {
+		h.logger.Error().Err(err).
+			Str("customer_id", customer.ID.String()).
+			Msg("Failed to publish customer created event")
+	}
+
+	h.logger.Info().
+		Str("customer_id", customer.ID.String()).
+		Str("stripe_customer_id", stripeCustomerID).
+		Msg("Created customer from checkout session")
 
-func (h *StripeWebhookHandler) handleCheckoutSessionExpired(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing checkout.session.expired")
-	return nil
+	return customer, nil
 }
 
 // Person handlers
@@ -288,6 +1038,16 @@ func (h *StripeWebhookHandler) handlePriceCreated(event stripe.Event) error {
 		return nil
 	}
 
+	// Variants created or repriced locally get their own Stripe price, which
+	// the variant already references
+	if existingVariant.StripePriceID == stripePrice.ID {
+		h.logger.Info().
+			Str("stripe_price_id", stripePrice.ID).
+			Str("variant_id", existingVariant.ID.String()).
+			Msg("Variant already uses this price, skipping creation")
+		return nil
+	}
+
 	// Get the parent product for the price record
 	parentProduct, err := h.productRepo.GetByID(ctx, existingVariant.ProductID)
 	if err != nil {
@@ -371,6 +1131,8 @@ func (h *StripeWebhookHandler) handlePriceCreated(event stripe.Event) error {
 		PriceType:       newPrice.Type,
 		Interval:        newPrice.Interval,
 		IntervalCount:   newPrice.IntervalCount,
+		Active:          existingVariant.Active,
+		OptionValues:    existingVariant.Options,
 		UpdatedAt:       time.Now(),
 		UpdateSource:    "stripe_webhook",
 	}
@@ -797,18 +1559,18 @@ func (h *StripeWebhookHandler) handleProductDeleted(event stripe.Event) error {
 	}
 
 	// Publish variant deleted event
-	payload := map[string]interface{}{
-		"variant_id":        existingVariant.ID.String(),
-		"product_id":        existingVariant.ProductID.String(),
-		"stripe_product_id": stripeProduct.ID,
-		"stripe_price_id":   existingVariant.StripePriceID,
-		"deleted_at":        time.Now(),
-		"delete_source":     "stripe_webhook",
+	payload := events.VariantDeletedPayload{
+		VariantID:       existingVariant.ID.String(),
+		ProductID:       existingVariant.ProductID.String(),
+		StripeProductID: stripeProduct.ID,
+		StripePriceID:   existingVariant.StripePriceID,
+		DeletedAt:       time.Now(),
+		DeleteSource:    "stripe_webhook",
 	}
 
 	// Include parent product info if available
 	if parentProduct != nil {
-		payload["product_name"] = parentProduct.Name
+		payload.ProductName = parentProduct.Name
 	}
 
 	err = h.eventBus.Publish(events.TopicVariantDeleted, payload)
@@ -901,6 +1663,7 @@ func (h *StripeWebhookHandler) handleProductUpdated(event stripe.Event) error {
 func (h *StripeWebhookHandler) updateVariantFromStripeProduct(ctx context.Context, variant *model.Variant, stripeProduct stripe.Product) error {
 	// Track what fields are being updated for logging
 	updatedFields := []string{}
+	var countedLevel *int
 
 	// Update basic variant fields
 	if variant.Active != stripeProduct.Active {
@@ -919,10 +1682,10 @@ func (h *StripeWebhookHandler) updateVariantFromStripeProduct(ctx context.Contex
 			}
 		}
 
-		// Update stock level if present in metadata
+		// Stock level changes go through the inventory ledger once the variant is saved
 		if val, ok := stripeProduct.Metadata["stock_level"]; ok {
 			if stockLevel, err := strconv.Atoi(val); err == nil && stockLevel != variant.StockLevel {
-				variant.StockLevel = stockLevel
+				countedLevel = &stockLevel
 				updatedFields = append(updatedFields, "stock_level")
 			}
 		}
@@ -976,6 +1739,25 @@ func (h *StripeWebhookHandler) updateVariantFromStripeProduct(ctx context.Contex
 		return fmt.Errorf("failed to update variant: %w", err)
 	}
 
+	if countedLevel != nil {
+		movement := &model.InventoryMovement{
+			ID:        uuid.New(),
+			VariantID: variant.ID,
+			Type:      model.MovementTypeAdjustment,
+			Reason:    "stock level set in Stripe",
+			Reference: stripeProduct.ID,
+			CreatedAt: variant.UpdatedAt,
+		}
+		if err := h.variantService.SetStockLevel(ctx, movement, *countedLevel); err != nil {
+			h.logger.Error().Err(err).
+				Str("variant_id", variant.ID.String()).
+				Str("stripe_product_id", stripeProduct.ID).
+				Msg("Failed to update variant stock level")
+			return fmt.Errorf("failed to update variant stock level: %w", err)
+		}
+		variant.StockLevel = movement.BalanceAfter
+	}
+
 	// Get parent product for event publishing
 	parentProduct, err := h.productRepo.GetByID(ctx, variant.ProductID)
 	if err != nil {
@@ -992,6 +1774,8 @@ func (h *StripeWebhookHandler) updateVariantFromStripeProduct(ctx context.Contex
 		PriceID:         variant.PriceID.String(),
 		StripeProductID: variant.StripeProductID,
 		StripePriceID:   variant.StripePriceID,
+		Active:          variant.Active,
+		OptionValues:    variant.Options,
 		UpdatedAt:       variant.UpdatedAt,
 		UpdateSource:    model.SyncSourceStripeWebhook,
 	}
@@ -1079,36 +1863,649 @@ func (h *StripeWebhookHandler) handleCustomerDeleted(event stripe.Event) error {
 	return nil
 }
 
+// handleSubscriptionCreated processes a customer.subscription.created webhook event
 func (h *StripeWebhookHandler) handleSubscriptionCreated(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing subscription.created")
-	return nil
+	return h.syncSubscription(event, events.TopicStripeSubscriptionCreated)
 }
 
+// handleSubscriptionUpdated processes a customer.subscription.updated webhook event
 func (h *StripeWebhookHandler) handleSubscriptionUpdated(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing subscription.updated")
-	return nil
+	return h.syncSubscription(event, events.TopicStripeSubscriptionUpdated)
 }
 
+// handleSubscriptionDeleted processes a customer.subscription.deleted webhook event.
+// Stripe sends this once the subscription has actually ended
 func (h *StripeWebhookHandler) handleSubscriptionDeleted(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing subscription.deleted")
+	return h.syncSubscription(event, events.TopicStripeSubscriptionCanceled)
+}
+
+// syncSubscription upserts our subscription row from a Stripe subscription
+// webhook and publishes the matching Stripe subscription event
+func (h *StripeWebhookHandler) syncSubscription(event stripe.Event, topic string) error {
+	var stripeSub stripe.Subscription
+	err := json.Unmarshal(event.Data.Raw, &stripeSub)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe subscription data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("stripe_subscription_id", stripeSub.ID).
+		Str("status", string(stripeSub.Status)).
+		Str("event_type", string(event.Type)).
+		Msg("Processing Stripe subscription event")
+
+	if stripeSub.Customer == nil || stripeSub.Items == nil || len(stripeSub.Items.Data) == 0 || stripeSub.Items.Data[0].Price == nil {
+		h.logger.Warn().
+			Str("stripe_subscription_id", stripeSub.ID).
+			Msg("Stripe subscription has no customer or price, skipping")
+		return nil
+	}
+
+	ctx := context.Background()
+	stripeItem := stripeSub.Items.Data[0]
+
+	customer, err := h.customerRepo.GetByStripeID(ctx, stripeSub.Customer.ID)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_customer_id", stripeSub.Customer.ID).
+			Msg("Error looking up customer")
+		return err
+	}
+	if customer == nil {
+		h.logger.Warn().
+			Str("stripe_subscription_id", stripeSub.ID).
+			Str("stripe_customer_id", stripeSub.Customer.ID).
+			Msg("Customer not found in database, cannot sync subscription")
+		return nil
+	}
+
+	price, err := h.priceRepo.GetByStripeID(ctx, stripeItem.Price.ID)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_price_id", stripeItem.Price.ID).
+			Msg("Error looking up price")
+		return err
+	}
+	if price == nil {
+		h.logger.Warn().
+			Str("stripe_subscription_id", stripeSub.ID).
+			Str("stripe_price_id", stripeItem.Price.ID).
+			Msg("Price not found in database, cannot sync subscription")
+		return nil
+	}
+
+	subscription, err := h.subscriptionRepo.GetByStripeID(ctx, stripeSub.ID)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_subscription_id", stripeSub.ID).
+			Msg("Error checking for existing subscription")
+		return err
+	}
+
+	isNew := subscription == nil
+	if isNew {
+		subscription = &model.Subscription{
+			ID:        uuid.New(),
+			StripeID:  stripeSub.ID,
+			Metadata:  map[string]string{},
+			CreatedAt: time.Unix(stripeSub.Created, 0),
+		}
+
+		// Subscriptions started through our API carry our ID in their metadata
+		if id, parseErr := uuid.Parse(stripeSub.Metadata["subscription_id"]); parseErr == nil {
+			subscription.ID = id
+		}
+		for k, v := range stripeSub.Metadata {
+			subscription.Metadata[k] = v
+		}
+	}
+
+	// We model a collection pause as our own status, while Stripe keeps it active
+	wasPaused := subscription.Status == model.SubscriptionStatusPaused
+
+	subscription.CustomerID = customer.ID
+	subscription.PriceID = price.ID
+	subscription.ProductID = price.ProductID
+	service.ApplyStripeSubscription(subscription, &stripeSub)
+
+	if wasPaused && stripeSub.Status == stripe.SubscriptionStatusActive && stripeSub.PauseCollection != nil {
+		subscription.Status = model.SubscriptionStatusPaused
+	}
+
+	if subscription.NextDeliveryDate.IsZero() {
+		subscription.NextDeliveryDate = subscription.CurrentPeriodEnd
+	}
+
+	if isNew {
+		if address, err := h.addressRepo.GetDefault(ctx, customer.ID); err == nil && address != nil {
+			subscription.AddressID = &address.ID
+		}
+		subscription.UpdatedAt = time.Now()
+		err = h.subscriptionRepo.Create(ctx, subscription)
+	} else {
+		err = h.subscriptionRepo.Update(ctx, subscription)
+	}
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_subscription_id", stripeSub.ID).
+			Bool("is_new", isNew).
+			Msg("Failed to save subscription from Stripe webhook")
+		return err
+	}
+
+	payload := events.StripeSubscriptionEventPayload{
+		StripeID:           stripeSub.ID,
+		CustomerID:         stripeSub.Customer.ID,
+		Status:             string(stripeSub.Status),
+		CurrentPeriodStart: subscription.CurrentPeriodStart,
+		CurrentPeriodEnd:   subscription.CurrentPeriodEnd,
+		CancelAtPeriodEnd:  stripeSub.CancelAtPeriodEnd,
+		CanceledAt:         subscription.CanceledAt,
+		Metadata:           stripeSub.Metadata,
+		CreatedAt:          time.Unix(stripeSub.Created, 0),
+		UpdatedAt:          subscription.UpdatedAt,
+	}
+	for _, item := range stripeSub.Items.Data {
+		payloadItem := events.StripeSubscriptionItem{
+			StripeID: item.ID,
+			Quantity: item.Quantity,
+		}
+		if item.Price != nil {
+			payloadItem.PriceID = item.Price.ID
+			if item.Price.Product != nil {
+				payloadItem.ProductID = item.Price.Product.ID
+			}
+		}
+		payload.Items = append(payload.Items, payloadItem)
+	}
+
+	err = h.eventBus.Publish(topic, payload)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_subscription_id", stripeSub.ID).
+			Msg("Failed to publish Stripe subscription event")
+		// Don't return error since the subscription is already saved
+	}
+
+	h.logger.Info().
+		Str("topic", topic).
+		Str("stripe_subscription_id", stripeSub.ID).
+		Str("subscription_id", subscription.ID.String()).
+		Str("status", subscription.Status).
+		Bool("created", isNew).
+		Msg("Successfully synced subscription from Stripe webhook")
+
 	return nil
 }
 
 func (h *StripeWebhookHandler) handleInvoiceCreated(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing invoice.created")
-	return nil
+	var stripeInvoice stripe.Invoice
+	err := json.Unmarshal(event.Data.Raw, &stripeInvoice)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe invoice data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("stripe_invoice_id", stripeInvoice.ID).
+		Str("billing_reason", string(stripeInvoice.BillingReason)).
+		Msg("Processing Stripe invoice.created event")
+
+	ctx := context.Background()
+
+	invoice, _, isNew, _, err := h.buildInvoice(ctx, &stripeInvoice)
+	if err != nil || invoice == nil {
+		return err
+	}
+
+	return h.saveInvoice(ctx, invoice, isNew)
 }
 
 func (h *StripeWebhookHandler) handleInvoicePaid(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing invoice.paid")
+	var stripeInvoice stripe.Invoice
+	err := json.Unmarshal(event.Data.Raw, &stripeInvoice)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe invoice data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("stripe_invoice_id", stripeInvoice.ID).
+		Str("billing_reason", string(stripeInvoice.BillingReason)).
+		Int64("amount_paid", stripeInvoice.AmountPaid).
+		Msg("Processing Stripe invoice.paid event")
+
+	ctx := context.Background()
+
+	invoice, subscription, isNew, wasPaid, err := h.buildInvoice(ctx, &stripeInvoice)
+	if err != nil || invoice == nil {
+		return err
+	}
+
+	// The invoice is saved as paid last, so a failed step is retried by Stripe
+	if !wasPaid && subscription != nil {
+		if err := h.dunningService.HandlePaymentSucceeded(ctx, subscription, invoice); err != nil {
+			h.logger.Error().Err(err).
+				Str("stripe_invoice_id", stripeInvoice.ID).
+				Msg("Failed to close dunning case for paid invoice")
+			return err
+		}
+
+		fulfilled := false
+		if stripeInvoice.BillingReason == stripe.InvoiceBillingReasonSubscriptionCreate {
+			fulfilled, err = h.fulfilledByCheckout(ctx, subscription)
+			if err != nil {
+				return err
+			}
+		}
+		if !fulfilled {
+			if err := h.renewSubscription(ctx, &stripeInvoice, invoice, subscription); err != nil {
+				return err
+			}
+		}
+	}
+
+	if err := h.saveInvoice(ctx, invoice, isNew); err != nil {
+		return err
+	}
+
+	h.publishInvoiceEvent(events.TopicStripeInvoicePaid, &stripeInvoice)
 	return nil
 }
 
 func (h *StripeWebhookHandler) handleInvoicePaymentFailed(event stripe.Event) error {
-	h.logger.Debug().Interface("data", event.Data).Msg("Stub: Processing invoice.payment_failed")
+	var stripeInvoice stripe.Invoice
+	err := json.Unmarshal(event.Data.Raw, &stripeInvoice)
+	if err != nil {
+		h.logger.Error().Err(err).Msg("Failed to unmarshal Stripe invoice data")
+		return err
+	}
+
+	h.logger.Info().
+		Str("stripe_invoice_id", stripeInvoice.ID).
+		Int64("attempt_count", stripeInvoice.AttemptCount).
+		Msg("Processing Stripe invoice.payment_failed event")
+
+	ctx := context.Background()
+
+	invoice, subscription, isNew, _, err := h.buildInvoice(ctx, &stripeInvoice)
+	if err != nil || invoice == nil {
+		return err
+	}
+
+	if err := h.saveInvoice(ctx, invoice, isNew); err != nil {
+		return err
+	}
+
+	if subscription != nil {
+		if _, err := h.dunningService.HandlePaymentFailed(ctx, subscription, invoice); err != nil {
+			h.logger.Error().Err(err).
+				Str("stripe_invoice_id", stripeInvoice.ID).
+				Str("subscription_id", subscription.ID.String()).
+				Msg("Failed to start dunning for failed invoice")
+			return err
+		}
+	}
+
+	h.publishInvoiceEvent(events.TopicStripeInvoicePaymentFailed, &stripeInvoice)
+	return nil
+}
+
+// buildInvoice loads our copy of a Stripe invoice, or starts a new one, and
+// applies the Stripe state to it without saving. It returns a nil invoice when
+// the customer is unknown to us
+func (h *StripeWebhookHandler) buildInvoice(ctx context.Context, stripeInvoice *stripe.Invoice) (invoice *model.Invoice, subscription *model.Subscription, isNew bool, wasPaid bool, err error) {
+	if stripeInvoice.Customer == nil {
+		h.logger.Warn().
+			Str("stripe_invoice_id", stripeInvoice.ID).
+			Msg("Stripe invoice has no customer, skipping")
+		return nil, nil, false, false, nil
+	}
+
+	customer, err := h.customerRepo.GetByStripeID(ctx, stripeInvoice.Customer.ID)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_customer_id", stripeInvoice.Customer.ID).
+			Msg("Error looking up customer")
+		return nil, nil, false, false, err
+	}
+	if customer == nil {
+		h.logger.Warn().
+			Str("stripe_invoice_id", stripeInvoice.ID).
+			Str("stripe_customer_id", stripeInvoice.Customer.ID).
+			Msg("Customer not found in database, cannot sync invoice")
+		return nil, nil, false, false, nil
+	}
+
+	if stripeSubID := invoiceSubscriptionID(stripeInvoice); stripeSubID != "" {
+		subscription, err = h.subscriptionRepo.GetByStripeID(ctx, stripeSubID)
+		if err != nil {
+			h.logger.Error().Err(err).
+				Str("stripe_subscription_id", stripeSubID).
+				Msg("Error looking up subscription")
+			return nil, nil, false, false, err
+		}
+		if subscription == nil {
+			h.logger.Warn().
+				Str("stripe_invoice_id", stripeInvoice.ID).
+				Str("stripe_subscription_id", stripeSubID).
+				Msg("Subscription not found in database, invoice saved without it")
+		}
+	}
+
+	invoice, err = h.invoiceRepo.GetByStripeID(ctx, stripeInvoice.ID)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_invoice_id", stripeInvoice.ID).
+			Msg("Error checking for existing invoice")
+		return nil, nil, false, false, err
+	}
+
+	isNew = invoice == nil
+	if isNew {
+		invoice = &model.Invoice{
+			ID:        uuid.New(),
+			StripeID:  stripeInvoice.ID,
+			CreatedAt: time.Unix(stripeInvoice.Created, 0),
+		}
+	}
+	wasPaid = invoice.Status == model.InvoiceStatusPaid
+
+	invoice.CustomerID = customer.ID
+	if subscription != nil {
+		invoice.SubscriptionID = &subscription.ID
+	}
+	if stripeInvoice.Status != "" {
+		invoice.Status = string(stripeInvoice.Status)
+	}
+	if invoice.Status == "" {
+		invoice.Status = model.InvoiceStatusDraft
+	}
+	invoice.BillingReason = string(stripeInvoice.BillingReason)
+	invoice.Currency = strings.ToUpper(string(stripeInvoice.Currency))
+	invoice.AmountDue = stripeInvoice.AmountDue
+	invoice.AmountPaid = stripeInvoice.AmountPaid
+	invoice.HostedInvoiceURL = stripeInvoice.HostedInvoiceURL
+	invoice.AttemptCount = int(stripeInvoice.AttemptCount)
+
+	invoice.NextPaymentAttempt = nil
+	if stripeInvoice.NextPaymentAttempt > 0 {
+		next := time.Unix(stripeInvoice.NextPaymentAttempt, 0)
+		invoice.NextPaymentAttempt = &next
+	}
+
+	// A renewal invoice's own period is the one just ended; its lines carry
+	// the period being paid for
+	if stripeInvoice.PeriodStart > 0 {
+		invoice.PeriodStart = time.Unix(stripeInvoice.PeriodStart, 0)
+		invoice.PeriodEnd = time.Unix(stripeInvoice.PeriodEnd, 0)
+	}
+	if stripeInvoice.Lines != nil && len(stripeInvoice.Lines.Data) > 0 && stripeInvoice.Lines.Data[0].Period != nil {
+		invoice.PeriodStart = time.Unix(stripeInvoice.Lines.Data[0].Period.Start, 0)
+		invoice.PeriodEnd = time.Unix(stripeInvoice.Lines.Data[0].Period.End, 0)
+	}
+
+	if invoice.Status == model.InvoiceStatusPaid && invoice.PaidAt == nil {
+		paidAt := time.Now()
+		if stripeInvoice.StatusTransitions != nil && stripeInvoice.StatusTransitions.PaidAt > 0 {
+			paidAt = time.Unix(stripeInvoice.StatusTransitions.PaidAt, 0)
+		}
+		invoice.PaidAt = &paidAt
+	}
+
+	return invoice, subscription, isNew, wasPaid, nil
+}
+
+// saveInvoice creates or updates our copy of a Stripe invoice
+func (h *StripeWebhookHandler) saveInvoice(ctx context.Context, invoice *model.Invoice, isNew bool) error {
+	var err error
+	if isNew {
+		invoice.UpdatedAt = time.Now()
+		err = h.invoiceRepo.Create(ctx, invoice)
+	} else {
+		err = h.invoiceRepo.Update(ctx, invoice)
+	}
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_invoice_id", invoice.StripeID).
+			Bool("is_new", isNew).
+			Msg("Failed to save invoice from Stripe webhook")
+		return err
+	}
+
+	h.logger.Info().
+		Str("stripe_invoice_id", invoice.StripeID).
+		Str("invoice_id", invoice.ID.String()).
+		Str("status", invoice.Status).
+		Bool("created", isNew).
+		Msg("Successfully synced invoice from Stripe webhook")
+
+	return nil
+}
+
+// fulfilledByCheckout reports whether a subscription's first invoice is covered
+// by the order of the Checkout session that created it. Subscriptions created
+// through the API have no session, so their first invoice needs an order too
+func (h *StripeWebhookHandler) fulfilledByCheckout(ctx context.Context, subscription *model.Subscription) (bool, error) {
+	checkoutSession, err := h.stripeService.GetCheckoutSessionForSubscription(subscription.StripeID)
+	if err != nil {
+		return false, err
+	}
+	if checkoutSession == nil {
+		return false, nil
+	}
+
+	if _, err := h.orderService.GetByCheckoutSessionID(ctx, checkoutSession.ID); err != nil {
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			// checkout.session.completed hasn't been processed yet; retry until it has
+			return false, fmt.Errorf("checkout session %s for subscription %s has no order yet", checkoutSession.ID, subscription.StripeID)
+		}
+		h.logger.Error().Err(err).
+			Str("session_id", checkoutSession.ID).
+			Msg("Error looking up checkout order")
+		return false, err
+	}
+
+	return true, nil
+}
+
+// renewSubscription creates the delivery order for a paid subscription invoice and
+// moves the subscription's next delivery date to the end of the paid period
+func (h *StripeWebhookHandler) renewSubscription(ctx context.Context, stripeInvoice *stripe.Invoice, invoice *model.Invoice, subscription *model.Subscription) error {
+	renewal := &dto.RenewalOrderDTO{
+		CustomerID:      subscription.CustomerID,
+		SubscriptionID:  subscription.ID,
+		AddressID:       subscription.AddressID,
+		StripeInvoiceID: stripeInvoice.ID,
+		Currency:        string(stripeInvoice.Currency),
+		Total:           stripeInvoice.AmountPaid,
+		Metadata:        map[string]string{"billing_reason": string(stripeInvoice.BillingReason)},
+	}
+
+	if stripeInvoice.Lines != nil {
+		for _, line := range stripeInvoice.Lines.Data {
+			// Prorations adjust the amount charged but ship nothing
+			if line.Parent != nil && line.Parent.SubscriptionItemDetails != nil && line.Parent.SubscriptionItemDetails.Proration {
+				continue
+			}
+			if line.Pricing == nil || line.Pricing.PriceDetails == nil || line.Pricing.PriceDetails.Product == "" {
+				h.logger.Warn().
+					Str("stripe_invoice_id", stripeInvoice.ID).
+					Str("line_item_id", line.ID).
+					Msg("Invoice line has no product, skipping")
+				continue
+			}
+
+			// Stripe products map to our variants
+			variant, err := h.variantRepo.GetByStripeID(ctx, line.Pricing.PriceDetails.Product)
+			if err != nil {
+				h.logger.Error().Err(err).
+					Str("stripe_product_id", line.Pricing.PriceDetails.Product).
+					Msg("Error looking up variant")
+				return err
+			}
+			if variant == nil {
+				h.logger.Warn().
+					Str("stripe_invoice_id", stripeInvoice.ID).
+					Str("stripe_product_id", line.Pricing.PriceDetails.Product).
+					Msg("Variant not found for invoice line, skipping")
+				continue
+			}
+
+			unitAmount := line.Amount
+			if line.Quantity > 0 {
+				unitAmount = line.Amount / line.Quantity
+			}
+
+			renewal.Items = append(renewal.Items, dto.CheckoutOrderItemDTO{
+				VariantID:  variant.ID,
+				Quantity:   int(line.Quantity),
+				UnitAmount: unitAmount,
+			})
+		}
+	}
+
+	if len(renewal.Items) == 0 {
+		h.logger.Warn().
+			Str("stripe_invoice_id", stripeInvoice.ID).
+			Str("subscription_id", subscription.ID.String()).
+			Msg("No invoice lines could be matched to variants, renewal order not created")
+	} else {
+		order, err := h.orderService.CreateRenewal(ctx, renewal)
+		if err != nil {
+			h.logger.Error().Err(err).
+				Str("stripe_invoice_id", stripeInvoice.ID).
+				Msg("Failed to create renewal order from invoice")
+			return err
+		}
+		invoice.OrderID = &order.ID
+
+		h.logger.Info().
+			Str("stripe_invoice_id", stripeInvoice.ID).
+			Str("order_id", order.ID.String()).
+			Msg("Renewal order created from invoice")
+	}
+
+	// The paid period ends when the next delivery is due; fall back to one
+	// interval after the invoice was created if Stripe didn't tell us the
+	// period. Both only depend on the invoice, so a redelivery lands on the
+	// same date instead of moving it on again
+	next := invoice.PeriodEnd
+	if next.IsZero() {
+		base := time.Unix(stripeInvoice.Created, 0)
+		price, err := h.priceRepo.GetByID(ctx, subscription.PriceID)
+		if err != nil {
+			h.logger.Error().Err(err).
+				Str("price_id", subscription.PriceID.String()).
+				Msg("Error looking up subscription price")
+			return err
+		}
+		if price != nil {
+			next = service.AddBillingInterval(base, price.Interval, price.IntervalCount)
+		} else {
+			next = service.AddBillingInterval(base, "month", 1)
+		}
+	}
+
+	if next.After(subscription.NextDeliveryDate) {
+		subscription.NextDeliveryDate = next
+		if err := h.subscriptionRepo.Update(ctx, subscription); err != nil {
+			h.logger.Error().Err(err).
+				Str("subscription_id", subscription.ID.String()).
+				Msg("Failed to advance subscription delivery date")
+			return err
+		}
+	}
+
+	payload := events.SubscriptionRenewedPayload{
+		SubscriptionID:   subscription.ID.String(),
+		CustomerID:       subscription.CustomerID.String(),
+		StripeID:         subscription.StripeID,
+		StripeInvoiceID:  stripeInvoice.ID,
+		AmountPaid:       stripeInvoice.AmountPaid,
+		Currency:         invoice.Currency,
+		PeriodStart:      invoice.PeriodStart,
+		PeriodEnd:        invoice.PeriodEnd,
+		NextDeliveryDate: subscription.NextDeliveryDate,
+		RenewedAt:        time.Now(),
+	}
+	if invoice.OrderID != nil {
+		payload.OrderID = invoice.OrderID.String()
+	}
+
+	err := h.eventBus.Publish(events.TopicSubscriptionRenewed, payload)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("subscription_id", subscription.ID.String()).
+			Msg("Failed to publish subscription renewed event")
+		// Don't return error since the renewal is already saved
+	}
+
+	h.logger.Info().
+		Str("topic", events.TopicSubscriptionRenewed).
+		Str("subscription_id", subscription.ID.String()).
+		Time("next_delivery_date", subscription.NextDeliveryDate).
+		Msg("Published subscription renewed event")
+
 	return nil
 }
 
+// publishInvoiceEvent publishes a Stripe invoice event
+func (h *StripeWebhookHandler) publishInvoiceEvent(topic string, stripeInvoice *stripe.Invoice) {
+	payload := events.StripeInvoiceEventPayload{
+		StripeID:         stripeInvoice.ID,
+		SubscriptionID:   invoiceSubscriptionID(stripeInvoice),
+		Status:           string(stripeInvoice.Status),
+		AmountDue:        stripeInvoice.AmountDue,
+		AmountPaid:       stripeInvoice.AmountPaid,
+		Currency:         string(stripeInvoice.Currency),
+		Paid:             stripeInvoice.Status == stripe.InvoiceStatusPaid,
+		Attempted:        stripeInvoice.Attempted,
+		AttemptCount:     stripeInvoice.AttemptCount,
+		BillingReason:    string(stripeInvoice.BillingReason),
+		HostedInvoiceURL: stripeInvoice.HostedInvoiceURL,
+		Created:          time.Unix(stripeInvoice.Created, 0),
+	}
+	if stripeInvoice.Customer != nil {
+		payload.CustomerID = stripeInvoice.Customer.ID
+	}
+
+	err := h.eventBus.Publish(topic, payload)
+	if err != nil {
+		h.logger.Error().Err(err).
+			Str("stripe_invoice_id", stripeInvoice.ID).
+			Msg("Failed to publish Stripe invoice event")
+		// Don't return error since the invoice is already saved
+		return
+	}
+
+	h.logger.Info().
+		Str("topic", topic).
+		Str("stripe_invoice_id", stripeInvoice.ID).
+		Msg("Published Stripe invoice event")
+}
+
+// invoiceSubscriptionID returns the Stripe subscription an invoice bills for, if any
+func invoiceSubscriptionID(stripeInvoice *stripe.Invoice) string {
+	if parent := stripeInvoice.Parent; parent != nil && parent.SubscriptionDetails != nil && parent.SubscriptionDetails.Subscription != nil {
+		return parent.SubscriptionDetails.Subscription.ID
+	}
+
+	if stripeInvoice.Lines != nil {
+		for _, line := range stripeInvoice.Lines.Data {
+			if line.Subscription != nil {
+				return line.Subscription.ID
+			}
+			if line.Parent != nil && line.Parent.SubscriptionItemDetails != nil && line.Parent.SubscriptionItemDetails.Subscription != "" {
+				return line.Parent.SubscriptionItemDetails.Subscription
+			}
+		}
+	}
+
+	return ""
+}
+
 // Helper function to handle fetching and creating a product from Stripe
 func (h *StripeWebhookHandler) fetchAndCreateProduct(stripeProductID string) error {
 	// In a real implementation, you would:
@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"fmt"
+	"testing"
+)
+
+func TestWebhookPartition(t *testing.T) {
+	const partitions = 8
+
+	t.Run("same object maps to the same partition", func(t *testing.T) {
+		first := webhookPartition("sub_123", "evt_1", partitions)
+		for i := 2; i <= 20; i++ {
+			if got := webhookPartition("sub_123", fmt.Sprintf("evt_%d", i), partitions); got != first {
+				t.Fatalf("event %d for sub_123 went to partition %d, want %d", i, got, first)
+			}
+		}
+	})
+
+	t.Run("events without an object use the event ID", func(t *testing.T) {
+		if got, want := webhookPartition("", "evt_1", partitions), webhookPartition("evt_1", "", partitions); got != want {
+			t.Errorf("webhookPartition(\"\", \"evt_1\") = %d, want %d", got, want)
+		}
+	})
+
+	t.Run("partitions stay in range and are all used", func(t *testing.T) {
+		used := make(map[int]bool)
+		for i := 0; i < 1000; i++ {
+			p := webhookPartition(fmt.Sprintf("cs_%d", i), "", partitions)
+			if p < 0 || p >= partitions {
+				t.Fatalf("partition %d out of range [0, %d)", p, partitions)
+			}
+			used[p] = true
+		}
+		if len(used) != partitions {
+			t.Errorf("used %d of %d partitions", len(used), partitions)
+		}
+	})
+
+	t.Run("single partition", func(t *testing.T) {
+		if got := webhookPartition("sub_123", "evt_1", 1); got != 0 {
+			t.Errorf("webhookPartition with one partition = %d, want 0", got)
+		}
+	})
+}
+
+func TestWebhookPartitionStable(t *testing.T) {
+	// Changing the hash would reorder events queued before a deploy
+	tests := []struct {
+		objectID string
+		want     int
+	}{
+		{"sub_123", webhookPartitionFNV("sub_123", 16)},
+		{"cs_test_a1", webhookPartitionFNV("cs_test_a1", 16)},
+		{"in_1Nx", webhookPartitionFNV("in_1Nx", 16)},
+	}
+
+	for _, tt := range tests {
+		if got := webhookPartition(tt.objectID, "evt_1", 16); got != tt.want {
+			t.Errorf("webhookPartition(%q) = %d, want %d", tt.objectID, got, tt.want)
+		}
+	}
+}
+
+// webhookPartitionFNV is FNV-1a written out, independent of hash/fnv
+func webhookPartitionFNV(key string, partitions int) int {
+	hash := uint32(2166136261)
+	for i := 0; i < len(key); i++ {
+		hash ^= uint32(key[i])
+		hash *= 16777619
+	}
+	return int(hash % uint32(partitions))
+}
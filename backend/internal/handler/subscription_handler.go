@@ -0,0 +1,410 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
+	"github.com/google/uuid"
+
+	"github.com/labstack/echo/v4"
+	"github.com/rs/zerolog"
+)
+
+type SubscriptionHandler interface {
+	List(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Pause(c echo.Context) error
+	Resume(c echo.Context) error
+	Skip(c echo.Context) error
+	Cancel(c echo.Context) error
+}
+
+// subscriptionHandler handles HTTP requests for subscriptions
+type subscriptionHandler struct {
+	logger              zerolog.Logger
+	subscriptionService interfaces.SubscriptionService
+}
+
+// NewSubscriptionHandler creates a new subscription handler
+func NewSubscriptionHandler(logger *zerolog.Logger, subscriptionService interfaces.SubscriptionService) *subscriptionHandler {
+	sublogger := logger.With().Str("component", "subscription_handler").Logger()
+	return &subscriptionHandler{
+		logger:              sublogger,
+		subscriptionService: subscriptionService,
+	}
+}
+
+// parseID extracts the subscription ID from the URL.
+// On failure the 400 response has already been written and ok is false
+func (h *subscriptionHandler) parseID(c echo.Context, requestID string) (id uuid.UUID, ok bool, err error) {
+	id, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		h.logger.Warn().
+			Err(parseErr).
+			Str("request_id", requestID).
+			Str("id_param", c.Param("id")).
+			Msg("Invalid subscription ID format")
+
+		return id, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid subscription ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	return id, true, nil
+}
+
+// errorResponse maps service errors to HTTP responses
+func (h *subscriptionHandler) errorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Subscription not found",
+			Code:    "SUBSCRIPTION_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidState):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Status:  http.StatusConflict,
+			Message: err.Error(),
+			Code:    "INVALID_SUBSCRIPTION_STATE",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	case errors.Is(err, postgres.ErrDatabaseConnection):
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Status:  http.StatusServiceUnavailable,
+			Message: "Service temporarily unavailable, please try again later",
+			Code:    "SERVICE_UNAVAILABLE",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action + " subscription",
+			Code:    "INTERNAL_ERROR",
+		})
+	}
+}
+
+// List handles GET /api/v1/subscriptions?customer_id=...&status=...
+func (h *subscriptionHandler) List(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "SubscriptionHandler.List").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling subscription listing request")
+
+	params := NewParams(c)
+	status := c.QueryParam("status")
+
+	var customerID *uuid.UUID
+	if customerParam := c.QueryParam("customer_id"); customerParam != "" {
+		id, err := uuid.Parse(customerParam)
+		if err != nil {
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid customer ID format",
+				Code:    "INVALID_ID_FORMAT",
+			})
+		}
+		customerID = &id
+	}
+
+	subscriptions, total, err := h.subscriptionService.List(ctx, params.Offset, params.PerPage, customerID, status)
+	if err != nil {
+		h.logger.Error().
+			Str("handler", "SubscriptionHandler.List").
+			Str("request_id", requestID).
+			Err(err).
+			Int("offset", params.Offset).
+			Int("per_page", params.PerPage).
+			Msg("Failed to retrieve subscriptions from service")
+		return echo.NewHTTPError(http.StatusInternalServerError, "Failed to retrieve subscriptions")
+	}
+
+	meta := NewMeta(params, total)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.List").
+		Str("request_id", requestID).
+		Int("subscriptions_count", len(subscriptions)).
+		Int("total_count", total).
+		Int("page", params.Page).
+		Int("per_page", params.PerPage).
+		Msg("Subscription listing successfully returned")
+
+	return c.JSON(http.StatusOK, Response(subscriptions, meta))
+}
+
+// Create handles POST /api/v1/subscriptions
+func (h *subscriptionHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.Create").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling subscription creation request")
+
+	var subscriptionDTO dto.SubscriptionCreateDTO
+	if err := c.Bind(&subscriptionDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := subscriptionDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Subscription validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	subscription, err := h.subscriptionService.Create(ctx, &subscriptionDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("customer_id", subscriptionDTO.CustomerID.String()).
+			Msg("Failed to create subscription")
+		return h.errorResponse(c, err, "create")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":      "Subscription created successfully",
+		"subscription": subscription,
+	})
+}
+
+// Get handles GET /api/v1/subscriptions/:id
+func (h *subscriptionHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.Get").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling get subscription request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	subscription, err := h.subscriptionService.GetByID(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("subscription_id", id.String()).
+			Msg("Failed to retrieve subscription")
+		return h.errorResponse(c, err, "retrieve")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"subscription": subscription,
+	})
+}
+
+// Pause handles POST /api/v1/subscriptions/:id/pause
+func (h *subscriptionHandler) Pause(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.Pause").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling subscription pause request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	subscription, err := h.subscriptionService.Pause(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("subscription_id", id.String()).
+			Msg("Failed to pause subscription")
+		return h.errorResponse(c, err, "pause")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Subscription paused successfully",
+		"subscription": subscription,
+	})
+}
+
+// Resume handles POST /api/v1/subscriptions/:id/resume
+func (h *subscriptionHandler) Resume(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.Resume").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling subscription resume request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	subscription, err := h.subscriptionService.Resume(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("subscription_id", id.String()).
+			Msg("Failed to resume subscription")
+		return h.errorResponse(c, err, "resume")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Subscription resumed successfully",
+		"subscription": subscription,
+	})
+}
+
+// Skip handles POST /api/v1/subscriptions/:id/skip
+func (h *subscriptionHandler) Skip(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.Skip").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling skip next delivery request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	subscription, err := h.subscriptionService.SkipNextDelivery(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("subscription_id", id.String()).
+			Msg("Failed to skip next delivery")
+		return h.errorResponse(c, err, "skip delivery for")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Next delivery skipped successfully",
+		"subscription": subscription,
+	})
+}
+
+// Cancel handles POST /api/v1/subscriptions/:id/cancel
+func (h *subscriptionHandler) Cancel(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "SubscriptionHandler.Cancel").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling subscription cancel request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	// The body is optional; an empty body cancels immediately
+	var cancelDTO dto.SubscriptionCancelDTO
+	if c.Request().ContentLength > 0 {
+		if err := c.Bind(&cancelDTO); err != nil {
+			h.logger.Warn().
+				Err(err).
+				Str("request_id", requestID).
+				Msg("Failed to parse request body")
+
+			return c.JSON(http.StatusBadRequest, ErrorResponse{
+				Status:  http.StatusBadRequest,
+				Message: "Invalid request format",
+				Code:    "INVALID_FORMAT",
+			})
+		}
+	}
+
+	validationErrors := cancelDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	subscription, err := h.subscriptionService.Cancel(ctx, id, &cancelDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("subscription_id", id.String()).
+			Msg("Failed to cancel subscription")
+		return h.errorResponse(c, err, "cancel")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message":      "Subscription canceled successfully",
+		"subscription": subscription,
+	})
+}
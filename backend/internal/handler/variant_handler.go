@@ -2,9 +2,13 @@
 package handler
 
 import (
+	"errors"
 	"net/http"
 
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
 	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/service"
 	"github.com/google/uuid"
 	"github.com/labstack/echo/v4"
 	"github.com/rs/zerolog"
@@ -12,22 +16,96 @@ import (
 
 type VariantHandler interface {
 	ListByProduct(c echo.Context) error
+	PreviewRegeneration(c echo.Context) error
+	Regenerate(c echo.Context) error
+	Create(c echo.Context) error
+	Get(c echo.Context) error
+	Update(c echo.Context) error
+	Delete(c echo.Context) error
+	UpdateStockLevel(c echo.Context) error
+	CreateStockMovement(c echo.Context) error
+	StockHistory(c echo.Context) error
+	SetReorderThreshold(c echo.Context) error
+	LowStock(c echo.Context) error
 }
 
 // variantHandler handles HTTP requests for variants
 type variantHandler struct {
-	logger      zerolog.Logger
-	variantRepo interfaces.VariantRepository
-	productRepo interfaces.ProductRepository
+	logger         zerolog.Logger
+	variantService interfaces.VariantService
+	variantRepo    interfaces.VariantRepository
+	productRepo    interfaces.ProductRepository
 }
 
 // NewVariantHandler creates a new variant handler
-func NewVariantHandler(logger *zerolog.Logger, variantRepo interfaces.VariantRepository, productRepo interfaces.ProductRepository) *variantHandler {
+func NewVariantHandler(logger *zerolog.Logger, variantService interfaces.VariantService, variantRepo interfaces.VariantRepository, productRepo interfaces.ProductRepository) *variantHandler {
 	sublogger := logger.With().Str("component", "variant_handler").Logger()
 	return &variantHandler{
-		logger:      sublogger,
-		variantRepo: variantRepo,
-		productRepo: productRepo,
+		logger:         sublogger,
+		variantService: variantService,
+		variantRepo:    variantRepo,
+		productRepo:    productRepo,
+	}
+}
+
+// parseID extracts the variant ID from the URL.
+// On failure the 400 response has already been written and ok is false
+func (h *variantHandler) parseID(c echo.Context, requestID string) (id uuid.UUID, ok bool, err error) {
+	id, parseErr := uuid.Parse(c.Param("id"))
+	if parseErr != nil {
+		h.logger.Warn().
+			Err(parseErr).
+			Str("request_id", requestID).
+			Str("id_param", c.Param("id")).
+			Msg("Invalid variant ID format")
+
+		return id, false, c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid variant ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	return id, true, nil
+}
+
+// errorResponse maps service errors to HTTP responses
+func (h *variantHandler) errorResponse(c echo.Context, err error, action string) error {
+	switch {
+	case errors.Is(err, postgres.ErrResourceNotFound):
+		return c.JSON(http.StatusNotFound, ErrorResponse{
+			Status:  http.StatusNotFound,
+			Message: "Variant not found",
+			Code:    "VARIANT_NOT_FOUND",
+		})
+
+	case errors.Is(err, service.ErrInvalidState):
+		return c.JSON(http.StatusConflict, ErrorResponse{
+			Status:  http.StatusConflict,
+			Message: err.Error(),
+			Code:    "INVALID_VARIANT_STATE",
+		})
+
+	case errors.Is(err, service.ErrInvalidInput):
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: err.Error(),
+			Code:    "INVALID_INPUT",
+		})
+
+	case errors.Is(err, postgres.ErrDatabaseConnection):
+		return c.JSON(http.StatusServiceUnavailable, ErrorResponse{
+			Status:  http.StatusServiceUnavailable,
+			Message: "Service temporarily unavailable, please try again later",
+			Code:    "SERVICE_UNAVAILABLE",
+		})
+
+	default:
+		return c.JSON(http.StatusInternalServerError, ErrorResponse{
+			Status:  http.StatusInternalServerError,
+			Message: "Failed to " + action,
+			Code:    "INTERNAL_ERROR",
+		})
 	}
 }
 
@@ -121,3 +199,484 @@ func (h *variantHandler) ListByProduct(c echo.Context) error {
 		},
 	})
 }
+
+// PreviewRegeneration handles GET /api/v1/products/:id/variants/regeneration
+func (h *variantHandler) PreviewRegeneration(c echo.Context) error {
+	return h.regenerate(c, true)
+}
+
+// Regenerate handles POST /api/v1/products/:id/variants/regenerate
+func (h *variantHandler) Regenerate(c echo.Context) error {
+	return h.regenerate(c, false)
+}
+
+// regenerate plans, and unless dryRun is set applies, a product's variant regeneration
+func (h *variantHandler) regenerate(c echo.Context, dryRun bool) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.Regenerate").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Bool("dry_run", dryRun).
+		Msg("Handling variant regeneration request")
+
+	productID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid product ID format",
+			Code:    "INVALID_ID_FORMAT",
+		})
+	}
+
+	plan, err := h.variantService.RegenerateVariants(ctx, productID, dryRun)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("product_id", productID.String()).
+			Bool("dry_run", dryRun).
+			Msg("Failed to regenerate variants")
+
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			return c.JSON(http.StatusNotFound, ErrorResponse{
+				Status:  http.StatusNotFound,
+				Message: "Product not found",
+				Code:    "PRODUCT_NOT_FOUND",
+			})
+		}
+		return h.errorResponse(c, err, "regenerate variants")
+	}
+
+	return c.JSON(http.StatusOK, plan)
+}
+
+// Create handles POST /api/v1/variants
+func (h *variantHandler) Create(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.Create").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling variant creation request")
+
+	var createDTO dto.VariantCreateDTO
+	if err := c.Bind(&createDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := createDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Variant validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	variant, err := h.variantService.Create(ctx, &createDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("product_id", createDTO.ProductID.String()).
+			Msg("Failed to create variant")
+		return h.errorResponse(c, err, "create variant")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message": "Variant created successfully",
+		"variant": variant,
+	})
+}
+
+// Get handles GET /api/v1/variants/:id
+func (h *variantHandler) Get(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.Get").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling get variant request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	variant, err := h.variantService.GetByID(ctx, id)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to retrieve variant")
+		return h.errorResponse(c, err, "retrieve variant")
+	}
+
+	return c.JSON(http.StatusOK, variant)
+}
+
+// Update handles PUT /api/v1/variants/:id
+func (h *variantHandler) Update(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.Update").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling variant update request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	var updateDTO dto.VariantUpdateDTO
+	if err := c.Bind(&updateDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := updateDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Variant validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	variant, err := h.variantService.Update(ctx, id, &updateDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to update variant")
+		return h.errorResponse(c, err, "update variant")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Variant updated successfully",
+		"variant": variant,
+	})
+}
+
+// Delete handles DELETE /api/v1/variants/:id
+func (h *variantHandler) Delete(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.Delete").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling variant deletion request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	if err := h.variantService.Delete(ctx, id); err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to delete variant")
+		return h.errorResponse(c, err, "delete variant")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"success": true,
+		"message": "Variant successfully deleted",
+	})
+}
+
+// UpdateStockLevel handles PUT /api/v1/variants/:id/stock
+func (h *variantHandler) UpdateStockLevel(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.UpdateStockLevel").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling variant stock level request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	var stockDTO dto.VariantStockLevelDTO
+	if err := c.Bind(&stockDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := stockDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	if err := h.variantService.UpdateStockLevel(ctx, id, *stockDTO.StockLevel); err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to update variant stock level")
+		return h.errorResponse(c, err, "update stock level")
+	}
+
+	variant, err := h.variantService.GetByID(ctx, id)
+	if err != nil {
+		return h.errorResponse(c, err, "retrieve variant")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Stock level updated successfully",
+		"variant": variant,
+	})
+}
+
+// CreateStockMovement handles POST /api/v1/variants/:id/stock-movements
+func (h *variantHandler) CreateStockMovement(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.CreateStockMovement").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling stock movement request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	var movementDTO dto.StockMovementCreateDTO
+	if err := c.Bind(&movementDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := movementDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		h.logger.Warn().
+			Interface("validation_errors", validationErrors).
+			Str("request_id", requestID).
+			Msg("Stock movement validation failed")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	movement, err := h.variantService.RecordStockMovement(ctx, id, &movementDTO)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to record stock movement")
+		return h.errorResponse(c, err, "record stock movement")
+	}
+
+	return c.JSON(http.StatusCreated, map[string]interface{}{
+		"message":  "Stock movement recorded successfully",
+		"movement": movement,
+	})
+}
+
+// StockHistory handles GET /api/v1/variants/:id/stock-movements
+func (h *variantHandler) StockHistory(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "VariantHandler.StockHistory").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling stock history request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	params := NewParams(c)
+	movements, total, err := h.variantService.GetStockHistory(ctx, id, params.Offset, params.PerPage)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to retrieve stock history")
+		return h.errorResponse(c, err, "retrieve stock history")
+	}
+
+	return c.JSON(http.StatusOK, Response(movements, NewMeta(params, total)))
+}
+
+// SetReorderThreshold handles PUT /api/v1/variants/:id/reorder-threshold
+func (h *variantHandler) SetReorderThreshold(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Info().
+		Str("handler", "VariantHandler.SetReorderThreshold").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling reorder threshold request")
+
+	id, ok, err := h.parseID(c, requestID)
+	if !ok {
+		return err
+	}
+
+	var thresholdDTO dto.VariantReorderThresholdDTO
+	if err := c.Bind(&thresholdDTO); err != nil {
+		h.logger.Warn().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to parse request body")
+
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:  http.StatusBadRequest,
+			Message: "Invalid request format",
+			Code:    "INVALID_FORMAT",
+		})
+	}
+
+	validationErrors := thresholdDTO.Valid(ctx)
+	if len(validationErrors) > 0 {
+		return c.JSON(http.StatusBadRequest, ErrorResponse{
+			Status:           http.StatusBadRequest,
+			Message:          "Validation failed",
+			ValidationErrors: validationErrors,
+			Code:             "VALIDATION_ERROR",
+		})
+	}
+
+	variant, err := h.variantService.SetReorderThreshold(ctx, id, thresholdDTO.ReorderThreshold)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Str("variant_id", id.String()).
+			Msg("Failed to set reorder threshold")
+		return h.errorResponse(c, err, "set reorder threshold")
+	}
+
+	return c.JSON(http.StatusOK, map[string]interface{}{
+		"message": "Reorder threshold updated successfully",
+		"variant": variant,
+	})
+}
+
+// LowStock handles GET /api/v1/inventory/low-stock
+func (h *variantHandler) LowStock(c echo.Context) error {
+	ctx := c.Request().Context()
+	requestID := c.Response().Header().Get(echo.HeaderXRequestID)
+
+	h.logger.Debug().
+		Str("handler", "VariantHandler.LowStock").
+		Str("request_id", requestID).
+		Str("method", c.Request().Method).
+		Str("path", c.Request().URL.Path).
+		Str("remote_addr", c.Request().RemoteAddr).
+		Msg("Handling low stock report request")
+
+	params := NewParams(c)
+	variants, total, err := h.variantService.ListLowStock(ctx, params.Offset, params.PerPage)
+	if err != nil {
+		h.logger.Error().
+			Err(err).
+			Str("request_id", requestID).
+			Msg("Failed to retrieve low stock variants")
+		return h.errorResponse(c, err, "retrieve low stock report")
+	}
+
+	return c.JSON(http.StatusOK, Response(variants, NewMeta(params, total)))
+}
@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// AddressRepository defines operations for managing customer addresses
+type AddressRepository interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, address *model.Address) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Address, error)
+	GetByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*model.Address, error)
+	GetDefault(ctx context.Context, customerID uuid.UUID) (*model.Address, error)
+	Update(ctx context.Context, address *model.Address) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Default address management
+	SetDefault(ctx context.Context, customerID, addressID uuid.UUID) error
+
+	// Validation and constraints
+	// IsReferenced(ctx context.Context, id uuid.UUID) (bool, error) // used by subscriptions or orders
+}
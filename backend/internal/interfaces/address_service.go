@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type AddressService interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, customerID uuid.UUID, address *dto.AddressCreateDTO) (*model.Address, error)
+	GetByID(ctx context.Context, customerID, addressID uuid.UUID) (*model.Address, error)
+	ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*model.Address, error)
+	Update(ctx context.Context, customerID, addressID uuid.UUID, addressDTO *dto.AddressUpdateDTO) (*model.Address, error)
+	Delete(ctx context.Context, customerID, addressID uuid.UUID) error
+
+	// Address verification
+	// Verify(ctx context.Context, address *model.Address) (*model.Address, error)
+}
@@ -0,0 +1,24 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// CartRepository defines operations for managing shopping carts
+type CartRepository interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, cart *model.Cart) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Cart, error)
+	GetActiveByCustomerID(ctx context.Context, customerID uuid.UUID) (*model.Cart, error)
+	Update(ctx context.Context, cart *model.Cart) error
+
+	// Line operations
+	SaveItem(ctx context.Context, item *model.CartItem) error
+	RemoveItem(ctx context.Context, cartID, variantID uuid.UUID) error
+
+	// Maintenance
+	// DeleteAbandoned(ctx context.Context, olderThan time.Time) (int, error)
+}
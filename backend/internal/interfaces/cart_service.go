@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type CartService interface {
+	// Core operations (currently implemented)
+	Create(ctx context.Context, cart *dto.CartCreateDTO) (*model.Cart, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Cart, error)
+
+	// Line operations
+	AddItem(ctx context.Context, cartID uuid.UUID, item *dto.CartItemDTO) (*model.Cart, error)
+	UpdateItem(ctx context.Context, cartID, variantID uuid.UUID, item *dto.CartItemUpdateDTO) (*model.Cart, error)
+	RemoveItem(ctx context.Context, cartID, variantID uuid.UUID) (*model.Cart, error)
+
+	// Login
+	Merge(ctx context.Context, guestCartID uuid.UUID, merge *dto.CartMergeDTO) (*model.Cart, error)
+
+	// Checkout
+	// Convert(ctx context.Context, cartID uuid.UUID) error
+}
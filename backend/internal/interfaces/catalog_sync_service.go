@@ -0,0 +1,19 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+// CatalogSyncService pushes local catalog edits to the Stripe products and
+// prices our variants point to
+type CatalogSyncService interface {
+	// Start subscribes to product, variant and price update events
+	Start() error
+
+	// Push operations send the current local state to Stripe
+	PushProduct(ctx context.Context, productID uuid.UUID) error
+	PushVariant(ctx context.Context, variantID uuid.UUID) error
+	PushPrice(ctx context.Context, priceID uuid.UUID) error
+}
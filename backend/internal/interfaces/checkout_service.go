@@ -0,0 +1,16 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+)
+
+type CheckoutService interface {
+	// Core operations (currently implemented)
+	CreateSession(ctx context.Context, checkout *dto.CheckoutSessionCreateDTO) (*dto.CheckoutSessionResponseDTO, error)
+
+	// Session management
+	// ExpireSession(ctx context.Context, sessionID string) error
+	// GetSession(ctx context.Context, sessionID string) (*dto.CheckoutSessionResponseDTO, error)
+}
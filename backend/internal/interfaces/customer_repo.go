@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// CustomerRepository defines operations for managing customers
+type CustomerRepository interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, customer *model.Customer) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error)
+	GetByEmail(ctx context.Context, email string) (*model.Customer, error)
+	GetByStripeID(ctx context.Context, stripeID string) (*model.Customer, error)
+	List(ctx context.Context, offset, limit int, includeInactive bool) ([]*model.Customer, int, error)
+	Update(ctx context.Context, customer *model.Customer) error
+	Deactivate(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Search operations
+	// Search(ctx context.Context, query string, offset, limit int) ([]*model.Customer, int, error)
+	// ListCreatedBetween(ctx context.Context, start, end time.Time) ([]*model.Customer, error)
+
+	// Batch operations
+	// GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*model.Customer, error)
+	// BulkDeactivate(ctx context.Context, ids []uuid.UUID) error
+}
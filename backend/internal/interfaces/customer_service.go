@@ -0,0 +1,28 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type CustomerService interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, customer *dto.CustomerCreateDTO) (*model.Customer, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error)
+	GetByEmail(ctx context.Context, email string) (*model.Customer, error)
+	GetByStripeID(ctx context.Context, stripeID string) (*model.Customer, error)
+	List(ctx context.Context, offset, limit int, includeInactive bool) ([]*model.Customer, int, error)
+	Update(ctx context.Context, id uuid.UUID, customerDTO *dto.CustomerUpdateDTO) (*model.Customer, error)
+	Deactivate(ctx context.Context, id uuid.UUID) error
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	// Customer status management
+	// Reactivate(ctx context.Context, id uuid.UUID) error
+	// Merge(ctx context.Context, sourceID, targetID uuid.UUID) error
+
+	// Stripe integration
+	// SyncFromStripe(ctx context.Context, stripeID string) (*model.Customer, error)
+}
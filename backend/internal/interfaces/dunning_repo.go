@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// DunningRepository defines operations for tracking failed subscription payments
+type DunningRepository interface {
+	// Core CRUD operations (currently implemented)
+	// Create and Update record the given steps in the same transaction
+	Create(ctx context.Context, dunningCase *model.DunningCase, steps ...*model.DunningStep) error
+	GetOpenBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) (*model.DunningCase, error)
+	Update(ctx context.Context, dunningCase *model.DunningCase, steps ...*model.DunningStep) error
+	GetSteps(ctx context.Context, dunningCaseID uuid.UUID) ([]*model.DunningStep, error)
+
+	// Scheduling
+	ListDue(ctx context.Context, before time.Time, limit int) ([]*model.DunningCase, error)
+
+	// Reporting
+	// ListBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*model.DunningCase, error)
+}
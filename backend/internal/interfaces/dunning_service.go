@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+)
+
+type DunningService interface {
+	// Core operations (currently implemented)
+	HandlePaymentFailed(ctx context.Context, subscription *model.Subscription, invoice *model.Invoice) (*model.DunningCase, error)
+	HandlePaymentSucceeded(ctx context.Context, subscription *model.Subscription, invoice *model.Invoice) error
+
+	// Scheduling
+	ProcessDue(ctx context.Context, now time.Time) (int, error)
+	Run(ctx context.Context)
+
+	// Reporting
+	// GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*model.DunningCase, error)
+}
@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// InvoiceRepository defines operations for managing Stripe invoices
+type InvoiceRepository interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, invoice *model.Invoice) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error)
+	GetByStripeID(ctx context.Context, stripeID string) (*model.Invoice, error)
+	GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Invoice, error)
+	Update(ctx context.Context, invoice *model.Invoice) error
+
+	// Reporting
+	// ListByStatus(ctx context.Context, status string, offset, limit int) ([]*model.Invoice, int, error)
+}
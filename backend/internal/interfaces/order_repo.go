@@ -0,0 +1,29 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// OrderRepository defines operations for managing orders
+type OrderRepository interface {
+	// Core CRUD operations (currently implemented)
+	// Create and UpdateStatus record the order's stock movements in the same
+	// transaction. With checkAvailable, Create fails with ErrInsufficientStock
+	// rather than sell stock that reservations are holding
+	Create(ctx context.Context, order *model.Order, movements []*model.InventoryMovement, checkAvailable bool) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error)
+	List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Order, int, error)
+	UpdateStatus(ctx context.Context, order *model.Order, fromStatus, note string, movements []*model.InventoryMovement) error
+	GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*model.OrderStatusChange, error)
+
+	// Stripe lookups
+	GetByCheckoutSessionID(ctx context.Context, sessionID string) (*model.Order, error)
+	GetByInvoiceID(ctx context.Context, invoiceID string) (*model.Order, error)
+
+	// Fulfillment queries
+	// ListByStatus(ctx context.Context, status string, limit int) ([]*model.Order, error)
+	// ListBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Order, error)
+}
@@ -0,0 +1,32 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type OrderService interface {
+	// Core operations (currently implemented)
+	Create(ctx context.Context, order *dto.OrderCreateDTO) (*model.Order, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error)
+	List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Order, int, error)
+
+	// Lifecycle operations
+	UpdateStatus(ctx context.Context, id uuid.UUID, update *dto.OrderStatusUpdateDTO) (*model.Order, error)
+	Cancel(ctx context.Context, id uuid.UUID, reason string) (*model.Order, error)
+	GetStatusHistory(ctx context.Context, id uuid.UUID) ([]*model.OrderStatusChange, error)
+
+	// Stripe Checkout
+	CreateFromCheckout(ctx context.Context, checkout *dto.CheckoutOrderDTO) (*model.Order, error)
+	GetByCheckoutSessionID(ctx context.Context, sessionID string) (*model.Order, error)
+
+	// Subscription billing
+	CreateRenewal(ctx context.Context, renewal *dto.RenewalOrderDTO) (*model.Order, error)
+
+	// Fulfillment
+	// ListReadyToRoast(ctx context.Context) ([]*model.Order, error)
+	// BulkUpdateStatus(ctx context.Context, ids []uuid.UUID, status string) error
+}
@@ -0,0 +1,12 @@
+package interfaces
+
+import (
+	"context"
+)
+
+// OutboxRelay publishes events written to the outbox to the message bus
+type OutboxRelay interface {
+	// RelayPending publishes one batch of unsent events and returns how many were sent
+	RelayPending(ctx context.Context) (int, error)
+	Run(ctx context.Context)
+}
@@ -0,0 +1,23 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+)
+
+// OutboxRepository defines the relay's operations on the domain event outbox.
+// Events are written by the repositories whose changes they describe
+type OutboxRepository interface {
+	// ProcessPending locks up to limit unsent events, oldest first, and hands each
+	// to publish. Published events are marked sent; the first failure is recorded
+	// and ends the batch so events stay in order
+	ProcessPending(ctx context.Context, limit int, publish func(event *model.OutboxEvent) error) (int, error)
+
+	// PendingStats returns the number of unsent events and when the oldest was written
+	PendingStats(ctx context.Context) (int, *time.Time, error)
+
+	// DeleteSentBefore removes events published before the cutoff
+	DeleteSentBefore(ctx context.Context, before time.Time) (int64, error)
+}
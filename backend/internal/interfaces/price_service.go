@@ -21,6 +21,11 @@ type PriceService interface {
 	ValidatePriceCompatibility(ctx context.Context, priceID, variantID uuid.UUID) error
 	SyncStripeProductIDs(ctx context.Context) (*dto.SyncStripeProductIDsResult, error)
 
+	// Variant pricing rules
+	GetPricingRule(ctx context.Context, productID uuid.UUID) (*model.PricingRule, error)
+	SetPricingRule(ctx context.Context, productID uuid.UUID, ruleDTO *dto.PricingRuleDTO) (*model.PricingRule, error)
+	ApplyPricingRule(ctx context.Context, productID uuid.UUID) (*dto.PricingRuleApplyResult, error)
+
 	// Alternative lookup methods
 	// GetByStripeID(ctx context.Context, stripeID string) (*model.Price, error)
 	// GetActivePrices(ctx context.Context, productID uuid.UUID) ([]*model.Price, error)
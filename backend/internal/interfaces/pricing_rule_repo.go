@@ -0,0 +1,14 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// PricingRuleRepository defines operations for product pricing rules
+type PricingRuleRepository interface {
+	GetByProductID(ctx context.Context, productID uuid.UUID) (*model.PricingRule, error)
+	Upsert(ctx context.Context, rule *model.PricingRule) error
+}
@@ -8,16 +8,17 @@ import (
 )
 
 type ProductRepository interface {
-	// Core CRUD operations (currently implemented)
-	Create(ctx context.Context, product *model.Product) error
+	// Core CRUD operations (currently implemented). Outbox events passed to
+	// writes are stored in the same transaction and published by the relay
+	Create(ctx context.Context, product *model.Product, outbox ...*model.OutboxEvent) error
 	GetByID(ctx context.Context, id uuid.UUID) (*model.Product, error)
 	GetByName(ctx context.Context, name string) (*model.Product, error)
 	GetByStripeID(ctx context.Context, stripeID string) (*model.Product, error)
 	List(ctx context.Context, offset, limit int, includeInactive, includeArchived bool) ([]*model.Product, int, error)
-	Update(ctx context.Context, product *model.Product) error
-	Archive(ctx context.Context, id uuid.UUID) error // (soft delete)
-	Delete(ctx context.Context, id uuid.UUID) error  // (hard delete)
-	UpdateStockLevel(ctx context.Context, id uuid.UUID, quantity int) error
+	Update(ctx context.Context, product *model.Product, outbox ...*model.OutboxEvent) error
+	Archive(ctx context.Context, id uuid.UUID, outbox ...*model.OutboxEvent) error // (soft delete)
+	Delete(ctx context.Context, id uuid.UUID, outbox ...*model.OutboxEvent) error  // (hard delete)
+	// Stock is kept per variant and only changes through the inventory ledger
 
 	// Alternative lookup methods
 	// GetBySKU(ctx context.Context, sku string) (*model.Product, error)
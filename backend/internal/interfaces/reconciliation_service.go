@@ -0,0 +1,21 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+)
+
+// ReconciliationService compares the catalog in Postgres with Stripe
+type ReconciliationService interface {
+	// Reconcile walks every product, variant and price on both sides and
+	// reports the discrepancies without changing anything
+	Reconcile(ctx context.Context) (*dto.ReconciliationReport, error)
+
+	// Apply reconciles and then fixes each category in the policy by making
+	// the other side match its source of truth
+	Apply(ctx context.Context, policy map[string]string) (*dto.ReconciliationReport, error)
+
+	// Run reports on a schedule until the context is cancelled
+	Run(ctx context.Context)
+}
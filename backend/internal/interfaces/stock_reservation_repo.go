@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// StockReservationRepository defines operations for holding variant stock during checkout
+type StockReservationRepository interface {
+	// Core operations (currently implemented)
+	// Reserve holds every reservation or none, failing with ErrInsufficientStock
+	Reserve(ctx context.Context, reservations []*model.StockReservation) error
+	GetByReference(ctx context.Context, reference string) ([]*model.StockReservation, error)
+	GetAvailable(ctx context.Context, variantID uuid.UUID) (int, error)
+
+	// Status changes only move held reservations; they return how many changed
+	SetStatusByReference(ctx context.Context, reference, status string) (int, error)
+	ExpireHeld(ctx context.Context, before time.Time) (int, error)
+
+	// Reporting
+	// ListHeldByVariantID(ctx context.Context, variantID uuid.UUID) ([]*model.StockReservation, error)
+}
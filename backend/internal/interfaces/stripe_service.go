@@ -0,0 +1,42 @@
+package interfaces
+
+import (
+	"time"
+
+	"github.com/stripe/stripe-go/v82"
+)
+
+// StripeService defines the operations we perform against the Stripe API
+type StripeService interface {
+	// Product operations
+	CreateProduct(name, description string, imageURLs []string, metadata map[string]string) (*stripe.Product, error)
+	GetProduct(productID string) (*stripe.Product, error)
+	ListAllProducts() ([]*stripe.Product, error)
+	FindProductByName(name string) (*stripe.Product, error)
+	FindProductByMetadata(key, value string) (*stripe.Product, error)
+	UpdateProduct(productID string, active bool, metadata map[string]string) (*stripe.Product, error)
+	UpdateProductDetails(productID, name, description string, imageURLs []string, active bool, metadata map[string]string) (*stripe.Product, error)
+
+	// Price operations
+	// CreatePrice creates a price; a non-empty idempotency key makes retries return the first price
+	CreatePrice(productID string, unitAmount int64, currency string, recurring bool, interval string, intervalCount int64, idempotencyKey string) (*stripe.Price, error)
+	GetPrice(priceID string) (*stripe.Price, error)
+	SetPriceActive(priceID string, active bool) (*stripe.Price, error)
+	ListAllPrices() ([]*stripe.Price, error)
+
+	// Customer operations
+	CreateCustomer(email, name, phone string, metadata map[string]string) (*stripe.Customer, error)
+	UpdateCustomer(customerID, email, name, phone string) (*stripe.Customer, error)
+	DeleteCustomer(customerID string) error
+
+	// Subscription operations
+	CreateSubscription(customerID, priceID string, quantity int64, metadata map[string]string) (*stripe.Subscription, error)
+	PauseSubscription(subscriptionID string, resumesAt time.Time) (*stripe.Subscription, error)
+	ResumeSubscription(subscriptionID string) (*stripe.Subscription, error)
+	CancelSubscription(subscriptionID string, atPeriodEnd bool) (*stripe.Subscription, error)
+
+	// Checkout operations
+	CreateCheckoutSession(mode, customerID, customerEmail string, lineItems []*stripe.CheckoutSessionLineItemParams, successURL, cancelURL string, metadata map[string]string, expiresAt time.Time) (*stripe.CheckoutSession, error)
+	ListCheckoutSessionLineItems(sessionID string) ([]*stripe.LineItem, error)
+	GetCheckoutSessionForSubscription(subscriptionID string) (*stripe.CheckoutSession, error)
+}
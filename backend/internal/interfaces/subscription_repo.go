@@ -0,0 +1,26 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+// SubscriptionRepository defines operations for managing subscriptions
+type SubscriptionRepository interface {
+	// Core CRUD operations (currently implemented)
+	Create(ctx context.Context, subscription *model.Subscription) error
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	GetByStripeID(ctx context.Context, stripeID string) (*model.Subscription, error)
+	GetByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*model.Subscription, error)
+	List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Subscription, int, error)
+	Update(ctx context.Context, subscription *model.Subscription) error
+
+	// Delivery scheduling
+	// ListDueForDelivery(ctx context.Context, before time.Time) ([]*model.Subscription, error)
+	// ListRenewingBetween(ctx context.Context, start, end time.Time) ([]*model.Subscription, error)
+
+	// Analytics support
+	// CountByStatus(ctx context.Context) (map[string]int, error)
+}
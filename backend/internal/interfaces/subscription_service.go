@@ -0,0 +1,27 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
+
+type SubscriptionService interface {
+	// Core operations (currently implemented)
+	Create(ctx context.Context, subscription *dto.SubscriptionCreateDTO) (*model.Subscription, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.SubscriptionWithDetails, error)
+	List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Subscription, int, error)
+
+	// Lifecycle operations
+	Pause(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	Resume(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	SkipNextDelivery(ctx context.Context, id uuid.UUID) (*model.Subscription, error)
+	Cancel(ctx context.Context, id uuid.UUID, cancelDTO *dto.SubscriptionCancelDTO) (*model.Subscription, error)
+
+	// Plan changes
+	// ChangeQuantity(ctx context.Context, id uuid.UUID, quantity int) (*model.Subscription, error)
+	// ChangePrice(ctx context.Context, id uuid.UUID, priceID uuid.UUID) (*model.Subscription, error)
+	// ChangeAddress(ctx context.Context, id uuid.UUID, addressID uuid.UUID) (*model.Subscription, error)
+}
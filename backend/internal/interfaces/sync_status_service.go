@@ -0,0 +1,18 @@
+package interfaces
+
+import (
+	"context"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/google/uuid"
+)
+
+// SyncStatusService reports how each variant's recorded sync hash compares
+// with its live Stripe product
+type SyncStatusService interface {
+	// Status lists every variant's sync state, optionally only those in one state
+	Status(ctx context.Context, state string) (*dto.SyncStatusReport, error)
+
+	// History returns a variant's hash timeline, newest first
+	History(ctx context.Context, variantID uuid.UUID, limit int) (*dto.SyncHistoryReport, error)
+}
@@ -19,6 +19,9 @@ type SyncHashRepository interface {
 	DeleteByVariantID(ctx context.Context, variantID uuid.UUID) error
 	GetHashHistory(ctx context.Context, variantID uuid.UUID, limit int) ([]*model.SyncHash, error)
 
+	// Sync status reporting
+	ListVariantSyncStates(ctx context.Context) ([]*model.VariantSyncState, error)
+
 	// Batch operations
 	// GetByVariantIDs(ctx context.Context, variantIDs []uuid.UUID) ([]*model.SyncHash, error)
 	// GetByStripeProductIDs(ctx context.Context, stripeProductIDs []string) ([]*model.SyncHash, error)
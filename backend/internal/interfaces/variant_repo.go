@@ -17,7 +17,12 @@ type VariantRepository interface {
 	GetByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Variant, error)
 	Update(ctx context.Context, variant *model.Variant) error
 	Delete(ctx context.Context, id uuid.UUID) error
-	UpdateStockLevel(ctx context.Context, id uuid.UUID, stockLevel int) error
+
+	// Stock changes are appended to the inventory ledger in the same transaction
+	UpdateStockLevel(ctx context.Context, movement *model.InventoryMovement, stockLevel int) error
+	AdjustStock(ctx context.Context, movement *model.InventoryMovement) error
+	GetStockHistory(ctx context.Context, id uuid.UUID, offset, limit int) ([]*model.InventoryMovement, int, error)
+	ListLowStock(ctx context.Context, offset, limit int) ([]*model.LowStockVariant, int, error)
 
 	// Batch operations
 	// GetByIDs(ctx context.Context, ids []uuid.UUID) ([]*model.Variant, error)
@@ -49,8 +54,6 @@ type VariantRepository interface {
 	// Stock management
 	// ReserveStock(ctx context.Context, id uuid.UUID, quantity int) error
 	// ReleaseStock(ctx context.Context, id uuid.UUID, quantity int) error
-	// GetStockHistory(ctx context.Context, id uuid.UUID, from, to time.Time) ([]*model.StockAdjustment, error)
-	// AdjustStock(ctx context.Context, id uuid.UUID, adjustment int, reason string) error
 
 	// Subscription-specific queries
 	// ListSubscriptionCompatible(ctx context.Context, productID uuid.UUID) ([]*model.Variant, error)
@@ -1,22 +1,23 @@
 package interfaces
 
-// import (
-// 	"context"
+import (
+	"context"
+	"time"
 
-// 	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
-// 	"github.com/dukerupert/coffee-commerce/internal/domain/model"
-// 	"github.com/google/uuid"
-// )
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+)
 
 // VariantService defines the interface for variant-related operations
 type VariantService interface {
 	// Core CRUD operations that could be exposed via API
 
 	// Create creates a new variant manually (alternative to event-driven creation)
-	// Create(ctx context.Context, createDTO *dto.VariantCreateDTO) (*model.Variant, error)
+	Create(ctx context.Context, createDTO *dto.VariantCreateDTO) (*model.Variant, error)
 
 	// GetByID retrieves a variant by its ID
-	// GetByID(ctx context.Context, id uuid.UUID) (*model.Variant, error)
+	GetByID(ctx context.Context, id uuid.UUID) (*model.Variant, error)
 
 	// GetByProductID retrieves all variants for a specific product
 	// GetByProductID(ctx context.Context, productID uuid.UUID) ([]*model.Variant, error)
@@ -25,29 +26,62 @@ type VariantService interface {
 	// GetByStripeProductID(ctx context.Context, stripeProductID string) (*model.Variant, error)
 
 	// Update updates an existing variant
-	// Update(ctx context.Context, id uuid.UUID, updateDTO *dto.VariantUpdateDTO) (*model.Variant, error)
+	Update(ctx context.Context, id uuid.UUID, updateDTO *dto.VariantUpdateDTO) (*model.Variant, error)
 
-	// Delete removes a variant
-	// Delete(ctx context.Context, id uuid.UUID) error
+	// Delete removes a variant and archives its Stripe product
+	Delete(ctx context.Context, id uuid.UUID) error
 
 	// Stock management operations
 
-	// UpdateStockLevel updates the stock level for a variant
-	// UpdateStockLevel(ctx context.Context, id uuid.UUID, stockLevel int) error
+	// UpdateStockLevel sets a variant's stock level, recording the difference in the ledger
+	UpdateStockLevel(ctx context.Context, id uuid.UUID, stockLevel int) error
 
-	// CheckStockAvailability checks if enough stock is available for an order
-	// CheckStockAvailability(ctx context.Context, id uuid.UUID, quantity int) (bool, error)
+	// RecordStockMovement posts a manual receipt, adjustment, spoilage or return to a variant's ledger
+	RecordStockMovement(ctx context.Context, id uuid.UUID, movementDTO *dto.StockMovementCreateDTO) (*model.InventoryMovement, error)
 
-	// ReserveStock reserves stock for a pending order
-	// ReserveStock(ctx context.Context, id uuid.UUID, quantity int) error
+	// GetStockHistory retrieves a variant's inventory movements, newest first, with the total count
+	GetStockHistory(ctx context.Context, id uuid.UUID, offset, limit int) ([]*model.InventoryMovement, int, error)
 
-	// ReleaseStock releases previously reserved stock
-	// ReleaseStock(ctx context.Context, id uuid.UUID, quantity int) error
+	// AdjustStock applies a movement to a variant's stock and publishes the stock events
+	AdjustStock(ctx context.Context, movement *model.InventoryMovement) error
+
+	// SetStockLevel sets a variant's stock to a counted level and publishes the stock events
+	SetStockLevel(ctx context.Context, movement *model.InventoryMovement, stockLevel int) error
+
+	// PublishStockMovement publishes the stock events for a movement another
+	// repository applied, e.g. together with an order
+	PublishStockMovement(ctx context.Context, movement *model.InventoryMovement)
+
+	// SetReorderThreshold sets a variant's own reorder threshold, or clears it when nil
+	SetReorderThreshold(ctx context.Context, id uuid.UUID, threshold *int) (*model.Variant, error)
+
+	// ListLowStock retrieves variants at or below their reorder threshold, with the total count
+	ListLowStock(ctx context.Context, offset, limit int) ([]*model.LowStockVariant, int, error)
+
+	// CheckStockAvailability checks if enough unreserved stock is available for an order
+	CheckStockAvailability(ctx context.Context, id uuid.UUID, quantity int) (bool, error)
+
+	// ReserveStock holds stock for every variant under one reference until expiresAt,
+	// failing with postgres.ErrInsufficientStock if any variant is short
+	ReserveStock(ctx context.Context, reference string, quantities map[uuid.UUID]int, expiresAt time.Time) error
+
+	// ConfirmStock marks the held stock for a reference as taken by a paid order
+	ConfirmStock(ctx context.Context, reference string) error
+
+	// ReleaseStock releases the held stock for a reference
+	ReleaseStock(ctx context.Context, reference string) error
+
+	// ReleaseExpiredReservations expires holds that ran past their expiry
+	ReleaseExpiredReservations(ctx context.Context) (int, error)
+
+	// RunReservationSweeper releases expired holds every interval until ctx is done
+	RunReservationSweeper(ctx context.Context, interval time.Duration)
 
 	// Variant generation and management
 
-	// RegenerateVariants recreates all variants for a product based on current options
-	// RegenerateVariants(ctx context.Context, productID uuid.UUID) error
+	// RegenerateVariants lines a product's variants up with its current options,
+	// creating missing combinations and deactivating obsolete ones unless dryRun is set
+	RegenerateVariants(ctx context.Context, productID uuid.UUID, dryRun bool) (*dto.VariantRegenerationPlan, error)
 
 	// CreateVariantsFromOptions creates variants for all combinations of the given options
 	// CreateVariantsFromOptions(ctx context.Context, productID uuid.UUID, options map[string][]string) error
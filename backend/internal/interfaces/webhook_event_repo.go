@@ -0,0 +1,25 @@
+package interfaces
+
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+)
+
+// WebhookEventRepository defines operations on the store of received Stripe webhook events
+type WebhookEventRepository interface {
+	// Save records a newly received event. It returns false without changing
+	// anything if the event ID has been recorded before
+	Save(ctx context.Context, event *model.WebhookEvent) (bool, error)
+	GetByID(ctx context.Context, id string) (*model.WebhookEvent, error)
+	List(ctx context.Context, status string, offset, limit int) ([]*model.WebhookEvent, int, error)
+
+	// Claim marks an event as processing and counts the attempt. Only pending and
+	// failed events can be claimed, plus processing events not updated since
+	// staleBefore, whose processor is assumed to have died. It returns false if
+	// the event was processed or is being processed elsewhere
+	Claim(ctx context.Context, id string, staleBefore time.Time) (bool, error)
+	MarkProcessed(ctx context.Context, id string) error
+	MarkFailed(ctx context.Context, id string, lastError string) error
+}
@@ -13,6 +13,8 @@ type EventMetrics struct {
 	EventProcessTime  *prometheus.HistogramVec
 	EventsErrorCount  *prometheus.CounterVec
 	ActiveSubscribers *prometheus.GaugeVec
+	OutboxPending     prometheus.Gauge
+	OutboxLag         prometheus.Gauge
 }
 
 // NewEventMetrics creates and registers Prometheus metrics for the event system
@@ -54,5 +56,17 @@ func NewEventMetrics() *EventMetrics {
 			},
 			[]string{"topic"},
 		),
+		OutboxPending: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coffee_commerce_outbox_pending_events",
+				Help: "Number of outbox events not yet published",
+			},
+		),
+		OutboxLag: promauto.NewGauge(
+			prometheus.GaugeOpts{
+				Name: "coffee_commerce_outbox_lag_seconds",
+				Help: "Age of the oldest outbox event not yet published",
+			},
+		),
 	}
 }
\ No newline at end of file
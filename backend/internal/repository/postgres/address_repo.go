@@ -0,0 +1,347 @@
+// internal/repository/postgres/address_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// addressRepository implements the AddressRepository interface
+type addressRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewAddressRepository creates a new AddressRepository
+func NewAddressRepository(db *DB, logger *zerolog.Logger) interfaces.AddressRepository {
+	return &addressRepository{
+		db:     db,
+		logger: logger.With().Str("component", "address_repository").Logger(),
+	}
+}
+
+// clearDefaultQuery unsets the default flag on every other address of a customer
+const clearDefaultQuery = `
+	UPDATE customer_addresses SET
+		is_default = false,
+		updated_at = $1
+	WHERE customer_id = $2 AND id <> $3 AND is_default = true
+`
+
+// Create adds a new address. When the address is the default, any existing
+// default for the customer is cleared in the same transaction
+func (r *addressRepository) Create(ctx context.Context, address *model.Address) error {
+	query := `
+        INSERT INTO customer_addresses (
+            id, customer_id, line1, line2, city, state, postal_code,
+            country, is_default, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+        )
+    `
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		if address.IsDefault {
+			if _, err := tx.ExecContext(ctx, clearDefaultQuery, time.Now(), address.CustomerID, address.ID); err != nil {
+				return fmt.Errorf("failed to clear default address: %w", err)
+			}
+		}
+
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			address.ID,
+			address.CustomerID,
+			address.Line1,
+			address.Line2,
+			address.City,
+			address.State,
+			address.PostalCode,
+			address.Country,
+			address.IsDefault,
+			address.CreatedAt,
+			address.UpdatedAt,
+		)
+		return err
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("address_id", address.ID.String()).
+			Str("customer_id", address.CustomerID.String()).
+			Msg("Failed to create address")
+		return fmt.Errorf("failed to create address: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an address by its ID
+func (r *addressRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Address, error) {
+	query := `
+        SELECT
+            id, customer_id, line1, COALESCE(line2, ''), city, COALESCE(state, ''),
+            postal_code, country, COALESCE(is_default, false), created_at, updated_at
+        FROM customer_addresses
+        WHERE id = $1
+    `
+
+	var address model.Address
+
+	err := r.db.QueryRowContext(ctx, query, id).Scan(
+		&address.ID,
+		&address.CustomerID,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.State,
+		&address.PostalCode,
+		&address.Country,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Address not found
+		}
+		return nil, fmt.Errorf("failed to get address: %w", err)
+	}
+
+	return &address, nil
+}
+
+// GetByCustomerID retrieves all addresses for a customer, default first
+func (r *addressRepository) GetByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*model.Address, error) {
+	query := `
+        SELECT
+            id, customer_id, line1, COALESCE(line2, ''), city, COALESCE(state, ''),
+            postal_code, country, COALESCE(is_default, false), created_at, updated_at
+        FROM customer_addresses
+        WHERE customer_id = $1
+        ORDER BY is_default DESC, created_at
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query addresses: %w", err)
+	}
+	defer rows.Close()
+
+	addresses := make([]*model.Address, 0)
+
+	for rows.Next() {
+		var address model.Address
+
+		err := rows.Scan(
+			&address.ID,
+			&address.CustomerID,
+			&address.Line1,
+			&address.Line2,
+			&address.City,
+			&address.State,
+			&address.PostalCode,
+			&address.Country,
+			&address.IsDefault,
+			&address.CreatedAt,
+			&address.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan address: %w", err)
+		}
+
+		addresses = append(addresses, &address)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during address rows iteration: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// GetDefault retrieves the default address for a customer
+func (r *addressRepository) GetDefault(ctx context.Context, customerID uuid.UUID) (*model.Address, error) {
+	query := `
+        SELECT
+            id, customer_id, line1, COALESCE(line2, ''), city, COALESCE(state, ''),
+            postal_code, country, COALESCE(is_default, false), created_at, updated_at
+        FROM customer_addresses
+        WHERE customer_id = $1 AND is_default = true
+    `
+
+	var address model.Address
+
+	err := r.db.QueryRowContext(ctx, query, customerID).Scan(
+		&address.ID,
+		&address.CustomerID,
+		&address.Line1,
+		&address.Line2,
+		&address.City,
+		&address.State,
+		&address.PostalCode,
+		&address.Country,
+		&address.IsDefault,
+		&address.CreatedAt,
+		&address.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No default address
+		}
+		return nil, fmt.Errorf("failed to get default address: %w", err)
+	}
+
+	return &address, nil
+}
+
+// Update updates an existing address. When the address becomes the default,
+// any other default for the customer is cleared in the same transaction
+func (r *addressRepository) Update(ctx context.Context, address *model.Address) error {
+	address.UpdatedAt = time.Now()
+
+	query := `
+        UPDATE customer_addresses SET
+            line1 = $1,
+            line2 = $2,
+            city = $3,
+            state = $4,
+            postal_code = $5,
+            country = $6,
+            is_default = $7,
+            updated_at = $8
+        WHERE id = $9
+    `
+
+	var rowsAffected int64
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		if address.IsDefault {
+			if _, err := tx.ExecContext(ctx, clearDefaultQuery, address.UpdatedAt, address.CustomerID, address.ID); err != nil {
+				return fmt.Errorf("failed to clear default address: %w", err)
+			}
+		}
+
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			address.Line1,
+			address.Line2,
+			address.City,
+			address.State,
+			address.PostalCode,
+			address.Country,
+			address.IsDefault,
+			address.UpdatedAt,
+			address.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err = result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("address with ID %s not found", address.ID)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("address_id", address.ID.String()).
+			Msg("Failed to update address")
+		return fmt.Errorf("failed to update address: %w", err)
+	}
+
+	r.logger.Debug().
+		Str("address_id", address.ID.String()).
+		Int64("rows_affected", rowsAffected).
+		Msg("Address updated successfully")
+
+	return nil
+}
+
+// SetDefault makes the given address the customer's only default address
+func (r *addressRepository) SetDefault(ctx context.Context, customerID, addressID uuid.UUID) error {
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		now := time.Now()
+
+		if _, err := tx.ExecContext(ctx, clearDefaultQuery, now, customerID, addressID); err != nil {
+			return fmt.Errorf("failed to clear default address: %w", err)
+		}
+
+		result, err := tx.ExecContext(ctx, `
+			UPDATE customer_addresses SET
+				is_default = true,
+				updated_at = $1
+			WHERE id = $2 AND customer_id = $3
+		`, now, addressID, customerID)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return fmt.Errorf("address with ID %s not found", addressID)
+		}
+
+		return nil
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("address_id", addressID.String()).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to set default address")
+		return fmt.Errorf("failed to set default address: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes an address
+func (r *addressRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM customer_addresses WHERE id = $1"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("address_id", id.String()).
+			Msg("Failed to delete address")
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("address with ID %s not found", id)
+	}
+
+	r.logger.Debug().
+		Str("address_id", id.String()).
+		Int64("rows_affected", rowsAffected).
+		Msg("Address deleted successfully")
+
+	return nil
+}
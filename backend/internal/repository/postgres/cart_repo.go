@@ -0,0 +1,242 @@
+// internal/repository/postgres/cart_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// cartRepository implements the CartRepository interface
+type cartRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewCartRepository creates a new CartRepository
+func NewCartRepository(db *DB, logger *zerolog.Logger) interfaces.CartRepository {
+	return &cartRepository{
+		db:     db,
+		logger: logger.With().Str("component", "cart_repository").Logger(),
+	}
+}
+
+// Create adds a new, empty cart
+func (r *cartRepository) Create(ctx context.Context, cart *model.Cart) error {
+	query := `
+        INSERT INTO carts (id, customer_id, status, created_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+
+	_, err := r.db.ExecContext(ctx, query, cart.ID, cart.CustomerID, cart.Status, cart.CreatedAt, cart.UpdatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("cart_id", cart.ID.String()).
+			Msg("Failed to create cart")
+		return fmt.Errorf("failed to create cart: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a cart and its items
+func (r *cartRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Cart, error) {
+	query := `
+        SELECT id, customer_id, status, created_at, updated_at
+        FROM carts
+        WHERE id = $1
+    `
+
+	return r.getOne(ctx, query, id)
+}
+
+// GetActiveByCustomerID retrieves a customer's active cart, if any
+func (r *cartRepository) GetActiveByCustomerID(ctx context.Context, customerID uuid.UUID) (*model.Cart, error) {
+	query := `
+        SELECT id, customer_id, status, created_at, updated_at
+        FROM carts
+        WHERE customer_id = $1 AND status = 'active'
+    `
+
+	return r.getOne(ctx, query, customerID)
+}
+
+// getOne runs a single-cart query and loads the cart's items
+func (r *cartRepository) getOne(ctx context.Context, query string, arg interface{}) (*model.Cart, error) {
+	var cart model.Cart
+	var customerID uuid.NullUUID
+
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&cart.ID,
+		&customerID,
+		&cart.Status,
+		&cart.CreatedAt,
+		&cart.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Cart not found
+		}
+		return nil, fmt.Errorf("failed to get cart: %w", err)
+	}
+
+	if customerID.Valid {
+		cart.CustomerID = &customerID.UUID
+	}
+
+	itemsQuery := `
+        SELECT id, cart_id, variant_id, price_id, quantity, unit_amount, created_at, updated_at
+        FROM cart_items
+        WHERE cart_id = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, itemsQuery, cart.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query cart items: %w", err)
+	}
+	defer rows.Close()
+
+	cart.Items = make([]*model.CartItem, 0)
+	for rows.Next() {
+		var item model.CartItem
+		err := rows.Scan(
+			&item.ID,
+			&item.CartID,
+			&item.VariantID,
+			&item.PriceID,
+			&item.Quantity,
+			&item.UnitAmount,
+			&item.CreatedAt,
+			&item.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan cart item: %w", err)
+		}
+		cart.Items = append(cart.Items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during cart item rows iteration: %w", err)
+	}
+
+	return &cart, nil
+}
+
+// Update updates a cart's owner and status
+func (r *cartRepository) Update(ctx context.Context, cart *model.Cart) error {
+	cart.UpdatedAt = time.Now()
+
+	query := `
+        UPDATE carts SET
+            customer_id = $1,
+            status = $2,
+            updated_at = $3
+        WHERE id = $4
+    `
+
+	result, err := r.db.ExecContext(ctx, query, cart.CustomerID, cart.Status, cart.UpdatedAt, cart.ID)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("cart_id", cart.ID.String()).
+			Msg("Failed to update cart")
+		return fmt.Errorf("failed to update cart: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("cart with ID %s not found", cart.ID)
+	}
+
+	return nil
+}
+
+// SaveItem inserts a cart line, or replaces the quantity and price of the
+// existing line for the same variant
+func (r *cartRepository) SaveItem(ctx context.Context, item *model.CartItem) error {
+	item.UpdatedAt = time.Now()
+
+	query := `
+        INSERT INTO cart_items (
+            id, cart_id, variant_id, price_id, quantity, unit_amount, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8
+        )
+        ON CONFLICT (cart_id, variant_id) DO UPDATE SET
+            price_id = EXCLUDED.price_id,
+            quantity = EXCLUDED.quantity,
+            unit_amount = EXCLUDED.unit_amount,
+            updated_at = EXCLUDED.updated_at
+        RETURNING id, created_at
+    `
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		err := tx.QueryRowContext(
+			ctx,
+			query,
+			item.ID,
+			item.CartID,
+			item.VariantID,
+			item.PriceID,
+			item.Quantity,
+			item.UnitAmount,
+			item.CreatedAt,
+			item.UpdatedAt,
+		).Scan(&item.ID, &item.CreatedAt)
+		if err != nil {
+			return err
+		}
+
+		_, err = tx.ExecContext(ctx, "UPDATE carts SET updated_at = $1 WHERE id = $2", item.UpdatedAt, item.CartID)
+		return err
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("cart_id", item.CartID.String()).
+			Str("variant_id", item.VariantID.String()).
+			Msg("Failed to save cart item")
+		return fmt.Errorf("failed to save cart item: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveItem deletes the line for a variant from a cart
+func (r *cartRepository) RemoveItem(ctx context.Context, cartID, variantID uuid.UUID) error {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM cart_items WHERE cart_id = $1 AND variant_id = $2", cartID, variantID)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("cart_id", cartID.String()).
+			Str("variant_id", variantID.String()).
+			Msg("Failed to remove cart item")
+		return fmt.Errorf("failed to remove cart item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("variant %s is not in cart %s", variantID, cartID)
+	}
+
+	_, err = r.db.ExecContext(ctx, "UPDATE carts SET updated_at = $1 WHERE id = $2", time.Now(), cartID)
+	if err != nil {
+		return fmt.Errorf("failed to touch cart: %w", err)
+	}
+
+	return nil
+}
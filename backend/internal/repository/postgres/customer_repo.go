@@ -0,0 +1,316 @@
+// internal/repository/postgres/customer_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+// customerRepository implements the CustomerRepository interface
+type customerRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewCustomerRepository creates a new CustomerRepository
+func NewCustomerRepository(db *DB, logger *zerolog.Logger) interfaces.CustomerRepository {
+	return &customerRepository{
+		db:     db,
+		logger: logger.With().Str("component", "customer_repository").Logger(),
+	}
+}
+
+// Create adds a new customer to the database
+func (r *customerRepository) Create(ctx context.Context, customer *model.Customer) error {
+	query := `
+        INSERT INTO customers (
+            id, stripe_id, email, first_name, last_name, phone_number,
+            active, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9
+        )
+    `
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		customer.ID,
+		customer.StripeID,
+		customer.Email,
+		customer.FirstName,
+		customer.LastName,
+		customer.PhoneNumber,
+		customer.Active,
+		customer.CreatedAt,
+		customer.UpdatedAt,
+	)
+
+	if err != nil {
+		// Another request registered the email since the service checked it
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" && pqErr.Constraint == "customers_email_key" {
+			return ErrDuplicateEmail
+		}
+		r.logger.Error().Err(err).
+			Str("customer_id", customer.ID.String()).
+			Str("email", customer.Email).
+			Msg("Failed to create customer")
+		return fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a customer by its ID
+func (r *customerRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	query := `
+        SELECT
+            id, stripe_id, email, first_name, last_name, COALESCE(phone_number, ''),
+            active, created_at, updated_at
+        FROM customers
+        WHERE id = $1
+    `
+
+	return r.getOne(ctx, query, id)
+}
+
+// GetByEmail retrieves a customer by email address (case-insensitive)
+func (r *customerRepository) GetByEmail(ctx context.Context, email string) (*model.Customer, error) {
+	query := `
+        SELECT
+            id, stripe_id, email, first_name, last_name, COALESCE(phone_number, ''),
+            active, created_at, updated_at
+        FROM customers
+        WHERE LOWER(email) = LOWER($1)
+    `
+
+	return r.getOne(ctx, query, email)
+}
+
+// GetByStripeID retrieves a customer by its Stripe customer ID
+func (r *customerRepository) GetByStripeID(ctx context.Context, stripeID string) (*model.Customer, error) {
+	query := `
+        SELECT
+            id, stripe_id, email, first_name, last_name, COALESCE(phone_number, ''),
+            active, created_at, updated_at
+        FROM customers
+        WHERE stripe_id = $1
+    `
+
+	return r.getOne(ctx, query, stripeID)
+}
+
+// getOne runs a single-row customer query and scans the result
+func (r *customerRepository) getOne(ctx context.Context, query string, arg interface{}) (*model.Customer, error) {
+	var customer model.Customer
+
+	err := r.db.QueryRowContext(ctx, query, arg).Scan(
+		&customer.ID,
+		&customer.StripeID,
+		&customer.Email,
+		&customer.FirstName,
+		&customer.LastName,
+		&customer.PhoneNumber,
+		&customer.Active,
+		&customer.CreatedAt,
+		&customer.UpdatedAt,
+	)
+
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Customer not found
+		}
+		return nil, fmt.Errorf("failed to get customer: %w", err)
+	}
+
+	return &customer, nil
+}
+
+// List retrieves customers with pagination
+func (r *customerRepository) List(ctx context.Context, offset, limit int, includeInactive bool) ([]*model.Customer, int, error) {
+	whereClause := ""
+	if !includeInactive {
+		whereClause = "WHERE active = true"
+	}
+
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM customers %s", whereClause)
+	listQuery := fmt.Sprintf(`
+		SELECT
+			id, stripe_id, email, first_name, last_name, COALESCE(phone_number, ''),
+			active, created_at, updated_at
+		FROM customers
+		%s
+		ORDER BY last_name, first_name, email
+		LIMIT $1 OFFSET $2
+	`, whereClause)
+
+	// Get total count
+	var total int
+	err := r.db.QueryRowContext(ctx, countQuery).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count customers: %w", err)
+	}
+
+	if total == 0 {
+		return []*model.Customer{}, 0, nil
+	}
+
+	rows, err := r.db.QueryContext(ctx, listQuery, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+	}
+	defer rows.Close()
+
+	customers := make([]*model.Customer, 0)
+	for rows.Next() {
+		var customer model.Customer
+
+		err := rows.Scan(
+			&customer.ID,
+			&customer.StripeID,
+			&customer.Email,
+			&customer.FirstName,
+			&customer.LastName,
+			&customer.PhoneNumber,
+			&customer.Active,
+			&customer.CreatedAt,
+			&customer.UpdatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan customer: %w", err)
+		}
+
+		customers = append(customers, &customer)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during customer rows iteration: %w", err)
+	}
+
+	return customers, total, nil
+}
+
+// Update updates an existing customer
+func (r *customerRepository) Update(ctx context.Context, customer *model.Customer) error {
+	customer.UpdatedAt = time.Now()
+
+	query := `
+        UPDATE customers SET
+            stripe_id = $1,
+            email = $2,
+            first_name = $3,
+            last_name = $4,
+            phone_number = $5,
+            active = $6,
+            updated_at = $7
+        WHERE id = $8
+    `
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		customer.StripeID,
+		customer.Email,
+		customer.FirstName,
+		customer.LastName,
+		customer.PhoneNumber,
+		customer.Active,
+		customer.UpdatedAt,
+		customer.ID,
+	)
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("customer_id", customer.ID.String()).
+			Msg("Failed to update customer")
+		return fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("customer with ID %s not found", customer.ID)
+	}
+
+	r.logger.Debug().
+		Str("customer_id", customer.ID.String()).
+		Int64("rows_affected", rowsAffected).
+		Msg("Customer updated successfully")
+
+	return nil
+}
+
+// Deactivate marks a customer as inactive without removing their history
+func (r *customerRepository) Deactivate(ctx context.Context, id uuid.UUID) error {
+	query := `
+		UPDATE customers SET
+			active = false,
+			updated_at = $1
+		WHERE id = $2
+	`
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), id)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to deactivate customer")
+		return fmt.Errorf("failed to deactivate customer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("customer with ID %s not found", id)
+	}
+
+	return nil
+}
+
+// Delete removes a customer
+func (r *customerRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	query := "DELETE FROM customers WHERE id = $1"
+
+	result, err := r.db.ExecContext(ctx, query, id)
+	if err != nil {
+		// Orders, addresses and subscriptions keep their customer
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrResourceInUse
+		}
+		r.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to delete customer")
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("customer with ID %s not found", id)
+	}
+
+	r.logger.Debug().
+		Str("customer_id", id.String()).
+		Int64("rows_affected", rowsAffected).
+		Msg("Customer deleted successfully")
+
+	return nil
+}
@@ -0,0 +1,274 @@
+// internal/repository/postgres/dunning_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// dunningRepository implements the DunningRepository interface
+type dunningRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewDunningRepository creates a new DunningRepository
+func NewDunningRepository(db *DB, logger *zerolog.Logger) interfaces.DunningRepository {
+	return &dunningRepository{
+		db:     db,
+		logger: logger.With().Str("component", "dunning_repository").Logger(),
+	}
+}
+
+// dunningCaseColumns is the column list shared by every dunning case query
+const dunningCaseColumns = `
+	id, subscription_id, customer_id, invoice_id, stripe_invoice_id, status, attempt_count,
+	reminders_sent, held_delivery_date, next_action_at, started_at, resolved_at, created_at, updated_at
+`
+
+// scanDunningCase scans a row selected with dunningCaseColumns
+func scanDunningCase(row rowScanner) (*model.DunningCase, error) {
+	var dunningCase model.DunningCase
+	var heldDeliveryDate, nextActionAt, resolvedAt sql.NullTime
+
+	err := row.Scan(
+		&dunningCase.ID,
+		&dunningCase.SubscriptionID,
+		&dunningCase.CustomerID,
+		&dunningCase.InvoiceID,
+		&dunningCase.StripeInvoiceID,
+		&dunningCase.Status,
+		&dunningCase.AttemptCount,
+		&dunningCase.RemindersSent,
+		&heldDeliveryDate,
+		&nextActionAt,
+		&dunningCase.StartedAt,
+		&resolvedAt,
+		&dunningCase.CreatedAt,
+		&dunningCase.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if heldDeliveryDate.Valid {
+		dunningCase.HeldDeliveryDate = &heldDeliveryDate.Time
+	}
+	if nextActionAt.Valid {
+		dunningCase.NextActionAt = &nextActionAt.Time
+	}
+	if resolvedAt.Valid {
+		dunningCase.ResolvedAt = &resolvedAt.Time
+	}
+
+	return &dunningCase, nil
+}
+
+// Create adds a new dunning case and its first steps in a single transaction
+func (r *dunningRepository) Create(ctx context.Context, dunningCase *model.DunningCase, steps ...*model.DunningStep) error {
+	query := `
+        INSERT INTO dunning_cases (
+            id, subscription_id, customer_id, invoice_id, stripe_invoice_id, status, attempt_count,
+            reminders_sent, held_delivery_date, next_action_at, started_at, resolved_at, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14
+        )
+    `
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			dunningCase.ID,
+			dunningCase.SubscriptionID,
+			dunningCase.CustomerID,
+			dunningCase.InvoiceID,
+			dunningCase.StripeInvoiceID,
+			dunningCase.Status,
+			dunningCase.AttemptCount,
+			dunningCase.RemindersSent,
+			dunningCase.HeldDeliveryDate,
+			dunningCase.NextActionAt,
+			dunningCase.StartedAt,
+			dunningCase.ResolvedAt,
+			dunningCase.CreatedAt,
+			dunningCase.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		return insertDunningSteps(ctx, tx, dunningCase.ID, steps)
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("subscription_id", dunningCase.SubscriptionID.String()).
+			Str("stripe_invoice_id", dunningCase.StripeInvoiceID).
+			Msg("Failed to create dunning case")
+		return fmt.Errorf("failed to create dunning case: %w", err)
+	}
+
+	return nil
+}
+
+// GetOpenBySubscriptionID retrieves the open dunning case of a subscription, if any
+func (r *dunningRepository) GetOpenBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) (*model.DunningCase, error) {
+	query := "SELECT " + dunningCaseColumns + " FROM dunning_cases WHERE subscription_id = $1 AND status = 'open'"
+
+	dunningCase, err := scanDunningCase(r.db.QueryRowContext(ctx, query, subscriptionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // No open case
+		}
+		return nil, fmt.Errorf("failed to get open dunning case: %w", err)
+	}
+
+	return dunningCase, nil
+}
+
+// Update saves a dunning case and records the given steps in a single transaction
+func (r *dunningRepository) Update(ctx context.Context, dunningCase *model.DunningCase, steps ...*model.DunningStep) error {
+	dunningCase.UpdatedAt = time.Now()
+
+	query := `
+        UPDATE dunning_cases SET
+            stripe_invoice_id = $1,
+            invoice_id = $2,
+            status = $3,
+            attempt_count = $4,
+            reminders_sent = $5,
+            held_delivery_date = $6,
+            next_action_at = $7,
+            resolved_at = $8,
+            updated_at = $9
+        WHERE id = $10
+    `
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			dunningCase.StripeInvoiceID,
+			dunningCase.InvoiceID,
+			dunningCase.Status,
+			dunningCase.AttemptCount,
+			dunningCase.RemindersSent,
+			dunningCase.HeldDeliveryDate,
+			dunningCase.NextActionAt,
+			dunningCase.ResolvedAt,
+			dunningCase.UpdatedAt,
+			dunningCase.ID,
+		)
+		if err != nil {
+			return err
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+		if rowsAffected == 0 {
+			return fmt.Errorf("dunning case with ID %s not found", dunningCase.ID)
+		}
+
+		return insertDunningSteps(ctx, tx, dunningCase.ID, steps)
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("dunning_case_id", dunningCase.ID.String()).
+			Msg("Failed to update dunning case")
+		return fmt.Errorf("failed to update dunning case: %w", err)
+	}
+
+	return nil
+}
+
+// GetSteps retrieves the audit trail of a dunning case, oldest first
+func (r *dunningRepository) GetSteps(ctx context.Context, dunningCaseID uuid.UUID) ([]*model.DunningStep, error) {
+	query := `
+        SELECT id, dunning_case_id, step, COALESCE(detail, ''), created_at
+        FROM dunning_steps
+        WHERE dunning_case_id = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, dunningCaseID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query dunning steps: %w", err)
+	}
+	defer rows.Close()
+
+	steps := make([]*model.DunningStep, 0)
+	for rows.Next() {
+		var step model.DunningStep
+		if err := rows.Scan(&step.ID, &step.DunningCaseID, &step.Step, &step.Detail, &step.CreatedAt); err != nil {
+			return nil, fmt.Errorf("failed to scan dunning step: %w", err)
+		}
+		steps = append(steps, &step)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during dunning step rows iteration: %w", err)
+	}
+
+	return steps, nil
+}
+
+// ListDue retrieves open cases whose next reminder or final action is due
+func (r *dunningRepository) ListDue(ctx context.Context, before time.Time, limit int) ([]*model.DunningCase, error) {
+	query := "SELECT " + dunningCaseColumns + `
+        FROM dunning_cases
+        WHERE status = 'open' AND next_action_at <= $1
+        ORDER BY next_action_at
+        LIMIT $2
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, before, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query due dunning cases: %w", err)
+	}
+	defer rows.Close()
+
+	cases := make([]*model.DunningCase, 0)
+	for rows.Next() {
+		dunningCase, err := scanDunningCase(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan dunning case: %w", err)
+		}
+		cases = append(cases, dunningCase)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during dunning case rows iteration: %w", err)
+	}
+
+	return cases, nil
+}
+
+// insertDunningSteps writes audit trail entries inside an open transaction
+func insertDunningSteps(ctx context.Context, tx *sql.Tx, dunningCaseID uuid.UUID, steps []*model.DunningStep) error {
+	query := `
+        INSERT INTO dunning_steps (id, dunning_case_id, step, detail, created_at)
+        VALUES ($1, $2, $3, $4, $5)
+    `
+
+	for _, step := range steps {
+		step.DunningCaseID = dunningCaseID
+		_, err := tx.ExecContext(ctx, query, step.ID, step.DunningCaseID, step.Step, nullString(step.Detail), step.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to record dunning step %s: %w", step.Step, err)
+		}
+	}
+
+	return nil
+}
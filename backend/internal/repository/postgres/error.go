@@ -10,6 +10,9 @@ var (
     // ErrDuplicateName is returned when trying to create a resource with a name that already exists
     ErrDuplicateName = errors.New("resource with this name already exists")
     
+    // ErrDuplicateEmail is returned when trying to create a customer with an email that already exists
+    ErrDuplicateEmail = errors.New("resource with this email already exists")
+    
     // ErrDatabaseConnection is returned when there's an issue connecting to the database
     ErrDatabaseConnection = errors.New("database connection error")
     
@@ -18,6 +21,18 @@ var (
     
     // ErrTransactionFailed is returned when a database transaction fails
     ErrTransactionFailed = errors.New("database transaction failed")
+    
+    // ErrConcurrentUpdate is returned when a row changed between being read and being updated
+    ErrConcurrentUpdate = errors.New("resource was modified concurrently")
+    
+    // ErrInsufficientStock is returned when a reservation asks for more than is available
+    ErrInsufficientStock = errors.New("insufficient stock")
+    
+    // ErrResourceInUse is returned when a resource can't be deleted because other records reference it
+    ErrResourceInUse = errors.New("resource is still referenced")
+    
+    // ErrAlreadyExists is returned when a resource with the same ID or Stripe ID was already created
+    ErrAlreadyExists = errors.New("resource already exists")
 )
 
 // DuplicateNameError is a typed error for duplicate name scenarios with additional context
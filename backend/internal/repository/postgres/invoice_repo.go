@@ -0,0 +1,245 @@
+// internal/repository/postgres/invoice_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// invoiceRepository implements the InvoiceRepository interface
+type invoiceRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewInvoiceRepository creates a new InvoiceRepository
+func NewInvoiceRepository(db *DB, logger *zerolog.Logger) interfaces.InvoiceRepository {
+	return &invoiceRepository{
+		db:     db,
+		logger: logger.With().Str("component", "invoice_repository").Logger(),
+	}
+}
+
+// invoiceColumns is the column list shared by every invoice query
+const invoiceColumns = `
+	id, customer_id, subscription_id, order_id, stripe_id, status, COALESCE(billing_reason, ''),
+	currency, amount_due, amount_paid, period_start, period_end, COALESCE(hosted_invoice_url, ''),
+	attempt_count, next_payment_attempt, paid_at, created_at, updated_at
+`
+
+// scanInvoice scans a row selected with invoiceColumns
+func scanInvoice(row rowScanner) (*model.Invoice, error) {
+	var invoice model.Invoice
+	var subscriptionID, orderID uuid.NullUUID
+	var periodStart, periodEnd, nextAttempt, paidAt sql.NullTime
+
+	err := row.Scan(
+		&invoice.ID,
+		&invoice.CustomerID,
+		&subscriptionID,
+		&orderID,
+		&invoice.StripeID,
+		&invoice.Status,
+		&invoice.BillingReason,
+		&invoice.Currency,
+		&invoice.AmountDue,
+		&invoice.AmountPaid,
+		&periodStart,
+		&periodEnd,
+		&invoice.HostedInvoiceURL,
+		&invoice.AttemptCount,
+		&nextAttempt,
+		&paidAt,
+		&invoice.CreatedAt,
+		&invoice.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriptionID.Valid {
+		invoice.SubscriptionID = &subscriptionID.UUID
+	}
+	if orderID.Valid {
+		invoice.OrderID = &orderID.UUID
+	}
+	invoice.PeriodStart = periodStart.Time
+	invoice.PeriodEnd = periodEnd.Time
+	if nextAttempt.Valid {
+		invoice.NextPaymentAttempt = &nextAttempt.Time
+	}
+	if paidAt.Valid {
+		invoice.PaidAt = &paidAt.Time
+	}
+
+	return &invoice, nil
+}
+
+// Create adds a new invoice to the database
+func (r *invoiceRepository) Create(ctx context.Context, invoice *model.Invoice) error {
+	query := `
+        INSERT INTO invoices (
+            id, customer_id, subscription_id, order_id, stripe_id, status, billing_reason,
+            currency, amount_due, amount_paid, period_start, period_end, hosted_invoice_url,
+            attempt_count, next_payment_attempt, paid_at, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17, $18
+        )
+    `
+
+	_, err := r.db.ExecContext(
+		ctx,
+		query,
+		invoice.ID,
+		invoice.CustomerID,
+		invoice.SubscriptionID,
+		invoice.OrderID,
+		invoice.StripeID,
+		invoice.Status,
+		nullString(invoice.BillingReason),
+		invoice.Currency,
+		invoice.AmountDue,
+		invoice.AmountPaid,
+		nullTime(invoice.PeriodStart),
+		nullTime(invoice.PeriodEnd),
+		nullString(invoice.HostedInvoiceURL),
+		invoice.AttemptCount,
+		invoice.NextPaymentAttempt,
+		invoice.PaidAt,
+		invoice.CreatedAt,
+		invoice.UpdatedAt,
+	)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("stripe_invoice_id", invoice.StripeID).
+			Msg("Failed to create invoice")
+		return fmt.Errorf("failed to create invoice: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an invoice by its ID
+func (r *invoiceRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Invoice, error) {
+	query := "SELECT " + invoiceColumns + " FROM invoices WHERE id = $1"
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Invoice not found
+		}
+		return nil, fmt.Errorf("failed to get invoice: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// GetByStripeID retrieves an invoice by its Stripe ID
+func (r *invoiceRepository) GetByStripeID(ctx context.Context, stripeID string) (*model.Invoice, error) {
+	query := "SELECT " + invoiceColumns + " FROM invoices WHERE stripe_id = $1"
+
+	invoice, err := scanInvoice(r.db.QueryRowContext(ctx, query, stripeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Invoice not found
+		}
+		return nil, fmt.Errorf("failed to get invoice by Stripe ID: %w", err)
+	}
+
+	return invoice, nil
+}
+
+// GetBySubscriptionID retrieves a subscription's invoices, newest first
+func (r *invoiceRepository) GetBySubscriptionID(ctx context.Context, subscriptionID uuid.UUID) ([]*model.Invoice, error) {
+	query := "SELECT " + invoiceColumns + " FROM invoices WHERE subscription_id = $1 ORDER BY created_at DESC"
+
+	rows, err := r.db.QueryContext(ctx, query, subscriptionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query invoices: %w", err)
+	}
+	defer rows.Close()
+
+	invoices := make([]*model.Invoice, 0)
+	for rows.Next() {
+		invoice, err := scanInvoice(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan invoice: %w", err)
+		}
+		invoices = append(invoices, invoice)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during invoice rows iteration: %w", err)
+	}
+
+	return invoices, nil
+}
+
+// Update updates an existing invoice
+func (r *invoiceRepository) Update(ctx context.Context, invoice *model.Invoice) error {
+	invoice.UpdatedAt = time.Now()
+
+	query := `
+        UPDATE invoices SET
+            subscription_id = $1,
+            order_id = $2,
+            status = $3,
+            billing_reason = $4,
+            currency = $5,
+            amount_due = $6,
+            amount_paid = $7,
+            period_start = $8,
+            period_end = $9,
+            hosted_invoice_url = $10,
+            attempt_count = $11,
+            next_payment_attempt = $12,
+            paid_at = $13,
+            updated_at = $14
+        WHERE id = $15
+    `
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		invoice.SubscriptionID,
+		invoice.OrderID,
+		invoice.Status,
+		nullString(invoice.BillingReason),
+		invoice.Currency,
+		invoice.AmountDue,
+		invoice.AmountPaid,
+		nullTime(invoice.PeriodStart),
+		nullTime(invoice.PeriodEnd),
+		nullString(invoice.HostedInvoiceURL),
+		invoice.AttemptCount,
+		invoice.NextPaymentAttempt,
+		invoice.PaidAt,
+		invoice.UpdatedAt,
+		invoice.ID,
+	)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("invoice_id", invoice.ID.String()).
+			Msg("Failed to update invoice")
+		return fmt.Errorf("failed to update invoice: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("invoice with ID %s not found", invoice.ID)
+	}
+
+	return nil
+}
@@ -0,0 +1,570 @@
+// internal/repository/postgres/order_repo.go
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// orderRepository implements the OrderRepository interface
+type orderRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewOrderRepository creates a new OrderRepository
+func NewOrderRepository(db *DB, logger *zerolog.Logger) interfaces.OrderRepository {
+	return &orderRepository{
+		db:     db,
+		logger: logger.With().Str("component", "order_repository").Logger(),
+	}
+}
+
+// orderColumns is the column list shared by every order query
+const orderColumns = `
+	id, customer_id, subscription_id, address_id, status, currency,
+	subtotal, shipping_amount, tax_amount, total,
+	COALESCE(stripe_checkout_session_id, ''), COALESCE(stripe_payment_intent_id, ''), COALESCE(stripe_invoice_id, ''),
+	COALESCE(carrier, ''), COALESCE(tracking_number, ''), metadata,
+	paid_at, shipped_at, delivered_at, canceled_at, created_at, updated_at
+`
+
+// scanOrder scans a row selected with orderColumns
+func scanOrder(row rowScanner) (*model.Order, error) {
+	var order model.Order
+	var subscriptionID, addressID uuid.NullUUID
+	var paidAt, shippedAt, deliveredAt, canceledAt sql.NullTime
+	var metadataJSON []byte
+
+	err := row.Scan(
+		&order.ID,
+		&order.CustomerID,
+		&subscriptionID,
+		&addressID,
+		&order.Status,
+		&order.Currency,
+		&order.Subtotal,
+		&order.ShippingAmount,
+		&order.TaxAmount,
+		&order.Total,
+		&order.StripeCheckoutSessionID,
+		&order.StripePaymentIntentID,
+		&order.StripeInvoiceID,
+		&order.Carrier,
+		&order.TrackingNumber,
+		&metadataJSON,
+		&paidAt,
+		&shippedAt,
+		&deliveredAt,
+		&canceledAt,
+		&order.CreatedAt,
+		&order.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscriptionID.Valid {
+		order.SubscriptionID = &subscriptionID.UUID
+	}
+	if addressID.Valid {
+		order.AddressID = &addressID.UUID
+	}
+	if paidAt.Valid {
+		order.PaidAt = &paidAt.Time
+	}
+	if shippedAt.Valid {
+		order.ShippedAt = &shippedAt.Time
+	}
+	if deliveredAt.Valid {
+		order.DeliveredAt = &deliveredAt.Time
+	}
+	if canceledAt.Valid {
+		order.CanceledAt = &canceledAt.Time
+	}
+
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &order.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata for order %s: %w", order.ID, err)
+		}
+	}
+	if order.Metadata == nil {
+		order.Metadata = make(map[string]string)
+	}
+
+	return &order, nil
+}
+
+// nullString stores empty strings as NULL so partial unique indexes ignore them
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+// Create adds a new order, its line items, its initial status history entry
+// and its stock movements in a single transaction
+func (r *orderRepository) Create(ctx context.Context, order *model.Order, movements []*model.InventoryMovement, checkAvailable bool) error {
+	metadataJSON, err := json.Marshal(order.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	orderQuery := `
+        INSERT INTO orders (
+            id, customer_id, subscription_id, address_id, status, currency,
+            subtotal, shipping_amount, tax_amount, total,
+            stripe_checkout_session_id, stripe_payment_intent_id, stripe_invoice_id,
+            carrier, tracking_number, metadata,
+            paid_at, shipped_at, delivered_at, canceled_at, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11,
+            $12, $13, $14, $15, $16, $17, $18, $19, $20, $21, $22
+        )
+    `
+
+	itemQuery := `
+        INSERT INTO order_items (
+            id, order_id, variant_id, product_id, price_id, product_name,
+            options, quantity, unit_amount, total_amount, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+        )
+    `
+
+	err = r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			orderQuery,
+			order.ID,
+			order.CustomerID,
+			order.SubscriptionID,
+			order.AddressID,
+			order.Status,
+			order.Currency,
+			order.Subtotal,
+			order.ShippingAmount,
+			order.TaxAmount,
+			order.Total,
+			nullString(order.StripeCheckoutSessionID),
+			nullString(order.StripePaymentIntentID),
+			nullString(order.StripeInvoiceID),
+			nullString(order.Carrier),
+			nullString(order.TrackingNumber),
+			metadataJSON,
+			order.PaidAt,
+			order.ShippedAt,
+			order.DeliveredAt,
+			order.CanceledAt,
+			order.CreatedAt,
+			order.UpdatedAt,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to insert order: %w", err)
+		}
+
+		for _, item := range order.Items {
+			optionsJSON, err := json.Marshal(item.Options)
+			if err != nil {
+				return fmt.Errorf("failed to marshal item options: %w", err)
+			}
+
+			_, err = tx.ExecContext(
+				ctx,
+				itemQuery,
+				item.ID,
+				order.ID,
+				item.VariantID,
+				item.ProductID,
+				item.PriceID,
+				item.ProductName,
+				optionsJSON,
+				item.Quantity,
+				item.UnitAmount,
+				item.TotalAmount,
+				item.CreatedAt,
+			)
+			if err != nil {
+				return fmt.Errorf("failed to insert order item: %w", err)
+			}
+		}
+
+		if err := insertStatusChange(ctx, tx, order.ID, "", order.Status, "order created", order.CreatedAt); err != nil {
+			return err
+		}
+
+		return recordOrderStock(ctx, tx, movements, checkAvailable)
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrInsufficientStock) {
+			return err
+		}
+		r.logger.Error().Err(err).
+			Str("order_id", order.ID.String()).
+			Str("customer_id", order.CustomerID.String()).
+			Msg("Failed to create order")
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	return nil
+}
+
+// recordOrderStock applies an order's stock movements. Variant rows are locked
+// in ID order, as reservations lock them, so concurrent orders cannot deadlock
+func recordOrderStock(ctx context.Context, tx *sql.Tx, movements []*model.InventoryMovement, checkAvailable bool) error {
+	sorted := make([]*model.InventoryMovement, len(movements))
+	copy(sorted, movements)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].VariantID[:], sorted[j].VariantID[:]) < 0
+	})
+
+	for _, movement := range sorted {
+		if checkAvailable && movement.Quantity < 0 {
+			var stockLevel, held int
+			err := tx.QueryRowContext(ctx, "SELECT stock_level FROM variants WHERE id = $1 FOR UPDATE", movement.VariantID).Scan(&stockLevel)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("variant with ID %s not found", movement.VariantID)
+				}
+				return err
+			}
+			if err := tx.QueryRowContext(ctx, heldQuantityQuery, movement.VariantID, time.Now()).Scan(&held); err != nil {
+				return err
+			}
+			if stockLevel-held < -movement.Quantity {
+				return fmt.Errorf("%w for variant %s: %d available, %d requested", ErrInsufficientStock, movement.VariantID, stockLevel-held, -movement.Quantity)
+			}
+		}
+
+		if err := recordStockMovement(ctx, tx, movement, nil); err != nil {
+			if errors.Is(err, ErrResourceNotFound) {
+				return fmt.Errorf("variant with ID %s not found", movement.VariantID)
+			}
+			return fmt.Errorf("failed to record stock movement: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// insertStatusChange appends an entry to order_status_history
+func insertStatusChange(ctx context.Context, tx *sql.Tx, orderID uuid.UUID, fromStatus, toStatus, note string, at time.Time) error {
+	query := `
+        INSERT INTO order_status_history (id, order_id, from_status, to_status, note, created_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+    `
+
+	_, err := tx.ExecContext(ctx, query, uuid.New(), orderID, nullString(fromStatus), toStatus, nullString(note), at)
+	if err != nil {
+		return fmt.Errorf("failed to record status change: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves an order and its line items
+func (r *orderRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE id = $1"
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Order not found
+		}
+		return nil, fmt.Errorf("failed to get order: %w", err)
+	}
+
+	order.Items, err = r.getItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetByCheckoutSessionID retrieves the order created from a Stripe Checkout session
+func (r *orderRepository) GetByCheckoutSessionID(ctx context.Context, sessionID string) (*model.Order, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE stripe_checkout_session_id = $1"
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, sessionID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Order not found
+		}
+		return nil, fmt.Errorf("failed to get order by checkout session: %w", err)
+	}
+
+	order.Items, err = r.getItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// GetByInvoiceID retrieves the order created for a paid Stripe invoice
+func (r *orderRepository) GetByInvoiceID(ctx context.Context, invoiceID string) (*model.Order, error) {
+	query := "SELECT " + orderColumns + " FROM orders WHERE stripe_invoice_id = $1"
+
+	order, err := scanOrder(r.db.QueryRowContext(ctx, query, invoiceID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Order not found
+		}
+		return nil, fmt.Errorf("failed to get order by invoice: %w", err)
+	}
+
+	order.Items, err = r.getItems(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// getItems loads the line items of an order
+func (r *orderRepository) getItems(ctx context.Context, orderID uuid.UUID) ([]*model.OrderItem, error) {
+	query := `
+        SELECT
+            id, order_id, variant_id, product_id, price_id, product_name,
+            options, quantity, unit_amount, total_amount, created_at
+        FROM order_items
+        WHERE order_id = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order items: %w", err)
+	}
+	defer rows.Close()
+
+	items := make([]*model.OrderItem, 0)
+	for rows.Next() {
+		var item model.OrderItem
+		var optionsJSON []byte
+
+		err := rows.Scan(
+			&item.ID,
+			&item.OrderID,
+			&item.VariantID,
+			&item.ProductID,
+			&item.PriceID,
+			&item.ProductName,
+			&optionsJSON,
+			&item.Quantity,
+			&item.UnitAmount,
+			&item.TotalAmount,
+			&item.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order item: %w", err)
+		}
+
+		if len(optionsJSON) > 0 {
+			if err := json.Unmarshal(optionsJSON, &item.Options); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal options for order item %s: %w", item.ID, err)
+			}
+		}
+
+		items = append(items, &item)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during order item rows iteration: %w", err)
+	}
+
+	return items, nil
+}
+
+// List retrieves orders with pagination, optionally filtered by customer and status.
+// Line items are not loaded for listings
+func (r *orderRepository) List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Order, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if customerID != nil {
+		args = append(args, *customerID)
+		whereConditions = append(whereConditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+
+	if status != "" {
+		args = append(args, status)
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM orders %s", whereClause)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count orders: %w", err)
+	}
+
+	if total == 0 {
+		return []*model.Order{}, 0, nil
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM orders
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, orderColumns, whereClause, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, listQuery, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+	defer rows.Close()
+
+	orders := make([]*model.Order, 0)
+	for rows.Next() {
+		order, err := scanOrder(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan order: %w", err)
+		}
+		orders = append(orders, order)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during order rows iteration: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// UpdateStatus persists a status transition, records it in the history and
+// applies any stock movements it causes. The update only applies while the
+// stored status still equals fromStatus
+func (r *orderRepository) UpdateStatus(ctx context.Context, order *model.Order, fromStatus, note string, movements []*model.InventoryMovement) error {
+	order.UpdatedAt = time.Now()
+
+	metadataJSON, err := json.Marshal(order.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+        UPDATE orders SET
+            status = $1,
+            stripe_payment_intent_id = $2,
+            carrier = $3,
+            tracking_number = $4,
+            metadata = $5,
+            paid_at = $6,
+            shipped_at = $7,
+            delivered_at = $8,
+            canceled_at = $9,
+            updated_at = $10
+        WHERE id = $11 AND status = $12
+    `
+
+	err = r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			order.Status,
+			nullString(order.StripePaymentIntentID),
+			nullString(order.Carrier),
+			nullString(order.TrackingNumber),
+			metadataJSON,
+			order.PaidAt,
+			order.ShippedAt,
+			order.DeliveredAt,
+			order.CanceledAt,
+			order.UpdatedAt,
+			order.ID,
+			fromStatus,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update order status: %w", err)
+		}
+
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
+
+		if rowsAffected == 0 {
+			return ErrConcurrentUpdate
+		}
+
+		if err := insertStatusChange(ctx, tx, order.ID, fromStatus, order.Status, note, order.UpdatedAt); err != nil {
+			return err
+		}
+
+		return recordOrderStock(ctx, tx, movements, false)
+	})
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("order_id", order.ID.String()).
+			Str("from_status", fromStatus).
+			Str("to_status", order.Status).
+			Msg("Failed to update order status")
+		return err
+	}
+
+	r.logger.Debug().
+		Str("order_id", order.ID.String()).
+		Str("from_status", fromStatus).
+		Str("to_status", order.Status).
+		Msg("Order status updated successfully")
+
+	return nil
+}
+
+// GetStatusHistory retrieves the status transitions of an order, oldest first
+func (r *orderRepository) GetStatusHistory(ctx context.Context, orderID uuid.UUID) ([]*model.OrderStatusChange, error) {
+	query := `
+        SELECT id, order_id, COALESCE(from_status, ''), to_status, COALESCE(note, ''), created_at
+        FROM order_status_history
+        WHERE order_id = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, orderID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query order status history: %w", err)
+	}
+	defer rows.Close()
+
+	history := make([]*model.OrderStatusChange, 0)
+	for rows.Next() {
+		var change model.OrderStatusChange
+		err := rows.Scan(
+			&change.ID,
+			&change.OrderID,
+			&change.FromStatus,
+			&change.ToStatus,
+			&change.Note,
+			&change.CreatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan order status change: %w", err)
+		}
+		history = append(history, &change)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during order status history rows iteration: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,128 @@
+package postgres
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// createTestCustomer inserts a customer for orders to belong to
+func createTestCustomer(t *testing.T, db *DB) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	customerID := uuid.New()
+	_, err := db.ExecContext(ctx,
+		"INSERT INTO customers (id, stripe_id, email, first_name, last_name) VALUES ($1, $2, $3, 'Test', 'Buyer')",
+		customerID, "cus_"+customerID.String(), customerID.String()+"@example.com",
+	)
+	if err != nil {
+		t.Fatalf("failed to seed customer: %v", err)
+	}
+	t.Cleanup(func() {
+		db.ExecContext(ctx, "DELETE FROM customers WHERE id = $1", customerID)
+	})
+
+	return customerID
+}
+
+// newTestOrder builds a pending order for quantity of a variant, with the
+// movement that takes it out of stock
+func newTestOrder(t *testing.T, db *DB, customerID, variantID uuid.UUID, quantity int) (*model.Order, []*model.InventoryMovement) {
+	t.Helper()
+
+	var productID, priceID uuid.UUID
+	err := db.QueryRowContext(context.Background(), "SELECT product_id, price_id FROM variants WHERE id = $1", variantID).Scan(&productID, &priceID)
+	if err != nil {
+		t.Fatalf("failed to look up variant: %v", err)
+	}
+
+	now := time.Now()
+	order := &model.Order{
+		ID:         uuid.New(),
+		CustomerID: customerID,
+		Status:     model.OrderStatusPending,
+		Currency:   "USD",
+		Metadata:   map[string]string{},
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+	order.Items = []*model.OrderItem{{
+		ID:          uuid.New(),
+		OrderID:     order.ID,
+		VariantID:   variantID,
+		ProductID:   productID,
+		PriceID:     priceID,
+		ProductName: "Test Coffee",
+		Quantity:    quantity,
+		UnitAmount:  1800,
+		TotalAmount: 1800 * int64(quantity),
+		CreatedAt:   now,
+	}}
+
+	movements := []*model.InventoryMovement{{
+		ID:        uuid.New(),
+		VariantID: variantID,
+		Type:      model.MovementTypeSale,
+		Quantity:  -quantity,
+		Reason:    "order_placed",
+		Reference: order.ID.String(),
+		CreatedAt: now,
+	}}
+
+	return order, movements
+}
+
+func TestOrderCreateTakesStock(t *testing.T) {
+	db := testDB(t)
+	logger := zerolog.Nop()
+	repo := NewOrderRepository(db, &logger)
+	reservations := NewStockReservationRepository(db, &logger)
+	ctx := context.Background()
+
+	variantID := createTestVariant(t, db, 5)
+	customerID := createTestCustomer(t, db)
+	t.Cleanup(func() {
+		db.ExecContext(ctx, "DELETE FROM orders WHERE customer_id = $1", customerID)
+	})
+
+	// An open checkout holds 3 of the 5 bags
+	if err := reservations.Reserve(ctx, []*model.StockReservation{newReservation(variantID, "ref-"+uuid.NewString(), 3, time.Now().Add(time.Hour))}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	order, movements := newTestOrder(t, db, customerID, variantID, 3)
+	if err := repo.Create(ctx, order, movements, true); !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("Create() over held stock error = %v, want ErrInsufficientStock", err)
+	}
+	if saved, err := repo.GetByID(ctx, order.ID); err != nil || saved != nil {
+		t.Errorf("rejected order was saved: %+v, %v", saved, err)
+	}
+
+	order, movements = newTestOrder(t, db, customerID, variantID, 2)
+	if err := repo.Create(ctx, order, movements, true); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+
+	var stockLevel int
+	if err := db.QueryRowContext(ctx, "SELECT stock_level FROM variants WHERE id = $1", variantID).Scan(&stockLevel); err != nil {
+		t.Fatalf("failed to read stock level: %v", err)
+	}
+	if stockLevel != 3 {
+		t.Errorf("stock_level = %d, want 3", stockLevel)
+	}
+	if movements[0].BalanceAfter != 3 {
+		t.Errorf("movement balance_after = %d, want 3", movements[0].BalanceAfter)
+	}
+
+	// Paid Stripe orders are recorded even when the stock is held
+	order, movements = newTestOrder(t, db, customerID, variantID, 3)
+	if err := repo.Create(ctx, order, movements, false); err != nil {
+		t.Errorf("Create() without availability check error = %v", err)
+	}
+}
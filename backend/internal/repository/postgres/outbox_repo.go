@@ -0,0 +1,151 @@
+// internal/repository/postgres/outbox_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/rs/zerolog"
+)
+
+// outboxRepository implements the OutboxRepository interface
+type outboxRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewOutboxRepository creates a new OutboxRepository
+func NewOutboxRepository(db *DB, logger *zerolog.Logger) interfaces.OutboxRepository {
+	return &outboxRepository{
+		db:     db,
+		logger: logger.With().Str("component", "outbox_repository").Logger(),
+	}
+}
+
+// insertOutboxEvents writes events inside the transaction of the change they describe
+func insertOutboxEvents(ctx context.Context, tx *sql.Tx, outbox []*model.OutboxEvent) error {
+	query := `
+        INSERT INTO outbox (id, topic, payload, created_at)
+        VALUES ($1, $2, $3, $4)
+    `
+
+	for _, event := range outbox {
+		if event.CreatedAt.IsZero() {
+			event.CreatedAt = time.Now()
+		}
+
+		_, err := tx.ExecContext(ctx, query, event.ID, event.Topic, []byte(event.Payload), event.CreatedAt)
+		if err != nil {
+			return fmt.Errorf("failed to write %s event to outbox: %w", event.Topic, err)
+		}
+	}
+
+	return nil
+}
+
+// ProcessPending publishes a batch of unsent events. Rows are locked with SKIP
+// LOCKED so several relays can run without publishing the same event twice
+func (r *outboxRepository) ProcessPending(ctx context.Context, limit int, publish func(event *model.OutboxEvent) error) (int, error) {
+	sent := 0
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		rows, err := tx.QueryContext(ctx, `
+            SELECT id, topic, payload, attempts, COALESCE(last_error, ''), created_at
+            FROM outbox
+            WHERE sent_at IS NULL
+            ORDER BY created_at
+            LIMIT $1
+            FOR UPDATE SKIP LOCKED
+        `, limit)
+		if err != nil {
+			return fmt.Errorf("failed to query pending outbox events: %w", err)
+		}
+
+		var pending []*model.OutboxEvent
+		for rows.Next() {
+			var event model.OutboxEvent
+			var payload []byte
+			if err := rows.Scan(&event.ID, &event.Topic, &payload, &event.Attempts, &event.LastError, &event.CreatedAt); err != nil {
+				rows.Close()
+				return fmt.Errorf("failed to scan outbox event: %w", err)
+			}
+			event.Payload = payload
+			pending = append(pending, &event)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return fmt.Errorf("error iterating outbox events: %w", err)
+		}
+
+		for _, event := range pending {
+			if publishErr := publish(event); publishErr != nil {
+				r.logger.Warn().Err(publishErr).
+					Str("event_id", event.ID.String()).
+					Str("topic", event.Topic).
+					Int("attempts", event.Attempts+1).
+					Msg("Failed to publish outbox event")
+
+				_, err := tx.ExecContext(ctx, `
+                    UPDATE outbox SET attempts = attempts + 1, last_error = $1
+                    WHERE id = $2
+                `, publishErr.Error(), event.ID)
+				if err != nil {
+					return fmt.Errorf("failed to record outbox failure: %w", err)
+				}
+				return nil
+			}
+
+			now := time.Now()
+			_, err := tx.ExecContext(ctx, `
+                UPDATE outbox SET sent_at = $1, attempts = attempts + 1, last_error = NULL
+                WHERE id = $2
+            `, now, event.ID)
+			if err != nil {
+				return fmt.Errorf("failed to mark outbox event sent: %w", err)
+			}
+			event.SentAt = &now
+			sent++
+		}
+
+		return nil
+	})
+	if err != nil {
+		return 0, err
+	}
+
+	return sent, nil
+}
+
+// PendingStats returns the number of unsent events and the age of the oldest
+func (r *outboxRepository) PendingStats(ctx context.Context) (int, *time.Time, error) {
+	query := `
+        SELECT COUNT(*), MIN(created_at)
+        FROM outbox
+        WHERE sent_at IS NULL
+    `
+
+	var count int
+	var oldest sql.NullTime
+	if err := r.db.QueryRowContext(ctx, query).Scan(&count, &oldest); err != nil {
+		return 0, nil, fmt.Errorf("failed to get outbox stats: %w", err)
+	}
+
+	if !oldest.Valid {
+		return count, nil, nil
+	}
+	return count, &oldest.Time, nil
+}
+
+// DeleteSentBefore prunes published events
+func (r *outboxRepository) DeleteSentBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.db.ExecContext(ctx, "DELETE FROM outbox WHERE sent_at < $1", before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to prune outbox: %w", err)
+	}
+
+	return result.RowsAffected()
+}
@@ -78,10 +78,10 @@ func (db *DB) Close() error {
 }
 
 // Transaction executes a function within a database transaction
-func (db *DB) Transaction(txFunc func(*sql.Tx) error) error {
-	tx, err := db.Begin()
-	if err != nil {
-		return fmt.Errorf("failed to begin transaction: %w", err)
+func (db *DB) Transaction(txFunc func(*sql.Tx) error) (err error) {
+	tx, beginErr := db.Begin()
+	if beginErr != nil {
+		return fmt.Errorf("failed to begin transaction: %w", beginErr)
 	}
 
 	defer func() {
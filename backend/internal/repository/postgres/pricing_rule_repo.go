@@ -0,0 +1,114 @@
+// internal/repository/postgres/pricing_rule_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// pricingRuleRepository implements the PricingRuleRepository interface
+type pricingRuleRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewPricingRuleRepository creates a new PricingRuleRepository
+func NewPricingRuleRepository(db *DB, logger *zerolog.Logger) interfaces.PricingRuleRepository {
+	return &pricingRuleRepository{
+		db:     db,
+		logger: logger.With().Str("component", "pricing_rule_repository").Logger(),
+	}
+}
+
+// GetByProductID retrieves a product's pricing rule
+func (r *pricingRuleRepository) GetByProductID(ctx context.Context, productID uuid.UUID) (*model.PricingRule, error) {
+	query := `
+        SELECT product_id, currency, base_price, weight_prices, surcharges, created_at, updated_at
+        FROM pricing_rules
+        WHERE product_id = $1
+    `
+
+	var rule model.PricingRule
+	var weightPricesJSON, surchargesJSON []byte
+
+	err := r.db.QueryRowContext(ctx, query, productID).Scan(
+		&rule.ProductID,
+		&rule.Currency,
+		&rule.BasePrice,
+		&weightPricesJSON,
+		&surchargesJSON,
+		&rule.CreatedAt,
+		&rule.UpdatedAt,
+	)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Product has no pricing rule
+		}
+		return nil, fmt.Errorf("failed to get pricing rule: %w", err)
+	}
+
+	if err := json.Unmarshal(weightPricesJSON, &rule.WeightPrices); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal weight prices: %w", err)
+	}
+	if err := json.Unmarshal(surchargesJSON, &rule.Surcharges); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal surcharges: %w", err)
+	}
+
+	return &rule, nil
+}
+
+// Upsert creates or replaces a product's pricing rule
+func (r *pricingRuleRepository) Upsert(ctx context.Context, rule *model.PricingRule) error {
+	weightPricesJSON, err := json.Marshal(rule.WeightPrices)
+	if err != nil {
+		return fmt.Errorf("failed to marshal weight prices: %w", err)
+	}
+
+	surchargesJSON, err := json.Marshal(rule.Surcharges)
+	if err != nil {
+		return fmt.Errorf("failed to marshal surcharges: %w", err)
+	}
+
+	query := `
+        INSERT INTO pricing_rules (
+            product_id, currency, base_price, weight_prices, surcharges, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7
+        )
+        ON CONFLICT (product_id) DO UPDATE SET
+            currency = EXCLUDED.currency,
+            base_price = EXCLUDED.base_price,
+            weight_prices = EXCLUDED.weight_prices,
+            surcharges = EXCLUDED.surcharges,
+            updated_at = EXCLUDED.updated_at
+        RETURNING created_at
+    `
+
+	err = r.db.QueryRowContext(
+		ctx,
+		query,
+		rule.ProductID,
+		rule.Currency,
+		rule.BasePrice,
+		weightPricesJSON,
+		surchargesJSON,
+		rule.CreatedAt,
+		rule.UpdatedAt,
+	).Scan(&rule.CreatedAt)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("product_id", rule.ProductID.String()).
+			Msg("Failed to save pricing rule")
+		return fmt.Errorf("failed to save pricing rule: %w", err)
+	}
+
+	return nil
+}
@@ -31,7 +31,7 @@ func NewProductRepository(db *DB, logger *zerolog.Logger) interfaces.ProductRepo
 }
 
 // Create adds a new product to the database
-func (r *productRepository) Create(ctx context.Context, product *model.Product) error {
+func (r *productRepository) Create(ctx context.Context, product *model.Product, outbox ...*model.OutboxEvent) error {
 	// Convert Options map to JSON string for storage
 	optionsJSON, err := json.Marshal(product.Options)
 	if err != nil {
@@ -40,50 +40,53 @@ func (r *productRepository) Create(ctx context.Context, product *model.Product)
 
 	query := `
 		INSERT INTO products (
-			id, name, description, image_url, active, archived, stock_level,
+			id, name, description, image_url, active, archived, stock_level, reorder_threshold,
 			weight, origin, roast_level, flavor_notes, options, allow_subscription, stripe_id,
 			created_at, updated_at
 		) VALUES (
-			$1, $2, $3, $4, $5, $6,
-			$7, $8, $9, $10, $11, $12, $13,
-			$14, $15, $16
+			$1, $2, $3, $4, $5, $6, $7,
+			$8, $9, $10, $11, $12, $13, $14,
+			$15, $16, $17
 		)
 	`
 
-	// Access the underlying *sql.DB through our custom DB type
-	_, err = r.db.ExecContext(
-		ctx,
-		query,
-		product.ID,
-		product.Name,
-		product.Description,
-		product.ImageURL,
-		product.Active,
-		product.Archived,
-		product.StockLevel,
-		product.Weight,
-		product.Origin,
-		product.RoastLevel,
-		product.FlavorNotes,
-		optionsJSON,
-		product.AllowSubscription,
-		product.StripeID,
-		product.CreatedAt,
-		product.UpdatedAt,
-	)
+	// The product and its events are committed together
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			product.ID,
+			product.Name,
+			product.Description,
+			product.ImageURL,
+			product.Active,
+			product.Archived,
+			product.StockLevel,
+			product.ReorderThreshold,
+			product.Weight,
+			product.Origin,
+			product.RoastLevel,
+			product.FlavorNotes,
+			optionsJSON,
+			product.AllowSubscription,
+			product.StripeID,
+			product.CreatedAt,
+			product.UpdatedAt,
+		)
 
-	if err != nil {
-		return fmt.Errorf("failed to create product: %w", err)
-	}
+		if err != nil {
+			return fmt.Errorf("failed to create product: %w", err)
+		}
 
-	return nil
+		return insertOutboxEvents(ctx, tx, outbox)
+	})
 }
 
 // GetByID retrieves a product by its ID
 func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Product, error) {
 	query := `
 		SELECT 
-			id, name, description, image_url, active, archived, stock_level,
+			id, name, description, image_url, active, archived, stock_level, reorder_threshold,
 			weight, origin, roast_level, flavor_notes, options, allow_subscription, stripe_id,
 			created_at, updated_at
 		FROM products
@@ -101,6 +104,7 @@ func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.P
 		&product.Active,
 		&product.Archived,
 		&product.StockLevel,
+		&product.ReorderThreshold,
 		&product.Weight,
 		&product.Origin,
 		&product.RoastLevel,
@@ -136,7 +140,7 @@ func (r *productRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.P
 func (r *productRepository) GetByName(ctx context.Context, name string) (*model.Product, error) {
     query := `
         SELECT id, stripe_id, name, description, image_url, origin, roast_level,
-               stock_level, flavor_notes, active, archived, options, allow_subscription,
+               stock_level, reorder_threshold, flavor_notes, active, archived, options, allow_subscription,
                created_at, updated_at
         FROM products
         WHERE name = $1
@@ -157,6 +161,7 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (*model.
         &product.Origin,
         &product.RoastLevel,
         &product.StockLevel,
+        &product.ReorderThreshold,
         &product.FlavorNotes,
         &product.Active,
 		&product.Archived,
@@ -191,7 +196,7 @@ func (r *productRepository) GetByName(ctx context.Context, name string) (*model.
 func (r *productRepository) GetByStripeID(ctx context.Context, stripeID string) (*model.Product, error) {
 	query := `
 		SELECT 
-			id, name, description, image_url, active, archived, stock_level,
+			id, name, description, image_url, active, archived, stock_level, reorder_threshold,
 			weight, origin, roast_level, flavor_notes, options, allow_subscription, stripe_id,
 			created_at, updated_at
 		FROM products
@@ -209,6 +214,7 @@ func (r *productRepository) GetByStripeID(ctx context.Context, stripeID string)
 		&product.Active,
 		&product.Archived,
 		&product.StockLevel,
+		&product.ReorderThreshold,
 		&product.Weight,
 		&product.Origin,
 		&product.RoastLevel,
@@ -265,7 +271,7 @@ func (r *productRepository) List(ctx context.Context, offset, limit int, include
 	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM products %s", whereClause)
 	listQuery := fmt.Sprintf(`
 		SELECT 
-			id, name, description, image_url, active, archived, stock_level,
+			id, name, description, image_url, active, archived, stock_level, reorder_threshold,
 			weight, origin, roast_level, flavor_notes, options, allow_subscription, stripe_id,
 			created_at, updated_at
 		FROM products
@@ -309,6 +315,7 @@ func (r *productRepository) List(ctx context.Context, offset, limit int, include
 			&product.Active,
 			&product.Archived,
 			&product.StockLevel,
+			&product.ReorderThreshold,
 			&product.Weight,
 			&product.Origin,
 			&product.RoastLevel,
@@ -343,7 +350,7 @@ func (r *productRepository) List(ctx context.Context, offset, limit int, include
 }
 
 // Update updates an existing product
-func (r *productRepository) Update(ctx context.Context, product *model.Product) error {
+func (r *productRepository) Update(ctx context.Context, product *model.Product, outbox ...*model.OutboxEvent) error {
 	product.UpdatedAt = time.Now()
 
 	// Convert Options map to JSON string for storage
@@ -360,55 +367,60 @@ func (r *productRepository) Update(ctx context.Context, product *model.Product)
 			active = $4,
 			archived = $5,
 			stock_level = $6,
-			weight = $7,
-			origin = $8,
-			roast_level = $9,
-			flavor_notes = $10,
-			options = $11,
-			allow_subscription = $12,
-			stripe_id = $13,
-			updated_at = $14
-		WHERE id = $15
+			reorder_threshold = $7,
+			weight = $8,
+			origin = $9,
+			roast_level = $10,
+			flavor_notes = $11,
+			options = $12,
+			allow_subscription = $13,
+			stripe_id = $14,
+			updated_at = $15
+		WHERE id = $16
 	`
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		product.Name,
-		product.Description,
-		product.ImageURL,
-		product.Active,
-		product.Archived,
-		product.StockLevel,
-		product.Weight,
-		product.Origin,
-		product.RoastLevel,
-		product.FlavorNotes,
-		optionsJSON,
-		product.AllowSubscription,
-		product.StripeID,
-		product.UpdatedAt,
-		product.ID,
-	)
+	// The update and its events are committed together
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			product.Name,
+			product.Description,
+			product.ImageURL,
+			product.Active,
+			product.Archived,
+			product.StockLevel,
+			product.ReorderThreshold,
+			product.Weight,
+			product.Origin,
+			product.RoastLevel,
+			product.FlavorNotes,
+			optionsJSON,
+			product.AllowSubscription,
+			product.StripeID,
+			product.UpdatedAt,
+			product.ID,
+		)
 
-	if err != nil {
-		return fmt.Errorf("failed to update product: %w", err)
-	}
+		if err != nil {
+			return fmt.Errorf("failed to update product: %w", err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("product with ID %s not found", product.ID)
-	}
+		if rowsAffected == 0 {
+			return fmt.Errorf("product with ID %s not found", product.ID)
+		}
 
-	return nil
+		return insertOutboxEvents(ctx, tx, outbox)
+	})
 }
 
 // Archive marks a product as archived (soft delete)
-func (r *productRepository) Archive(ctx context.Context, id uuid.UUID) error {
+func (r *productRepository) Archive(ctx context.Context, id uuid.UUID, outbox ...*model.OutboxEvent) error {
 	query := `
 		UPDATE products SET
 			archived = true,
@@ -417,79 +429,50 @@ func (r *productRepository) Archive(ctx context.Context, id uuid.UUID) error {
 		WHERE id = $2
 	`
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		time.Now(),
-		id,
-	)
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(
+			ctx,
+			query,
+			time.Now(),
+			id,
+		)
 
-	if err != nil {
-		return fmt.Errorf("failed to archive product: %w", err)
-	}
+		if err != nil {
+			return fmt.Errorf("failed to archive product: %w", err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("product with ID %s not found", id)
-	}
+		if rowsAffected == 0 {
+			return fmt.Errorf("product with ID %s not found", id)
+		}
 
-	return nil
+		return insertOutboxEvents(ctx, tx, outbox)
+	})
 }
 
 // Delete removes a product from the database
-func (r *productRepository) Delete(ctx context.Context, id uuid.UUID) error {
+func (r *productRepository) Delete(ctx context.Context, id uuid.UUID, outbox ...*model.OutboxEvent) error {
 	query := "DELETE FROM products WHERE id = $1"
 
-	result, err := r.db.ExecContext(ctx, query, id)
-	if err != nil {
-		return fmt.Errorf("failed to delete product: %w", err)
-	}
+	return r.db.Transaction(func(tx *sql.Tx) error {
+		result, err := tx.ExecContext(ctx, query, id)
+		if err != nil {
+			return fmt.Errorf("failed to delete product: %w", err)
+		}
 
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
+		rowsAffected, err := result.RowsAffected()
+		if err != nil {
+			return fmt.Errorf("failed to get rows affected: %w", err)
+		}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("product with ID %s not found", id)
-	}
+		if rowsAffected == 0 {
+			return fmt.Errorf("product with ID %s not found", id)
+		}
 
-	return nil
+		return insertOutboxEvents(ctx, tx, outbox)
+	})
 }
-
-// UpdateStockLevel updates the stock level of a product
-func (r *productRepository) UpdateStockLevel(ctx context.Context, id uuid.UUID, quantity int) error {
-	query := `
-		UPDATE products SET
-			stock_level = $1,
-			updated_at = $2
-		WHERE id = $3
-	`
-
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		quantity,
-		time.Now(),
-		id,
-	)
-
-	if err != nil {
-		return fmt.Errorf("failed to update product stock level: %w", err)
-	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
-	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("product with ID %s not found", id)
-	}
-
-	return nil
-}
\ No newline at end of file
@@ -0,0 +1,220 @@
+// internal/repository/postgres/stock_reservation_repo.go
+package postgres
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// stockReservationRepository implements the StockReservationRepository interface
+type stockReservationRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewStockReservationRepository creates a new StockReservationRepository
+func NewStockReservationRepository(db *DB, logger *zerolog.Logger) interfaces.StockReservationRepository {
+	return &stockReservationRepository{
+		db:     db,
+		logger: logger.With().Str("component", "stock_reservation_repository").Logger(),
+	}
+}
+
+// heldQuantityQuery sums the unexpired holds on a variant
+const heldQuantityQuery = `
+	SELECT COALESCE(SUM(quantity), 0)
+	FROM stock_reservations
+	WHERE variant_id = $1 AND status = 'held' AND expires_at > $2
+`
+
+// Reserve holds stock for every reservation in a single transaction. Variant
+// rows are locked in ID order so concurrent checkouts cannot deadlock or
+// both take the last unit
+func (r *stockReservationRepository) Reserve(ctx context.Context, reservations []*model.StockReservation) error {
+	sorted := make([]*model.StockReservation, len(reservations))
+	copy(sorted, reservations)
+	sort.Slice(sorted, func(i, j int) bool {
+		return bytes.Compare(sorted[i].VariantID[:], sorted[j].VariantID[:]) < 0
+	})
+
+	insertQuery := `
+        INSERT INTO stock_reservations (
+            id, variant_id, reference, quantity, status, expires_at, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8
+        )
+    `
+
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		for _, reservation := range sorted {
+			var stockLevel int
+			err := tx.QueryRowContext(ctx, "SELECT stock_level FROM variants WHERE id = $1 FOR UPDATE", reservation.VariantID).Scan(&stockLevel)
+			if err != nil {
+				if errors.Is(err, sql.ErrNoRows) {
+					return fmt.Errorf("variant with ID %s not found", reservation.VariantID)
+				}
+				return err
+			}
+
+			var held int
+			if err := tx.QueryRowContext(ctx, heldQuantityQuery, reservation.VariantID, time.Now()).Scan(&held); err != nil {
+				return err
+			}
+
+			if stockLevel-held < reservation.Quantity {
+				r.logger.Warn().
+					Str("variant_id", reservation.VariantID.String()).
+					Int("stock_level", stockLevel).
+					Int("held", held).
+					Int("requested", reservation.Quantity).
+					Msg("Not enough stock to reserve")
+				return fmt.Errorf("%w for variant %s: %d available, %d requested", ErrInsufficientStock, reservation.VariantID, stockLevel-held, reservation.Quantity)
+			}
+
+			_, err = tx.ExecContext(
+				ctx,
+				insertQuery,
+				reservation.ID,
+				reservation.VariantID,
+				reservation.Reference,
+				reservation.Quantity,
+				reservation.Status,
+				reservation.ExpiresAt,
+				reservation.CreatedAt,
+				reservation.UpdatedAt,
+			)
+			if err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+
+	if err != nil {
+		if errors.Is(err, ErrInsufficientStock) {
+			return err
+		}
+		r.logger.Error().Err(err).Msg("Failed to reserve stock")
+		return fmt.Errorf("failed to reserve stock: %w", err)
+	}
+
+	return nil
+}
+
+// GetByReference retrieves every reservation made under a reference
+func (r *stockReservationRepository) GetByReference(ctx context.Context, reference string) ([]*model.StockReservation, error) {
+	query := `
+        SELECT id, variant_id, reference, quantity, status, expires_at, created_at, updated_at
+        FROM stock_reservations
+        WHERE reference = $1
+        ORDER BY created_at, id
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, reference)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query stock reservations: %w", err)
+	}
+	defer rows.Close()
+
+	reservations := make([]*model.StockReservation, 0)
+	for rows.Next() {
+		var reservation model.StockReservation
+		err := rows.Scan(
+			&reservation.ID,
+			&reservation.VariantID,
+			&reservation.Reference,
+			&reservation.Quantity,
+			&reservation.Status,
+			&reservation.ExpiresAt,
+			&reservation.CreatedAt,
+			&reservation.UpdatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan stock reservation: %w", err)
+		}
+		reservations = append(reservations, &reservation)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during stock reservation rows iteration: %w", err)
+	}
+
+	return reservations, nil
+}
+
+// GetAvailable returns a variant's stock level less its unexpired holds
+func (r *stockReservationRepository) GetAvailable(ctx context.Context, variantID uuid.UUID) (int, error) {
+	var stockLevel, held int
+
+	err := r.db.QueryRowContext(ctx, "SELECT stock_level FROM variants WHERE id = $1", variantID).Scan(&stockLevel)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return 0, ErrResourceNotFound
+		}
+		return 0, fmt.Errorf("failed to get variant stock level: %w", err)
+	}
+
+	if err := r.db.QueryRowContext(ctx, heldQuantityQuery, variantID, time.Now()).Scan(&held); err != nil {
+		return 0, fmt.Errorf("failed to sum held stock: %w", err)
+	}
+
+	return stockLevel - held, nil
+}
+
+// SetStatusByReference moves the held reservations of a reference to status
+func (r *stockReservationRepository) SetStatusByReference(ctx context.Context, reference, status string) (int, error) {
+	query := `
+        UPDATE stock_reservations SET
+            status = $1,
+            updated_at = $2
+        WHERE reference = $3 AND status = 'held'
+    `
+
+	result, err := r.db.ExecContext(ctx, query, status, time.Now(), reference)
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("reference", reference).
+			Str("status", status).
+			Msg("Failed to update stock reservations")
+		return 0, fmt.Errorf("failed to update stock reservations: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
+
+// ExpireHeld marks every hold that expired before the given time as expired
+func (r *stockReservationRepository) ExpireHeld(ctx context.Context, before time.Time) (int, error) {
+	query := `
+        UPDATE stock_reservations SET
+            status = 'expired',
+            updated_at = $1
+        WHERE status = 'held' AND expires_at <= $2
+    `
+
+	result, err := r.db.ExecContext(ctx, query, time.Now(), before)
+	if err != nil {
+		return 0, fmt.Errorf("failed to expire stock reservations: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return int(rowsAffected), nil
+}
@@ -0,0 +1,236 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/golang-migrate/migrate/v4"
+	_ "github.com/golang-migrate/migrate/v4/database/postgres"
+	_ "github.com/golang-migrate/migrate/v4/source/file"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// testDB connects to TEST_DATABASE_URL and migrates it, skipping the test when
+// it isn't set. The URL must point at a disposable database
+func testDB(t *testing.T) *DB {
+	t.Helper()
+
+	dsn := os.Getenv("TEST_DATABASE_URL")
+	if dsn == "" {
+		t.Skip("TEST_DATABASE_URL not set")
+	}
+
+	m, err := migrate.New("file://../../../migrations", dsn)
+	if err != nil {
+		t.Fatalf("failed to create migration instance: %v", err)
+	}
+	if err := m.Up(); err != nil && !errors.Is(err, migrate.ErrNoChange) {
+		t.Fatalf("failed to migrate: %v", err)
+	}
+	m.Close()
+
+	db, err := sql.Open("postgres", dsn)
+	if err != nil {
+		t.Fatalf("failed to open database: %v", err)
+	}
+	t.Cleanup(func() { db.Close() })
+
+	return &DB{db}
+}
+
+// createTestVariant inserts a product, price and variant with the given stock
+func createTestVariant(t *testing.T, db *DB, stockLevel int) uuid.UUID {
+	t.Helper()
+	ctx := context.Background()
+
+	productID, priceID, variantID := uuid.New(), uuid.New(), uuid.New()
+	statements := []struct {
+		query string
+		args  []interface{}
+	}{
+		{"INSERT INTO products (id, stripe_id, name) VALUES ($1, $2, 'Test Coffee')",
+			[]interface{}{productID, "prod_" + productID.String()}},
+		{"INSERT INTO prices (id, stripe_id, product_id, amount, type) VALUES ($1, $2, $3, 1800, 'one_time')",
+			[]interface{}{priceID, "price_" + priceID.String(), productID}},
+		{"INSERT INTO variants (id, product_id, stripe_product_id, price_id, stripe_price_id, stock_level) VALUES ($1, $2, $3, $4, $5, $6)",
+			[]interface{}{variantID, productID, "prod_" + variantID.String(), priceID, "price_" + priceID.String(), stockLevel}},
+	}
+	for _, stmt := range statements {
+		if _, err := db.ExecContext(ctx, stmt.query, stmt.args...); err != nil {
+			t.Fatalf("failed to seed variant: %v", err)
+		}
+	}
+
+	t.Cleanup(func() {
+		db.ExecContext(ctx, "DELETE FROM variants WHERE id = $1", variantID)
+		db.ExecContext(ctx, "DELETE FROM prices WHERE id = $1", priceID)
+		db.ExecContext(ctx, "DELETE FROM products WHERE id = $1", productID)
+	})
+
+	return variantID
+}
+
+func newReservation(variantID uuid.UUID, reference string, quantity int, expiresAt time.Time) *model.StockReservation {
+	now := time.Now()
+	return &model.StockReservation{
+		ID:        uuid.New(),
+		VariantID: variantID,
+		Reference: reference,
+		Quantity:  quantity,
+		Status:    model.ReservationStatusHeld,
+		ExpiresAt: expiresAt,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+}
+
+func TestStockReservationReserve(t *testing.T) {
+	db := testDB(t)
+	logger := zerolog.Nop()
+	repo := NewStockReservationRepository(db, &logger)
+	ctx := context.Background()
+
+	first := createTestVariant(t, db, 5)
+	second := createTestVariant(t, db, 2)
+	expiresAt := time.Now().Add(time.Hour)
+
+	if err := repo.Reserve(ctx, []*model.StockReservation{newReservation(first, "ref-a", 3, expiresAt)}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	available, err := repo.GetAvailable(ctx, first)
+	if err != nil {
+		t.Fatalf("GetAvailable() error = %v", err)
+	}
+	if available != 2 {
+		t.Errorf("available = %d, want 2", available)
+	}
+
+	// One short line fails the whole checkout and holds nothing
+	err = repo.Reserve(ctx, []*model.StockReservation{
+		newReservation(first, "ref-b", 2, expiresAt),
+		newReservation(second, "ref-b", 3, expiresAt),
+	})
+	if !errors.Is(err, ErrInsufficientStock) {
+		t.Fatalf("Reserve() error = %v, want ErrInsufficientStock", err)
+	}
+	held, err := repo.GetByReference(ctx, "ref-b")
+	if err != nil {
+		t.Fatalf("GetByReference() error = %v", err)
+	}
+	if len(held) != 0 {
+		t.Errorf("failed reservation left %d holds", len(held))
+	}
+
+	// Expired holds no longer count against stock
+	expiredRef := "ref-expired-" + uuid.NewString()
+	if err := repo.Reserve(ctx, []*model.StockReservation{newReservation(second, expiredRef, 2, time.Now().Add(-time.Minute))}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := repo.Reserve(ctx, []*model.StockReservation{newReservation(second, "ref-c", 2, expiresAt)}); err != nil {
+		t.Errorf("Reserve() over an expired hold error = %v", err)
+	}
+}
+
+func TestStockReservationReserveConcurrent(t *testing.T) {
+	db := testDB(t)
+	logger := zerolog.Nop()
+	repo := NewStockReservationRepository(db, &logger)
+	ctx := context.Background()
+
+	const stock, buyers = 5, 12
+	variantID := createTestVariant(t, db, stock)
+	expiresAt := time.Now().Add(time.Hour)
+
+	var wg sync.WaitGroup
+	var mu sync.Mutex
+	reserved := 0
+	for i := 0; i < buyers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := repo.Reserve(ctx, []*model.StockReservation{newReservation(variantID, uuid.NewString(), 1, expiresAt)})
+			if err == nil {
+				mu.Lock()
+				reserved++
+				mu.Unlock()
+			} else if !errors.Is(err, ErrInsufficientStock) {
+				t.Errorf("Reserve() error = %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if reserved != stock {
+		t.Errorf("%d checkouts reserved stock, want %d", reserved, stock)
+	}
+}
+
+func TestStockReservationExpireHeld(t *testing.T) {
+	db := testDB(t)
+	logger := zerolog.Nop()
+	repo := NewStockReservationRepository(db, &logger)
+	ctx := context.Background()
+
+	variantID := createTestVariant(t, db, 10)
+	now := time.Now()
+
+	pastRef, futureRef := "ref-past-"+uuid.NewString(), "ref-future-"+uuid.NewString()
+	if err := repo.Reserve(ctx, []*model.StockReservation{newReservation(variantID, pastRef, 2, now.Add(-time.Minute))}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+	if err := repo.Reserve(ctx, []*model.StockReservation{newReservation(variantID, futureRef, 3, now.Add(time.Hour))}); err != nil {
+		t.Fatalf("Reserve() error = %v", err)
+	}
+
+	if _, err := repo.ExpireHeld(ctx, now); err != nil {
+		t.Fatalf("ExpireHeld() error = %v", err)
+	}
+
+	tests := []struct {
+		reference  string
+		wantStatus string
+	}{
+		{pastRef, model.ReservationStatusExpired},
+		{futureRef, model.ReservationStatusHeld},
+	}
+	for _, tt := range tests {
+		reservations, err := repo.GetByReference(ctx, tt.reference)
+		if err != nil {
+			t.Fatalf("GetByReference() error = %v", err)
+		}
+		if len(reservations) != 1 || reservations[0].Status != tt.wantStatus {
+			t.Errorf("reservations for %s = %+v, want one %s", tt.reference, reservations, tt.wantStatus)
+		}
+	}
+
+	// Confirmed holds are left alone
+	if _, err := repo.SetStatusByReference(ctx, futureRef, model.ReservationStatusConfirmed); err != nil {
+		t.Fatalf("SetStatusByReference() error = %v", err)
+	}
+	if _, err := repo.ExpireHeld(ctx, now.Add(2*time.Hour)); err != nil {
+		t.Fatalf("ExpireHeld() error = %v", err)
+	}
+	reservations, err := repo.GetByReference(ctx, futureRef)
+	if err != nil {
+		t.Fatalf("GetByReference() error = %v", err)
+	}
+	if reservations[0].Status != model.ReservationStatusConfirmed {
+		t.Errorf("confirmed hold became %s", reservations[0].Status)
+	}
+
+	available, err := repo.GetAvailable(ctx, variantID)
+	if err != nil {
+		t.Fatalf("GetAvailable() error = %v", err)
+	}
+	if available != 10 {
+		t.Errorf("available = %d, want 10 with no holds left", available)
+	}
+}
@@ -0,0 +1,344 @@
+// internal/repository/postgres/subscription_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/rs/zerolog"
+)
+
+// subscriptionRepository implements the SubscriptionRepository interface
+type subscriptionRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewSubscriptionRepository creates a new SubscriptionRepository
+func NewSubscriptionRepository(db *DB, logger *zerolog.Logger) interfaces.SubscriptionRepository {
+	return &subscriptionRepository{
+		db:     db,
+		logger: logger.With().Str("component", "subscription_repository").Logger(),
+	}
+}
+
+// subscriptionColumns is the column list shared by every subscription query
+const subscriptionColumns = `
+	id, customer_id, product_id, price_id, address_id, stripe_id, COALESCE(stripe_item_id, ''),
+	quantity, status, current_period_start, current_period_end, next_delivery_date,
+	COALESCE(cancel_at_period_end, false), canceled_at, metadata, created_at, updated_at
+`
+
+// rowScanner is satisfied by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
+}
+
+// scanSubscription scans a row selected with subscriptionColumns
+func scanSubscription(row rowScanner) (*model.Subscription, error) {
+	var subscription model.Subscription
+	var addressID uuid.NullUUID
+	var periodStart, periodEnd, nextDelivery, canceledAt sql.NullTime
+	var metadataJSON []byte
+
+	err := row.Scan(
+		&subscription.ID,
+		&subscription.CustomerID,
+		&subscription.ProductID,
+		&subscription.PriceID,
+		&addressID,
+		&subscription.StripeID,
+		&subscription.StripeItemID,
+		&subscription.Quantity,
+		&subscription.Status,
+		&periodStart,
+		&periodEnd,
+		&nextDelivery,
+		&subscription.CancelAtPeriodEnd,
+		&canceledAt,
+		&metadataJSON,
+		&subscription.CreatedAt,
+		&subscription.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if addressID.Valid {
+		subscription.AddressID = &addressID.UUID
+	}
+	subscription.CurrentPeriodStart = periodStart.Time
+	subscription.CurrentPeriodEnd = periodEnd.Time
+	subscription.NextDeliveryDate = nextDelivery.Time
+	if canceledAt.Valid {
+		subscription.CanceledAt = &canceledAt.Time
+	}
+
+	// Unmarshal the metadata JSON
+	if len(metadataJSON) > 0 {
+		if err := json.Unmarshal(metadataJSON, &subscription.Metadata); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal metadata for subscription %s: %w", subscription.ID, err)
+		}
+	}
+	if subscription.Metadata == nil {
+		subscription.Metadata = make(map[string]string)
+	}
+
+	return &subscription, nil
+}
+
+// nullTime stores zero times as NULL
+func nullTime(t time.Time) sql.NullTime {
+	return sql.NullTime{Time: t, Valid: !t.IsZero()}
+}
+
+// Create adds a new subscription to the database
+func (r *subscriptionRepository) Create(ctx context.Context, subscription *model.Subscription) error {
+	metadataJSON, err := json.Marshal(subscription.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+        INSERT INTO subscriptions (
+            id, customer_id, product_id, price_id, address_id, stripe_id, stripe_item_id,
+            quantity, status, current_period_start, current_period_end, next_delivery_date,
+            cancel_at_period_end, canceled_at, metadata, created_at, updated_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17
+        )
+    `
+
+	_, err = r.db.ExecContext(
+		ctx,
+		query,
+		subscription.ID,
+		subscription.CustomerID,
+		subscription.ProductID,
+		subscription.PriceID,
+		subscription.AddressID,
+		subscription.StripeID,
+		subscription.StripeItemID,
+		subscription.Quantity,
+		subscription.Status,
+		nullTime(subscription.CurrentPeriodStart),
+		nullTime(subscription.CurrentPeriodEnd),
+		nullTime(subscription.NextDeliveryDate),
+		subscription.CancelAtPeriodEnd,
+		subscription.CanceledAt,
+		metadataJSON,
+		subscription.CreatedAt,
+		subscription.UpdatedAt,
+	)
+
+	if err != nil {
+		// The Stripe webhook may have recorded the subscription first
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23505" {
+			return ErrAlreadyExists
+		}
+		r.logger.Error().Err(err).
+			Str("subscription_id", subscription.ID.String()).
+			Str("stripe_id", subscription.StripeID).
+			Msg("Failed to create subscription")
+		return fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetByID retrieves a subscription by its ID
+func (r *subscriptionRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE id = $1"
+
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Subscription not found
+		}
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetByStripeID retrieves a subscription by its Stripe subscription ID
+func (r *subscriptionRepository) GetByStripeID(ctx context.Context, stripeID string) (*model.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE stripe_id = $1"
+
+	subscription, err := scanSubscription(r.db.QueryRowContext(ctx, query, stripeID))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Subscription not found
+		}
+		return nil, fmt.Errorf("failed to get subscription by Stripe ID: %w", err)
+	}
+
+	return subscription, nil
+}
+
+// GetByCustomerID retrieves all subscriptions for a customer
+func (r *subscriptionRepository) GetByCustomerID(ctx context.Context, customerID uuid.UUID) ([]*model.Subscription, error) {
+	query := "SELECT " + subscriptionColumns + " FROM subscriptions WHERE customer_id = $1 ORDER BY created_at"
+
+	rows, err := r.db.QueryContext(ctx, query, customerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*model.Subscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during subscription rows iteration: %w", err)
+	}
+
+	return subscriptions, nil
+}
+
+// List retrieves subscriptions with pagination, optionally filtered by customer and status
+func (r *subscriptionRepository) List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Subscription, int, error) {
+	whereConditions := []string{}
+	args := []interface{}{}
+
+	if customerID != nil {
+		args = append(args, *customerID)
+		whereConditions = append(whereConditions, fmt.Sprintf("customer_id = $%d", len(args)))
+	}
+
+	if status != "" {
+		args = append(args, status)
+		whereConditions = append(whereConditions, fmt.Sprintf("status = $%d", len(args)))
+	}
+
+	whereClause := ""
+	if len(whereConditions) > 0 {
+		whereClause = "WHERE " + strings.Join(whereConditions, " AND ")
+	}
+
+	var total int
+	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM subscriptions %s", whereClause)
+	if err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count subscriptions: %w", err)
+	}
+
+	if total == 0 {
+		return []*model.Subscription{}, 0, nil
+	}
+
+	listQuery := fmt.Sprintf(`
+		SELECT %s
+		FROM subscriptions
+		%s
+		ORDER BY created_at DESC
+		LIMIT $%d OFFSET $%d
+	`, subscriptionColumns, whereClause, len(args)+1, len(args)+2)
+
+	rows, err := r.db.QueryContext(ctx, listQuery, append(args, limit, offset)...)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	subscriptions := make([]*model.Subscription, 0)
+	for rows.Next() {
+		subscription, err := scanSubscription(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan subscription: %w", err)
+		}
+		subscriptions = append(subscriptions, subscription)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during subscription rows iteration: %w", err)
+	}
+
+	return subscriptions, total, nil
+}
+
+// Update updates an existing subscription
+func (r *subscriptionRepository) Update(ctx context.Context, subscription *model.Subscription) error {
+	subscription.UpdatedAt = time.Now()
+
+	metadataJSON, err := json.Marshal(subscription.Metadata)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	query := `
+        UPDATE subscriptions SET
+            product_id = $1,
+            price_id = $2,
+            address_id = $3,
+            stripe_item_id = $4,
+            quantity = $5,
+            status = $6,
+            current_period_start = $7,
+            current_period_end = $8,
+            next_delivery_date = $9,
+            cancel_at_period_end = $10,
+            canceled_at = $11,
+            metadata = $12,
+            updated_at = $13
+        WHERE id = $14
+    `
+
+	result, err := r.db.ExecContext(
+		ctx,
+		query,
+		subscription.ProductID,
+		subscription.PriceID,
+		subscription.AddressID,
+		subscription.StripeItemID,
+		subscription.Quantity,
+		subscription.Status,
+		nullTime(subscription.CurrentPeriodStart),
+		nullTime(subscription.CurrentPeriodEnd),
+		nullTime(subscription.NextDeliveryDate),
+		subscription.CancelAtPeriodEnd,
+		subscription.CanceledAt,
+		metadataJSON,
+		subscription.UpdatedAt,
+		subscription.ID,
+	)
+
+	if err != nil {
+		r.logger.Error().Err(err).
+			Str("subscription_id", subscription.ID.String()).
+			Msg("Failed to update subscription")
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("subscription with ID %s not found", subscription.ID)
+	}
+
+	r.logger.Debug().
+		Str("subscription_id", subscription.ID.String()).
+		Str("status", subscription.Status).
+		Int64("rows_affected", rowsAffected).
+		Msg("Subscription updated successfully")
+
+	return nil
+}
@@ -4,6 +4,7 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"errors"
 	"fmt"
 
@@ -26,7 +27,7 @@ func NewSyncHashRepository(db *DB, logger *zerolog.Logger) *syncHashRepository {
 	}
 }
 
-// Create adds a new sync hash record
+// Create adds a new sync hash record and appends it to the history
 func (r *syncHashRepository) Create(ctx context.Context, syncHash *model.SyncHash) error {
 	query := `
         INSERT INTO sync_hashes (
@@ -37,18 +38,24 @@ func (r *syncHashRepository) Create(ctx context.Context, syncHash *model.SyncHas
         )
     `
 
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		syncHash.ID,
-		syncHash.VariantID,
-		syncHash.StripeProductID,
-		syncHash.ContentHash,
-		syncHash.HashAlgorithm,
-		syncHash.SyncSource,
-		syncHash.CreatedAt,
-		syncHash.UpdatedAt,
-	)
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			syncHash.ID,
+			syncHash.VariantID,
+			syncHash.StripeProductID,
+			syncHash.ContentHash,
+			syncHash.HashAlgorithm,
+			syncHash.SyncSource,
+			syncHash.CreatedAt,
+			syncHash.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return recordSyncHashHistory(ctx, tx, syncHash)
+	})
 
 	if err != nil {
 		r.logger.Error().Err(err).
@@ -160,7 +167,7 @@ func (r *syncHashRepository) GetByVariantAndStripeID(ctx context.Context, varian
 	return &syncHash, nil
 }
 
-// Upsert creates or updates a sync hash record
+// Upsert creates or updates a sync hash record, appending it to the history
 func (r *syncHashRepository) Upsert(ctx context.Context, syncHash *model.SyncHash) error {
 	query := `
         INSERT INTO sync_hashes (
@@ -177,18 +184,24 @@ func (r *syncHashRepository) Upsert(ctx context.Context, syncHash *model.SyncHas
             updated_at = EXCLUDED.updated_at
     `
 
-	_, err := r.db.ExecContext(
-		ctx,
-		query,
-		syncHash.ID,
-		syncHash.VariantID,
-		syncHash.StripeProductID,
-		syncHash.ContentHash,
-		syncHash.HashAlgorithm,
-		syncHash.SyncSource,
-		syncHash.CreatedAt,
-		syncHash.UpdatedAt,
-	)
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			syncHash.ID,
+			syncHash.VariantID,
+			syncHash.StripeProductID,
+			syncHash.ContentHash,
+			syncHash.HashAlgorithm,
+			syncHash.SyncSource,
+			syncHash.CreatedAt,
+			syncHash.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+		return recordSyncHashHistory(ctx, tx, syncHash)
+	})
 
 	if err != nil {
 		r.logger.Error().Err(err).
@@ -234,18 +247,19 @@ func (r *syncHashRepository) DeleteByVariantID(ctx context.Context, variantID uu
 	return nil
 }
 
-// GetHashHistory retrieves historical hashes for debugging/audit purposes
+// GetHashHistory retrieves the hashes written for a variant, newest first.
+// History entries have CreatedAt and UpdatedAt set to when they were recorded
 func (r *syncHashRepository) GetHashHistory(ctx context.Context, variantID uuid.UUID, limit int) ([]*model.SyncHash, error) {
 	if limit <= 0 {
 		limit = 10 // Default limit
 	}
 
 	query := `
-        SELECT id, variant_id, stripe_product_id, content_hash, 
-               hash_algorithm, sync_source, created_at, updated_at
-        FROM sync_hashes
+        SELECT id, variant_id, stripe_product_id, content_hash,
+               hash_algorithm, sync_source, recorded_at, recorded_at
+        FROM sync_hash_history
         WHERE variant_id = $1
-        ORDER BY updated_at DESC
+        ORDER BY recorded_at DESC
         LIMIT $2
     `
 
@@ -255,7 +269,7 @@ func (r *syncHashRepository) GetHashHistory(ctx context.Context, variantID uuid.
 	}
 	defer rows.Close()
 
-	var hashes []*model.SyncHash
+	hashes := make([]*model.SyncHash, 0)
 	for rows.Next() {
 		var syncHash model.SyncHash
 		err := rows.Scan(
@@ -279,4 +293,107 @@ func (r *syncHashRepository) GetHashHistory(ctx context.Context, variantID uuid.
 	}
 
 	return hashes, nil
-}
\ No newline at end of file
+}
+
+// ListVariantSyncStates lists every variant with the sync hash recorded for
+// its current Stripe product, if any
+func (r *syncHashRepository) ListVariantSyncStates(ctx context.Context) ([]*model.VariantSyncState, error) {
+	query := `
+        SELECT v.id, v.product_id, p.name, v.options, v.stripe_product_id, v.active,
+               s.id, s.content_hash, s.hash_algorithm, s.sync_source, s.created_at, s.updated_at
+        FROM variants v
+        JOIN products p ON p.id = v.product_id
+        LEFT JOIN sync_hashes s ON s.variant_id = v.id AND s.stripe_product_id = v.stripe_product_id
+        ORDER BY p.name, v.created_at
+    `
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query variant sync states: %w", err)
+	}
+	defer rows.Close()
+
+	states := make([]*model.VariantSyncState, 0)
+	for rows.Next() {
+		var state model.VariantSyncState
+		var optionsJSON []byte
+		var hashID uuid.NullUUID
+		var contentHash, hashAlgorithm, syncSource sql.NullString
+		var createdAt, updatedAt sql.NullTime
+
+		err := rows.Scan(
+			&state.VariantID,
+			&state.ProductID,
+			&state.ProductName,
+			&optionsJSON,
+			&state.StripeProductID,
+			&state.Active,
+			&hashID,
+			&contentHash,
+			&hashAlgorithm,
+			&syncSource,
+			&createdAt,
+			&updatedAt,
+		)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan variant sync state: %w", err)
+		}
+
+		state.Options = make(map[string]string)
+		if len(optionsJSON) > 0 {
+			if err := json.Unmarshal(optionsJSON, &state.Options); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal options for variant %s: %w", state.VariantID, err)
+			}
+		}
+
+		if hashID.Valid {
+			state.SyncHash = &model.SyncHash{
+				ID:              hashID.UUID,
+				VariantID:       state.VariantID,
+				StripeProductID: state.StripeProductID,
+				ContentHash:     contentHash.String,
+				HashAlgorithm:   hashAlgorithm.String,
+				SyncSource:      syncSource.String,
+				CreatedAt:       createdAt.Time,
+				UpdatedAt:       updatedAt.Time,
+			}
+		}
+
+		states = append(states, &state)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, fmt.Errorf("error during variant sync state iteration: %w", err)
+	}
+
+	return states, nil
+}
+
+// recordSyncHashHistory appends a written hash to the variant's timeline
+func recordSyncHashHistory(ctx context.Context, tx *sql.Tx, syncHash *model.SyncHash) error {
+	query := `
+        INSERT INTO sync_hash_history (
+            id, variant_id, stripe_product_id, content_hash,
+            hash_algorithm, sync_source, recorded_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7
+        )
+    `
+
+	_, err := tx.ExecContext(
+		ctx,
+		query,
+		uuid.New(),
+		syncHash.VariantID,
+		syncHash.StripeProductID,
+		syncHash.ContentHash,
+		syncHash.HashAlgorithm,
+		syncHash.SyncSource,
+		syncHash.UpdatedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("failed to record sync hash history: %w", err)
+	}
+
+	return nil
+}
@@ -12,6 +12,7 @@ import (
 	"github.com/dukerupert/coffee-commerce/internal/domain/model"
 	"github.com/dukerupert/coffee-commerce/internal/interfaces"
 	"github.com/google/uuid"
+	"github.com/lib/pq"
 	"github.com/rs/zerolog"
 )
 
@@ -40,27 +41,46 @@ func (r *variantRepository) Create(ctx context.Context, variant *model.Variant)
 	query := `
         INSERT INTO variants (
             id, product_id, price_id, stripe_product_id, stripe_price_id, weight,
-            options, active, stock_level, created_at, updated_at
+            options, active, stock_level, reorder_threshold, created_at, updated_at
         ) VALUES (
-            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11
+            $1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12
         )
     `
 
-	_, err = r.db.ExecContext(
-		ctx,
-		query,
-		variant.ID,
-		variant.ProductID,
-		variant.PriceID,
-		variant.StripeProductID,
-		variant.StripePriceID,
-		variant.Weight,
-		optionsJSON,
-		variant.Active,
-		variant.StockLevel,
-		variant.CreatedAt,
-		variant.UpdatedAt,
-	)
+	err = r.db.Transaction(func(tx *sql.Tx) error {
+		_, err := tx.ExecContext(
+			ctx,
+			query,
+			variant.ID,
+			variant.ProductID,
+			variant.PriceID,
+			variant.StripeProductID,
+			variant.StripePriceID,
+			variant.Weight,
+			optionsJSON,
+			variant.Active,
+			0,
+			variant.ReorderThreshold,
+			variant.CreatedAt,
+			variant.UpdatedAt,
+		)
+		if err != nil {
+			return err
+		}
+
+		// Starting stock enters through the ledger like any other receipt
+		if variant.StockLevel == 0 {
+			return nil
+		}
+		return recordStockMovement(ctx, tx, &model.InventoryMovement{
+			ID:        uuid.New(),
+			VariantID: variant.ID,
+			Type:      model.MovementTypeReceipt,
+			Quantity:  variant.StockLevel,
+			Reason:    "initial stock",
+			CreatedAt: variant.CreatedAt,
+		}, nil)
+	})
 
 	if err != nil {
 		r.logger.Error().Err(err).
@@ -78,7 +98,7 @@ func (r *variantRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.V
 	query := `
         SELECT
             id, product_id, price_id, stripe_product_id, stripe_price_id, weight,
-            options, active, stock_level, created_at, updated_at
+            options, active, stock_level, reorder_threshold, created_at, updated_at
         FROM variants
         WHERE id = $1
     `
@@ -96,6 +116,7 @@ func (r *variantRepository) GetByID(ctx context.Context, id uuid.UUID) (*model.V
 		&optionsJSON,
 		&variant.Active,
 		&variant.StockLevel,
+		&variant.ReorderThreshold,
 		&variant.CreatedAt,
 		&variant.UpdatedAt,
 	)
@@ -125,7 +146,7 @@ func (r *variantRepository) GetByStripeID(ctx context.Context, id string) (*mode
 	query := `
         SELECT
             id, product_id, price_id, stripe_product_id, stripe_price_id, weight,
-            options, active, stock_level, created_at, updated_at
+            options, active, stock_level, reorder_threshold, created_at, updated_at
         FROM variants
         WHERE stripe_product_id = $1
     `
@@ -143,6 +164,7 @@ func (r *variantRepository) GetByStripeID(ctx context.Context, id string) (*mode
 		&optionsJSON,
 		&variant.Active,
 		&variant.StockLevel,
+		&variant.ReorderThreshold,
 		&variant.CreatedAt,
 		&variant.UpdatedAt,
 	)
@@ -172,7 +194,7 @@ func (r *variantRepository) GetByProductID(ctx context.Context, productID uuid.U
 	query := `
         SELECT
             id, product_id, price_id, stripe_product_id, stripe_price_id, weight,
-            options, active, stock_level, created_at, updated_at
+            options, active, stock_level, reorder_threshold, created_at, updated_at
         FROM variants
         WHERE product_id = $1
         ORDER BY created_at
@@ -200,6 +222,7 @@ func (r *variantRepository) GetByProductID(ctx context.Context, productID uuid.U
 			&optionsJSON,
 			&variant.Active,
 			&variant.StockLevel,
+			&variant.ReorderThreshold,
 			&variant.CreatedAt,
 			&variant.UpdatedAt,
 		)
@@ -233,7 +256,7 @@ func (r *variantRepository) GetByStripeProductID(ctx context.Context, stripeProd
 	query := `
         SELECT
             id, product_id, price_id, stripe_product_id, stripe_price_id, weight,
-            options, active, stock_level, created_at, updated_at
+            options, active, stock_level, reorder_threshold, created_at, updated_at
         FROM variants
         WHERE stripe_product_id = $1
     `
@@ -251,6 +274,7 @@ func (r *variantRepository) GetByStripeProductID(ctx context.Context, stripeProd
 		&optionsJSON,
 		&variant.Active,
 		&variant.StockLevel,
+		&variant.ReorderThreshold,
 		&variant.CreatedAt,
 		&variant.UpdatedAt,
 	)
@@ -284,16 +308,17 @@ func (r *variantRepository) Update(ctx context.Context, variant *model.Variant)
 		return fmt.Errorf("failed to marshal options: %w", err)
 	}
 
+	// stock_level is only changed through the inventory ledger
 	query := `
         UPDATE variants SET
             price_id = $1,
             stripe_price_id = $2,
             weight = $3,
-            options = $5,
-            active = $6,
-            stock_level = $7,
-            updated_at = $8
-        WHERE id = $9
+            options = $4,
+            active = $5,
+            reorder_threshold = $6,
+            updated_at = $7
+        WHERE id = $8
     `
 
 	result, err := r.db.ExecContext(
@@ -304,7 +329,7 @@ func (r *variantRepository) Update(ctx context.Context, variant *model.Variant)
 		variant.Weight,
 		optionsJSON,
 		variant.Active,
-		variant.StockLevel,
+		variant.ReorderThreshold,
 		variant.UpdatedAt,
 		variant.ID,
 	)
@@ -339,6 +364,11 @@ func (r *variantRepository) Delete(ctx context.Context, id uuid.UUID) error {
 
 	result, err := r.db.ExecContext(ctx, query, id)
 	if err != nil {
+		// Order lines keep their variant for the order's lifetime
+		var pqErr *pq.Error
+		if errors.As(err, &pqErr) && pqErr.Code == "23503" {
+			return ErrResourceInUse
+		}
 		r.logger.Error().Err(err).
 			Str("variant_id", id.String()).
 			Msg("Failed to delete variant")
@@ -362,35 +392,213 @@ func (r *variantRepository) Delete(ctx context.Context, id uuid.UUID) error {
 	return nil
 }
 
-// UpdateStockLevel updates a variant's stock level
-func (r *variantRepository) UpdateStockLevel(ctx context.Context, id uuid.UUID, stockLevel int) error {
+// UpdateStockLevel sets a variant's stock level to a counted value, recording
+// the difference in the ledger. movement.Quantity is filled in with that difference
+func (r *variantRepository) UpdateStockLevel(ctx context.Context, movement *model.InventoryMovement, stockLevel int) error {
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		return recordStockMovement(ctx, tx, movement, &stockLevel)
+	})
+	if err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			return fmt.Errorf("variant with ID %s not found", movement.VariantID)
+		}
+		return fmt.Errorf("failed to update variant stock level: %w", err)
+	}
+
+	r.logger.Debug().
+		Str("variant_id", movement.VariantID.String()).
+		Int("adjustment", movement.Quantity).
+		Int("stock_level", movement.BalanceAfter).
+		Str("reason", movement.Reason).
+		Msg("Variant stock level set")
+
+	return nil
+}
+
+// AdjustStock atomically applies movement.Quantity (negative to remove) to a
+// variant's stock level and appends the movement to the ledger
+func (r *variantRepository) AdjustStock(ctx context.Context, movement *model.InventoryMovement) error {
+	err := r.db.Transaction(func(tx *sql.Tx) error {
+		return recordStockMovement(ctx, tx, movement, nil)
+	})
+	if err != nil {
+		if errors.Is(err, ErrResourceNotFound) {
+			return fmt.Errorf("variant with ID %s not found", movement.VariantID)
+		}
+		return fmt.Errorf("failed to adjust variant stock level: %w", err)
+	}
+
+	r.logger.Debug().
+		Str("variant_id", movement.VariantID.String()).
+		Str("type", movement.Type).
+		Int("adjustment", movement.Quantity).
+		Int("stock_level", movement.BalanceAfter).
+		Str("reason", movement.Reason).
+		Msg("Variant stock adjusted")
+
+	return nil
+}
+
+// GetStockHistory retrieves a variant's ledger, newest first, with the total count
+func (r *variantRepository) GetStockHistory(ctx context.Context, id uuid.UUID, offset, limit int) ([]*model.InventoryMovement, int, error) {
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) FROM inventory_movements WHERE variant_id = $1", id).Scan(&total)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to count inventory movements: %w", err)
+	}
+
 	query := `
-		UPDATE variants SET
-			stock_level = $1,
-			updated_at = $2
-		WHERE id = $3
-	`
+        SELECT id, variant_id, movement_type, quantity, balance_after, reason, reference, created_at
+        FROM inventory_movements
+        WHERE variant_id = $1
+        ORDER BY created_at DESC, id
+        LIMIT $2 OFFSET $3
+    `
 
-	result, err := r.db.ExecContext(
-		ctx,
-		query,
-		stockLevel,
-		time.Now(),
-		id,
-	)
+	rows, err := r.db.QueryContext(ctx, query, id, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to query inventory movements: %w", err)
+	}
+	defer rows.Close()
+
+	movements := make([]*model.InventoryMovement, 0)
+	for rows.Next() {
+		var movement model.InventoryMovement
+		var reference sql.NullString
+		err := rows.Scan(
+			&movement.ID,
+			&movement.VariantID,
+			&movement.Type,
+			&movement.Quantity,
+			&movement.BalanceAfter,
+			&movement.Reason,
+			&reference,
+			&movement.CreatedAt,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan inventory movement: %w", err)
+		}
+		movement.Reference = reference.String
+		movements = append(movements, &movement)
+	}
+
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during inventory movement rows iteration: %w", err)
+	}
 
+	return movements, total, nil
+}
+
+// ListLowStock retrieves active variants at or below their effective reorder
+// threshold, lowest stock first, with the total count
+func (r *variantRepository) ListLowStock(ctx context.Context, offset, limit int) ([]*model.LowStockVariant, int, error) {
+	from := `
+        FROM variants v
+        JOIN products p ON p.id = v.product_id
+        WHERE v.active = true
+          AND p.archived = false
+          AND COALESCE(v.reorder_threshold, p.reorder_threshold) > 0
+          AND v.stock_level <= COALESCE(v.reorder_threshold, p.reorder_threshold)
+    `
+
+	var total int
+	err := r.db.QueryRowContext(ctx, "SELECT COUNT(*) "+from).Scan(&total)
 	if err != nil {
-		return fmt.Errorf("failed to update variant stock level: %w", err)
+		return nil, 0, fmt.Errorf("failed to count low stock variants: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	query := `
+        SELECT v.id, v.product_id, p.name, v.options, v.stock_level,
+               COALESCE(v.reorder_threshold, p.reorder_threshold)
+    ` + from + `
+        ORDER BY v.stock_level, p.name, v.id
+        LIMIT $1 OFFSET $2
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, limit, offset)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		return nil, 0, fmt.Errorf("failed to query low stock variants: %w", err)
 	}
+	defer rows.Close()
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("variant with ID %s not found", id)
+	variants := make([]*model.LowStockVariant, 0)
+	for rows.Next() {
+		var variant model.LowStockVariant
+		var optionsJSON []byte
+		err := rows.Scan(
+			&variant.VariantID,
+			&variant.ProductID,
+			&variant.ProductName,
+			&optionsJSON,
+			&variant.StockLevel,
+			&variant.ReorderThreshold,
+		)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan low stock variant: %w", err)
+		}
+
+		variant.Options = make(map[string]string)
+		if len(optionsJSON) > 0 {
+			if err := json.Unmarshal(optionsJSON, &variant.Options); err != nil {
+				return nil, 0, fmt.Errorf("failed to unmarshal options: %w", err)
+			}
+		}
+
+		variants = append(variants, &variant)
 	}
 
-	return nil
+	if err = rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error during low stock variant rows iteration: %w", err)
+	}
+
+	return variants, total, nil
+}
+
+// recordStockMovement locks the variant, applies the movement to its stock
+// level and appends it to the ledger. When setLevel is given the movement's
+// quantity becomes the difference between it and the current level
+func recordStockMovement(ctx context.Context, tx *sql.Tx, movement *model.InventoryMovement, setLevel *int) error {
+	var current int
+	err := tx.QueryRowContext(ctx, "SELECT stock_level FROM variants WHERE id = $1 FOR UPDATE", movement.VariantID).Scan(&current)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return ErrResourceNotFound
+		}
+		return err
+	}
+
+	if setLevel != nil {
+		movement.Quantity = *setLevel - current
+	}
+	movement.BalanceAfter = current + movement.Quantity
+
+	_, err = tx.ExecContext(ctx,
+		"UPDATE variants SET stock_level = $1, updated_at = $2 WHERE id = $3",
+		movement.BalanceAfter, movement.CreatedAt, movement.VariantID,
+	)
+	if err != nil {
+		return err
+	}
+
+	query := `
+        INSERT INTO inventory_movements (
+            id, variant_id, movement_type, quantity, balance_after, reason, reference, created_at
+        ) VALUES (
+            $1, $2, $3, $4, $5, $6, $7, $8
+        )
+    `
+
+	_, err = tx.ExecContext(
+		ctx,
+		query,
+		movement.ID,
+		movement.VariantID,
+		movement.Type,
+		movement.Quantity,
+		movement.BalanceAfter,
+		movement.Reason,
+		nullString(movement.Reference),
+		movement.CreatedAt,
+	)
+	return err
 }
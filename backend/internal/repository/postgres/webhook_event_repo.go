@@ -0,0 +1,218 @@
+// internal/repository/postgres/webhook_event_repo.go
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/rs/zerolog"
+)
+
+// webhookEventRepository implements the WebhookEventRepository interface
+type webhookEventRepository struct {
+	db     *DB
+	logger zerolog.Logger
+}
+
+// NewWebhookEventRepository creates a new WebhookEventRepository
+func NewWebhookEventRepository(db *DB, logger *zerolog.Logger) interfaces.WebhookEventRepository {
+	return &webhookEventRepository{
+		db:     db,
+		logger: logger.With().Str("component", "webhook_event_repository").Logger(),
+	}
+}
+
+// Save records a received event unless its ID is already stored
+func (r *webhookEventRepository) Save(ctx context.Context, event *model.WebhookEvent) (bool, error) {
+	now := time.Now()
+	if event.ReceivedAt.IsZero() {
+		event.ReceivedAt = now
+	}
+	event.UpdatedAt = now
+	if event.Status == "" {
+		event.Status = model.WebhookEventStatusPending
+	}
+
+	query := `
+        INSERT INTO stripe_webhook_events (id, type, payload, status, received_at, updated_at)
+        VALUES ($1, $2, $3, $4, $5, $6)
+        ON CONFLICT (id) DO NOTHING
+    `
+
+	result, err := r.db.ExecContext(ctx, query,
+		event.ID,
+		event.Type,
+		[]byte(event.Payload),
+		event.Status,
+		event.ReceivedAt,
+		event.UpdatedAt,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to save webhook event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// GetByID retrieves a webhook event by its Stripe event ID
+func (r *webhookEventRepository) GetByID(ctx context.Context, id string) (*model.WebhookEvent, error) {
+	query := `
+        SELECT id, type, payload, status, attempts, COALESCE(last_error, ''), received_at, updated_at, processed_at
+        FROM stripe_webhook_events
+        WHERE id = $1
+    `
+
+	event, err := scanWebhookEvent(r.db.QueryRowContext(ctx, query, id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil // Event not found
+		}
+		return nil, fmt.Errorf("failed to get webhook event: %w", err)
+	}
+
+	return event, nil
+}
+
+// List retrieves webhook events, newest first, optionally filtered by status
+func (r *webhookEventRepository) List(ctx context.Context, status string, offset, limit int) ([]*model.WebhookEvent, int, error) {
+	var total int
+	countQuery := `SELECT COUNT(*) FROM stripe_webhook_events WHERE ($1 = '' OR status = $1)`
+	if err := r.db.QueryRowContext(ctx, countQuery, status).Scan(&total); err != nil {
+		return nil, 0, fmt.Errorf("failed to count webhook events: %w", err)
+	}
+
+	query := `
+        SELECT id, type, payload, status, attempts, COALESCE(last_error, ''), received_at, updated_at, processed_at
+        FROM stripe_webhook_events
+        WHERE ($1 = '' OR status = $1)
+        ORDER BY received_at DESC
+        LIMIT $2 OFFSET $3
+    `
+
+	rows, err := r.db.QueryContext(ctx, query, status, limit, offset)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to list webhook events: %w", err)
+	}
+	defer rows.Close()
+
+	var webhookEvents []*model.WebhookEvent
+	for rows.Next() {
+		event, err := scanWebhookEvent(rows)
+		if err != nil {
+			return nil, 0, fmt.Errorf("failed to scan webhook event: %w", err)
+		}
+		webhookEvents = append(webhookEvents, event)
+	}
+
+	if err := rows.Err(); err != nil {
+		return nil, 0, fmt.Errorf("error iterating webhook events: %w", err)
+	}
+
+	return webhookEvents, total, nil
+}
+
+// Claim marks an event as processing if no one else is processing it
+func (r *webhookEventRepository) Claim(ctx context.Context, id string, staleBefore time.Time) (bool, error) {
+	query := `
+        UPDATE stripe_webhook_events SET
+            status = $1,
+            attempts = attempts + 1,
+            updated_at = $2
+        WHERE id = $3
+          AND (status IN ($4, $5) OR (status = $1 AND updated_at < $6))
+    `
+
+	result, err := r.db.ExecContext(ctx, query,
+		model.WebhookEventStatusProcessing,
+		time.Now(),
+		id,
+		model.WebhookEventStatusPending,
+		model.WebhookEventStatusFailed,
+		staleBefore,
+	)
+	if err != nil {
+		return false, fmt.Errorf("failed to claim webhook event: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// MarkProcessed records that an event was processed successfully
+func (r *webhookEventRepository) MarkProcessed(ctx context.Context, id string) error {
+	now := time.Now()
+	query := `
+        UPDATE stripe_webhook_events SET
+            status = $1,
+            last_error = NULL,
+            updated_at = $2,
+            processed_at = $2
+        WHERE id = $3
+    `
+
+	if _, err := r.db.ExecContext(ctx, query, model.WebhookEventStatusProcessed, now, id); err != nil {
+		return fmt.Errorf("failed to mark webhook event processed: %w", err)
+	}
+
+	return nil
+}
+
+// MarkFailed records that processing an event failed
+func (r *webhookEventRepository) MarkFailed(ctx context.Context, id string, lastError string) error {
+	query := `
+        UPDATE stripe_webhook_events SET
+            status = $1,
+            last_error = $2,
+            updated_at = $3
+        WHERE id = $4
+    `
+
+	if _, err := r.db.ExecContext(ctx, query, model.WebhookEventStatusFailed, lastError, time.Now(), id); err != nil {
+		return fmt.Errorf("failed to mark webhook event failed: %w", err)
+	}
+
+	return nil
+}
+
+// scanWebhookEvent reads a webhook event row
+func scanWebhookEvent(row rowScanner) (*model.WebhookEvent, error) {
+	var event model.WebhookEvent
+	var payload []byte
+	var processedAt sql.NullTime
+
+	err := row.Scan(
+		&event.ID,
+		&event.Type,
+		&payload,
+		&event.Status,
+		&event.Attempts,
+		&event.LastError,
+		&event.ReceivedAt,
+		&event.UpdatedAt,
+		&processedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	event.Payload = payload
+	if processedAt.Valid {
+		event.ProcessedAt = &processedAt.Time
+	}
+
+	return &event, nil
+}
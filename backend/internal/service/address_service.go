@@ -0,0 +1,213 @@
+// internal/service/address_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// addressService implements AddressService
+type addressService struct {
+	logger       zerolog.Logger
+	repo         interfaces.AddressRepository
+	customerRepo interfaces.CustomerRepository
+}
+
+// NewAddressService creates a new address service
+func NewAddressService(logger *zerolog.Logger, addressRepo interfaces.AddressRepository, customerRepo interfaces.CustomerRepository) interfaces.AddressService {
+	subLogger := logger.With().Str("component", "address_service").Logger()
+	return &addressService{
+		logger:       subLogger,
+		repo:         addressRepo,
+		customerRepo: customerRepo,
+	}
+}
+
+// ensureCustomer returns ErrResourceNotFound if the customer does not exist
+func (s *addressService) ensureCustomer(ctx context.Context, customerID uuid.UUID) error {
+	customer, err := s.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to retrieve customer")
+		return fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	if customer == nil {
+		s.logger.Warn().
+			Str("customer_id", customerID.String()).
+			Msg("Customer not found")
+		return postgres.ErrResourceNotFound
+	}
+
+	return nil
+}
+
+// Create adds an address to a customer's address book. The first address a
+// customer adds always becomes their default
+func (s *addressService) Create(ctx context.Context, customerID uuid.UUID, a *dto.AddressCreateDTO) (*model.Address, error) {
+	s.logger.Info().
+		Str("customer_id", customerID.String()).
+		Str("country", a.Country).
+		Bool("is_default", a.IsDefault).
+		Msg("Creating address")
+
+	if err := s.ensureCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	address := a.ToModel(customerID)
+
+	if !address.IsDefault {
+		currentDefault, err := s.repo.GetDefault(ctx, customerID)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Error checking for default address")
+			return nil, fmt.Errorf("error checking for default address: %w", err)
+		}
+		address.IsDefault = currentDefault == nil
+	}
+
+	err := s.repo.Create(ctx, address)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to save address to database")
+		return nil, fmt.Errorf("failed to create address: %w", err)
+	}
+
+	s.logger.Info().
+		Str("customer_id", customerID.String()).
+		Str("address_id", address.ID.String()).
+		Bool("is_default", address.IsDefault).
+		Msg("Address created successfully")
+
+	return address, nil
+}
+
+// GetByID retrieves one of a customer's addresses
+func (s *addressService) GetByID(ctx context.Context, customerID, addressID uuid.UUID) (*model.Address, error) {
+	address, err := s.repo.GetByID(ctx, addressID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("address_id", addressID.String()).
+			Msg("Failed to retrieve address")
+		return nil, fmt.Errorf("failed to retrieve address: %w", err)
+	}
+
+	// An address belonging to another customer is treated as missing
+	if address == nil || address.CustomerID != customerID {
+		s.logger.Warn().
+			Str("customer_id", customerID.String()).
+			Str("address_id", addressID.String()).
+			Msg("Address not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return address, nil
+}
+
+// ListByCustomer retrieves a customer's address book
+func (s *addressService) ListByCustomer(ctx context.Context, customerID uuid.UUID) ([]*model.Address, error) {
+	if err := s.ensureCustomer(ctx, customerID); err != nil {
+		return nil, err
+	}
+
+	addresses, err := s.repo.GetByCustomerID(ctx, customerID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", customerID.String()).
+			Msg("Failed to list addresses")
+		return nil, fmt.Errorf("failed to list addresses: %w", err)
+	}
+
+	return addresses, nil
+}
+
+// Update updates one of a customer's addresses
+func (s *addressService) Update(ctx context.Context, customerID, addressID uuid.UUID, dto *dto.AddressUpdateDTO) (*model.Address, error) {
+	s.logger.Info().
+		Str("customer_id", customerID.String()).
+		Str("address_id", addressID.String()).
+		Msg("Updating address")
+
+	address, err := s.GetByID(ctx, customerID, addressID)
+	if err != nil {
+		return nil, err
+	}
+
+	// The default can be moved to another address but not simply removed
+	if dto.IsDefault != nil && !*dto.IsDefault && address.IsDefault {
+		s.logger.Warn().
+			Str("address_id", addressID.String()).
+			Msg("Refusing to unset the default address without a replacement")
+		return nil, fmt.Errorf("%w: set another address as default instead", ErrInvalidInput)
+	}
+
+	dto.ApplyToModel(address)
+
+	err = s.repo.Update(ctx, address)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("address_id", addressID.String()).
+			Msg("Failed to update address in database")
+		return nil, fmt.Errorf("failed to update address: %w", err)
+	}
+
+	s.logger.Info().
+		Str("address_id", addressID.String()).
+		Bool("is_default", address.IsDefault).
+		Msg("Address updated successfully")
+
+	return address, nil
+}
+
+// Delete removes one of a customer's addresses. If it was the default, the
+// oldest remaining address is promoted
+func (s *addressService) Delete(ctx context.Context, customerID, addressID uuid.UUID) error {
+	s.logger.Info().
+		Str("customer_id", customerID.String()).
+		Str("address_id", addressID.String()).
+		Msg("Deleting address")
+
+	address, err := s.GetByID(ctx, customerID, addressID)
+	if err != nil {
+		return err
+	}
+
+	err = s.repo.Delete(ctx, addressID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("address_id", addressID.String()).
+			Msg("Failed to delete address")
+		return fmt.Errorf("failed to delete address: %w", err)
+	}
+
+	if address.IsDefault {
+		remaining, err := s.repo.GetByCustomerID(ctx, customerID)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Str("customer_id", customerID.String()).
+				Msg("Failed to load remaining addresses to promote a new default")
+			return nil // The address itself was deleted
+		}
+
+		if len(remaining) > 0 {
+			if err := s.repo.SetDefault(ctx, customerID, remaining[0].ID); err != nil {
+				s.logger.Error().Err(err).
+					Str("address_id", remaining[0].ID.String()).
+					Msg("Failed to promote new default address")
+			}
+		}
+	}
+
+	s.logger.Info().
+		Str("address_id", addressID.String()).
+		Msg("Address deleted successfully")
+
+	return nil
+}
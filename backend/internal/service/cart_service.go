@@ -0,0 +1,509 @@
+// internal/service/cart_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// cartService implements CartService
+type cartService struct {
+	logger       zerolog.Logger
+	cartRepo     interfaces.CartRepository
+	variantRepo  interfaces.VariantRepository
+	productRepo  interfaces.ProductRepository
+	customerRepo interfaces.CustomerRepository
+	priceService interfaces.PriceService
+}
+
+// NewCartService creates a new cart service
+func NewCartService(
+	logger *zerolog.Logger,
+	cartRepo interfaces.CartRepository,
+	variantRepo interfaces.VariantRepository,
+	productRepo interfaces.ProductRepository,
+	customerRepo interfaces.CustomerRepository,
+	priceService interfaces.PriceService,
+) interfaces.CartService {
+	subLogger := logger.With().Str("component", "cart_service").Logger()
+	return &cartService{
+		logger:       subLogger,
+		cartRepo:     cartRepo,
+		variantRepo:  variantRepo,
+		productRepo:  productRepo,
+		customerRepo: customerRepo,
+		priceService: priceService,
+	}
+}
+
+// cartLine is the current catalog state behind a cart line
+type cartLine struct {
+	variant *model.Variant
+	product *model.Product
+	price   *model.Price
+}
+
+// Create starts a new cart. A customer who already has an active cart gets
+// that cart back instead of a second one
+func (s *cartService) Create(ctx context.Context, d *dto.CartCreateDTO) (*model.Cart, error) {
+	if d.CustomerID != nil {
+		if err := s.requireCustomer(ctx, *d.CustomerID); err != nil {
+			return nil, err
+		}
+
+		existing, err := s.cartRepo.GetActiveByCustomerID(ctx, *d.CustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve customer cart: %w", err)
+		}
+		if existing != nil {
+			s.logger.Debug().
+				Str("cart_id", existing.ID.String()).
+				Str("customer_id", d.CustomerID.String()).
+				Msg("Returning existing active cart")
+			return s.price(ctx, existing)
+		}
+	}
+
+	now := time.Now()
+	cart := &model.Cart{
+		ID:         uuid.New(),
+		CustomerID: d.CustomerID,
+		Status:     model.CartStatusActive,
+		Items:      make([]*model.CartItem, 0),
+		CreatedAt:  now,
+		UpdatedAt:  now,
+	}
+
+	if err := s.cartRepo.Create(ctx, cart); err != nil {
+		return nil, fmt.Errorf("failed to create cart: %w", err)
+	}
+
+	s.logger.Info().
+		Str("cart_id", cart.ID.String()).
+		Bool("guest", cart.CustomerID == nil).
+		Msg("Cart created")
+
+	return cart, nil
+}
+
+// GetByID retrieves a cart, repricing it against the current catalog
+func (s *cartService) GetByID(ctx context.Context, id uuid.UUID) (*model.Cart, error) {
+	cart, err := s.getCart(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.price(ctx, cart)
+}
+
+// AddItem adds a variant to an active cart. Adding a variant that is already
+// in the cart increases the quantity of the existing line
+func (s *cartService) AddItem(ctx context.Context, cartID uuid.UUID, d *dto.CartItemDTO) (*model.Cart, error) {
+	cart, err := s.getActiveCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	quantity := d.Quantity
+	var existing *model.CartItem
+	for _, item := range cart.Items {
+		if item.VariantID == d.VariantID {
+			existing = item
+			quantity += item.Quantity
+			break
+		}
+	}
+
+	if err := s.saveLine(ctx, cart, existing, d.VariantID, quantity); err != nil {
+		return nil, err
+	}
+
+	return s.reload(ctx, cartID)
+}
+
+// UpdateItem sets the quantity of a line. A quantity of zero removes it
+func (s *cartService) UpdateItem(ctx context.Context, cartID, variantID uuid.UUID, d *dto.CartItemUpdateDTO) (*model.Cart, error) {
+	if d.Quantity == 0 {
+		return s.RemoveItem(ctx, cartID, variantID)
+	}
+
+	cart, err := s.getActiveCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	existing := findCartItem(cart, variantID)
+	if existing == nil {
+		return nil, fmt.Errorf("%w: variant %s is not in the cart", ErrInvalidInput, variantID)
+	}
+
+	if err := s.saveLine(ctx, cart, existing, variantID, d.Quantity); err != nil {
+		return nil, err
+	}
+
+	return s.reload(ctx, cartID)
+}
+
+// RemoveItem removes a variant line from an active cart
+func (s *cartService) RemoveItem(ctx context.Context, cartID, variantID uuid.UUID) (*model.Cart, error) {
+	cart, err := s.getActiveCart(ctx, cartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if findCartItem(cart, variantID) == nil {
+		return nil, fmt.Errorf("%w: variant %s is not in the cart", ErrInvalidInput, variantID)
+	}
+
+	if err := s.cartRepo.RemoveItem(ctx, cartID, variantID); err != nil {
+		return nil, fmt.Errorf("failed to remove cart item: %w", err)
+	}
+
+	s.logger.Info().
+		Str("cart_id", cartID.String()).
+		Str("variant_id", variantID.String()).
+		Msg("Removed item from cart")
+
+	return s.reload(ctx, cartID)
+}
+
+// Merge folds a guest cart into the customer's active cart on login. If the
+// customer has no active cart, the guest cart simply becomes theirs
+func (s *cartService) Merge(ctx context.Context, guestCartID uuid.UUID, d *dto.CartMergeDTO) (*model.Cart, error) {
+	guest, err := s.getActiveCart(ctx, guestCartID)
+	if err != nil {
+		return nil, err
+	}
+
+	if guest.CustomerID != nil {
+		if *guest.CustomerID == d.CustomerID {
+			return s.price(ctx, guest)
+		}
+		return nil, fmt.Errorf("%w: cart belongs to another customer", ErrInvalidState)
+	}
+
+	if err := s.requireCustomer(ctx, d.CustomerID); err != nil {
+		return nil, err
+	}
+
+	target, err := s.cartRepo.GetActiveByCustomerID(ctx, d.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve customer cart: %w", err)
+	}
+
+	if target == nil {
+		guest.CustomerID = &d.CustomerID
+		if err := s.cartRepo.Update(ctx, guest); err != nil {
+			return nil, fmt.Errorf("failed to assign cart to customer: %w", err)
+		}
+
+		s.logger.Info().
+			Str("cart_id", guest.ID.String()).
+			Str("customer_id", d.CustomerID.String()).
+			Msg("Guest cart assigned to customer")
+
+		return s.price(ctx, guest)
+	}
+
+	for _, item := range guest.Items {
+		quantity := item.Quantity
+		existing := findCartItem(target, item.VariantID)
+		if existing != nil {
+			quantity += existing.Quantity
+		}
+		if quantity > 99 {
+			quantity = 99
+		}
+
+		merged := &model.CartItem{
+			ID:         uuid.New(),
+			CartID:     target.ID,
+			VariantID:  item.VariantID,
+			PriceID:    item.PriceID,
+			Quantity:   quantity,
+			UnitAmount: item.UnitAmount,
+			CreatedAt:  time.Now(),
+		}
+		if existing != nil {
+			merged.ID = existing.ID
+			merged.CreatedAt = existing.CreatedAt
+		}
+
+		// Repricing the target cart below catches anything stale
+		if err := s.cartRepo.SaveItem(ctx, merged); err != nil {
+			return nil, fmt.Errorf("failed to merge cart item: %w", err)
+		}
+	}
+
+	guest.Status = model.CartStatusMerged
+	if err := s.cartRepo.Update(ctx, guest); err != nil {
+		return nil, fmt.Errorf("failed to mark guest cart merged: %w", err)
+	}
+
+	s.logger.Info().
+		Str("guest_cart_id", guest.ID.String()).
+		Str("cart_id", target.ID.String()).
+		Str("customer_id", d.CustomerID.String()).
+		Int("item_count", len(guest.Items)).
+		Msg("Guest cart merged into customer cart")
+
+	return s.reload(ctx, target.ID)
+}
+
+// saveLine validates a variant against the catalog and writes the line at
+// its current price
+func (s *cartService) saveLine(ctx context.Context, cart *model.Cart, existing *model.CartItem, variantID uuid.UUID, quantity int) error {
+	if quantity > 99 {
+		return fmt.Errorf("%w: quantity must not exceed 99", ErrInvalidInput)
+	}
+
+	line, reason, err := s.resolveLine(ctx, variantID)
+	if err != nil {
+		return err
+	}
+	if reason != "" {
+		return fmt.Errorf("%w: variant %s %s", ErrInvalidInput, variantID, reason)
+	}
+
+	if line.variant.StockLevel < quantity {
+		s.logger.Warn().
+			Str("variant_id", variantID.String()).
+			Int("stock_level", line.variant.StockLevel).
+			Int("requested", quantity).
+			Msg("Insufficient stock for cart")
+		return fmt.Errorf("%w: insufficient stock for variant %s", ErrInvalidInput, variantID)
+	}
+
+	// All lines are paid in a single checkout, so they must share a currency
+	priced, err := s.price(ctx, cart)
+	if err != nil {
+		return err
+	}
+	if priced.Currency != "" && priced.Currency != line.price.Currency {
+		return fmt.Errorf("%w: cart is priced in %s, variant %s is priced in %s", ErrInvalidInput, priced.Currency, variantID, line.price.Currency)
+	}
+
+	item := &model.CartItem{
+		ID:         uuid.New(),
+		CartID:     cart.ID,
+		VariantID:  variantID,
+		PriceID:    line.price.ID,
+		Quantity:   quantity,
+		UnitAmount: line.price.Amount,
+		CreatedAt:  time.Now(),
+	}
+	if existing != nil {
+		item.ID = existing.ID
+		item.CreatedAt = existing.CreatedAt
+	}
+
+	if err := s.cartRepo.SaveItem(ctx, item); err != nil {
+		return fmt.Errorf("failed to save cart item: %w", err)
+	}
+
+	s.logger.Info().
+		Str("cart_id", cart.ID.String()).
+		Str("variant_id", variantID.String()).
+		Int("quantity", quantity).
+		Msg("Saved cart item")
+
+	return nil
+}
+
+// resolveLine loads the catalog state for a variant. A non-empty reason means
+// the variant can no longer be bought
+func (s *cartService) resolveLine(ctx context.Context, variantID uuid.UUID) (*cartLine, string, error) {
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, "no longer exists", nil
+	}
+	if !variant.Active {
+		return nil, "is no longer available", nil
+	}
+
+	product, err := s.productRepo.GetByID(ctx, variant.ProductID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product == nil || !product.Active || product.Archived {
+		return nil, "belongs to a product that is no longer available", nil
+	}
+
+	// Resolve prices the same way the storefront's product price list does
+	prices, err := s.priceService.GetByProductID(ctx, product.ID)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to retrieve prices: %w", err)
+	}
+
+	for _, price := range prices {
+		if price.ID == variant.PriceID && price.Active {
+			return &cartLine{variant: variant, product: product, price: price}, "", nil
+		}
+	}
+
+	return nil, "has no active price", nil
+}
+
+// price reprices an active cart against the current catalog. Lines that can
+// no longer be bought are removed and changed prices are saved, each recorded
+// as an adjustment so the storefront can tell the shopper
+func (s *cartService) price(ctx context.Context, cart *model.Cart) (*model.Cart, error) {
+	cart.Subtotal = 0
+	cart.Currency = ""
+	cart.Adjustments = nil
+
+	// Carts that were merged or converted are kept as they were
+	if cart.Status != model.CartStatusActive {
+		for _, item := range cart.Items {
+			item.TotalAmount = item.UnitAmount * int64(item.Quantity)
+			cart.Subtotal += item.TotalAmount
+		}
+		return cart, nil
+	}
+
+	items := make([]*model.CartItem, 0, len(cart.Items))
+	for _, item := range cart.Items {
+		line, reason, err := s.resolveLine(ctx, item.VariantID)
+		if err != nil {
+			return nil, err
+		}
+
+		if reason != "" {
+			if err := s.cartRepo.RemoveItem(ctx, cart.ID, item.VariantID); err != nil {
+				return nil, fmt.Errorf("failed to remove unavailable cart item: %w", err)
+			}
+
+			s.logger.Info().
+				Str("cart_id", cart.ID.String()).
+				Str("variant_id", item.VariantID.String()).
+				Str("reason", reason).
+				Msg("Removed unavailable item from cart")
+
+			cart.Adjustments = append(cart.Adjustments, model.CartAdjustment{
+				VariantID: item.VariantID,
+				Type:      "removed",
+				Reason:    "variant " + reason,
+				OldAmount: item.UnitAmount,
+			})
+			continue
+		}
+
+		if item.PriceID != line.price.ID || item.UnitAmount != line.price.Amount {
+			oldAmount := item.UnitAmount
+			item.PriceID = line.price.ID
+			item.UnitAmount = line.price.Amount
+
+			if err := s.cartRepo.SaveItem(ctx, item); err != nil {
+				return nil, fmt.Errorf("failed to reprice cart item: %w", err)
+			}
+
+			s.logger.Info().
+				Str("cart_id", cart.ID.String()).
+				Str("variant_id", item.VariantID.String()).
+				Int64("old_amount", oldAmount).
+				Int64("new_amount", item.UnitAmount).
+				Msg("Repriced cart item")
+
+			cart.Adjustments = append(cart.Adjustments, model.CartAdjustment{
+				VariantID: item.VariantID,
+				Type:      "repriced",
+				Reason:    "price changed",
+				OldAmount: oldAmount,
+				NewAmount: item.UnitAmount,
+			})
+		}
+
+		if cart.Currency == "" {
+			cart.Currency = line.price.Currency
+		}
+
+		item.ProductID = line.product.ID
+		item.ProductName = line.product.Name
+		item.Options = line.variant.Options
+		item.TotalAmount = item.UnitAmount * int64(item.Quantity)
+		cart.Subtotal += item.TotalAmount
+		items = append(items, item)
+	}
+
+	cart.Items = items
+	return cart, nil
+}
+
+// reload fetches and prices a cart after it was changed
+func (s *cartService) reload(ctx context.Context, id uuid.UUID) (*model.Cart, error) {
+	cart, err := s.getCart(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.price(ctx, cart)
+}
+
+// getCart retrieves a cart or returns ErrResourceNotFound
+func (s *cartService) getCart(ctx context.Context, id uuid.UUID) (*model.Cart, error) {
+	cart, err := s.cartRepo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("cart_id", id.String()).
+			Msg("Failed to retrieve cart")
+		return nil, fmt.Errorf("failed to retrieve cart: %w", err)
+	}
+
+	if cart == nil {
+		s.logger.Warn().
+			Str("cart_id", id.String()).
+			Msg("Cart not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return cart, nil
+}
+
+// getActiveCart retrieves a cart that can still be changed
+func (s *cartService) getActiveCart(ctx context.Context, id uuid.UUID) (*model.Cart, error) {
+	cart, err := s.getCart(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if cart.Status != model.CartStatusActive {
+		return nil, fmt.Errorf("%w: cart is %s", ErrInvalidState, cart.Status)
+	}
+
+	return cart, nil
+}
+
+// requireCustomer checks that a customer exists and is active
+func (s *cartService) requireCustomer(ctx context.Context, customerID uuid.UUID) error {
+	customer, err := s.customerRepo.GetByID(ctx, customerID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	if customer == nil || !customer.Active {
+		return fmt.Errorf("%w: customer %s not found", ErrInvalidInput, customerID)
+	}
+
+	return nil
+}
+
+// findCartItem returns the line for a variant, or nil
+func findCartItem(cart *model.Cart, variantID uuid.UUID) *model.CartItem {
+	for _, item := range cart.Items {
+		if item.VariantID == variantID {
+			return item
+		}
+	}
+	return nil
+}
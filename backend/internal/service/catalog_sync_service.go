@@ -0,0 +1,360 @@
+// internal/service/catalog_sync_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/events"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/sync"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// catalogSyncService implements CatalogSyncService
+type catalogSyncService struct {
+	logger        zerolog.Logger
+	eventBus      events.EventBus
+	productRepo   interfaces.ProductRepository
+	variantRepo   interfaces.VariantRepository
+	priceRepo     interfaces.PriceRepository
+	syncRepo      interfaces.SyncHashRepository
+	stripeService interfaces.StripeService
+}
+
+// NewCatalogSyncService creates a new catalog sync service
+func NewCatalogSyncService(
+	logger *zerolog.Logger,
+	eventBus events.EventBus,
+	productRepo interfaces.ProductRepository,
+	variantRepo interfaces.VariantRepository,
+	priceRepo interfaces.PriceRepository,
+	syncRepo interfaces.SyncHashRepository,
+	stripeService interfaces.StripeService,
+) interfaces.CatalogSyncService {
+	subLogger := logger.With().Str("component", "catalog_sync_service").Logger()
+	return &catalogSyncService{
+		logger:        subLogger,
+		eventBus:      eventBus,
+		productRepo:   productRepo,
+		variantRepo:   variantRepo,
+		priceRepo:     priceRepo,
+		syncRepo:      syncRepo,
+		stripeService: stripeService,
+	}
+}
+
+// Start subscribes to update events durably, so edits made while Stripe is
+// unreachable are pushed once it recovers. Each push sends the current state
+// rather than the event's, so a late redelivery can't restore stale values
+func (s *catalogSyncService) Start() error {
+	_, err := events.SubscribeDurable(s.eventBus, events.TopicProductUpdated, "catalog-sync-product-updated", s.handleProductUpdated)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to subscribe to product updated events")
+		return err
+	}
+
+	_, err = events.SubscribeDurable(s.eventBus, events.TopicVariantUpdated, "catalog-sync-variant-updated", s.handleVariantUpdated)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to subscribe to variant updated events")
+		return err
+	}
+
+	_, err = events.SubscribeDurable(s.eventBus, events.TopicPriceUpdated, "catalog-sync-price-updated", s.handlePriceUpdated)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to subscribe to price updated events")
+		return err
+	}
+
+	s.logger.Info().Msg("Catalog sync subscribed to product, variant and price updates")
+	return nil
+}
+
+// handleProductUpdated pushes an edited product to Stripe
+func (s *catalogSyncService) handleProductUpdated(event events.TypedEvent[events.ProductUpdatedPayload]) error {
+	productID, err := uuid.Parse(event.Payload.ProductID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid product ID: %v", events.ErrNoRetry, err)
+	}
+
+	s.logger.Debug().
+		Str("event_id", event.ID).
+		Str("product_id", productID.String()).
+		Msg("Pushing product update to Stripe")
+
+	return s.PushProduct(context.Background(), productID)
+}
+
+// handleVariantUpdated pushes an edited variant to Stripe. Updates that came
+// from a Stripe webhook are already in Stripe
+func (s *catalogSyncService) handleVariantUpdated(event events.TypedEvent[events.VariantUpdatedPayload]) error {
+	if event.Payload.UpdateSource == model.SyncSourceStripeWebhook {
+		return nil
+	}
+
+	variantID, err := uuid.Parse(event.Payload.VariantID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid variant ID: %v", events.ErrNoRetry, err)
+	}
+
+	s.logger.Debug().
+		Str("event_id", event.ID).
+		Str("variant_id", variantID.String()).
+		Str("update_source", event.Payload.UpdateSource).
+		Msg("Pushing variant update to Stripe")
+
+	return s.PushVariant(context.Background(), variantID)
+}
+
+// handlePriceUpdated pushes an edited price to Stripe
+func (s *catalogSyncService) handlePriceUpdated(event events.TypedEvent[events.PriceUpdatedPayload]) error {
+	priceID, err := uuid.Parse(event.Payload.PriceID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid price ID: %v", events.ErrNoRetry, err)
+	}
+
+	s.logger.Debug().
+		Str("event_id", event.ID).
+		Str("price_id", priceID.String()).
+		Msg("Pushing price update to Stripe")
+
+	return s.PushPrice(context.Background(), priceID)
+}
+
+// PushProduct updates the product's own Stripe product, if it has one, and
+// the Stripe product of each of its variants
+func (s *catalogSyncService) PushProduct(ctx context.Context, productID uuid.UUID) error {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve product: %w", err)
+	}
+
+	if product.StripeID != "" {
+		_, err := s.stripeService.UpdateProductDetails(
+			product.StripeID,
+			product.Name,
+			product.Description,
+			productImages(product),
+			productOnSale(product),
+			nil,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to update Stripe product %s: %w", product.StripeID, err)
+		}
+	}
+
+	variants, err := s.variantRepo.GetByProductID(ctx, product.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve variants: %w", err)
+	}
+	for _, variant := range variants {
+		if err := s.pushVariantProduct(ctx, product, variant); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// PushVariant updates the variant's Stripe product and, if the variant uses
+// its price's Stripe price, that price
+func (s *catalogSyncService) PushVariant(ctx context.Context, variantID uuid.UUID) error {
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		// Deleted since the event was published
+		return nil
+	}
+
+	product, err := s.productRepo.GetByID(ctx, variant.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve product: %w", err)
+	}
+
+	if err := s.pushVariantProduct(ctx, product, variant); err != nil {
+		return err
+	}
+
+	price, err := s.priceRepo.GetByID(ctx, variant.PriceID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve price: %w", err)
+	}
+	if price == nil || price.StripeID == "" || price.StripeID != variant.StripePriceID {
+		return nil
+	}
+	return s.pushPrice(ctx, price)
+}
+
+// PushPrice brings the price's Stripe price in line with it
+func (s *catalogSyncService) PushPrice(ctx context.Context, priceID uuid.UUID) error {
+	price, err := s.priceRepo.GetByID(ctx, priceID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve price: %w", err)
+	}
+	if price == nil || price.StripeID == "" {
+		return nil
+	}
+	return s.pushPrice(ctx, price)
+}
+
+// pushVariantProduct sends a variant's name, description, images, options and
+// status to its Stripe product in one update, then records the resulting hash
+// so the product.updated webhook it triggers is recognised as our own
+func (s *catalogSyncService) pushVariantProduct(ctx context.Context, product *model.Product, variant *model.Variant) error {
+	if variant.StripeProductID == "" {
+		return nil
+	}
+
+	stripeProduct, err := s.stripeService.UpdateProductDetails(
+		variant.StripeProductID,
+		product.Name,
+		product.Description,
+		productImages(product),
+		variant.Active,
+		variantStripeMetadata(variant),
+	)
+	if err != nil {
+		return fmt.Errorf("failed to update Stripe product %s: %w", variant.StripeProductID, err)
+	}
+
+	storeSyncHash(ctx, s.logger, s.syncRepo, variant.ID, stripeProduct, model.SyncSourceAPICall)
+
+	s.logger.Info().
+		Str("variant_id", variant.ID.String()).
+		Str("stripe_product_id", stripeProduct.ID).
+		Msg("Pushed variant to Stripe")
+
+	return nil
+}
+
+// pushPrice updates the status of the price's Stripe price in place. Any other
+// change needs a new Stripe price, since prices are immutable: the new one
+// replaces the old on the price and on the variants using it, and the old one
+// is retired
+func (s *catalogSyncService) pushPrice(ctx context.Context, price *model.Price) error {
+	current, err := s.stripeService.GetPrice(price.StripeID)
+	if err != nil {
+		return err
+	}
+
+	if len(priceDiffs(price, current)) == 0 {
+		return nil
+	}
+
+	if !priceTermsDiffer(price, current) {
+		if _, err := s.stripeService.SetPriceActive(current.ID, price.Active); err != nil {
+			return err
+		}
+		s.logger.Info().
+			Str("price_id", price.ID.String()).
+			Str("stripe_price_id", current.ID).
+			Bool("active", price.Active).
+			Msg("Pushed price status to Stripe")
+		return nil
+	}
+
+	if current.Product == nil {
+		return fmt.Errorf("Stripe price %s has no product", current.ID)
+	}
+	// Keyed on the price being replaced and the new terms, so a redelivery after
+	// a failed save reuses the replacement instead of orphaning it
+	replacement, err := createStripePrice(s.stripeService, current.Product.ID, price, priceReplacementKey(price, current.ID))
+	if err != nil {
+		return err
+	}
+
+	price.StripeID = replacement.ID
+	price.UpdatedAt = time.Now()
+	if err := s.priceRepo.Update(ctx, price); err != nil {
+		return fmt.Errorf("failed to update price: %w", err)
+	}
+
+	variants, err := s.variantRepo.GetByProductID(ctx, price.ProductID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve variants: %w", err)
+	}
+	for _, variant := range variants {
+		if variant.PriceID != price.ID || variant.StripePriceID != current.ID {
+			continue
+		}
+		variant.StripePriceID = replacement.ID
+		variant.UpdatedAt = time.Now()
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return fmt.Errorf("failed to update variant %s: %w", variant.ID, err)
+		}
+	}
+
+	if current.Active {
+		if _, err := s.stripeService.SetPriceActive(current.ID, false); err != nil {
+			s.logger.Error().Err(err).
+				Str("price_id", price.ID.String()).
+				Str("stripe_price_id", current.ID).
+				Msg("Failed to deactivate replaced Stripe price")
+		}
+	}
+
+	s.logger.Info().
+		Str("price_id", price.ID.String()).
+		Str("old_stripe_price_id", current.ID).
+		Str("stripe_price_id", replacement.ID).
+		Msg("Replaced Stripe price")
+
+	return nil
+}
+
+// createStripePrice creates a Stripe price with the terms of a local price.
+// With an idempotency key, a retry returns the price the first attempt created
+func createStripePrice(stripeService interfaces.StripeService, stripeProductID string, price *model.Price, idempotencyKey string) (*stripe.Price, error) {
+	stripePrice, err := stripeService.CreatePrice(
+		stripeProductID,
+		price.Amount,
+		price.Currency,
+		price.Type == "recurring",
+		price.Interval,
+		int64(price.IntervalCount),
+		idempotencyKey,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Stripe price: %w", err)
+	}
+	if !price.Active {
+		if _, err := stripeService.SetPriceActive(stripePrice.ID, false); err != nil {
+			return nil, fmt.Errorf("failed to deactivate Stripe price: %w", err)
+		}
+		stripePrice.Active = false
+	}
+	return stripePrice, nil
+}
+
+// priceReplacementKey is the idempotency key for replacing a Stripe price with
+// one carrying the local price's terms
+func priceReplacementKey(price *model.Price, replacedStripeID string) string {
+	return fmt.Sprintf("price-replace-%s-%s-%d-%s-%s-%s-%d",
+		price.ID, replacedStripeID, price.Amount, strings.ToLower(price.Currency),
+		price.Type, price.Interval, price.IntervalCount)
+}
+
+// storeSyncHash records the hash of a Stripe product we just wrote, so the
+// webhook echoing the write is skipped as unchanged. Failures are logged
+func storeSyncHash(ctx context.Context, logger zerolog.Logger, syncRepo interfaces.SyncHashRepository, variantID uuid.UUID, stripeProduct *stripe.Product, source string) {
+	hash, err := sync.ComputeStripeProductHash(*stripeProduct)
+	if err != nil {
+		logger.Error().Err(err).Str("stripe_product_id", stripeProduct.ID).Msg("Failed to hash Stripe product")
+		return
+	}
+
+	record := sync.CreateSyncHashRecord(variantID, stripeProduct.ID, hash, source)
+	if err := syncRepo.Upsert(ctx, record); err != nil {
+		logger.Error().Err(err).
+			Str("variant_id", variantID.String()).
+			Str("stripe_product_id", stripeProduct.ID).
+			Msg("Failed to store sync hash")
+	}
+}
@@ -0,0 +1,191 @@
+// internal/service/checkout_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/config"
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	stripeSDK "github.com/stripe/stripe-go/v82"
+)
+
+// checkoutService implements CheckoutService
+type checkoutService struct {
+	logger          zerolog.Logger
+	stripeConfig    *config.StripeConfig
+	inventoryConfig *config.InventoryConfig
+	variantRepo     interfaces.VariantRepository
+	priceRepo       interfaces.PriceRepository
+	productRepo     interfaces.ProductRepository
+	customerRepo    interfaces.CustomerRepository
+	variantService  interfaces.VariantService
+	stripeService   interfaces.StripeService
+}
+
+// NewCheckoutService creates a new checkout service
+func NewCheckoutService(
+	logger *zerolog.Logger,
+	stripeConfig *config.StripeConfig,
+	inventoryConfig *config.InventoryConfig,
+	variantRepo interfaces.VariantRepository,
+	priceRepo interfaces.PriceRepository,
+	productRepo interfaces.ProductRepository,
+	customerRepo interfaces.CustomerRepository,
+	variantService interfaces.VariantService,
+	stripeService interfaces.StripeService,
+) interfaces.CheckoutService {
+	subLogger := logger.With().Str("component", "checkout_service").Logger()
+	return &checkoutService{
+		logger:          subLogger,
+		stripeConfig:    stripeConfig,
+		inventoryConfig: inventoryConfig,
+		variantRepo:     variantRepo,
+		priceRepo:       priceRepo,
+		productRepo:     productRepo,
+		customerRepo:    customerRepo,
+		variantService:  variantService,
+		stripeService:   stripeService,
+	}
+}
+
+// CreateSession validates a cart and starts a hosted Stripe Checkout session.
+// Carts containing a recurring price are checked out in subscription mode.
+// Stock is held for the lifetime of the session and released if it expires
+func (s *checkoutService) CreateSession(ctx context.Context, d *dto.CheckoutSessionCreateDTO) (*dto.CheckoutSessionResponseDTO, error) {
+	s.logger.Info().
+		Int("item_count", len(d.Items)).
+		Bool("has_customer", d.CustomerID != nil).
+		Msg("Creating checkout session")
+
+	var stripeCustomerID string
+	email := d.Email
+	metadata := map[string]string{}
+
+	if d.CustomerID != nil {
+		customer, err := s.customerRepo.GetByID(ctx, *d.CustomerID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+		}
+		if customer == nil || !customer.Active {
+			s.logger.Warn().Str("customer_id", d.CustomerID.String()).Msg("Customer not found or inactive")
+			return nil, postgres.ErrResourceNotFound
+		}
+
+		stripeCustomerID = customer.StripeID
+		email = customer.Email
+		metadata["customer_id"] = customer.ID.String()
+	}
+
+	mode := stripeSDK.CheckoutSessionModePayment
+	var recurring *model.Price
+	lineItems := make([]*stripeSDK.CheckoutSessionLineItemParams, 0, len(d.Items))
+	quantities := make(map[uuid.UUID]int, len(d.Items))
+
+	for _, item := range d.Items {
+		variant, err := s.variantRepo.GetByID(ctx, item.VariantID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve variant: %w", err)
+		}
+		if variant == nil {
+			return nil, fmt.Errorf("%w: variant %s not found", ErrInvalidInput, item.VariantID)
+		}
+		if !variant.Active {
+			return nil, fmt.Errorf("%w: variant %s is not available", ErrInvalidInput, item.VariantID)
+		}
+
+		product, err := s.productRepo.GetByID(ctx, variant.ProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve product: %w", err)
+		}
+		if product == nil || !product.Active || product.Archived {
+			return nil, fmt.Errorf("%w: variant %s belongs to an unavailable product", ErrInvalidInput, item.VariantID)
+		}
+
+		price, err := s.priceRepo.GetByID(ctx, variant.PriceID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve price: %w", err)
+		}
+		if price == nil || !price.Active {
+			return nil, fmt.Errorf("%w: variant %s has no active price", ErrInvalidInput, item.VariantID)
+		}
+
+		stripePriceID := variant.StripePriceID
+		if stripePriceID == "" {
+			stripePriceID = price.StripeID
+		}
+		if stripePriceID == "" {
+			s.logger.Error().
+				Str("variant_id", variant.ID.String()).
+				Msg("Variant has no Stripe price")
+			return nil, fmt.Errorf("%w: variant %s is not purchasable yet", ErrInvalidInput, item.VariantID)
+		}
+
+		if price.Type == "recurring" {
+			// Stripe bills every recurring line of a subscription on one schedule
+			if recurring != nil && (recurring.Interval != price.Interval || recurring.IntervalCount != price.IntervalCount) {
+				return nil, fmt.Errorf("%w: all subscription items must share the same delivery interval", ErrInvalidInput)
+			}
+			recurring = price
+			mode = stripeSDK.CheckoutSessionModeSubscription
+		}
+
+		lineItems = append(lineItems, &stripeSDK.CheckoutSessionLineItemParams{
+			Price:    stripeSDK.String(stripePriceID),
+			Quantity: stripeSDK.Int64(int64(item.Quantity)),
+		})
+		quantities[variant.ID] += item.Quantity
+	}
+
+	// Hold the stock before sending the customer to Stripe so two checkouts
+	// cannot both pay for the last bag. The hold outlives the session so a
+	// payment completed just before expiry still finds its stock
+	reference := uuid.New().String()
+	expiresAt := time.Now().Add(s.inventoryConfig.ReservationTTL)
+	holdUntil := expiresAt.Add(s.inventoryConfig.ReservationGrace)
+	if err := s.variantService.ReserveStock(ctx, reference, quantities, holdUntil); err != nil {
+		if errors.Is(err, postgres.ErrInsufficientStock) {
+			s.logger.Warn().Err(err).Msg("Insufficient stock for checkout")
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		return nil, fmt.Errorf("failed to reserve stock: %w", err)
+	}
+	metadata["reservation_ref"] = reference
+
+	session, err := s.stripeService.CreateCheckoutSession(
+		string(mode),
+		stripeCustomerID,
+		email,
+		lineItems,
+		s.stripeConfig.SuccessURL,
+		s.stripeConfig.CancelURL,
+		metadata,
+		expiresAt,
+	)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to create Stripe checkout session")
+		if releaseErr := s.variantService.ReleaseStock(ctx, reference); releaseErr != nil {
+			s.logger.Error().Err(releaseErr).Str("reference", reference).Msg("Failed to release stock reservation")
+		}
+		return nil, fmt.Errorf("%w: %v", ErrServiceUnavailable, err)
+	}
+
+	s.logger.Info().
+		Str("session_id", session.ID).
+		Str("mode", string(mode)).
+		Msg("Checkout session created successfully")
+
+	return &dto.CheckoutSessionResponseDTO{
+		SessionID: session.ID,
+		URL:       session.URL,
+		Mode:      string(mode),
+		ExpiresAt: time.Unix(session.ExpiresAt, 0),
+	}, nil
+}
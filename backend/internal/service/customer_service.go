@@ -0,0 +1,423 @@
+// internal/service/customer_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/events"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// customerService implements CustomerService
+type customerService struct {
+	logger        zerolog.Logger
+	eventBus      events.EventBus
+	repo          interfaces.CustomerRepository
+	stripeService interfaces.StripeService
+}
+
+// NewCustomerService creates a new customer service
+func NewCustomerService(logger *zerolog.Logger, eventBus events.EventBus, customerRepo interfaces.CustomerRepository, stripeService interfaces.StripeService) interfaces.CustomerService {
+	subLogger := logger.With().Str("component", "customer_service").Logger()
+	return &customerService{
+		logger:        subLogger,
+		eventBus:      eventBus,
+		repo:          customerRepo,
+		stripeService: stripeService,
+	}
+}
+
+// Create registers the customer in Stripe, saves it to the database and publishes an event
+func (s *customerService) Create(ctx context.Context, c *dto.CustomerCreateDTO) (*model.Customer, error) {
+	s.logger.Info().
+		Str("email", c.Email).
+		Msg("Creating customer")
+
+	customer := c.ToModel()
+
+	// Emails are unique per customer
+	existingCustomer, err := s.repo.GetByEmail(ctx, customer.Email)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Error checking for existing customer")
+		return nil, fmt.Errorf("error checking for existing customer: %w", err)
+	}
+
+	if existingCustomer != nil {
+		s.logger.Warn().
+			Str("email", customer.Email).
+			Str("existing_id", existingCustomer.ID.String()).
+			Msg("Customer with this email already exists")
+		return nil, postgres.ErrDuplicateEmail
+	}
+
+	// The customers table requires a Stripe ID, so create the Stripe customer first
+	stripeCustomer, err := s.stripeService.CreateCustomer(
+		customer.Email,
+		strings.TrimSpace(customer.FirstName+" "+customer.LastName),
+		customer.PhoneNumber,
+		map[string]string{"customer_id": customer.ID.String()},
+	)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("email", customer.Email).
+			Msg("Failed to create Stripe customer")
+		return nil, fmt.Errorf("failed to create Stripe customer: %w", err)
+	}
+	customer.StripeID = stripeCustomer.ID
+
+	err = s.repo.Create(ctx, customer)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to save customer to database")
+
+		// Don't leave a Stripe customer behind that nothing refers to
+		if delErr := s.stripeService.DeleteCustomer(customer.StripeID); delErr != nil {
+			s.logger.Error().Err(delErr).
+				Str("stripe_id", customer.StripeID).
+				Msg("Failed to delete orphaned Stripe customer")
+		}
+
+		if errors.Is(err, postgres.ErrDuplicateEmail) {
+			return nil, err
+		}
+		return nil, fmt.Errorf("failed to create customer: %w", err)
+	}
+
+	payload := events.CustomerCreatedPayload{
+		CustomerID:  customer.ID.String(),
+		StripeID:    customer.StripeID,
+		Email:       customer.Email,
+		FirstName:   customer.FirstName,
+		LastName:    customer.LastName,
+		PhoneNumber: customer.PhoneNumber,
+		CreatedAt:   customer.CreatedAt,
+	}
+
+	err = s.eventBus.Publish(events.TopicCustomerCreated, payload)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to publish customer created event")
+		// Don't return error since the customer was already saved to DB
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicCustomerCreated).
+		Str("customer_id", customer.ID.String()).
+		Str("stripe_id", customer.StripeID).
+		Msg("Published customer created event")
+
+	return customer, nil
+}
+
+// GetByID retrieves a customer by ID
+func (s *customerService) GetByID(ctx context.Context, id uuid.UUID) (*model.Customer, error) {
+	s.logger.Info().
+		Str("customer_id", id.String()).
+		Msg("Retrieving customer by ID")
+
+	customer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to retrieve customer")
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	if customer == nil {
+		s.logger.Warn().
+			Str("customer_id", id.String()).
+			Msg("Customer not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return customer, nil
+}
+
+// GetByEmail retrieves a customer by email address
+func (s *customerService) GetByEmail(ctx context.Context, email string) (*model.Customer, error) {
+	s.logger.Info().
+		Str("email", email).
+		Msg("Retrieving customer by email")
+
+	customer, err := s.repo.GetByEmail(ctx, strings.TrimSpace(email))
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("email", email).
+			Msg("Failed to retrieve customer by email")
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	if customer == nil {
+		s.logger.Warn().
+			Str("email", email).
+			Msg("Customer not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return customer, nil
+}
+
+// GetByStripeID retrieves a customer by Stripe customer ID
+func (s *customerService) GetByStripeID(ctx context.Context, stripeID string) (*model.Customer, error) {
+	s.logger.Info().
+		Str("stripe_id", stripeID).
+		Msg("Retrieving customer by Stripe ID")
+
+	customer, err := s.repo.GetByStripeID(ctx, stripeID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("stripe_id", stripeID).
+			Msg("Failed to retrieve customer by Stripe ID")
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	if customer == nil {
+		s.logger.Warn().
+			Str("stripe_id", stripeID).
+			Msg("Customer not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return customer, nil
+}
+
+// List retrieves customers with pagination
+func (s *customerService) List(ctx context.Context, offset, limit int, includeInactive bool) ([]*model.Customer, int, error) {
+	s.logger.Debug().
+		Str("function", "customerService.List").
+		Int("offset", offset).
+		Int("limit", limit).
+		Bool("includeInactive", includeInactive).
+		Msg("Starting customer listing")
+
+	customers, total, err := s.repo.List(ctx, offset, limit, includeInactive)
+	if err != nil {
+		s.logger.Error().
+			Str("function", "customerService.List").
+			Err(err).
+			Int("offset", offset).
+			Int("limit", limit).
+			Msg("Failed to retrieve customers from repository")
+		return nil, 0, fmt.Errorf("failed to list customers: %w", err)
+	}
+
+	s.logger.Info().
+		Str("function", "customerService.List").
+		Int("total_customers", total).
+		Int("returned_customers", len(customers)).
+		Msg("Customer listing completed successfully")
+
+	return customers, total, nil
+}
+
+// Update updates an existing customer and keeps the Stripe customer in step
+func (s *customerService) Update(ctx context.Context, id uuid.UUID, dto *dto.CustomerUpdateDTO) (*model.Customer, error) {
+	s.logger.Info().
+		Str("customer_id", id.String()).
+		Msg("Updating customer")
+
+	existingCustomer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to retrieve customer for update")
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+
+	if existingCustomer == nil {
+		s.logger.Warn().
+			Str("customer_id", id.String()).
+			Msg("Customer not found for update")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	// Check if the email is being changed to one that belongs to someone else
+	if dto.Email != nil && !strings.EqualFold(*dto.Email, existingCustomer.Email) {
+		conflictingCustomer, err := s.repo.GetByEmail(ctx, *dto.Email)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Error checking for conflicting customer email")
+			return nil, fmt.Errorf("error checking for conflicting customer email: %w", err)
+		}
+
+		if conflictingCustomer != nil && conflictingCustomer.ID != id {
+			s.logger.Warn().
+				Str("customer_id", id.String()).
+				Str("conflicting_id", conflictingCustomer.ID.String()).
+				Msg("Customer email conflicts with existing customer")
+			return nil, postgres.ErrDuplicateEmail
+		}
+	}
+
+	contactChanged := dto.Email != nil || dto.FirstName != nil || dto.LastName != nil || dto.PhoneNumber != nil
+
+	dto.ApplyToModel(existingCustomer)
+
+	if contactChanged {
+		_, err = s.stripeService.UpdateCustomer(
+			existingCustomer.StripeID,
+			existingCustomer.Email,
+			strings.TrimSpace(existingCustomer.FirstName+" "+existingCustomer.LastName),
+			existingCustomer.PhoneNumber,
+		)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Str("customer_id", id.String()).
+				Str("stripe_id", existingCustomer.StripeID).
+				Msg("Failed to update Stripe customer")
+			return nil, fmt.Errorf("failed to update Stripe customer: %w", err)
+		}
+	}
+
+	err = s.repo.Update(ctx, existingCustomer)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to update customer in database")
+		return nil, fmt.Errorf("failed to update customer: %w", err)
+	}
+
+	payload := events.CustomerUpdatedPayload{
+		CustomerID:  existingCustomer.ID.String(),
+		StripeID:    existingCustomer.StripeID,
+		Email:       existingCustomer.Email,
+		FirstName:   existingCustomer.FirstName,
+		LastName:    existingCustomer.LastName,
+		PhoneNumber: existingCustomer.PhoneNumber,
+		Active:      existingCustomer.Active,
+		UpdatedAt:   existingCustomer.UpdatedAt,
+	}
+
+	err = s.eventBus.Publish(events.TopicCustomerUpdated, payload)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to publish customer updated event")
+		// Don't return error since the customer was updated successfully
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicCustomerUpdated).
+		Str("customer_id", existingCustomer.ID.String()).
+		Msg("Published customer updated event")
+
+	return existingCustomer, nil
+}
+
+// Deactivate soft deletes a customer by marking it inactive
+func (s *customerService) Deactivate(ctx context.Context, id uuid.UUID) error {
+	s.logger.Info().
+		Str("customer_id", id.String()).
+		Msg("Deactivating customer")
+
+	customer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Error retrieving customer for deactivation")
+		return fmt.Errorf("error retrieving customer: %w", err)
+	}
+
+	if customer == nil {
+		s.logger.Warn().
+			Str("customer_id", id.String()).
+			Msg("Customer not found for deactivation")
+		return postgres.ErrResourceNotFound
+	}
+
+	if !customer.Active {
+		s.logger.Info().
+			Str("customer_id", id.String()).
+			Msg("Customer is already inactive")
+		return nil // Already deactivated, nothing to do
+	}
+
+	err = s.repo.Deactivate(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to deactivate customer")
+		return fmt.Errorf("failed to deactivate customer: %w", err)
+	}
+
+	payload := events.CustomerDeletedPayload{
+		CustomerID: id.String(),
+		StripeID:   customer.StripeID,
+		Email:      customer.Email,
+		HardDelete: false,
+		DeletedAt:  time.Now(),
+	}
+
+	err = s.eventBus.Publish(events.TopicCustomerDeleted, payload)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to publish customer deactivated event")
+		// Don't return the error since the customer is already deactivated in DB
+	}
+
+	s.logger.Info().
+		Str("customer_id", id.String()).
+		Msg("Customer deactivated successfully")
+
+	return nil
+}
+
+// Delete hard deletes a customer. This fails once the customer has
+// addresses or subscriptions, in which case Deactivate should be used
+func (s *customerService) Delete(ctx context.Context, id uuid.UUID) error {
+	s.logger.Warn().
+		Str("customer_id", id.String()).
+		Msg("Attempting hard delete of customer")
+
+	customer, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Error retrieving customer for deletion")
+		return fmt.Errorf("error retrieving customer: %w", err)
+	}
+
+	if customer == nil {
+		s.logger.Warn().
+			Str("customer_id", id.String()).
+			Msg("Customer not found for deletion")
+		return postgres.ErrResourceNotFound
+	}
+
+	err = s.repo.Delete(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to delete customer from database")
+		return fmt.Errorf("failed to delete customer: %w", err)
+	}
+
+	payload := events.CustomerDeletedPayload{
+		CustomerID: id.String(),
+		StripeID:   customer.StripeID,
+		Email:      customer.Email,
+		HardDelete: true,
+		DeletedAt:  time.Now(),
+	}
+
+	err = s.eventBus.Publish(events.TopicCustomerDeleted, payload)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", id.String()).
+			Msg("Failed to publish customer deleted event")
+		// Don't return the error since the customer is already deleted from DB
+	}
+
+	s.logger.Info().
+		Str("customer_id", id.String()).
+		Msg("Customer deleted successfully")
+
+	return nil
+}
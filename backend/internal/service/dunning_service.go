@@ -0,0 +1,459 @@
+// internal/service/dunning_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/config"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/events"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// dunningBatchSize caps how many due cases one pass of the worker handles
+const dunningBatchSize = 100
+
+// dunningService implements DunningService
+type dunningService struct {
+	logger           zerolog.Logger
+	config           *config.DunningConfig
+	eventBus         events.EventBus
+	repo             interfaces.DunningRepository
+	subscriptionRepo interfaces.SubscriptionRepository
+	invoiceRepo      interfaces.InvoiceRepository
+	stripeService    interfaces.StripeService
+}
+
+// NewDunningService creates a new dunning service
+func NewDunningService(
+	logger *zerolog.Logger,
+	dunningConfig *config.DunningConfig,
+	eventBus events.EventBus,
+	repo interfaces.DunningRepository,
+	subscriptionRepo interfaces.SubscriptionRepository,
+	invoiceRepo interfaces.InvoiceRepository,
+	stripeService interfaces.StripeService,
+) interfaces.DunningService {
+	subLogger := logger.With().Str("component", "dunning_service").Logger()
+	return &dunningService{
+		logger:           subLogger,
+		config:           dunningConfig,
+		eventBus:         eventBus,
+		repo:             repo,
+		subscriptionRepo: subscriptionRepo,
+		invoiceRepo:      invoiceRepo,
+		stripeService:    stripeService,
+	}
+}
+
+// HandlePaymentFailed opens a dunning case for a subscription whose invoice
+// could not be charged, or records another failed attempt on the open case.
+// Opening a case moves the subscription to past_due and holds its next delivery:
+// the renewal order for that delivery is only created once the invoice is paid
+func (s *dunningService) HandlePaymentFailed(ctx context.Context, subscription *model.Subscription, invoice *model.Invoice) (*model.DunningCase, error) {
+	s.logger.Info().
+		Str("subscription_id", subscription.ID.String()).
+		Str("stripe_invoice_id", invoice.StripeID).
+		Int("attempt_count", invoice.AttemptCount).
+		Msg("Handling failed subscription payment")
+
+	dunningCase, err := s.repo.GetOpenBySubscriptionID(ctx, subscription.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve dunning case: %w", err)
+	}
+
+	now := time.Now()
+	failed := newDunningStep(model.DunningStepPaymentFailed, fmt.Sprintf("payment attempt %d failed", invoice.AttemptCount), now)
+
+	if dunningCase != nil {
+		dunningCase.InvoiceID = invoice.ID
+		dunningCase.StripeInvoiceID = invoice.StripeID
+		dunningCase.AttemptCount = invoice.AttemptCount
+
+		if err := s.repo.Update(ctx, dunningCase, failed); err != nil {
+			return nil, err
+		}
+
+		s.publishStep(dunningCase, failed, invoice)
+		return dunningCase, nil
+	}
+
+	dunningCase = &model.DunningCase{
+		ID:              uuid.New(),
+		SubscriptionID:  subscription.ID,
+		CustomerID:      subscription.CustomerID,
+		InvoiceID:       invoice.ID,
+		StripeInvoiceID: invoice.StripeID,
+		Status:          model.DunningStatusOpen,
+		AttemptCount:    invoice.AttemptCount,
+		StartedAt:       now,
+		CreatedAt:       now,
+		UpdatedAt:       now,
+	}
+	next := s.nextActionAt(dunningCase)
+	dunningCase.NextActionAt = &next
+
+	steps := []*model.DunningStep{
+		newDunningStep(model.DunningStepStarted, "", now),
+		failed,
+	}
+	if !subscription.NextDeliveryDate.IsZero() {
+		held := subscription.NextDeliveryDate
+		dunningCase.HeldDeliveryDate = &held
+		steps = append(steps, newDunningStep(model.DunningStepDeliveryHeld, "delivery of "+held.Format("2006-01-02")+" held until payment", now))
+	}
+
+	if err := s.repo.Create(ctx, dunningCase, steps...); err != nil {
+		return nil, err
+	}
+
+	if subscription.Status == model.SubscriptionStatusActive || subscription.Status == model.SubscriptionStatusTrialing {
+		subscription.Status = model.SubscriptionStatusPastDue
+		if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return nil, fmt.Errorf("failed to mark subscription past due: %w", err)
+		}
+		s.publishSubscriptionUpdated(subscription, "past_due")
+	}
+
+	for _, step := range steps {
+		s.publishStep(dunningCase, step, invoice)
+	}
+
+	s.logger.Info().
+		Str("dunning_case_id", dunningCase.ID.String()).
+		Str("subscription_id", subscription.ID.String()).
+		Time("next_action_at", next).
+		Msg("Dunning case opened")
+
+	return dunningCase, nil
+}
+
+// HandlePaymentSucceeded closes the open dunning case of a subscription once
+// the invoice it is chasing is paid, releasing the held delivery. Paying any
+// other invoice leaves the case, and the hold, in place
+func (s *dunningService) HandlePaymentSucceeded(ctx context.Context, subscription *model.Subscription, invoice *model.Invoice) error {
+	dunningCase, err := s.repo.GetOpenBySubscriptionID(ctx, subscription.ID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve dunning case: %w", err)
+	}
+	if dunningCase == nil {
+		return nil // Nothing to recover
+	}
+
+	if dunningCase.StripeInvoiceID != invoice.StripeID {
+		s.logger.Info().
+			Str("dunning_case_id", dunningCase.ID.String()).
+			Str("stripe_invoice_id", invoice.StripeID).
+			Str("unpaid_invoice_id", dunningCase.StripeInvoiceID).
+			Msg("Paid invoice does not settle the dunning case, delivery stays held")
+		return nil
+	}
+
+	return s.recover(ctx, dunningCase, subscription, invoice)
+}
+
+// ProcessDue sends the reminders and final actions that are due, returning how
+// many cases were handled. A case that fails is logged and retried on the next pass
+func (s *dunningService) ProcessDue(ctx context.Context, now time.Time) (int, error) {
+	cases, err := s.repo.ListDue(ctx, now, dunningBatchSize)
+	if err != nil {
+		return 0, fmt.Errorf("failed to list due dunning cases: %w", err)
+	}
+
+	processed := 0
+	for _, dunningCase := range cases {
+		if err := s.processCase(ctx, dunningCase, now); err != nil {
+			s.logger.Error().Err(err).
+				Str("dunning_case_id", dunningCase.ID.String()).
+				Str("subscription_id", dunningCase.SubscriptionID.String()).
+				Msg("Failed to process dunning case")
+			continue
+		}
+		processed++
+	}
+
+	return processed, nil
+}
+
+// Run processes due cases every CheckInterval until ctx is canceled
+func (s *dunningService) Run(ctx context.Context) {
+	s.logger.Info().
+		Dur("interval", s.config.CheckInterval).
+		Ints("reminder_days", s.config.ReminderDays).
+		Str("final_action", s.config.FinalAction).
+		Msg("Starting dunning worker")
+
+	ticker := time.NewTicker(s.config.CheckInterval)
+	defer ticker.Stop()
+
+	for {
+		processed, err := s.ProcessDue(ctx, time.Now())
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Dunning pass failed")
+		} else if processed > 0 {
+			s.logger.Info().Int("processed", processed).Msg("Dunning pass complete")
+		}
+
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("Dunning worker stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// processCase sends the next reminder of a case or, once reminders are used
+// up, applies the final action
+func (s *dunningService) processCase(ctx context.Context, dunningCase *model.DunningCase, now time.Time) error {
+	subscription, err := s.subscriptionRepo.GetByID(ctx, dunningCase.SubscriptionID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve subscription: %w", err)
+	}
+
+	invoice, err := s.invoiceRepo.GetByID(ctx, dunningCase.InvoiceID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve invoice: %w", err)
+	}
+
+	// The invoice was paid but we missed the webhook
+	if subscription != nil && invoice != nil && invoice.Status == model.InvoiceStatusPaid {
+		return s.recover(ctx, dunningCase, subscription, invoice)
+	}
+
+	// The subscription ended some other way, e.g. Stripe's own retries gave up
+	if subscription == nil || subscription.Status == model.SubscriptionStatusCanceled {
+		return s.close(ctx, dunningCase, invoice, model.DunningStatusCanceled, "subscription canceled outside dunning", now)
+	}
+
+	if dunningCase.RemindersSent < len(s.config.ReminderDays) {
+		dunningCase.RemindersSent++
+		next := s.nextActionAt(dunningCase)
+		dunningCase.NextActionAt = &next
+
+		step := newDunningStep(model.DunningStepReminder, fmt.Sprintf("reminder %d of %d", dunningCase.RemindersSent, len(s.config.ReminderDays)), now)
+		if err := s.repo.Update(ctx, dunningCase, step); err != nil {
+			return err
+		}
+
+		s.publishStep(dunningCase, step, invoice)
+		return nil
+	}
+
+	return s.applyFinalAction(ctx, dunningCase, subscription, invoice, now)
+}
+
+// applyFinalAction cancels or pauses the subscription as configured and closes the case
+func (s *dunningService) applyFinalAction(ctx context.Context, dunningCase *model.DunningCase, subscription *model.Subscription, invoice *model.Invoice, now time.Time) error {
+	s.logger.Info().
+		Str("dunning_case_id", dunningCase.ID.String()).
+		Str("subscription_id", subscription.ID.String()).
+		Str("final_action", s.config.FinalAction).
+		Msg("Dunning exhausted, applying final action")
+
+	if s.config.FinalAction == "pause" {
+		if _, err := s.stripeService.PauseSubscription(subscription.StripeID, time.Time{}); err != nil {
+			return fmt.Errorf("%w: failed to pause Stripe subscription: %v", ErrServiceUnavailable, err)
+		}
+
+		subscription.Status = model.SubscriptionStatusPaused
+		if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return fmt.Errorf("failed to update subscription: %w", err)
+		}
+
+		payload := events.SubscriptionPausedPayload{
+			SubscriptionID: subscription.ID.String(),
+			CustomerID:     subscription.CustomerID.String(),
+			StripeID:       subscription.StripeID,
+			Reason:         "payment_failed",
+			PausedAt:       now,
+		}
+		if err := s.eventBus.Publish(events.TopicSubscriptionPaused, payload); err != nil {
+			s.logger.Error().Err(err).
+				Str("subscription_id", subscription.ID.String()).
+				Msg("Failed to publish subscription paused event")
+		}
+
+		return s.close(ctx, dunningCase, invoice, model.DunningStatusPaused, "subscription paused after failed payments", now)
+	}
+
+	stripeSub, err := s.stripeService.CancelSubscription(subscription.StripeID, false)
+	if err != nil {
+		return fmt.Errorf("%w: failed to cancel Stripe subscription: %v", ErrServiceUnavailable, err)
+	}
+
+	ApplyStripeSubscription(subscription, stripeSub)
+	subscription.Status = model.SubscriptionStatusCanceled
+	if subscription.CanceledAt == nil {
+		subscription.CanceledAt = &now
+	}
+	subscription.Metadata["cancellation_reason"] = "payment_failed"
+	if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	payload := events.SubscriptionCanceledPayload{
+		SubscriptionID: subscription.ID.String(),
+		CustomerID:     subscription.CustomerID.String(),
+		StripeID:       subscription.StripeID,
+		Reason:         "payment_failed",
+		CanceledAt:     *subscription.CanceledAt,
+	}
+	if err := s.eventBus.Publish(events.TopicSubscriptionCanceled, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", subscription.ID.String()).
+			Msg("Failed to publish subscription canceled event")
+	}
+
+	return s.close(ctx, dunningCase, invoice, model.DunningStatusCanceled, "subscription canceled after failed payments", now)
+}
+
+// close ends a case without recovery
+func (s *dunningService) close(ctx context.Context, dunningCase *model.DunningCase, invoice *model.Invoice, status, detail string, now time.Time) error {
+	dunningCase.Status = status
+	dunningCase.ResolvedAt = &now
+	dunningCase.NextActionAt = nil
+
+	step := newDunningStep(model.DunningStepExhausted, detail, now)
+	if err := s.repo.Update(ctx, dunningCase, step); err != nil {
+		return err
+	}
+
+	s.publishStep(dunningCase, step, invoice)
+
+	s.logger.Info().
+		Str("dunning_case_id", dunningCase.ID.String()).
+		Str("status", status).
+		Msg("Dunning case closed")
+
+	return nil
+}
+
+// recover closes a case after payment and releases the held delivery
+func (s *dunningService) recover(ctx context.Context, dunningCase *model.DunningCase, subscription *model.Subscription, invoice *model.Invoice) error {
+	now := time.Now()
+	dunningCase.Status = model.DunningStatusRecovered
+	dunningCase.ResolvedAt = &now
+	dunningCase.NextActionAt = nil
+
+	steps := []*model.DunningStep{newDunningStep(model.DunningStepRecovered, "invoice "+invoice.StripeID+" paid", now)}
+	if dunningCase.HeldDeliveryDate != nil {
+		steps = append(steps, newDunningStep(model.DunningStepDeliveryReleased, "", now))
+	}
+
+	if err := s.repo.Update(ctx, dunningCase, steps...); err != nil {
+		return err
+	}
+
+	if subscription.Status == model.SubscriptionStatusPastDue || subscription.Status == model.SubscriptionStatusUnpaid {
+		subscription.Status = model.SubscriptionStatusActive
+		if err := s.subscriptionRepo.Update(ctx, subscription); err != nil {
+			return fmt.Errorf("failed to reactivate subscription: %w", err)
+		}
+		s.publishSubscriptionUpdated(subscription, "payment_recovered")
+	}
+
+	for _, step := range steps {
+		s.publishStep(dunningCase, step, invoice)
+	}
+
+	s.logger.Info().
+		Str("dunning_case_id", dunningCase.ID.String()).
+		Str("subscription_id", subscription.ID.String()).
+		Msg("Dunning case recovered")
+
+	return nil
+}
+
+// nextActionAt schedules the next reminder, or the final action once every
+// reminder has been sent. Days are counted from the first failure
+func (s *dunningService) nextActionAt(dunningCase *model.DunningCase) time.Time {
+	days := s.config.FinalAfterDays
+	if dunningCase.RemindersSent < len(s.config.ReminderDays) {
+		days = s.config.ReminderDays[dunningCase.RemindersSent]
+	}
+	return dunningCase.StartedAt.AddDate(0, 0, days)
+}
+
+// dunningStepTopics maps each recorded step to the topic it is published on
+var dunningStepTopics = map[string]string{
+	model.DunningStepStarted:          events.TopicDunningStarted,
+	model.DunningStepPaymentFailed:    events.TopicDunningPaymentFailed,
+	model.DunningStepDeliveryHeld:     events.TopicDunningDeliveryHeld,
+	model.DunningStepReminder:         events.TopicDunningReminder,
+	model.DunningStepRecovered:        events.TopicDunningRecovered,
+	model.DunningStepDeliveryReleased: events.TopicDunningDeliveryReleased,
+	model.DunningStepExhausted:        events.TopicDunningExhausted,
+}
+
+// publishStep publishes a recorded step. Failures are logged only because the
+// step is already saved
+func (s *dunningService) publishStep(dunningCase *model.DunningCase, step *model.DunningStep, invoice *model.Invoice) {
+	topic := dunningStepTopics[step.Step]
+
+	payload := events.DunningStepPayload{
+		DunningCaseID:    dunningCase.ID.String(),
+		SubscriptionID:   dunningCase.SubscriptionID.String(),
+		CustomerID:       dunningCase.CustomerID.String(),
+		StripeInvoiceID:  dunningCase.StripeInvoiceID,
+		Step:             step.Step,
+		Status:           dunningCase.Status,
+		AttemptCount:     dunningCase.AttemptCount,
+		RemindersSent:    dunningCase.RemindersSent,
+		HeldDeliveryDate: dunningCase.HeldDeliveryDate,
+		NextActionAt:     dunningCase.NextActionAt,
+		Detail:           step.Detail,
+		OccurredAt:       step.CreatedAt,
+	}
+	if invoice != nil {
+		payload.AmountDue = invoice.AmountDue
+		payload.Currency = invoice.Currency
+		payload.HostedInvoiceURL = invoice.HostedInvoiceURL
+	}
+
+	if err := s.eventBus.Publish(topic, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("dunning_case_id", dunningCase.ID.String()).
+			Str("step", step.Step).
+			Msg("Failed to publish dunning event")
+		return
+	}
+
+	s.logger.Info().
+		Str("topic", topic).
+		Str("dunning_case_id", dunningCase.ID.String()).
+		Msg("Published dunning event")
+}
+
+// publishSubscriptionUpdated announces a status change made by dunning
+func (s *dunningService) publishSubscriptionUpdated(subscription *model.Subscription, change string) {
+	payload := events.SubscriptionUpdatedPayload{
+		SubscriptionID:   subscription.ID.String(),
+		CustomerID:       subscription.CustomerID.String(),
+		StripeID:         subscription.StripeID,
+		Status:           subscription.Status,
+		NextDeliveryDate: subscription.NextDeliveryDate,
+		Change:           change,
+		UpdatedAt:        subscription.UpdatedAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicSubscriptionUpdated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", subscription.ID.String()).
+			Msg("Failed to publish subscription updated event")
+	}
+}
+
+// newDunningStep starts an audit trail entry; the repository sets its case
+func newDunningStep(step, detail string, at time.Time) *model.DunningStep {
+	return &model.DunningStep{
+		ID:        uuid.New(),
+		Step:      step,
+		Detail:    detail,
+		CreatedAt: at,
+	}
+}
@@ -16,6 +16,9 @@ var (
     // ErrServiceUnavailable is returned when a dependent service is unavailable
     ErrServiceUnavailable = errors.New("service unavailable")
     
+    // ErrInvalidState is returned when an operation is not allowed in the resource's current state
+    ErrInvalidState = errors.New("operation not allowed in current state")
+    
     // ErrOperationFailed is returned when an operation fails for a generic reason
     ErrOperationFailed = errors.New("operation failed")
 )
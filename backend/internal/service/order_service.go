@@ -0,0 +1,635 @@
+// internal/service/order_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/events"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Canceled and refunded are terminal
+var orderTransitions = map[string][]string{
+	model.OrderStatusPending:   {model.OrderStatusPaid, model.OrderStatusCanceled},
+	model.OrderStatusPaid:      {model.OrderStatusRoasting, model.OrderStatusCanceled, model.OrderStatusRefunded},
+	model.OrderStatusRoasting:  {model.OrderStatusPacked, model.OrderStatusRefunded},
+	model.OrderStatusPacked:    {model.OrderStatusShipped, model.OrderStatusRefunded},
+	model.OrderStatusShipped:   {model.OrderStatusDelivered, model.OrderStatusRefunded},
+	model.OrderStatusDelivered: {model.OrderStatusRefunded},
+}
+
+// CanTransitionOrder reports whether an order may move from one status to another
+func CanTransitionOrder(from, to string) bool {
+	for _, allowed := range orderTransitions[from] {
+		if allowed == to {
+			return true
+		}
+	}
+	return false
+}
+
+// orderService implements OrderService
+type orderService struct {
+	logger       zerolog.Logger
+	eventBus     events.EventBus
+	repo         interfaces.OrderRepository
+	customerRepo interfaces.CustomerRepository
+	addressRepo  interfaces.AddressRepository
+	variantRepo  interfaces.VariantRepository
+	priceRepo    interfaces.PriceRepository
+	productRepo  interfaces.ProductRepository
+
+	// Stock events go through the variant service so low-stock alerts fire
+	variantService interfaces.VariantService
+}
+
+// NewOrderService creates a new order service
+func NewOrderService(
+	logger *zerolog.Logger,
+	eventBus events.EventBus,
+	orderRepo interfaces.OrderRepository,
+	customerRepo interfaces.CustomerRepository,
+	addressRepo interfaces.AddressRepository,
+	variantRepo interfaces.VariantRepository,
+	priceRepo interfaces.PriceRepository,
+	productRepo interfaces.ProductRepository,
+	variantService interfaces.VariantService,
+) interfaces.OrderService {
+	subLogger := logger.With().Str("component", "order_service").Logger()
+	return &orderService{
+		logger:       subLogger,
+		eventBus:     eventBus,
+		repo:         orderRepo,
+		customerRepo: customerRepo,
+		addressRepo:  addressRepo,
+		variantRepo:  variantRepo,
+		priceRepo:    priceRepo,
+		productRepo:  productRepo,
+
+		variantService: variantService,
+	}
+}
+
+// Create places a new pending order, snapshotting each variant's price and options
+func (s *orderService) Create(ctx context.Context, d *dto.OrderCreateDTO) (*model.Order, error) {
+	s.logger.Info().
+		Str("customer_id", d.CustomerID.String()).
+		Int("item_count", len(d.Items)).
+		Msg("Creating order")
+
+	customer, err := s.customerRepo.GetByID(ctx, d.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+	if customer == nil || !customer.Active {
+		s.logger.Warn().Str("customer_id", d.CustomerID.String()).Msg("Customer not found or inactive")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	// Resolve the shipping address, falling back to the customer's default
+	var address *model.Address
+	if d.AddressID != nil {
+		address, err = s.addressRepo.GetByID(ctx, *d.AddressID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve address: %w", err)
+		}
+		if address == nil || address.CustomerID != customer.ID {
+			return nil, fmt.Errorf("%w: address does not belong to customer", ErrInvalidInput)
+		}
+	} else {
+		address, err = s.addressRepo.GetDefault(ctx, customer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve default address: %w", err)
+		}
+	}
+
+	now := time.Now()
+	order := &model.Order{
+		ID:             uuid.New(),
+		CustomerID:     customer.ID,
+		SubscriptionID: d.SubscriptionID,
+		Status:         model.OrderStatusPending,
+		ShippingAmount: d.ShippingAmount,
+		Items:          make([]*model.OrderItem, 0, len(d.Items)),
+		Metadata:       d.Metadata,
+		CreatedAt:      now,
+		UpdatedAt:      now,
+	}
+	if address != nil {
+		order.AddressID = &address.ID
+	}
+	if order.Metadata == nil {
+		order.Metadata = make(map[string]string)
+	}
+
+	for _, requested := range d.Items {
+		item, variant, currency, err := s.buildItem(ctx, order.ID, requested.VariantID, requested.Quantity, now)
+		if err != nil {
+			return nil, err
+		}
+		if !variant.Active {
+			return nil, fmt.Errorf("%w: variant %s is not available", ErrInvalidInput, variant.ID)
+		}
+
+		if order.Currency == "" {
+			order.Currency = currency
+		} else if !strings.EqualFold(order.Currency, currency) {
+			return nil, fmt.Errorf("%w: all items must be priced in the same currency", ErrInvalidInput)
+		}
+
+		order.Items = append(order.Items, item)
+		order.Subtotal += item.TotalAmount
+	}
+	order.Total = order.Subtotal + order.ShippingAmount + order.TaxAmount
+
+	// Stock held for open checkouts isn't for sale, so the order is only
+	// saved if its lines fit in what the reservations leave
+	movements := stockMovements(order, -1, "order_placed")
+	err = s.repo.Create(ctx, order, movements, true)
+	if err != nil {
+		if errors.Is(err, postgres.ErrInsufficientStock) {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidInput, err)
+		}
+		s.logger.Error().Err(err).
+			Str("order_id", order.ID.String()).
+			Msg("Failed to save order to database")
+		return nil, fmt.Errorf("failed to create order: %w", err)
+	}
+
+	s.publishStockMovements(ctx, movements)
+	s.publishCreated(order)
+
+	return order, nil
+}
+
+// CreateFromCheckout records the order for a completed Stripe Checkout
+// session. It is idempotent: a session that already has an order returns it
+func (s *orderService) CreateFromCheckout(ctx context.Context, d *dto.CheckoutOrderDTO) (*model.Order, error) {
+	s.logger.Info().
+		Str("session_id", d.StripeCheckoutSessionID).
+		Str("customer_id", d.CustomerID.String()).
+		Bool("paid", d.Paid).
+		Int("item_count", len(d.Items)).
+		Msg("Creating order from checkout session")
+
+	existing, err := s.repo.GetByCheckoutSessionID(ctx, d.StripeCheckoutSessionID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing order: %w", err)
+	}
+	if existing != nil {
+		s.logger.Info().
+			Str("session_id", d.StripeCheckoutSessionID).
+			Str("order_id", existing.ID.String()).
+			Msg("Order already exists for checkout session")
+		return existing, nil
+	}
+
+	now := time.Now()
+	order := &model.Order{
+		ID:                      uuid.New(),
+		CustomerID:              d.CustomerID,
+		Status:                  model.OrderStatusPending,
+		Currency:                strings.ToUpper(d.Currency),
+		ShippingAmount:          d.ShippingAmount,
+		TaxAmount:               d.TaxAmount,
+		Total:                   d.Total,
+		StripeCheckoutSessionID: d.StripeCheckoutSessionID,
+		StripePaymentIntentID:   d.StripePaymentIntentID,
+		Items:                   make([]*model.OrderItem, 0, len(d.Items)),
+		Metadata:                d.Metadata,
+		CreatedAt:               now,
+		UpdatedAt:               now,
+	}
+	if d.Paid {
+		order.Status = model.OrderStatusPaid
+		order.PaidAt = &now
+	}
+	if order.Metadata == nil {
+		order.Metadata = make(map[string]string)
+	}
+
+	if address, err := s.addressRepo.GetDefault(ctx, d.CustomerID); err == nil && address != nil {
+		order.AddressID = &address.ID
+	}
+
+	err = s.createFromStripe(ctx, order, d.Items, now, "checkout_completed")
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("order_id", order.ID.String()).
+			Str("session_id", d.StripeCheckoutSessionID).
+			Msg("Failed to save checkout order to database")
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// CreateRenewal creates the paid delivery order for a subscription invoice.
+// It is idempotent: an invoice that already has an order returns that order
+func (s *orderService) CreateRenewal(ctx context.Context, d *dto.RenewalOrderDTO) (*model.Order, error) {
+	s.logger.Info().
+		Str("stripe_invoice_id", d.StripeInvoiceID).
+		Str("subscription_id", d.SubscriptionID.String()).
+		Int("item_count", len(d.Items)).
+		Msg("Creating renewal order from invoice")
+
+	existing, err := s.repo.GetByInvoiceID(ctx, d.StripeInvoiceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to check for existing order: %w", err)
+	}
+	if existing != nil {
+		s.logger.Info().
+			Str("stripe_invoice_id", d.StripeInvoiceID).
+			Str("order_id", existing.ID.String()).
+			Msg("Order already exists for invoice")
+		return existing, nil
+	}
+
+	now := time.Now()
+	subscriptionID := d.SubscriptionID
+	order := &model.Order{
+		ID:                    uuid.New(),
+		CustomerID:            d.CustomerID,
+		SubscriptionID:        &subscriptionID,
+		AddressID:             d.AddressID,
+		Status:                model.OrderStatusPaid,
+		Currency:              strings.ToUpper(d.Currency),
+		TaxAmount:             d.TaxAmount,
+		Total:                 d.Total,
+		StripeInvoiceID:       d.StripeInvoiceID,
+		StripePaymentIntentID: d.StripePaymentIntentID,
+		Items:                 make([]*model.OrderItem, 0, len(d.Items)),
+		Metadata:              d.Metadata,
+		PaidAt:                &now,
+		CreatedAt:             now,
+		UpdatedAt:             now,
+	}
+	if order.Metadata == nil {
+		order.Metadata = make(map[string]string)
+	}
+
+	if order.AddressID == nil {
+		if address, err := s.addressRepo.GetDefault(ctx, d.CustomerID); err == nil && address != nil {
+			order.AddressID = &address.ID
+		}
+	}
+
+	err = s.createFromStripe(ctx, order, d.Items, now, "subscription_renewal")
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("order_id", order.ID.String()).
+			Str("stripe_invoice_id", d.StripeInvoiceID).
+			Msg("Failed to save renewal order to database")
+		return nil, err
+	}
+
+	return order, nil
+}
+
+// createFromStripe adds lines Stripe has already charged for, saves the order,
+// takes its stock and publishes it
+func (s *orderService) createFromStripe(ctx context.Context, order *model.Order, lines []dto.CheckoutOrderItemDTO, now time.Time, stockReason string) error {
+	// The customer has already paid, so lines are recorded even if the
+	// variant has since gone inactive or out of stock
+	for _, line := range lines {
+		item, _, _, err := s.buildItem(ctx, order.ID, line.VariantID, line.Quantity, now)
+		if err != nil {
+			return err
+		}
+		item.UnitAmount = line.UnitAmount
+		item.TotalAmount = line.UnitAmount * int64(line.Quantity)
+
+		order.Items = append(order.Items, item)
+		order.Subtotal += item.TotalAmount
+	}
+	if order.Total == 0 {
+		order.Total = order.Subtotal + order.ShippingAmount + order.TaxAmount
+	}
+
+	movements := stockMovements(order, -1, stockReason)
+	if err := s.repo.Create(ctx, order, movements, false); err != nil {
+		return fmt.Errorf("failed to create order: %w", err)
+	}
+
+	s.publishStockMovements(ctx, movements)
+	s.publishCreated(order)
+
+	return nil
+}
+
+// GetByCheckoutSessionID retrieves the order created from a Stripe Checkout session
+func (s *orderService) GetByCheckoutSessionID(ctx context.Context, sessionID string) (*model.Order, error) {
+	order, err := s.repo.GetByCheckoutSessionID(ctx, sessionID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("session_id", sessionID).
+			Msg("Failed to retrieve order by checkout session")
+		return nil, fmt.Errorf("failed to retrieve order: %w", err)
+	}
+
+	if order == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return order, nil
+}
+
+// stockMovements builds the movements that take each line's quantity out of
+// (direction -1) or put it back into (direction 1) variant stock
+func stockMovements(order *model.Order, direction int, reason string) []*model.InventoryMovement {
+	movementType := model.MovementTypeSale
+	if direction > 0 {
+		movementType = model.MovementTypeReturn
+	}
+
+	now := time.Now()
+	movements := make([]*model.InventoryMovement, 0, len(order.Items))
+	for _, item := range order.Items {
+		movements = append(movements, &model.InventoryMovement{
+			ID:        uuid.New(),
+			VariantID: item.VariantID,
+			Type:      movementType,
+			Quantity:  direction * item.Quantity,
+			Reason:    reason,
+			Reference: order.ID.String(),
+			CreatedAt: now,
+		})
+	}
+	return movements
+}
+
+// publishStockMovements publishes the stock events for movements saved with an order
+func (s *orderService) publishStockMovements(ctx context.Context, movements []*model.InventoryMovement) {
+	for _, movement := range movements {
+		s.variantService.PublishStockMovement(ctx, movement)
+	}
+}
+
+// buildItem resolves a variant into an order line with a snapshot of its
+// price and options
+func (s *orderService) buildItem(ctx context.Context, orderID, variantID uuid.UUID, quantity int, now time.Time) (*model.OrderItem, *model.Variant, string, error) {
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, nil, "", fmt.Errorf("%w: variant %s not found", ErrInvalidInput, variantID)
+	}
+
+	price, err := s.priceRepo.GetByID(ctx, variant.PriceID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to retrieve price: %w", err)
+	}
+	if price == nil {
+		return nil, nil, "", fmt.Errorf("%w: variant %s has no price", ErrInvalidInput, variantID)
+	}
+
+	productName := ""
+	product, err := s.productRepo.GetByID(ctx, variant.ProductID)
+	if err != nil {
+		return nil, nil, "", fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product != nil {
+		productName = product.Name
+	}
+
+	return &model.OrderItem{
+		ID:          uuid.New(),
+		OrderID:     orderID,
+		VariantID:   variant.ID,
+		ProductID:   variant.ProductID,
+		PriceID:     price.ID,
+		ProductName: productName,
+		Options:     variant.Options,
+		Quantity:    quantity,
+		UnitAmount:  price.Amount,
+		TotalAmount: price.Amount * int64(quantity),
+		CreatedAt:   now,
+	}, variant, price.Currency, nil
+}
+
+// publishCreated publishes an orders.created event for a newly saved order
+func (s *orderService) publishCreated(order *model.Order) {
+	payload := events.OrderCreatedPayload{
+		OrderID:    order.ID.String(),
+		CustomerID: order.CustomerID.String(),
+		Status:     order.Status,
+		Currency:   order.Currency,
+		Total:      order.Total,
+		Items:      make([]events.OrderItemPayload, 0, len(order.Items)),
+		CreatedAt:  order.CreatedAt,
+	}
+	if order.SubscriptionID != nil {
+		payload.SubscriptionID = order.SubscriptionID.String()
+	}
+	for _, item := range order.Items {
+		payload.Items = append(payload.Items, events.OrderItemPayload{
+			VariantID:   item.VariantID.String(),
+			ProductID:   item.ProductID.String(),
+			ProductName: item.ProductName,
+			Quantity:    item.Quantity,
+			UnitAmount:  item.UnitAmount,
+		})
+	}
+
+	if err := s.eventBus.Publish(events.TopicOrderCreated, payload); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to publish order created event")
+		// Don't return error since the order was already saved to DB
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicOrderCreated).
+		Str("order_id", order.ID.String()).
+		Int64("total", order.Total).
+		Int("item_count", len(order.Items)).
+		Msg("Published order created event")
+}
+
+// GetByID retrieves an order with its line items
+func (s *orderService) GetByID(ctx context.Context, id uuid.UUID) (*model.Order, error) {
+	order, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("order_id", id.String()).
+			Msg("Failed to retrieve order")
+		return nil, fmt.Errorf("failed to retrieve order: %w", err)
+	}
+
+	if order == nil {
+		s.logger.Warn().
+			Str("order_id", id.String()).
+			Msg("Order not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return order, nil
+}
+
+// List retrieves orders with pagination
+func (s *orderService) List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Order, int, error) {
+	s.logger.Debug().
+		Str("function", "orderService.List").
+		Int("offset", offset).
+		Int("limit", limit).
+		Str("status", status).
+		Msg("Starting order listing")
+
+	orders, total, err := s.repo.List(ctx, offset, limit, customerID, status)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("function", "orderService.List").
+			Msg("Failed to retrieve orders from repository")
+		return nil, 0, fmt.Errorf("failed to list orders: %w", err)
+	}
+
+	return orders, total, nil
+}
+
+// UpdateStatus moves an order through its lifecycle, rejecting transitions
+// the state machine does not allow
+func (s *orderService) UpdateStatus(ctx context.Context, id uuid.UUID, update *dto.OrderStatusUpdateDTO) (*model.Order, error) {
+	s.logger.Info().
+		Str("order_id", id.String()).
+		Str("status", update.Status).
+		Msg("Updating order status")
+
+	order, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	fromStatus := order.Status
+	if !CanTransitionOrder(fromStatus, update.Status) {
+		s.logger.Warn().
+			Str("order_id", id.String()).
+			Str("from_status", fromStatus).
+			Str("to_status", update.Status).
+			Msg("Order status transition not allowed")
+		return nil, fmt.Errorf("%w: cannot move order from %s to %s", ErrInvalidState, fromStatus, update.Status)
+	}
+
+	now := time.Now()
+	order.Status = update.Status
+	switch update.Status {
+	case model.OrderStatusPaid:
+		order.PaidAt = &now
+	case model.OrderStatusShipped:
+		order.ShippedAt = &now
+		order.Carrier = update.Carrier
+		order.TrackingNumber = update.TrackingNumber
+	case model.OrderStatusDelivered:
+		order.DeliveredAt = &now
+	case model.OrderStatusCanceled:
+		order.CanceledAt = &now
+	}
+
+	// Beans that were never roasted go back on the shelf
+	var movements []*model.InventoryMovement
+	if (update.Status == model.OrderStatusCanceled || update.Status == model.OrderStatusRefunded) &&
+		(fromStatus == model.OrderStatusPending || fromStatus == model.OrderStatusPaid) {
+		movements = stockMovements(order, 1, "order_"+update.Status)
+	}
+
+	err = s.repo.UpdateStatus(ctx, order, fromStatus, update.Note, movements)
+	if err != nil {
+		if errors.Is(err, postgres.ErrConcurrentUpdate) {
+			return nil, fmt.Errorf("%w: order status changed while updating", ErrInvalidState)
+		}
+		return nil, fmt.Errorf("failed to update order status: %w", err)
+	}
+
+	s.publishStockMovements(ctx, movements)
+
+	s.publishTransition(order, fromStatus, update.Note)
+
+	return order, nil
+}
+
+// Cancel cancels an order that has not started roasting
+func (s *orderService) Cancel(ctx context.Context, id uuid.UUID, reason string) (*model.Order, error) {
+	return s.UpdateStatus(ctx, id, &dto.OrderStatusUpdateDTO{
+		Status: model.OrderStatusCanceled,
+		Note:   reason,
+	})
+}
+
+// publishTransition publishes orders.status_updated for every transition,
+// plus orders.shipped or orders.delivered where they apply
+func (s *orderService) publishTransition(order *model.Order, fromStatus, note string) {
+	payload := events.OrderStatusUpdatedPayload{
+		OrderID:    order.ID.String(),
+		CustomerID: order.CustomerID.String(),
+		FromStatus: fromStatus,
+		ToStatus:   order.Status,
+		Note:       note,
+		UpdatedAt:  order.UpdatedAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicOrderStatusUpdated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("order_id", order.ID.String()).
+			Msg("Failed to publish order status updated event")
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicOrderStatusUpdated).
+		Str("order_id", order.ID.String()).
+		Str("from_status", fromStatus).
+		Str("to_status", order.Status).
+		Msg("Published order status updated event")
+
+	switch order.Status {
+	case model.OrderStatusShipped:
+		shipped := events.OrderShippedPayload{
+			OrderID:        order.ID.String(),
+			CustomerID:     order.CustomerID.String(),
+			Carrier:        order.Carrier,
+			TrackingNumber: order.TrackingNumber,
+			ShippedAt:      *order.ShippedAt,
+		}
+		if err := s.eventBus.Publish(events.TopicOrderShipped, shipped); err != nil {
+			s.logger.Error().Err(err).
+				Str("order_id", order.ID.String()).
+				Msg("Failed to publish order shipped event")
+		}
+
+	case model.OrderStatusDelivered:
+		delivered := events.OrderDeliveredPayload{
+			OrderID:     order.ID.String(),
+			CustomerID:  order.CustomerID.String(),
+			DeliveredAt: *order.DeliveredAt,
+		}
+		if err := s.eventBus.Publish(events.TopicOrderDelivered, delivered); err != nil {
+			s.logger.Error().Err(err).
+				Str("order_id", order.ID.String()).
+				Msg("Failed to publish order delivered event")
+		}
+	}
+}
+
+// GetStatusHistory retrieves an order's status transitions
+func (s *orderService) GetStatusHistory(ctx context.Context, id uuid.UUID) ([]*model.OrderStatusChange, error) {
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetStatusHistory(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("order_id", id.String()).
+			Msg("Failed to retrieve order status history")
+		return nil, fmt.Errorf("failed to retrieve order status history: %w", err)
+	}
+
+	return history, nil
+}
@@ -0,0 +1,55 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+)
+
+func TestCanTransitionOrder(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{model.OrderStatusPending, model.OrderStatusPaid, true},
+		{model.OrderStatusPending, model.OrderStatusCanceled, true},
+		{model.OrderStatusPending, model.OrderStatusShipped, false},
+		{model.OrderStatusPending, model.OrderStatusRefunded, false},
+		{model.OrderStatusPaid, model.OrderStatusRoasting, true},
+		{model.OrderStatusPaid, model.OrderStatusCanceled, true},
+		{model.OrderStatusPaid, model.OrderStatusRefunded, true},
+		{model.OrderStatusPaid, model.OrderStatusPending, false},
+		{model.OrderStatusRoasting, model.OrderStatusPacked, true},
+		{model.OrderStatusRoasting, model.OrderStatusCanceled, false},
+		{model.OrderStatusPacked, model.OrderStatusShipped, true},
+		{model.OrderStatusPacked, model.OrderStatusDelivered, false},
+		{model.OrderStatusShipped, model.OrderStatusDelivered, true},
+		{model.OrderStatusShipped, model.OrderStatusRefunded, true},
+		{model.OrderStatusDelivered, model.OrderStatusRefunded, true},
+		{model.OrderStatusDelivered, model.OrderStatusShipped, false},
+
+		// Terminal statuses
+		{model.OrderStatusCanceled, model.OrderStatusPending, false},
+		{model.OrderStatusCanceled, model.OrderStatusPaid, false},
+		{model.OrderStatusRefunded, model.OrderStatusPaid, false},
+		{model.OrderStatusRefunded, model.OrderStatusRefunded, false},
+
+		// Unknown statuses
+		{"lost", model.OrderStatusPaid, false},
+		{model.OrderStatusPending, "lost", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionOrder(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionOrder(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestCanTransitionOrderNoSelfTransitions(t *testing.T) {
+	for status := range orderTransitions {
+		if CanTransitionOrder(status, status) {
+			t.Errorf("CanTransitionOrder(%q, %q) = true, want false", status, status)
+		}
+	}
+}
@@ -0,0 +1,156 @@
+// internal/service/outbox_relay.go
+package service
+
+import (
+	"context"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/config"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/events"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/metrics"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+)
+
+// outboxPruneInterval is how often published events past their retention are deleted
+const outboxPruneInterval = time.Hour
+
+// outboxRelay implements OutboxRelay
+type outboxRelay struct {
+	logger   zerolog.Logger
+	config   *config.OutboxConfig
+	eventBus events.EventBus
+	repo     interfaces.OutboxRepository
+	metrics  *metrics.EventMetrics
+}
+
+// NewOutboxRelay creates a new outbox relay
+func NewOutboxRelay(
+	logger *zerolog.Logger,
+	outboxConfig *config.OutboxConfig,
+	eventBus events.EventBus,
+	repo interfaces.OutboxRepository,
+	eventMetrics *metrics.EventMetrics,
+) interfaces.OutboxRelay {
+	subLogger := logger.With().Str("component", "outbox_relay").Logger()
+	return &outboxRelay{
+		logger:   subLogger,
+		config:   outboxConfig,
+		eventBus: eventBus,
+		repo:     repo,
+		metrics:  eventMetrics,
+	}
+}
+
+// newOutboxEvent wraps a payload for writing to the outbox alongside the change it
+// describes. Payloads are validated now, since one the bus rejects would hold up
+// every event queued behind it
+func newOutboxEvent(topic string, payload interface{}) (*model.OutboxEvent, error) {
+	event, err := events.NewEvent(topic, payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := event.Validate(); err != nil {
+		return nil, err
+	}
+
+	return &model.OutboxEvent{
+		ID:        uuid.New(),
+		Topic:     topic,
+		Payload:   event.Payload,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// RelayPending publishes one batch of unsent events. An event is marked sent only
+// after the bus accepts it, so a crash in between publishes it again: delivery is
+// at least once and consumers dedupe on the event ID
+func (r *outboxRelay) RelayPending(ctx context.Context) (int, error) {
+	sent, err := r.repo.ProcessPending(ctx, r.config.BatchSize, func(event *model.OutboxEvent) error {
+		return r.eventBus.PublishEvent(events.Event{
+			Metadata: events.Metadata{
+				ID:        event.ID.String(),
+				Topic:     event.Topic,
+				Timestamp: event.CreatedAt,
+			},
+			Payload: event.Payload,
+		})
+	})
+
+	r.recordLag(ctx)
+
+	return sent, err
+}
+
+// recordLag exports the backlog size and the age of its oldest event
+func (r *outboxRelay) recordLag(ctx context.Context) {
+	if r.metrics == nil {
+		return
+	}
+
+	count, oldest, err := r.repo.PendingStats(ctx)
+	if err != nil {
+		r.logger.Error().Err(err).Msg("Failed to read outbox stats")
+		return
+	}
+
+	r.metrics.OutboxPending.Set(float64(count))
+	if oldest == nil {
+		r.metrics.OutboxLag.Set(0)
+		return
+	}
+	r.metrics.OutboxLag.Set(time.Since(*oldest).Seconds())
+}
+
+// Run relays outbox events until the context is cancelled. A full batch is
+// followed straight away by the next one so a backlog drains quickly
+func (r *outboxRelay) Run(ctx context.Context) {
+	r.logger.Info().
+		Dur("interval", r.config.RelayInterval).
+		Int("batch_size", r.config.BatchSize).
+		Dur("retention", r.config.Retention).
+		Msg("Starting outbox relay")
+
+	ticker := time.NewTicker(r.config.RelayInterval)
+	defer ticker.Stop()
+
+	lastPrune := time.Time{}
+
+	for {
+		sent, err := r.RelayPending(ctx)
+		if err != nil {
+			r.logger.Error().Err(err).Msg("Outbox relay pass failed")
+		} else if sent > 0 {
+			r.logger.Debug().Int("sent", sent).Msg("Relayed outbox events")
+		}
+
+		if time.Since(lastPrune) >= outboxPruneInterval {
+			lastPrune = time.Now()
+			pruned, err := r.repo.DeleteSentBefore(ctx, lastPrune.Add(-r.config.Retention))
+			if err != nil {
+				r.logger.Error().Err(err).Msg("Failed to prune outbox")
+			} else if pruned > 0 {
+				r.logger.Info().Int64("pruned", pruned).Msg("Pruned published outbox events")
+			}
+		}
+
+		if err == nil && sent == r.config.BatchSize {
+			select {
+			case <-ctx.Done():
+				r.logger.Info().Msg("Outbox relay stopped")
+				return
+			default:
+				continue
+			}
+		}
+
+		select {
+		case <-ctx.Done():
+			r.logger.Info().Msg("Outbox relay stopped")
+			return
+		case <-ticker.C:
+		}
+	}
+}
@@ -3,6 +3,7 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 	"time"
@@ -24,6 +25,7 @@ type priceService struct {
 	priceRepo     interfaces.PriceRepository
 	productRepo   interfaces.ProductRepository
 	variantRepo   interfaces.VariantRepository
+	ruleRepo      interfaces.PricingRuleRepository
 	stripeService interfaces.StripeService
 }
 
@@ -34,6 +36,7 @@ func NewPriceService(
 	priceRepo interfaces.PriceRepository,
 	productRepo interfaces.ProductRepository,
 	variantRepo interfaces.VariantRepository,
+	ruleRepo interfaces.PricingRuleRepository,
 	stripeService interfaces.StripeService,
 ) interfaces.PriceService {
 	subLogger := logger.With().Str("component", "price_service").Logger()
@@ -43,6 +46,7 @@ func NewPriceService(
 		priceRepo:     priceRepo,
 		productRepo:   productRepo,
 		variantRepo:   variantRepo,
+		ruleRepo:      ruleRepo,
 		stripeService: stripeService,
 	}
 }
@@ -95,6 +99,7 @@ func (s *priceService) Create(ctx context.Context, createDTO *dto.PriceCreateDTO
 			recurring,
 			price.Interval,
 			int64(price.IntervalCount),
+			"",
 		)
 		if err != nil {
 			s.logger.Error().Err(err).
@@ -123,21 +128,21 @@ func (s *priceService) Create(ctx context.Context, createDTO *dto.PriceCreateDTO
 	}
 
 	// Publish price created event
-	payload := map[string]interface{}{
-		"price_id":       price.ID.String(),
-		"product_id":     price.ProductID.String(),
-		"name":           price.Name,
-		"amount":         price.Amount,
-		"currency":       price.Currency,
-		"type":           price.Type,
-		"interval":       price.Interval,
-		"interval_count": price.IntervalCount,
-		"active":         price.Active,
-		"stripe_id":      price.StripeID,
-		"created_at":     price.CreatedAt,
-	}
-
-	err = s.eventBus.Publish("prices.created", payload)
+	payload := events.PriceCreatedPayload{
+		PriceID:       price.ID.String(),
+		ProductID:     price.ProductID.String(),
+		Name:          price.Name,
+		Amount:        price.Amount,
+		Currency:      price.Currency,
+		Type:          price.Type,
+		Interval:      price.Interval,
+		IntervalCount: price.IntervalCount,
+		Active:        price.Active,
+		StripeID:      price.StripeID,
+		CreatedAt:     price.CreatedAt,
+	}
+
+	err = s.eventBus.Publish(events.TopicPriceCreated, payload)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("price_id", price.ID.String()).
@@ -254,23 +259,23 @@ func (s *priceService) Update(ctx context.Context, id uuid.UUID, updateDTO *dto.
 	}
 
 	// Publish price updated event
-	payload := map[string]interface{}{
-		"price_id":        price.ID.String(),
-		"product_id":      price.ProductID.String(),
-		"name":            price.Name,
-		"amount":          price.Amount,
-		"original_amount": originalAmount,
-		"currency":        price.Currency,
-		"type":            price.Type,
-		"interval":        price.Interval,
-		"interval_count":  price.IntervalCount,
-		"active":          price.Active,
-		"original_active": originalActive,
-		"stripe_id":       price.StripeID,
-		"updated_at":      price.UpdatedAt,
-	}
-
-	err = s.eventBus.Publish("prices.updated", payload)
+	payload := events.PriceUpdatedPayload{
+		PriceID:        price.ID.String(),
+		ProductID:      price.ProductID.String(),
+		Name:           price.Name,
+		Amount:         price.Amount,
+		OriginalAmount: originalAmount,
+		Currency:       price.Currency,
+		Type:           price.Type,
+		Interval:       price.Interval,
+		IntervalCount:  price.IntervalCount,
+		Active:         price.Active,
+		OriginalActive: originalActive,
+		StripeID:       price.StripeID,
+		UpdatedAt:      price.UpdatedAt,
+	}
+
+	err = s.eventBus.Publish(events.TopicPriceUpdated, payload)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("price_id", price.ID.String()).
@@ -343,15 +348,15 @@ func (s *priceService) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	// Publish price deleted event
-	payload := map[string]interface{}{
-		"price_id":   id.String(),
-		"product_id": price.ProductID.String(),
-		"name":       price.Name,
-		"stripe_id":  price.StripeID,
-		"deleted_at": time.Now(),
+	payload := events.PriceDeletedPayload{
+		PriceID:   id.String(),
+		ProductID: price.ProductID.String(),
+		Name:      price.Name,
+		StripeID:  price.StripeID,
+		DeletedAt: time.Now(),
 	}
 
-	err = s.eventBus.Publish("prices.deleted", payload)
+	err = s.eventBus.Publish(events.TopicPriceDeleted, payload)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("price_id", id.String()).
@@ -434,16 +439,16 @@ func (s *priceService) AssignToVariant(ctx context.Context, assignmentDTO *dto.V
 	}
 
 	// Publish variant price assigned event
-	payload := map[string]interface{}{
-		"variant_id":     assignmentDTO.VariantID.String(),
-		"product_id":     variant.ProductID.String(),
-		"new_price_id":   assignmentDTO.PriceID.String(),
-		"old_price_id":   oldPriceID.String(),
-		"stripe_price_id": price.StripeID,
-		"assigned_at":    time.Now(),
+	payload := events.VariantPriceAssignedPayload{
+		VariantID:     assignmentDTO.VariantID.String(),
+		ProductID:     variant.ProductID.String(),
+		NewPriceID:    assignmentDTO.PriceID.String(),
+		OldPriceID:    oldPriceID.String(),
+		StripePriceID: price.StripeID,
+		AssignedAt:    time.Now(),
 	}
 
-	err = s.eventBus.Publish("variants.price_assigned", payload)
+	err = s.eventBus.Publish(events.TopicVariantPriceAssigned, payload)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("variant_id", assignmentDTO.VariantID.String()).
@@ -796,4 +801,236 @@ func (s *priceService) calculateNameMatchScore(name1, name2 string) int {
 	}
 	
 	return (matches * 100) / maxWords
-}
\ No newline at end of file
+}
+// Fallback price for variants of products without a pricing rule
+const (
+	defaultVariantPrice    = int64(1000) // $10.00
+	defaultVariantCurrency = "USD"
+)
+
+// variantPrice computes a variant's price from its product's pricing rule: the
+// price for its weight (or the base price) plus a surcharge per other option.
+// Without a rule every variant gets the fallback price
+func variantPrice(rule *model.PricingRule, options map[string]string) (int64, string) {
+	if rule == nil {
+		return defaultVariantPrice, defaultVariantCurrency
+	}
+
+	amount := rule.BasePrice
+	if weightPrice, ok := rule.WeightPrices[options["weight"]]; ok {
+		amount = weightPrice
+	}
+
+	for key, value := range options {
+		amount += rule.Surcharges[key][value]
+	}
+
+	return amount, rule.Currency
+}
+
+// GetPricingRule retrieves a product's pricing rule
+func (s *priceService) GetPricingRule(ctx context.Context, productID uuid.UUID) (*model.PricingRule, error) {
+	rule, err := s.ruleRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pricing rule: %w", err)
+	}
+	if rule == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return rule, nil
+}
+
+// SetPricingRule creates or replaces a product's pricing rule. Existing
+// variants keep their prices until the rule is applied
+func (s *priceService) SetPricingRule(ctx context.Context, productID uuid.UUID, ruleDTO *dto.PricingRuleDTO) (*model.PricingRule, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	rule := ruleDTO.ToModel(productID)
+	if err := s.ruleRepo.Upsert(ctx, rule); err != nil {
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("product_id", productID.String()).
+		Int64("base_price", rule.BasePrice).
+		Str("currency", rule.Currency).
+		Msg("Saved pricing rule")
+
+	return rule, nil
+}
+
+// ApplyPricingRule reprices every variant of a product whose price no longer
+// matches the rule. Each gets a new Stripe and local price; the old price is
+// retired once no variant uses it. Failures are reported per variant so one
+// bad Stripe call doesn't block the rest
+func (s *priceService) ApplyPricingRule(ctx context.Context, productID uuid.UUID) (*dto.PricingRuleApplyResult, error) {
+	rule, err := s.GetPricingRule(ctx, productID)
+	if err != nil {
+		if errors.Is(err, postgres.ErrResourceNotFound) {
+			return nil, fmt.Errorf("%w: product has no pricing rule", ErrInvalidInput)
+		}
+		return nil, err
+	}
+
+	variants, err := s.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve variants: %w", err)
+	}
+
+	priceUsers := make(map[uuid.UUID]int)
+	for _, variant := range variants {
+		priceUsers[variant.PriceID]++
+	}
+
+	result := &dto.PricingRuleApplyResult{
+		ProductID: productID,
+		Repriced:  make([]*model.Variant, 0),
+	}
+
+	for _, variant := range variants {
+		amount, currency := variantPrice(rule, variant.Options)
+
+		oldPrice, err := s.priceRepo.GetByID(ctx, variant.PriceID)
+		if err != nil {
+			result.Errors = append(result.Errors, fmt.Sprintf("variant %s: %v", variant.ID, err))
+			continue
+		}
+		if oldPrice != nil && oldPrice.Amount == amount && strings.EqualFold(oldPrice.Currency, currency) {
+			result.Unchanged++
+			continue
+		}
+
+		newPrice, err := s.repriceVariant(ctx, variant, oldPrice, amount, currency)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Str("variant_id", variant.ID.String()).
+				Msg("Failed to reprice variant")
+			result.Errors = append(result.Errors, fmt.Sprintf("variant %s: %v", variant.ID, err))
+			continue
+		}
+		result.Repriced = append(result.Repriced, variant)
+
+		priceUsers[newPrice.ID]++
+		if oldPrice == nil {
+			continue
+		}
+		priceUsers[oldPrice.ID]--
+		if priceUsers[oldPrice.ID] == 0 {
+			s.retirePrice(ctx, oldPrice)
+		}
+	}
+
+	s.logger.Info().
+		Str("product_id", productID.String()).
+		Int("repriced", len(result.Repriced)).
+		Int("unchanged", result.Unchanged).
+		Int("errors", len(result.Errors)).
+		Msg("Applied pricing rule")
+
+	return result, nil
+}
+
+// repriceVariant creates a Stripe and local price for a variant and points the
+// variant at it. Recurring prices keep their billing interval
+func (s *priceService) repriceVariant(ctx context.Context, variant *model.Variant, oldPrice *model.Price, amount int64, currency string) (*model.Price, error) {
+	if variant.StripeProductID == "" {
+		return nil, fmt.Errorf("variant has no Stripe product")
+	}
+
+	now := time.Now()
+	newPrice := &model.Price{
+		ID:        uuid.New(),
+		ProductID: variant.ProductID,
+		Amount:    amount,
+		Currency:  currency,
+		Type:      "one_time",
+		Active:    true,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if oldPrice != nil {
+		newPrice.Name = oldPrice.Name
+		newPrice.Type = oldPrice.Type
+		newPrice.Interval = oldPrice.Interval
+		newPrice.IntervalCount = oldPrice.IntervalCount
+	}
+	if newPrice.Name == "" {
+		newPrice.Name = fmt.Sprintf("Variant %s - %s", variant.ID, currency)
+	}
+
+	stripePrice, err := s.stripeService.CreatePrice(
+		variant.StripeProductID,
+		amount,
+		currency,
+		newPrice.Type == "recurring",
+		newPrice.Interval,
+		int64(newPrice.IntervalCount),
+		"",
+	)
+	if err != nil {
+		return nil, err
+	}
+	newPrice.StripeID = stripePrice.ID
+
+	if err := s.priceRepo.Create(ctx, newPrice); err != nil {
+		return nil, fmt.Errorf("failed to create price: %w", err)
+	}
+
+	variant.PriceID = newPrice.ID
+	variant.StripePriceID = newPrice.StripeID
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	payload := events.VariantUpdatedPayload{
+		VariantID:       variant.ID.String(),
+		ProductID:       variant.ProductID.String(),
+		PriceID:         newPrice.ID.String(),
+		StripeProductID: variant.StripeProductID,
+		StripePriceID:   newPrice.StripeID,
+		Amount:          newPrice.Amount,
+		Currency:        newPrice.Currency,
+		PriceType:       newPrice.Type,
+		Interval:        newPrice.Interval,
+		IntervalCount:   newPrice.IntervalCount,
+		Active:          variant.Active,
+		OptionValues:    variant.Options,
+		UpdatedAt:       variant.UpdatedAt,
+		UpdateSource:    model.SyncSourceAPICall,
+	}
+	if err := s.eventBus.Publish(events.TopicVariantUpdated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", variant.ID.String()).
+			Msg("Failed to publish variant updated event")
+	}
+
+	return newPrice, nil
+}
+
+// retirePrice deactivates a price locally and in Stripe. Failures are logged
+// because the variants have already moved to their new prices
+func (s *priceService) retirePrice(ctx context.Context, price *model.Price) {
+	price.Active = false
+	if err := s.priceRepo.Update(ctx, price); err != nil {
+		s.logger.Error().Err(err).
+			Str("price_id", price.ID.String()).
+			Msg("Failed to deactivate retired price")
+	}
+
+	if price.StripeID == "" {
+		return
+	}
+	if _, err := s.stripeService.SetPriceActive(price.StripeID, false); err != nil {
+		s.logger.Error().Err(err).
+			Str("price_id", price.ID.String()).
+			Str("stripe_price_id", price.StripeID).
+			Msg("Failed to deactivate retired Stripe price")
+	}
+}
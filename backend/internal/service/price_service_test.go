@@ -0,0 +1,82 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+)
+
+func TestVariantPrice(t *testing.T) {
+	rule := &model.PricingRule{
+		Currency:  "usd",
+		BasePrice: 1500,
+		WeightPrices: map[string]int64{
+			"12oz": 1800,
+			"5lb":  6500,
+		},
+		Surcharges: map[string]map[string]int64{
+			"grind": {"Drip Ground": 100, "Espresso": 150},
+			"roast": {"Dark": 50},
+		},
+	}
+
+	tests := []struct {
+		name         string
+		rule         *model.PricingRule
+		options      map[string]string
+		wantAmount   int64
+		wantCurrency string
+	}{
+		{
+			name:         "no rule uses the fallback price",
+			rule:         nil,
+			options:      map[string]string{"weight": "12oz", "grind": "Espresso"},
+			wantAmount:   defaultVariantPrice,
+			wantCurrency: defaultVariantCurrency,
+		},
+		{
+			name:         "weight price without surcharges",
+			rule:         rule,
+			options:      map[string]string{"weight": "12oz", "grind": "Whole Bean"},
+			wantAmount:   1800,
+			wantCurrency: "usd",
+		},
+		{
+			name:         "unpriced weight falls back to the base price",
+			rule:         rule,
+			options:      map[string]string{"weight": "2lb"},
+			wantAmount:   1500,
+			wantCurrency: "usd",
+		},
+		{
+			name:         "surcharge is added to the weight price",
+			rule:         rule,
+			options:      map[string]string{"weight": "5lb", "grind": "Drip Ground"},
+			wantAmount:   6600,
+			wantCurrency: "usd",
+		},
+		{
+			name:         "surcharges from several options add up",
+			rule:         rule,
+			options:      map[string]string{"weight": "12oz", "grind": "Espresso", "roast": "Dark"},
+			wantAmount:   2000,
+			wantCurrency: "usd",
+		},
+		{
+			name:         "no options uses the base price",
+			rule:         rule,
+			options:      nil,
+			wantAmount:   1500,
+			wantCurrency: "usd",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			amount, currency := variantPrice(tt.rule, tt.options)
+			if amount != tt.wantAmount || currency != tt.wantCurrency {
+				t.Errorf("variantPrice() = %d %s, want %d %s", amount, currency, tt.wantAmount, tt.wantCurrency)
+			}
+		})
+	}
+}
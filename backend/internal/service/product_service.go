@@ -58,13 +58,6 @@ func (s *productService) Create(ctx context.Context, p *dto.ProductCreateDTO) (*
 		return product, fmt.Errorf("a product with the name '%s' already exists", product.Name)
 	}
 
-	// Save product to database using repository
-	err = s.repo.Create(ctx, product)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to save product to database")
-		return product, fmt.Errorf("failed to create product: %w", err)
-	}
-
 	// Create event payload with important product details
 	payload := events.ProductCreatedPayload{
 		ProductID:         product.ID.String(),
@@ -82,18 +75,23 @@ func (s *productService) Create(ctx context.Context, p *dto.ProductCreateDTO) (*
 		CreatedAt:         product.CreatedAt,
 	}
 
-	// Publish product created event with detailed payload
-	err = s.eventBus.Publish(events.TopicProductCreated, payload)
+	event, err := newOutboxEvent(events.TopicProductCreated, payload)
 	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to publish product created event")
-		// Note: We don't return error here since the product was already saved to DB
-		// Consider adding a mechanism to retry publishing the event
+		return product, err
+	}
+
+	// Save the product and its event together; the outbox relay publishes the event
+	err = s.repo.Create(ctx, product, event)
+	if err != nil {
+		s.logger.Error().Err(err).Msg("Failed to save product to database")
+		return product, fmt.Errorf("failed to create product: %w", err)
 	}
 
 	s.logger.Info().
 		Str("topic", events.TopicProductCreated).
 		Str("product_id", product.ID.String()).
-		Msg("Published product created event")
+		Str("event_id", event.ID.String()).
+		Msg("Queued product created event")
 
 	return product, nil
 }
@@ -220,22 +218,14 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, dto *dto.Prod
 		subscriptionEnabled = existingProduct.AllowSubscription
 	}
 
+	oldStockLevel := existingProduct.StockLevel
+
 	// Apply the updates to the existing product
 	dto.ApplyToModel(existingProduct)
 
-	// Update the product in the database
-	err = s.repo.Update(ctx, existingProduct)
-	if err != nil {
-		s.logger.Error().
-			Err(err).
-			Str("product_id", id.String()).
-			Msg("Failed to update product in database")
-		return nil, fmt.Errorf("failed to update product: %w", err)
-	}
-
 	// Check if we need to trigger variant creation
 	shouldCreateVariants := false
-	
+
 	// Case 1: Product now has options and allows subscription (and previously had no options)
 	if oldOptionsEmpty && !newOptionsEmpty && subscriptionEnabled {
 		shouldCreateVariants = true
@@ -243,7 +233,7 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, dto *dto.Prod
 			Str("product_id", id.String()).
 			Msg("Product now has options and allows subscription - will trigger variant creation")
 	}
-	
+
 	// Case 2: Product had no options, now has options (regardless of subscription)
 	if oldOptionsEmpty && !newOptionsEmpty {
 		shouldCreateVariants = true
@@ -252,40 +242,70 @@ func (s *productService) Update(ctx context.Context, id uuid.UUID, dto *dto.Prod
 			Msg("Product now has options - will trigger variant creation")
 	}
 
-	// Publish product updated event
+	existingProduct.UpdatedAt = time.Now()
+
+	// Build product updated event
 	payload := events.ProductUpdatedPayload{
-		ProductID:         existingProduct.ID.String(),
-		Name:              existingProduct.Name,
-		Description:       existingProduct.Description,
-		ImageURL:          existingProduct.ImageURL,
-		StockLevel:        existingProduct.StockLevel,
-		Weight:            existingProduct.Weight,
-		Origin:            existingProduct.Origin,
-		RoastLevel:        existingProduct.RoastLevel,
-		FlavorNotes:       existingProduct.FlavorNotes,
-		Options:           existingProduct.Options,
-		AllowSubscription: existingProduct.AllowSubscription,
-		Active:            existingProduct.Active,
-		Archived:          existingProduct.Archived,
-		UpdatedAt:         existingProduct.UpdatedAt,
-		OptionsChanged:    optionsChanged,
+		ProductID:            existingProduct.ID.String(),
+		Name:                 existingProduct.Name,
+		Description:          existingProduct.Description,
+		ImageURL:             existingProduct.ImageURL,
+		StockLevel:           existingProduct.StockLevel,
+		Weight:               existingProduct.Weight,
+		Origin:               existingProduct.Origin,
+		RoastLevel:           existingProduct.RoastLevel,
+		FlavorNotes:          existingProduct.FlavorNotes,
+		Options:              existingProduct.Options,
+		AllowSubscription:    existingProduct.AllowSubscription,
+		Active:               existingProduct.Active,
+		Archived:             existingProduct.Archived,
+		UpdatedAt:            existingProduct.UpdatedAt,
+		OptionsChanged:       optionsChanged,
 		ShouldCreateVariants: shouldCreateVariants,
 	}
 
-	err = s.eventBus.Publish(events.TopicProductUpdated, payload)
+	event, err := newOutboxEvent(events.TopicProductUpdated, payload)
 	if err != nil {
-		s.logger.Error().Err(err).
+		return nil, err
+	}
+	outbox := []*model.OutboxEvent{event}
+
+	if existingProduct.StockLevel != oldStockLevel {
+		stockPayload := events.ProductStockUpdatedPayload{
+			ProductID:        existingProduct.ID.String(),
+			Name:             existingProduct.Name,
+			OldStockLevel:    oldStockLevel,
+			NewStockLevel:    existingProduct.StockLevel,
+			ReorderThreshold: existingProduct.ReorderThreshold,
+			IsLowStock:       isLowStock(existingProduct.StockLevel, existingProduct.ReorderThreshold),
+			UpdatedAt:        existingProduct.UpdatedAt,
+		}
+		for _, topic := range stockEventTopics(stockPayload) {
+			stockEvent, err := newOutboxEvent(topic, stockPayload)
+			if err != nil {
+				return nil, err
+			}
+			outbox = append(outbox, stockEvent)
+		}
+	}
+
+	// Update the product in the database along with its events
+	err = s.repo.Update(ctx, existingProduct, outbox...)
+	if err != nil {
+		s.logger.Error().
+			Err(err).
 			Str("product_id", id.String()).
-			Msg("Failed to publish product updated event")
-		// Don't return error since the product was updated successfully
+			Msg("Failed to update product in database")
+		return nil, fmt.Errorf("failed to update product: %w", err)
 	}
 
 	s.logger.Info().
 		Str("topic", events.TopicProductUpdated).
 		Str("product_id", existingProduct.ID.String()).
+		Int("events", len(outbox)).
 		Bool("options_changed", optionsChanged).
 		Bool("should_create_variants", shouldCreateVariants).
-		Msg("Published product updated event")
+		Msg("Queued product updated event")
 
 	return existingProduct, nil
 }
@@ -320,28 +340,36 @@ func (s *productService) Archive(ctx context.Context, id uuid.UUID) error {
 		return nil // Already archived, nothing to do
 	}
 
-	// Archive the product
-	err = s.repo.Archive(ctx, id)
-	if err != nil {
-		s.logger.Error().Err(err).
-			Str("product_id", id.String()).
-			Msg("Failed to archive product")
-		return fmt.Errorf("failed to archive product: %w", err)
+	// Product archived event, carrying the product as it will be stored
+	payload := events.ProductUpdatedPayload{
+		ProductID:         id.String(),
+		Name:              product.Name,
+		Description:       product.Description,
+		ImageURL:          product.ImageURL,
+		StockLevel:        product.StockLevel,
+		Weight:            product.Weight,
+		Origin:            product.Origin,
+		RoastLevel:        product.RoastLevel,
+		FlavorNotes:       product.FlavorNotes,
+		Options:           product.Options,
+		AllowSubscription: product.AllowSubscription,
+		Active:            false,
+		Archived:          true,
+		UpdatedAt:         time.Now(),
 	}
 
-	// Publish product archived event
-	payload := map[string]interface{}{
-		"product_id":  id.String(),
-		"product_name": product.Name,
-		"archived_at": time.Now().Format(time.RFC3339),
+	event, err := newOutboxEvent(events.TopicProductUpdated, payload)
+	if err != nil {
+		return err
 	}
 
-	err = s.eventBus.Publish(events.TopicProductUpdated, payload)
+	// Archive the product and queue its event together
+	err = s.repo.Archive(ctx, id, event)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("product_id", id.String()).
-			Msg("Failed to publish product archived event")
-		// Don't return the error since the product is already archived in DB
+			Msg("Failed to archive product")
+		return fmt.Errorf("failed to archive product: %w", err)
 	}
 
 	s.logger.Info().
@@ -374,27 +402,25 @@ func (s *productService) Delete(ctx context.Context, id uuid.UUID) error {
 		return postgres.ErrResourceNotFound
 	}
 
-	// Delete the product from the database
-	err = s.repo.Delete(ctx, id)
-	if err != nil {
-		s.logger.Error().Err(err).
-			Str("product_id", id.String()).
-			Msg("Failed to delete product from database")
-		return fmt.Errorf("failed to delete product: %w", err)
+	// Product deleted event
+	payload := events.ProductDeletedPayload{
+		ProductID: id.String(),
+		Name:      product.Name,
+		DeletedAt: time.Now(),
 	}
 
-	// Publish product deleted event
-	payload := map[string]string{
-		"product_id": id.String(),
-		"deleted_at": time.Now().Format(time.RFC3339),
+	event, err := newOutboxEvent(events.TopicProductDeleted, payload)
+	if err != nil {
+		return err
 	}
 
-	err = s.eventBus.Publish(events.TopicProductDeleted, payload)
+	// Delete the product from the database and queue its event together
+	err = s.repo.Delete(ctx, id, event)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("product_id", id.String()).
-			Msg("Failed to publish product deleted event")
-		// Don't return the error since the product is already deleted from DB
+			Msg("Failed to delete product from database")
+		return fmt.Errorf("failed to delete product: %w", err)
 	}
 
 	s.logger.Info().
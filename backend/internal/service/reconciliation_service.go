@@ -0,0 +1,1174 @@
+// internal/service/reconciliation_service.go
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/config"
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/sync"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// reconcilePageSize is how many local products are loaded per query
+const reconcilePageSize = 100
+
+// reservedStripeMetadata are metadata keys on a variant's Stripe product that
+// aren't variant options
+var reservedStripeMetadata = map[string]bool{
+	"original_product_id": true,
+	"product_id":          true,
+	"variant_id":          true,
+	"stock_level":         true,
+	"sync_hash":           true,
+	"last_sync":           true,
+	"sync_source":         true,
+}
+
+// reconciliationService implements ReconciliationService
+type reconciliationService struct {
+	logger        zerolog.Logger
+	config        *config.ReconciliationConfig
+	productRepo   interfaces.ProductRepository
+	variantRepo   interfaces.VariantRepository
+	priceRepo     interfaces.PriceRepository
+	syncRepo      interfaces.SyncHashRepository
+	stripeService interfaces.StripeService
+}
+
+// NewReconciliationService creates a new reconciliation service
+func NewReconciliationService(
+	logger *zerolog.Logger,
+	reconcileConfig *config.ReconciliationConfig,
+	productRepo interfaces.ProductRepository,
+	variantRepo interfaces.VariantRepository,
+	priceRepo interfaces.PriceRepository,
+	syncRepo interfaces.SyncHashRepository,
+	stripeService interfaces.StripeService,
+) interfaces.ReconciliationService {
+	subLogger := logger.With().Str("component", "reconciliation_service").Logger()
+	return &reconciliationService{
+		logger:        subLogger,
+		config:        reconcileConfig,
+		productRepo:   productRepo,
+		variantRepo:   variantRepo,
+		priceRepo:     priceRepo,
+		syncRepo:      syncRepo,
+		stripeService: stripeService,
+	}
+}
+
+// catalogSnapshot holds both sides of the catalog, indexed for matching
+type catalogSnapshot struct {
+	products     []*model.Product
+	productsByID map[uuid.UUID]*model.Product
+	variants     []*model.Variant
+	variantsByID map[uuid.UUID]*model.Variant
+	prices       []*model.Price
+	pricesByID   map[uuid.UUID]*model.Price
+
+	stripeProducts   []*stripe.Product
+	stripeProductsBy map[string]*stripe.Product
+	stripePrices     []*stripe.Price
+	stripePricesBy   map[string]*stripe.Price
+
+	// Stripe IDs referenced by a local record
+	linkedProducts map[string]bool
+	linkedPrices   map[string]bool
+}
+
+// reconcileFix resolves a finding by making the other side match source,
+// returning a description of what it changed
+type reconcileFix func(ctx context.Context, source string) (string, error)
+
+// reconcileItem is a finding with the fix that resolves it
+type reconcileItem struct {
+	finding dto.ReconciliationFinding
+	fix     reconcileFix
+}
+
+// Reconcile reports every discrepancy between Postgres and Stripe
+func (s *reconciliationService) Reconcile(ctx context.Context) (*dto.ReconciliationReport, error) {
+	report, _, err := s.reconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.CompletedAt = time.Now()
+
+	s.logger.Info().
+		Interface("summary", report.Summary).
+		Msg("Catalog reconciliation complete")
+
+	return report, nil
+}
+
+// Apply reconciles and fixes every finding whose category is in the policy.
+// A failed fix is recorded on its finding and doesn't stop the others
+func (s *reconciliationService) Apply(ctx context.Context, policy map[string]string) (*dto.ReconciliationReport, error) {
+	report, items, err := s.reconcile(ctx)
+	if err != nil {
+		return nil, err
+	}
+	report.Applied = true
+	report.Policy = policy
+
+	for i, item := range items {
+		source, ok := policy[item.finding.Category]
+		if !ok {
+			continue
+		}
+
+		action, err := item.fix(ctx, source)
+		if err != nil {
+			s.logger.Error().Err(err).
+				Str("category", item.finding.Category).
+				Str("kind", item.finding.Kind).
+				Str("local_id", item.finding.LocalID).
+				Str("stripe_id", item.finding.StripeID).
+				Msg("Failed to apply reconciliation fix")
+			report.Findings[i].Error = err.Error()
+			report.Failed++
+			continue
+		}
+		report.Findings[i].Action = action
+		report.Fixed++
+	}
+	report.CompletedAt = time.Now()
+
+	s.logger.Info().
+		Interface("summary", report.Summary).
+		Interface("policy", policy).
+		Int("fixed", report.Fixed).
+		Int("failed", report.Failed).
+		Msg("Catalog reconciliation applied")
+
+	return report, nil
+}
+
+// Run reports on the configured interval. A zero interval disables the schedule
+func (s *reconciliationService) Run(ctx context.Context) {
+	if s.config.Interval <= 0 {
+		s.logger.Info().Msg("Scheduled catalog reconciliation disabled")
+		return
+	}
+
+	s.logger.Info().
+		Dur("interval", s.config.Interval).
+		Msg("Starting catalog reconciliation worker")
+
+	ticker := time.NewTicker(s.config.Interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("Catalog reconciliation worker stopped")
+			return
+		case <-ticker.C:
+		}
+
+		report, err := s.Reconcile(ctx)
+		if err != nil {
+			s.logger.Error().Err(err).Msg("Catalog reconciliation failed")
+			continue
+		}
+		if len(report.Findings) > 0 {
+			s.logger.Warn().
+				Int("findings", len(report.Findings)).
+				Interface("summary", report.Summary).
+				Msg("Catalog has drifted from Stripe")
+		}
+	}
+}
+
+// reconcile loads both sides and compares them
+func (s *reconciliationService) reconcile(ctx context.Context) (*dto.ReconciliationReport, []reconcileItem, error) {
+	report := &dto.ReconciliationReport{
+		StartedAt: time.Now(),
+		Summary:   make(map[string]int),
+		Findings:  []dto.ReconciliationFinding{},
+	}
+
+	snap, err := s.loadSnapshot(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+	report.Counts = dto.ReconciliationCounts{
+		LocalProducts:  len(snap.products),
+		LocalVariants:  len(snap.variants),
+		LocalPrices:    len(snap.prices),
+		StripeProducts: len(snap.stripeProducts),
+		StripePrices:   len(snap.stripePrices),
+	}
+
+	var items []reconcileItem
+	items = append(items, s.compareProducts(snap)...)
+	items = append(items, s.compareVariants(ctx, snap)...)
+	items = append(items, s.comparePrices(snap)...)
+	items = append(items, s.compareStripeSide(snap)...)
+
+	for _, item := range items {
+		report.Findings = append(report.Findings, item.finding)
+		report.Summary[item.finding.Category]++
+	}
+
+	return report, items, nil
+}
+
+// loadSnapshot reads the whole catalog from Postgres and Stripe
+func (s *reconciliationService) loadSnapshot(ctx context.Context) (*catalogSnapshot, error) {
+	snap := &catalogSnapshot{
+		productsByID:     make(map[uuid.UUID]*model.Product),
+		variantsByID:     make(map[uuid.UUID]*model.Variant),
+		pricesByID:       make(map[uuid.UUID]*model.Price),
+		stripeProductsBy: make(map[string]*stripe.Product),
+		stripePricesBy:   make(map[string]*stripe.Price),
+		linkedProducts:   make(map[string]bool),
+		linkedPrices:     make(map[string]bool),
+	}
+
+	for offset := 0; ; offset += reconcilePageSize {
+		products, total, err := s.productRepo.List(ctx, offset, reconcilePageSize, true, true)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list products: %w", err)
+		}
+		snap.products = append(snap.products, products...)
+		if len(products) == 0 || offset+len(products) >= total {
+			break
+		}
+	}
+
+	for _, product := range snap.products {
+		snap.productsByID[product.ID] = product
+		if product.StripeID != "" {
+			snap.linkedProducts[product.StripeID] = true
+		}
+
+		variants, err := s.variantRepo.GetByProductID(ctx, product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list variants of product %s: %w", product.ID, err)
+		}
+		for _, variant := range variants {
+			snap.variants = append(snap.variants, variant)
+			snap.variantsByID[variant.ID] = variant
+			if variant.StripeProductID != "" {
+				snap.linkedProducts[variant.StripeProductID] = true
+			}
+			if variant.StripePriceID != "" {
+				snap.linkedPrices[variant.StripePriceID] = true
+			}
+		}
+
+		prices, err := s.priceRepo.GetByProductID(ctx, product.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list prices of product %s: %w", product.ID, err)
+		}
+		for _, price := range prices {
+			snap.prices = append(snap.prices, price)
+			snap.pricesByID[price.ID] = price
+			if price.StripeID != "" {
+				snap.linkedPrices[price.StripeID] = true
+			}
+		}
+	}
+
+	stripeProducts, err := s.stripeService.ListAllProducts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Stripe products: %w", err)
+	}
+	for _, stripeProduct := range stripeProducts {
+		snap.stripeProducts = append(snap.stripeProducts, stripeProduct)
+		snap.stripeProductsBy[stripeProduct.ID] = stripeProduct
+	}
+
+	stripePrices, err := s.stripeService.ListAllPrices()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Stripe prices: %w", err)
+	}
+	for _, stripePrice := range stripePrices {
+		snap.stripePrices = append(snap.stripePrices, stripePrice)
+		snap.stripePricesBy[stripePrice.ID] = stripePrice
+	}
+
+	return snap, nil
+}
+
+// compareProducts checks products linked to a base-level Stripe product
+func (s *reconciliationService) compareProducts(snap *catalogSnapshot) []reconcileItem {
+	var items []reconcileItem
+
+	for _, product := range snap.products {
+		if product.StripeID == "" {
+			continue
+		}
+
+		stripeProduct := snap.stripeProductsBy[product.StripeID]
+		if stripeProduct == nil {
+			items = append(items, reconcileItem{
+				finding: dto.ReconciliationFinding{
+					Category: dto.ReconcileMissingInStripe,
+					Kind:     "product",
+					LocalID:  product.ID.String(),
+					StripeID: product.StripeID,
+					Name:     product.Name,
+					Detail:   "product links to a Stripe product that doesn't exist",
+				},
+				fix: func(ctx context.Context, source string) (string, error) {
+					return s.fixProductMissingInStripe(ctx, snap, product, source)
+				},
+			})
+			continue
+		}
+
+		diffs := productDiffs(product, stripeProduct)
+		if len(diffs) == 0 {
+			continue
+		}
+		items = append(items, reconcileItem{
+			finding: dto.ReconciliationFinding{
+				Category: dto.ReconcileDrift,
+				Kind:     "product",
+				LocalID:  product.ID.String(),
+				StripeID: stripeProduct.ID,
+				Name:     product.Name,
+				Diffs:    diffs,
+			},
+			fix: func(ctx context.Context, source string) (string, error) {
+				return s.fixProductDrift(ctx, product, stripeProduct, source)
+			},
+		})
+	}
+
+	return items
+}
+
+// compareVariants checks each variant against its Stripe product, using the
+// sync hashes to tell which side moved
+func (s *reconciliationService) compareVariants(ctx context.Context, snap *catalogSnapshot) []reconcileItem {
+	var items []reconcileItem
+
+	for _, variant := range snap.variants {
+		// A variant that was never pushed and isn't on sale has nothing to reconcile
+		if variant.StripeProductID == "" && !variant.Active {
+			continue
+		}
+		name := variantDisplayName(snap.productsByID[variant.ProductID], variant)
+
+		stripeProduct := snap.stripeProductsBy[variant.StripeProductID]
+		if stripeProduct == nil {
+			detail := "variant links to a Stripe product that doesn't exist"
+			if variant.StripeProductID == "" {
+				detail = "active variant has no Stripe product"
+			}
+			items = append(items, reconcileItem{
+				finding: dto.ReconciliationFinding{
+					Category: dto.ReconcileMissingInStripe,
+					Kind:     "variant",
+					LocalID:  variant.ID.String(),
+					StripeID: variant.StripeProductID,
+					Name:     name,
+					Detail:   detail,
+				},
+				fix: func(ctx context.Context, source string) (string, error) {
+					return s.fixVariantMissingInStripe(ctx, snap, variant, source)
+				},
+			})
+			continue
+		}
+
+		projected := projectVariant(variant, stripeProduct, snap)
+		localHash, err := sync.ComputeVariantHash(variant)
+		if err != nil {
+			s.logger.Error().Err(err).Str("variant_id", variant.ID.String()).Msg("Failed to hash variant")
+			continue
+		}
+		stripeHash, err := sync.ComputeVariantHash(projected)
+		if err != nil {
+			s.logger.Error().Err(err).Str("stripe_product_id", stripeProduct.ID).Msg("Failed to hash Stripe product")
+			continue
+		}
+		if localHash == stripeHash {
+			continue
+		}
+
+		items = append(items, reconcileItem{
+			finding: dto.ReconciliationFinding{
+				Category: dto.ReconcileDrift,
+				Kind:     "variant",
+				LocalID:  variant.ID.String(),
+				StripeID: stripeProduct.ID,
+				Name:     name,
+				Detail:   s.driftDetail(ctx, variant, stripeProduct),
+				Diffs:    variantDiffs(variant, projected),
+			},
+			fix: func(ctx context.Context, source string) (string, error) {
+				return s.fixVariantDrift(ctx, snap, variant, stripeProduct, source)
+			},
+		})
+	}
+
+	return items
+}
+
+// driftDetail uses the last recorded sync hash to say which side changed
+func (s *reconciliationService) driftDetail(ctx context.Context, variant *model.Variant, stripeProduct *stripe.Product) string {
+	stored, err := s.syncRepo.GetByVariantAndStripeID(ctx, variant.ID, stripeProduct.ID)
+	if err != nil || stored == nil {
+		return "no sync hash recorded for this variant"
+	}
+
+	currentHash, err := sync.ComputeStripeProductHash(*stripeProduct)
+	if err != nil {
+		return ""
+	}
+	if currentHash != stored.ContentHash {
+		return fmt.Sprintf("Stripe product changed since the last sync at %s", stored.UpdatedAt.Format(time.RFC3339))
+	}
+	return fmt.Sprintf("Stripe product unchanged since the last sync at %s; the local variant changed", stored.UpdatedAt.Format(time.RFC3339))
+}
+
+// comparePrices checks each local price against its Stripe price
+func (s *reconciliationService) comparePrices(snap *catalogSnapshot) []reconcileItem {
+	var items []reconcileItem
+
+	for _, price := range snap.prices {
+		if price.StripeID == "" && !price.Active {
+			continue
+		}
+
+		stripePrice := snap.stripePricesBy[price.StripeID]
+		if stripePrice == nil {
+			detail := "price links to a Stripe price that doesn't exist"
+			if price.StripeID == "" {
+				detail = "active price has no Stripe price"
+			}
+			items = append(items, reconcileItem{
+				finding: dto.ReconciliationFinding{
+					Category: dto.ReconcileMissingInStripe,
+					Kind:     "price",
+					LocalID:  price.ID.String(),
+					StripeID: price.StripeID,
+					Name:     price.Name,
+					Detail:   detail,
+				},
+				fix: func(ctx context.Context, source string) (string, error) {
+					return s.fixPriceMissingInStripe(ctx, snap, price, source)
+				},
+			})
+			continue
+		}
+
+		diffs := priceDiffs(price, stripePrice)
+		if len(diffs) == 0 {
+			continue
+		}
+		items = append(items, reconcileItem{
+			finding: dto.ReconciliationFinding{
+				Category: dto.ReconcileDrift,
+				Kind:     "price",
+				LocalID:  price.ID.String(),
+				StripeID: stripePrice.ID,
+				Name:     price.Name,
+				Diffs:    diffs,
+			},
+			fix: func(ctx context.Context, source string) (string, error) {
+				return s.fixPriceDrift(ctx, snap, price, stripePrice, source)
+			},
+		})
+	}
+
+	return items
+}
+
+// compareStripeSide finds active Stripe objects no local record links to.
+// Those whose metadata points back at our catalog are missing locally; the
+// rest are orphans
+func (s *reconciliationService) compareStripeSide(snap *catalogSnapshot) []reconcileItem {
+	var items []reconcileItem
+
+	for _, stripeProduct := range snap.stripeProducts {
+		if !stripeProduct.Active || snap.linkedProducts[stripeProduct.ID] {
+			continue
+		}
+
+		variant := metadataVariant(snap, stripeProduct)
+		product := metadataProduct(snap, stripeProduct)
+		if variant == nil && product == nil {
+			items = append(items, reconcileItem{
+				finding: dto.ReconciliationFinding{
+					Category: dto.ReconcileOrphan,
+					Kind:     "product",
+					StripeID: stripeProduct.ID,
+					Name:     stripeProduct.Name,
+					Detail:   "active Stripe product doesn't belong to any local product",
+				},
+				fix: func(ctx context.Context, source string) (string, error) {
+					return s.fixOrphanProduct(stripeProduct, source)
+				},
+			})
+			continue
+		}
+
+		finding := dto.ReconciliationFinding{
+			Category: dto.ReconcileMissingLocally,
+			Kind:     "variant",
+			StripeID: stripeProduct.ID,
+			Name:     stripeProduct.Name,
+			Detail:   "Stripe product belongs to our catalog but no variant links to it",
+		}
+		if variant != nil {
+			finding.LocalID = variant.ID.String()
+		}
+		items = append(items, reconcileItem{
+			finding: finding,
+			fix: func(ctx context.Context, source string) (string, error) {
+				return s.fixProductMissingLocally(ctx, variant, stripeProduct, source)
+			},
+		})
+	}
+
+	for _, stripePrice := range snap.stripePrices {
+		if !stripePrice.Active || snap.linkedPrices[stripePrice.ID] || stripePrice.Product == nil {
+			continue
+		}
+		// Prices on products we don't know are covered by the orphan product
+		if !snap.linkedProducts[stripePrice.Product.ID] {
+			continue
+		}
+
+		items = append(items, reconcileItem{
+			finding: dto.ReconciliationFinding{
+				Category: dto.ReconcileMissingLocally,
+				Kind:     "price",
+				StripeID: stripePrice.ID,
+				Name:     stripePrice.Nickname,
+				Detail:   fmt.Sprintf("active price on Stripe product %s has no local price", stripePrice.Product.ID),
+			},
+			fix: func(ctx context.Context, source string) (string, error) {
+				return s.fixPriceMissingLocally(ctx, snap, stripePrice, source)
+			},
+		})
+	}
+
+	return items
+}
+
+// fixProductMissingInStripe recreates the Stripe product or unlinks the product
+func (s *reconciliationService) fixProductMissingInStripe(ctx context.Context, snap *catalogSnapshot, product *model.Product, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		product.StripeID = ""
+		product.UpdatedAt = time.Now()
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return "", fmt.Errorf("failed to update product: %w", err)
+		}
+		return "cleared the product's Stripe product ID", nil
+	}
+
+	stripeProduct, err := s.stripeService.CreateProduct(product.Name, product.Description, productImages(product), map[string]string{
+		"original_product_id": product.ID.String(),
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to create Stripe product: %w", err)
+	}
+	snap.stripeProductsBy[stripeProduct.ID] = stripeProduct
+
+	product.StripeID = stripeProduct.ID
+	product.UpdatedAt = time.Now()
+	if err := s.productRepo.Update(ctx, product); err != nil {
+		return "", fmt.Errorf("failed to update product: %w", err)
+	}
+	return fmt.Sprintf("created Stripe product %s", stripeProduct.ID), nil
+}
+
+// fixProductDrift copies name, description and status across
+func (s *reconciliationService) fixProductDrift(ctx context.Context, product *model.Product, stripeProduct *stripe.Product, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		product.Name = stripeProduct.Name
+		product.Description = stripeProduct.Description
+		if productOnSale(product) != stripeProduct.Active {
+			product.Active = stripeProduct.Active
+		}
+		product.UpdatedAt = time.Now()
+		if err := s.productRepo.Update(ctx, product); err != nil {
+			return "", fmt.Errorf("failed to update product: %w", err)
+		}
+		return "updated the product from Stripe", nil
+	}
+
+	if _, err := s.stripeService.UpdateProductDetails(stripeProduct.ID, product.Name, product.Description, productImages(product), productOnSale(product), nil); err != nil {
+		return "", err
+	}
+	return "updated the Stripe product", nil
+}
+
+// fixVariantMissingInStripe recreates the variant's Stripe product and price,
+// or takes the variant off sale
+func (s *reconciliationService) fixVariantMissingInStripe(ctx context.Context, snap *catalogSnapshot, variant *model.Variant, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		variant.Active = false
+		variant.UpdatedAt = time.Now()
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return "", fmt.Errorf("failed to update variant: %w", err)
+		}
+		return "deactivated the variant", nil
+	}
+
+	price := snap.pricesByID[variant.PriceID]
+	if price == nil {
+		return "", fmt.Errorf("variant has no local price to create in Stripe")
+	}
+	product := snap.productsByID[variant.ProductID]
+	if product == nil {
+		return "", fmt.Errorf("variant's product %s not found", variant.ProductID)
+	}
+
+	stripeProduct, err := s.stripeService.CreateProduct(product.Name, product.Description, productImages(product), variantStripeMetadata(variant))
+	if err != nil {
+		return "", fmt.Errorf("failed to create Stripe product: %w", err)
+	}
+	snap.stripeProductsBy[stripeProduct.ID] = stripeProduct
+	variant.StripeProductID = stripeProduct.ID
+
+	// Stripe prices can't move between products, so the variant always gets a
+	// new one. The local price takes it too unless its own still exists
+	stripePrice, err := createStripePrice(s.stripeService, stripeProduct.ID, price, "")
+	if err != nil {
+		return "", err
+	}
+	snap.stripePricesBy[stripePrice.ID] = stripePrice
+	variant.StripePriceID = stripePrice.ID
+
+	if snap.stripePricesBy[price.StripeID] == nil {
+		price.StripeID = stripePrice.ID
+		price.UpdatedAt = time.Now()
+		if err := s.priceRepo.Update(ctx, price); err != nil {
+			return "", fmt.Errorf("failed to update price: %w", err)
+		}
+	}
+
+	variant.UpdatedAt = time.Now()
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return "", fmt.Errorf("failed to update variant: %w", err)
+	}
+	s.recordSyncHash(ctx, variant, stripeProduct)
+
+	return fmt.Sprintf("created Stripe product %s and price %s", stripeProduct.ID, stripePrice.ID), nil
+}
+
+// fixVariantDrift pushes the variant to its Stripe product or pulls the
+// Stripe product's values into the variant
+func (s *reconciliationService) fixVariantDrift(ctx context.Context, snap *catalogSnapshot, variant *model.Variant, stripeProduct *stripe.Product, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		projected := projectVariant(variant, stripeProduct, snap)
+		variant.Active = projected.Active
+		variant.Weight = projected.Weight
+		variant.Options = projected.Options
+		if projected.StripePriceID != "" {
+			variant.StripePriceID = projected.StripePriceID
+		}
+		variant.UpdatedAt = time.Now()
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return "", fmt.Errorf("failed to update variant: %w", err)
+		}
+		s.recordSyncHash(ctx, variant, stripeProduct)
+		return "updated the variant from Stripe", nil
+	}
+
+	// A Stripe price can't move between products, so a variant pointing at a
+	// price on another product gets a new one here
+	var actions []string
+	if !priceBelongsTo(snap.stripePricesBy[variant.StripePriceID], stripeProduct.ID) {
+		price := snap.pricesByID[variant.PriceID]
+		if price == nil {
+			return "", fmt.Errorf("variant has no local price to create in Stripe")
+		}
+		stripePrice, err := createStripePrice(s.stripeService, stripeProduct.ID, price, "")
+		if err != nil {
+			return "", err
+		}
+		snap.stripePricesBy[stripePrice.ID] = stripePrice
+		variant.StripePriceID = stripePrice.ID
+		variant.UpdatedAt = time.Now()
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return "", fmt.Errorf("failed to update variant: %w", err)
+		}
+		actions = append(actions, fmt.Sprintf("created Stripe price %s", stripePrice.ID))
+	}
+
+	// Options the variant no longer has are unset by sending them empty
+	metadata := variantStripeMetadata(variant)
+	for key := range stripeProduct.Metadata {
+		if _, ok := metadata[key]; !ok && !reservedStripeMetadata[key] {
+			metadata[key] = ""
+		}
+	}
+	updated, err := s.stripeService.UpdateProduct(stripeProduct.ID, variant.Active, metadata)
+	if err != nil {
+		return "", err
+	}
+	s.recordSyncHash(ctx, variant, updated)
+	actions = append(actions, "updated the Stripe product")
+
+	return strings.Join(actions, "; "), nil
+}
+
+// fixPriceMissingInStripe recreates the Stripe price or deactivates the price
+func (s *reconciliationService) fixPriceMissingInStripe(ctx context.Context, snap *catalogSnapshot, price *model.Price, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		price.Active = false
+		price.UpdatedAt = time.Now()
+		if err := s.priceRepo.Update(ctx, price); err != nil {
+			return "", fmt.Errorf("failed to update price: %w", err)
+		}
+		return "deactivated the price", nil
+	}
+
+	// Fixing a variant may already have created it
+	if snap.stripePricesBy[price.StripeID] != nil {
+		return fmt.Sprintf("Stripe price %s was created with its variant", price.StripeID), nil
+	}
+
+	stripeProductID := s.stripeProductForPrice(snap, price)
+	if stripeProductID == "" {
+		return "", fmt.Errorf("no Stripe product to attach the price to")
+	}
+	stripePrice, err := createStripePrice(s.stripeService, stripeProductID, price, "")
+	if err != nil {
+		return "", err
+	}
+	snap.stripePricesBy[stripePrice.ID] = stripePrice
+
+	oldStripeID := price.StripeID
+	price.StripeID = stripePrice.ID
+	price.UpdatedAt = time.Now()
+	if err := s.priceRepo.Update(ctx, price); err != nil {
+		return "", fmt.Errorf("failed to update price: %w", err)
+	}
+	if err := s.repointVariants(ctx, snap, price.ID, oldStripeID, stripePrice.ID); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprintf("created Stripe price %s", stripePrice.ID), nil
+}
+
+// fixPriceDrift replaces or updates the Stripe price, or copies the Stripe
+// price's values into the local price
+func (s *reconciliationService) fixPriceDrift(ctx context.Context, snap *catalogSnapshot, price *model.Price, stripePrice *stripe.Price, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		price.Amount = stripePrice.UnitAmount
+		price.Currency = strings.ToUpper(string(stripePrice.Currency))
+		price.Type = string(stripePrice.Type)
+		price.Interval = ""
+		price.IntervalCount = 0
+		if stripePrice.Recurring != nil {
+			price.Interval = string(stripePrice.Recurring.Interval)
+			price.IntervalCount = int(stripePrice.Recurring.IntervalCount)
+		}
+		price.Active = stripePrice.Active
+		price.UpdatedAt = time.Now()
+		if err := s.priceRepo.Update(ctx, price); err != nil {
+			return "", fmt.Errorf("failed to update price: %w", err)
+		}
+		return "updated the price from Stripe", nil
+	}
+
+	// Only the status of a Stripe price can change in place
+	if !priceTermsDiffer(price, stripePrice) {
+		if _, err := s.stripeService.SetPriceActive(stripePrice.ID, price.Active); err != nil {
+			return "", err
+		}
+		return "updated the Stripe price status", nil
+	}
+
+	if stripePrice.Product == nil {
+		return "", fmt.Errorf("Stripe price %s has no product", stripePrice.ID)
+	}
+	replacement, err := createStripePrice(s.stripeService, stripePrice.Product.ID, price, "")
+	if err != nil {
+		return "", err
+	}
+	snap.stripePricesBy[replacement.ID] = replacement
+
+	price.StripeID = replacement.ID
+	price.UpdatedAt = time.Now()
+	if err := s.priceRepo.Update(ctx, price); err != nil {
+		return "", fmt.Errorf("failed to update price: %w", err)
+	}
+	if err := s.repointVariants(ctx, snap, price.ID, stripePrice.ID, replacement.ID); err != nil {
+		return "", err
+	}
+	if stripePrice.Active {
+		if _, err := s.stripeService.SetPriceActive(stripePrice.ID, false); err != nil {
+			s.logger.Error().Err(err).
+				Str("stripe_price_id", stripePrice.ID).
+				Msg("Failed to deactivate replaced Stripe price")
+		}
+	}
+
+	return fmt.Sprintf("replaced Stripe price %s with %s", stripePrice.ID, replacement.ID), nil
+}
+
+// fixProductMissingLocally archives the Stripe product or links it back to
+// the variant named in its metadata
+func (s *reconciliationService) fixProductMissingLocally(ctx context.Context, variant *model.Variant, stripeProduct *stripe.Product, source string) (string, error) {
+	if source == dto.ReconcileSourceLocal {
+		if _, err := s.stripeService.UpdateProduct(stripeProduct.ID, false, nil); err != nil {
+			return "", err
+		}
+		return "archived the Stripe product", nil
+	}
+
+	if variant == nil {
+		return "", fmt.Errorf("no local variant to link the Stripe product to")
+	}
+	if variant.StripeProductID != "" && variant.StripeProductID != stripeProduct.ID {
+		return "", fmt.Errorf("variant is already linked to Stripe product %s", variant.StripeProductID)
+	}
+
+	variant.StripeProductID = stripeProduct.ID
+	if stripeProduct.DefaultPrice != nil {
+		variant.StripePriceID = stripeProduct.DefaultPrice.ID
+	}
+	variant.UpdatedAt = time.Now()
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return "", fmt.Errorf("failed to update variant: %w", err)
+	}
+	s.recordSyncHash(ctx, variant, stripeProduct)
+
+	return "linked the variant to the Stripe product", nil
+}
+
+// fixPriceMissingLocally deactivates the Stripe price or imports it
+func (s *reconciliationService) fixPriceMissingLocally(ctx context.Context, snap *catalogSnapshot, stripePrice *stripe.Price, source string) (string, error) {
+	if source == dto.ReconcileSourceLocal {
+		if _, err := s.stripeService.SetPriceActive(stripePrice.ID, false); err != nil {
+			return "", err
+		}
+		return "deactivated the Stripe price", nil
+	}
+
+	productID, ok := localOwner(snap, stripePrice.Product.ID)
+	if !ok {
+		return "", fmt.Errorf("no local product owns Stripe product %s", stripePrice.Product.ID)
+	}
+
+	now := time.Now()
+	price := &model.Price{
+		ID:        uuid.New(),
+		ProductID: productID,
+		Name:      stripePrice.Nickname,
+		Amount:    stripePrice.UnitAmount,
+		Currency:  strings.ToUpper(string(stripePrice.Currency)),
+		Type:      string(stripePrice.Type),
+		Active:    stripePrice.Active,
+		StripeID:  stripePrice.ID,
+		CreatedAt: now,
+		UpdatedAt: now,
+	}
+	if price.Name == "" {
+		price.Name = fmt.Sprintf("Imported %s", stripePrice.ID)
+	}
+	if stripePrice.Recurring != nil {
+		price.Interval = string(stripePrice.Recurring.Interval)
+		price.IntervalCount = int(stripePrice.Recurring.IntervalCount)
+	}
+	if err := s.priceRepo.Create(ctx, price); err != nil {
+		return "", fmt.Errorf("failed to create price: %w", err)
+	}
+
+	return fmt.Sprintf("created local price %s", price.ID), nil
+}
+
+// fixOrphanProduct archives the Stripe product; with Stripe as the source of
+// truth it is kept as is
+func (s *reconciliationService) fixOrphanProduct(stripeProduct *stripe.Product, source string) (string, error) {
+	if source == dto.ReconcileSourceStripe {
+		return "kept the Stripe product", nil
+	}
+	if _, err := s.stripeService.UpdateProduct(stripeProduct.ID, false, nil); err != nil {
+		return "", err
+	}
+	return "archived the Stripe product", nil
+}
+
+// repointVariants moves variants using a price from its old Stripe price to the new one
+func (s *reconciliationService) repointVariants(ctx context.Context, snap *catalogSnapshot, priceID uuid.UUID, oldStripeID, newStripeID string) error {
+	for _, variant := range snap.variants {
+		if variant.PriceID != priceID {
+			continue
+		}
+		if variant.StripePriceID != "" && variant.StripePriceID != oldStripeID {
+			continue
+		}
+		variant.StripePriceID = newStripeID
+		variant.UpdatedAt = time.Now()
+		if err := s.variantRepo.Update(ctx, variant); err != nil {
+			return fmt.Errorf("failed to update variant %s: %w", variant.ID, err)
+		}
+	}
+	return nil
+}
+
+// stripeProductForPrice picks the Stripe product a local price belongs on:
+// that of a variant using it, else the product's own
+func (s *reconciliationService) stripeProductForPrice(snap *catalogSnapshot, price *model.Price) string {
+	for _, variant := range snap.variants {
+		if variant.PriceID == price.ID && snap.stripeProductsBy[variant.StripeProductID] != nil {
+			return variant.StripeProductID
+		}
+	}
+	if product := snap.productsByID[price.ProductID]; product != nil && snap.stripeProductsBy[product.StripeID] != nil {
+		return product.StripeID
+	}
+	return ""
+}
+
+// recordSyncHash stores the Stripe product's hash so webhooks for the state
+// we just reconciled to are recognised as unchanged
+func (s *reconciliationService) recordSyncHash(ctx context.Context, variant *model.Variant, stripeProduct *stripe.Product) {
+	storeSyncHash(ctx, s.logger, s.syncRepo, variant.ID, stripeProduct, model.SyncSourceSystemSync)
+}
+
+// projectVariant is the variant as its Stripe product describes it, so both
+// can be compared with ComputeVariantHash
+func projectVariant(variant *model.Variant, stripeProduct *stripe.Product, snap *catalogSnapshot) *model.Variant {
+	projected := *variant
+	projected.StripeProductID = stripeProduct.ID
+	projected.Active = stripeProduct.Active
+	projected.Options = make(map[string]string)
+
+	for key, value := range stripeProduct.Metadata {
+		if reservedStripeMetadata[key] || value == "" {
+			continue
+		}
+		projected.Options[strings.TrimPrefix(key, "variant_")] = value
+	}
+	if weight, ok := projected.Options["weight"]; ok {
+		if grams := convertWeightToGrams(weight); grams > 0 {
+			projected.Weight = grams
+		}
+	}
+	if level, ok := stripeProduct.Metadata["stock_level"]; ok {
+		if stockLevel, err := strconv.Atoi(level); err == nil {
+			projected.StockLevel = stockLevel
+		}
+	}
+
+	switch {
+	case priceBelongsTo(snap.stripePricesBy[variant.StripePriceID], stripeProduct.ID):
+		projected.StripePriceID = variant.StripePriceID
+	case stripeProduct.DefaultPrice != nil:
+		projected.StripePriceID = stripeProduct.DefaultPrice.ID
+	default:
+		projected.StripePriceID = ""
+	}
+
+	return &projected
+}
+
+// productDiffs lists the fields of a product that differ from its Stripe product
+func productDiffs(product *model.Product, stripeProduct *stripe.Product) []dto.FieldDiff {
+	var diffs []dto.FieldDiff
+	if product.Name != stripeProduct.Name {
+		diffs = append(diffs, dto.FieldDiff{Field: "name", Local: product.Name, Stripe: stripeProduct.Name})
+	}
+	if product.Description != stripeProduct.Description {
+		diffs = append(diffs, dto.FieldDiff{Field: "description", Local: product.Description, Stripe: stripeProduct.Description})
+	}
+	if productOnSale(product) != stripeProduct.Active {
+		diffs = append(diffs, dto.FieldDiff{
+			Field:  "active",
+			Local:  strconv.FormatBool(productOnSale(product)),
+			Stripe: strconv.FormatBool(stripeProduct.Active),
+		})
+	}
+	return diffs
+}
+
+// variantDiffs lists the fields of a variant that differ from its projection
+func variantDiffs(variant, projected *model.Variant) []dto.FieldDiff {
+	var diffs []dto.FieldDiff
+	if variant.Active != projected.Active {
+		diffs = append(diffs, dto.FieldDiff{
+			Field:  "active",
+			Local:  strconv.FormatBool(variant.Active),
+			Stripe: strconv.FormatBool(projected.Active),
+		})
+	}
+	if variant.Weight != projected.Weight {
+		diffs = append(diffs, dto.FieldDiff{
+			Field:  "weight",
+			Local:  strconv.Itoa(variant.Weight),
+			Stripe: strconv.Itoa(projected.Weight),
+		})
+	}
+	if variant.StockLevel != projected.StockLevel {
+		diffs = append(diffs, dto.FieldDiff{
+			Field:  "stock_level",
+			Local:  strconv.Itoa(variant.StockLevel),
+			Stripe: strconv.Itoa(projected.StockLevel),
+		})
+	}
+	if variant.StripePriceID != projected.StripePriceID {
+		diffs = append(diffs, dto.FieldDiff{Field: "stripe_price_id", Local: variant.StripePriceID, Stripe: projected.StripePriceID})
+	}
+
+	keys := make(map[string]bool)
+	for key := range variant.Options {
+		keys[key] = true
+	}
+	for key := range projected.Options {
+		keys[key] = true
+	}
+	sorted := make([]string, 0, len(keys))
+	for key := range keys {
+		sorted = append(sorted, key)
+	}
+	sort.Strings(sorted)
+	for _, key := range sorted {
+		if variant.Options[key] != projected.Options[key] {
+			diffs = append(diffs, dto.FieldDiff{Field: "options." + key, Local: variant.Options[key], Stripe: projected.Options[key]})
+		}
+	}
+
+	return diffs
+}
+
+// priceDiffs lists the fields of a price that differ from its Stripe price
+func priceDiffs(price *model.Price, stripePrice *stripe.Price) []dto.FieldDiff {
+	var diffs []dto.FieldDiff
+	if price.Amount != stripePrice.UnitAmount {
+		diffs = append(diffs, dto.FieldDiff{
+			Field:  "amount",
+			Local:  strconv.FormatInt(price.Amount, 10),
+			Stripe: strconv.FormatInt(stripePrice.UnitAmount, 10),
+		})
+	}
+	if !strings.EqualFold(price.Currency, string(stripePrice.Currency)) {
+		diffs = append(diffs, dto.FieldDiff{Field: "currency", Local: price.Currency, Stripe: string(stripePrice.Currency)})
+	}
+	if price.Type != string(stripePrice.Type) {
+		diffs = append(diffs, dto.FieldDiff{Field: "type", Local: price.Type, Stripe: string(stripePrice.Type)})
+	}
+
+	interval, intervalCount := "", 0
+	if stripePrice.Recurring != nil {
+		interval = string(stripePrice.Recurring.Interval)
+		intervalCount = int(stripePrice.Recurring.IntervalCount)
+	}
+	if price.Type == "recurring" || stripePrice.Recurring != nil {
+		if price.Interval != interval {
+			diffs = append(diffs, dto.FieldDiff{Field: "interval", Local: price.Interval, Stripe: interval})
+		}
+		if price.IntervalCount != intervalCount {
+			diffs = append(diffs, dto.FieldDiff{
+				Field:  "interval_count",
+				Local:  strconv.Itoa(price.IntervalCount),
+				Stripe: strconv.Itoa(intervalCount),
+			})
+		}
+	}
+
+	if price.Active != stripePrice.Active {
+		diffs = append(diffs, dto.FieldDiff{
+			Field:  "active",
+			Local:  strconv.FormatBool(price.Active),
+			Stripe: strconv.FormatBool(stripePrice.Active),
+		})
+	}
+	return diffs
+}
+
+// priceTermsDiffer reports whether anything but the status differs, which
+// Stripe only allows by creating a new price
+func priceTermsDiffer(price *model.Price, stripePrice *stripe.Price) bool {
+	for _, diff := range priceDiffs(price, stripePrice) {
+		if diff.Field != "active" {
+			return true
+		}
+	}
+	return false
+}
+
+// priceBelongsTo reports whether a Stripe price is attached to the product
+func priceBelongsTo(stripePrice *stripe.Price, stripeProductID string) bool {
+	return stripePrice != nil && stripePrice.Product != nil && stripePrice.Product.ID == stripeProductID
+}
+
+// metadataVariant resolves the variant_id a Stripe product carries
+func metadataVariant(snap *catalogSnapshot, stripeProduct *stripe.Product) *model.Variant {
+	id, err := uuid.Parse(stripeProduct.Metadata["variant_id"])
+	if err != nil {
+		return nil
+	}
+	return snap.variantsByID[id]
+}
+
+// metadataProduct resolves the original_product_id a Stripe product carries
+func metadataProduct(snap *catalogSnapshot, stripeProduct *stripe.Product) *model.Product {
+	id, err := uuid.Parse(stripeProduct.Metadata["original_product_id"])
+	if err != nil {
+		return nil
+	}
+	return snap.productsByID[id]
+}
+
+// localOwner finds the local product a linked Stripe product belongs to
+func localOwner(snap *catalogSnapshot, stripeProductID string) (uuid.UUID, bool) {
+	for _, product := range snap.products {
+		if product.StripeID == stripeProductID {
+			return product.ID, true
+		}
+	}
+	for _, variant := range snap.variants {
+		if variant.StripeProductID == stripeProductID {
+			return variant.ProductID, true
+		}
+	}
+	return uuid.Nil, false
+}
+
+// productOnSale is whether the product should be active in Stripe
+func productOnSale(product *model.Product) bool {
+	return product.Active && !product.Archived
+}
+
+// productImages is the product's image as a Stripe image list
+func productImages(product *model.Product) []string {
+	if product.ImageURL == "" {
+		return nil
+	}
+	return []string{product.ImageURL}
+}
+
+// variantDisplayName names a variant after its product and option values
+func variantDisplayName(product *model.Product, variant *model.Variant) string {
+	name := variant.ID.String()
+	if product != nil {
+		name = product.Name
+	}
+
+	keys := make([]string, 0, len(variant.Options))
+	for key := range variant.Options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	values := make([]string, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, variant.Options[key])
+	}
+	if len(values) == 0 {
+		return name
+	}
+	return fmt.Sprintf("%s - %s", name, strings.Join(values, ", "))
+}
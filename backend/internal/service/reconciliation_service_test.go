@@ -0,0 +1,85 @@
+package service
+
+import (
+	"testing"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/stripe/stripe-go/v82"
+)
+
+func TestPriceTermsDiffer(t *testing.T) {
+	oneTime := func() (*model.Price, *stripe.Price) {
+		return &model.Price{Amount: 1800, Currency: "USD", Type: "one_time", Active: true},
+			&stripe.Price{UnitAmount: 1800, Currency: "usd", Type: stripe.PriceTypeOneTime, Active: true}
+	}
+	recurring := func() (*model.Price, *stripe.Price) {
+		return &model.Price{Amount: 1600, Currency: "usd", Type: "recurring", Interval: "week", IntervalCount: 2, Active: true},
+			&stripe.Price{
+				UnitAmount: 1600,
+				Currency:   "usd",
+				Type:       stripe.PriceTypeRecurring,
+				Active:     true,
+				Recurring:  &stripe.PriceRecurring{Interval: stripe.PriceRecurringIntervalWeek, IntervalCount: 2},
+			}
+	}
+
+	tests := []struct {
+		name      string
+		prices    func() (*model.Price, *stripe.Price)
+		change    func(*model.Price, *stripe.Price)
+		wantTerms bool
+		wantDiffs int
+	}{
+		{"identical one-time price", oneTime, func(*model.Price, *stripe.Price) {}, false, 0},
+		{"identical recurring price", recurring, func(*model.Price, *stripe.Price) {}, false, 0},
+		{"only active differs", oneTime, func(p *model.Price, _ *stripe.Price) { p.Active = false }, false, 1},
+		{"amount differs", oneTime, func(p *model.Price, _ *stripe.Price) { p.Amount = 1900 }, true, 1},
+		{"currency differs", oneTime, func(p *model.Price, _ *stripe.Price) { p.Currency = "eur" }, true, 1},
+		{"amount and active differ", oneTime, func(p *model.Price, _ *stripe.Price) {
+			p.Amount = 1900
+			p.Active = false
+		}, true, 2},
+		{"interval differs", recurring, func(p *model.Price, _ *stripe.Price) { p.Interval = "month" }, true, 1},
+		{"interval count differs", recurring, func(p *model.Price, _ *stripe.Price) { p.IntervalCount = 4 }, true, 1},
+		{"local one-time, Stripe recurring", recurring, func(p *model.Price, _ *stripe.Price) {
+			p.Type = "one_time"
+			p.Interval = ""
+			p.IntervalCount = 0
+		}, true, 3},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			price, stripePrice := tt.prices()
+			tt.change(price, stripePrice)
+
+			if got := priceTermsDiffer(price, stripePrice); got != tt.wantTerms {
+				t.Errorf("priceTermsDiffer() = %v, want %v", got, tt.wantTerms)
+			}
+			if diffs := priceDiffs(price, stripePrice); len(diffs) != tt.wantDiffs {
+				t.Errorf("priceDiffs() = %+v, want %d diffs", diffs, tt.wantDiffs)
+			}
+		})
+	}
+}
+
+func TestPriceBelongsTo(t *testing.T) {
+	tests := []struct {
+		name        string
+		stripePrice *stripe.Price
+		want        bool
+	}{
+		{"nil price", nil, false},
+		{"price without product", &stripe.Price{ID: "price_1"}, false},
+		{"other product", &stripe.Price{ID: "price_1", Product: &stripe.Product{ID: "prod_other"}}, false},
+		{"same product", &stripe.Price{ID: "price_1", Product: &stripe.Product{ID: "prod_1"}}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := priceBelongsTo(tt.stripePrice, "prod_1"); got != tt.want {
+				t.Errorf("priceBelongsTo() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,592 @@
+// internal/service/subscription_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/events"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	stripeSDK "github.com/stripe/stripe-go/v82"
+)
+
+// subscriptionService implements SubscriptionService
+type subscriptionService struct {
+	logger        zerolog.Logger
+	eventBus      events.EventBus
+	repo          interfaces.SubscriptionRepository
+	customerRepo  interfaces.CustomerRepository
+	addressRepo   interfaces.AddressRepository
+	priceRepo     interfaces.PriceRepository
+	productRepo   interfaces.ProductRepository
+	stripeService interfaces.StripeService
+}
+
+// NewSubscriptionService creates a new subscription service
+func NewSubscriptionService(
+	logger *zerolog.Logger,
+	eventBus events.EventBus,
+	subscriptionRepo interfaces.SubscriptionRepository,
+	customerRepo interfaces.CustomerRepository,
+	addressRepo interfaces.AddressRepository,
+	priceRepo interfaces.PriceRepository,
+	productRepo interfaces.ProductRepository,
+	stripeService interfaces.StripeService,
+) interfaces.SubscriptionService {
+	subLogger := logger.With().Str("component", "subscription_service").Logger()
+	return &subscriptionService{
+		logger:        subLogger,
+		eventBus:      eventBus,
+		repo:          subscriptionRepo,
+		customerRepo:  customerRepo,
+		addressRepo:   addressRepo,
+		priceRepo:     priceRepo,
+		productRepo:   productRepo,
+		stripeService: stripeService,
+	}
+}
+
+// AddBillingInterval advances t by count billing intervals (week, month or year)
+func AddBillingInterval(t time.Time, interval string, count int) time.Time {
+	if count < 1 {
+		count = 1
+	}
+
+	switch interval {
+	case "day":
+		return t.AddDate(0, 0, count)
+	case "week":
+		return t.AddDate(0, 0, 7*count)
+	case "year":
+		return t.AddDate(count, 0, 0)
+	default:
+		return t.AddDate(0, count, 0)
+	}
+}
+
+// ApplyStripeSubscription copies the billing state of a Stripe subscription onto our model
+func ApplyStripeSubscription(subscription *model.Subscription, stripeSub *stripeSDK.Subscription) {
+	if stripeSub.Status != "" {
+		subscription.Status = string(stripeSub.Status)
+	}
+	subscription.CancelAtPeriodEnd = stripeSub.CancelAtPeriodEnd
+
+	if stripeSub.CanceledAt > 0 {
+		canceledAt := time.Unix(stripeSub.CanceledAt, 0)
+		subscription.CanceledAt = &canceledAt
+	}
+
+	// Since API version 2025-03-31 the billing period lives on the subscription item
+	if stripeSub.Items != nil && len(stripeSub.Items.Data) > 0 {
+		item := stripeSub.Items.Data[0]
+		subscription.StripeItemID = item.ID
+		if item.Quantity > 0 {
+			subscription.Quantity = int(item.Quantity)
+		}
+		if item.CurrentPeriodStart > 0 {
+			subscription.CurrentPeriodStart = time.Unix(item.CurrentPeriodStart, 0)
+		}
+		if item.CurrentPeriodEnd > 0 {
+			subscription.CurrentPeriodEnd = time.Unix(item.CurrentPeriodEnd, 0)
+		}
+	}
+}
+
+// Create starts a new subscription in Stripe and records it locally
+func (s *subscriptionService) Create(ctx context.Context, d *dto.SubscriptionCreateDTO) (*model.Subscription, error) {
+	s.logger.Info().
+		Str("customer_id", d.CustomerID.String()).
+		Str("price_id", d.PriceID.String()).
+		Int("quantity", d.Quantity).
+		Msg("Creating subscription")
+
+	customer, err := s.customerRepo.GetByID(ctx, d.CustomerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve customer: %w", err)
+	}
+	if customer == nil || !customer.Active {
+		s.logger.Warn().Str("customer_id", d.CustomerID.String()).Msg("Customer not found or inactive")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	price, err := s.priceRepo.GetByID(ctx, d.PriceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price: %w", err)
+	}
+	if price == nil {
+		s.logger.Warn().Str("price_id", d.PriceID.String()).Msg("Price not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+	if !price.Active || price.Type != "recurring" {
+		s.logger.Warn().
+			Str("price_id", price.ID.String()).
+			Bool("active", price.Active).
+			Str("type", price.Type).
+			Msg("Price cannot be subscribed to")
+		return nil, fmt.Errorf("%w: price must be an active recurring price", ErrInvalidInput)
+	}
+
+	product, err := s.productRepo.GetByID(ctx, price.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product == nil || !product.AllowSubscription || product.Archived {
+		return nil, fmt.Errorf("%w: product does not allow subscriptions", ErrInvalidInput)
+	}
+
+	// Resolve the shipping address, falling back to the customer's default
+	var address *model.Address
+	if d.AddressID != nil {
+		address, err = s.addressRepo.GetByID(ctx, *d.AddressID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve address: %w", err)
+		}
+		if address == nil || address.CustomerID != customer.ID {
+			return nil, fmt.Errorf("%w: address does not belong to customer", ErrInvalidInput)
+		}
+	} else {
+		address, err = s.addressRepo.GetDefault(ctx, customer.ID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve default address: %w", err)
+		}
+		if address == nil {
+			return nil, fmt.Errorf("%w: customer has no shipping address", ErrInvalidInput)
+		}
+	}
+
+	subscription := &model.Subscription{
+		ID:         uuid.New(),
+		CustomerID: customer.ID,
+		ProductID:  product.ID,
+		PriceID:    price.ID,
+		AddressID:  &address.ID,
+		Quantity:   d.Quantity,
+		Metadata:   map[string]string{},
+		CreatedAt:  time.Now(),
+		UpdatedAt:  time.Now(),
+	}
+
+	stripeSub, err := s.stripeService.CreateSubscription(customer.StripeID, price.StripeID, int64(d.Quantity), map[string]string{
+		"subscription_id": subscription.ID.String(),
+		"customer_id":     customer.ID.String(),
+		"product_id":      product.ID.String(),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", customer.ID.String()).
+			Msg("Failed to create Stripe subscription")
+		return nil, fmt.Errorf("failed to create Stripe subscription: %w", err)
+	}
+
+	subscription.StripeID = stripeSub.ID
+	ApplyStripeSubscription(subscription, stripeSub)
+	if subscription.CurrentPeriodStart.IsZero() {
+		subscription.CurrentPeriodStart = time.Now()
+	}
+	if subscription.CurrentPeriodEnd.IsZero() {
+		subscription.CurrentPeriodEnd = AddBillingInterval(subscription.CurrentPeriodStart, price.Interval, price.IntervalCount)
+	}
+
+	// The first bag ships when the first invoice is paid, the next one at renewal
+	subscription.NextDeliveryDate = subscription.CurrentPeriodEnd
+
+	err = s.repo.Create(ctx, subscription)
+	if errors.Is(err, postgres.ErrAlreadyExists) {
+		// The customer.subscription.created webhook got here first
+		subscription, err = s.adoptSyncedSubscription(ctx, stripeSub.ID, address.ID)
+	}
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("stripe_id", stripeSub.ID).
+			Msg("Failed to save subscription to database")
+		return nil, fmt.Errorf("failed to create subscription: %w", err)
+	}
+
+	if stripeSub.LatestInvoice != nil && stripeSub.LatestInvoice.Status == stripeSDK.InvoiceStatusOpen {
+		subscription.HostedInvoiceURL = stripeSub.LatestInvoice.HostedInvoiceURL
+		if stripeSub.LatestInvoice.ConfirmationSecret != nil {
+			subscription.PaymentClientSecret = stripeSub.LatestInvoice.ConfirmationSecret.ClientSecret
+		}
+	}
+
+	payload := events.SubscriptionCreatedPayload{
+		SubscriptionID:   subscription.ID.String(),
+		CustomerID:       subscription.CustomerID.String(),
+		ProductID:        subscription.ProductID.String(),
+		PriceID:          subscription.PriceID.String(),
+		AddressID:        address.ID.String(),
+		StripeID:         subscription.StripeID,
+		Quantity:         subscription.Quantity,
+		Status:           subscription.Status,
+		NextDeliveryDate: subscription.NextDeliveryDate,
+		CreatedAt:        subscription.CreatedAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicSubscriptionCreated, payload); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to publish subscription created event")
+		// Don't return error since the subscription was already saved to DB
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicSubscriptionCreated).
+		Str("subscription_id", subscription.ID.String()).
+		Str("stripe_id", subscription.StripeID).
+		Str("status", subscription.Status).
+		Msg("Published subscription created event")
+
+	return subscription, nil
+}
+
+// adoptSyncedSubscription loads the row the Stripe webhook recorded for a
+// subscription we just created and applies the address the customer chose
+func (s *subscriptionService) adoptSyncedSubscription(ctx context.Context, stripeID string, addressID uuid.UUID) (*model.Subscription, error) {
+	subscription, err := s.repo.GetByStripeID(ctx, stripeID)
+	if err != nil {
+		return nil, err
+	}
+	if subscription == nil {
+		return nil, fmt.Errorf("subscription %s conflicts with an existing row", stripeID)
+	}
+
+	s.logger.Info().
+		Str("subscription_id", subscription.ID.String()).
+		Str("stripe_id", stripeID).
+		Msg("Subscription already recorded from Stripe webhook")
+
+	subscription.AddressID = &addressID
+	subscription.UpdatedAt = time.Now()
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, err
+	}
+
+	return subscription, nil
+}
+
+// get loads a subscription or returns ErrResourceNotFound
+func (s *subscriptionService) get(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	subscription, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", id.String()).
+			Msg("Failed to retrieve subscription")
+		return nil, fmt.Errorf("failed to retrieve subscription: %w", err)
+	}
+
+	if subscription == nil {
+		s.logger.Warn().
+			Str("subscription_id", id.String()).
+			Msg("Subscription not found")
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return subscription, nil
+}
+
+// GetByID retrieves a subscription together with its product and price details
+func (s *subscriptionService) GetByID(ctx context.Context, id uuid.UUID) (*model.SubscriptionWithDetails, error) {
+	subscription, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	details := &model.SubscriptionWithDetails{Subscription: *subscription}
+
+	if product, err := s.productRepo.GetByID(ctx, subscription.ProductID); err == nil && product != nil {
+		details.ProductName = product.Name
+		details.ProductImage = product.ImageURL
+	} else {
+		s.logger.Warn().Err(err).
+			Str("product_id", subscription.ProductID.String()).
+			Msg("Failed to load product for subscription details")
+	}
+
+	if price, err := s.priceRepo.GetByID(ctx, subscription.PriceID); err == nil && price != nil {
+		details.PriceName = price.Name
+		details.Interval = price.Interval
+		details.IntervalCount = price.IntervalCount
+		details.Amount = price.Amount
+		details.Currency = price.Currency
+	} else {
+		s.logger.Warn().Err(err).
+			Str("price_id", subscription.PriceID.String()).
+			Msg("Failed to load price for subscription details")
+	}
+
+	return details, nil
+}
+
+// List retrieves subscriptions with pagination
+func (s *subscriptionService) List(ctx context.Context, offset, limit int, customerID *uuid.UUID, status string) ([]*model.Subscription, int, error) {
+	s.logger.Debug().
+		Str("function", "subscriptionService.List").
+		Int("offset", offset).
+		Int("limit", limit).
+		Str("status", status).
+		Msg("Starting subscription listing")
+
+	subscriptions, total, err := s.repo.List(ctx, offset, limit, customerID, status)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("function", "subscriptionService.List").
+			Msg("Failed to retrieve subscriptions from repository")
+		return nil, 0, fmt.Errorf("failed to list subscriptions: %w", err)
+	}
+
+	return subscriptions, total, nil
+}
+
+// Pause stops deliveries and payment collection until the subscription is resumed
+func (s *subscriptionService) Pause(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	s.logger.Info().
+		Str("subscription_id", id.String()).
+		Msg("Pausing subscription")
+
+	subscription, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status == model.SubscriptionStatusPaused {
+		return subscription, nil // Already paused, nothing to do
+	}
+
+	if subscription.Status != model.SubscriptionStatusActive && subscription.Status != model.SubscriptionStatusTrialing {
+		s.logger.Warn().
+			Str("subscription_id", id.String()).
+			Str("status", subscription.Status).
+			Msg("Subscription cannot be paused in its current status")
+		return nil, fmt.Errorf("%w: cannot pause a %s subscription", ErrInvalidState, subscription.Status)
+	}
+
+	if _, err := s.stripeService.PauseSubscription(subscription.StripeID, time.Time{}); err != nil {
+		return nil, fmt.Errorf("failed to pause Stripe subscription: %w", err)
+	}
+
+	subscription.Status = model.SubscriptionStatusPaused
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	payload := events.SubscriptionPausedPayload{
+		SubscriptionID: subscription.ID.String(),
+		CustomerID:     subscription.CustomerID.String(),
+		StripeID:       subscription.StripeID,
+		Reason:         "customer_request",
+		PausedAt:       subscription.UpdatedAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicSubscriptionPaused, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", id.String()).
+			Msg("Failed to publish subscription paused event")
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicSubscriptionPaused).
+		Str("subscription_id", id.String()).
+		Msg("Subscription paused")
+
+	return subscription, nil
+}
+
+// Resume restarts a paused subscription. Deliveries that fell due while paused are not made up
+func (s *subscriptionService) Resume(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	s.logger.Info().
+		Str("subscription_id", id.String()).
+		Msg("Resuming subscription")
+
+	subscription, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status != model.SubscriptionStatusPaused {
+		s.logger.Warn().
+			Str("subscription_id", id.String()).
+			Str("status", subscription.Status).
+			Msg("Only paused subscriptions can be resumed")
+		return nil, fmt.Errorf("%w: cannot resume a %s subscription", ErrInvalidState, subscription.Status)
+	}
+
+	stripeSub, err := s.stripeService.ResumeSubscription(subscription.StripeID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resume Stripe subscription: %w", err)
+	}
+
+	ApplyStripeSubscription(subscription, stripeSub)
+	subscription.Status = model.SubscriptionStatusActive
+
+	price, err := s.priceRepo.GetByID(ctx, subscription.PriceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price: %w", err)
+	}
+
+	// Roll the delivery date forward past anything missed while paused
+	if price != nil {
+		now := time.Now()
+		for subscription.NextDeliveryDate.Before(now) {
+			subscription.NextDeliveryDate = AddBillingInterval(subscription.NextDeliveryDate, price.Interval, price.IntervalCount)
+		}
+	}
+
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	payload := events.SubscriptionResumedPayload{
+		SubscriptionID:   subscription.ID.String(),
+		CustomerID:       subscription.CustomerID.String(),
+		StripeID:         subscription.StripeID,
+		NextDeliveryDate: subscription.NextDeliveryDate,
+		ResumedAt:        subscription.UpdatedAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicSubscriptionResumed, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", id.String()).
+			Msg("Failed to publish subscription resumed event")
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicSubscriptionResumed).
+		Str("subscription_id", id.String()).
+		Time("next_delivery_date", subscription.NextDeliveryDate).
+		Msg("Subscription resumed")
+
+	return subscription, nil
+}
+
+// SkipNextDelivery moves the next delivery back by one billing interval. The
+// renewal invoice for the skipped delivery is voided by pausing collection
+// in Stripe until just after it would have been raised
+func (s *subscriptionService) SkipNextDelivery(ctx context.Context, id uuid.UUID) (*model.Subscription, error) {
+	s.logger.Info().
+		Str("subscription_id", id.String()).
+		Msg("Skipping next delivery")
+
+	subscription, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status != model.SubscriptionStatusActive && subscription.Status != model.SubscriptionStatusTrialing {
+		return nil, fmt.Errorf("%w: cannot skip a delivery on a %s subscription", ErrInvalidState, subscription.Status)
+	}
+
+	if subscription.CancelAtPeriodEnd {
+		return nil, fmt.Errorf("%w: subscription is scheduled to cancel", ErrInvalidState)
+	}
+
+	price, err := s.priceRepo.GetByID(ctx, subscription.PriceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price: %w", err)
+	}
+	if price == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	skipped := subscription.NextDeliveryDate
+	if _, err := s.stripeService.PauseSubscription(subscription.StripeID, skipped.Add(time.Hour)); err != nil {
+		return nil, fmt.Errorf("failed to skip Stripe renewal: %w", err)
+	}
+
+	subscription.NextDeliveryDate = AddBillingInterval(skipped, price.Interval, price.IntervalCount)
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	payload := events.SubscriptionUpdatedPayload{
+		SubscriptionID:      subscription.ID.String(),
+		CustomerID:          subscription.CustomerID.String(),
+		StripeID:            subscription.StripeID,
+		Status:              subscription.Status,
+		NextDeliveryDate:    subscription.NextDeliveryDate,
+		Change:              "delivery_skipped",
+		SkippedDeliveryDate: &skipped,
+		UpdatedAt:           subscription.UpdatedAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicSubscriptionUpdated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", id.String()).
+			Msg("Failed to publish subscription updated event")
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicSubscriptionUpdated).
+		Str("subscription_id", id.String()).
+		Time("skipped_delivery_date", skipped).
+		Time("next_delivery_date", subscription.NextDeliveryDate).
+		Msg("Next delivery skipped")
+
+	return subscription, nil
+}
+
+// Cancel cancels a subscription immediately or at the end of the current period
+func (s *subscriptionService) Cancel(ctx context.Context, id uuid.UUID, d *dto.SubscriptionCancelDTO) (*model.Subscription, error) {
+	s.logger.Info().
+		Str("subscription_id", id.String()).
+		Bool("at_period_end", d.AtPeriodEnd).
+		Msg("Canceling subscription")
+
+	subscription, err := s.get(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if subscription.Status == model.SubscriptionStatusCanceled ||
+		subscription.Status == model.SubscriptionStatusIncompleteExpired {
+		return nil, fmt.Errorf("%w: subscription is already %s", ErrInvalidState, subscription.Status)
+	}
+
+	stripeSub, err := s.stripeService.CancelSubscription(subscription.StripeID, d.AtPeriodEnd)
+	if err != nil {
+		return nil, fmt.Errorf("failed to cancel Stripe subscription: %w", err)
+	}
+
+	ApplyStripeSubscription(subscription, stripeSub)
+	if !d.AtPeriodEnd {
+		subscription.Status = model.SubscriptionStatusCanceled
+	}
+	if subscription.CanceledAt == nil {
+		now := time.Now()
+		subscription.CanceledAt = &now
+	}
+	if d.Reason != "" {
+		subscription.Metadata["cancellation_reason"] = d.Reason
+	}
+
+	if err := s.repo.Update(ctx, subscription); err != nil {
+		return nil, fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	payload := events.SubscriptionCanceledPayload{
+		SubscriptionID:    subscription.ID.String(),
+		CustomerID:        subscription.CustomerID.String(),
+		StripeID:          subscription.StripeID,
+		CancelAtPeriodEnd: subscription.CancelAtPeriodEnd,
+		Reason:            d.Reason,
+		CanceledAt:        *subscription.CanceledAt,
+	}
+
+	if err := s.eventBus.Publish(events.TopicSubscriptionCanceled, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", id.String()).
+			Msg("Failed to publish subscription canceled event")
+	}
+
+	s.logger.Info().
+		Str("topic", events.TopicSubscriptionCanceled).
+		Str("subscription_id", id.String()).
+		Bool("cancel_at_period_end", subscription.CancelAtPeriodEnd).
+		Msg("Subscription canceled")
+
+	return subscription, nil
+}
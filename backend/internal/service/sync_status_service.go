@@ -0,0 +1,173 @@
+// internal/service/sync_status_service.go
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
+	"github.com/dukerupert/coffee-commerce/internal/domain/model"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
+	"github.com/dukerupert/coffee-commerce/internal/sync"
+	"github.com/google/uuid"
+	"github.com/rs/zerolog"
+	"github.com/stripe/stripe-go/v82"
+)
+
+// syncStatusService implements SyncStatusService
+type syncStatusService struct {
+	logger        zerolog.Logger
+	syncRepo      interfaces.SyncHashRepository
+	variantRepo   interfaces.VariantRepository
+	stripeService interfaces.StripeService
+}
+
+// NewSyncStatusService creates a new sync status service
+func NewSyncStatusService(
+	logger *zerolog.Logger,
+	syncRepo interfaces.SyncHashRepository,
+	variantRepo interfaces.VariantRepository,
+	stripeService interfaces.StripeService,
+) interfaces.SyncStatusService {
+	subLogger := logger.With().Str("component", "sync_status_service").Logger()
+	return &syncStatusService{
+		logger:        subLogger,
+		syncRepo:      syncRepo,
+		variantRepo:   variantRepo,
+		stripeService: stripeService,
+	}
+}
+
+// Status compares every variant's recorded hash with its Stripe product
+func (s *syncStatusService) Status(ctx context.Context, state string) (*dto.SyncStatusReport, error) {
+	switch state {
+	case "", dto.SyncStateInSync, dto.SyncStateDrifted, dto.SyncStateNeverSynced,
+		dto.SyncStateMissingInStripe, dto.SyncStateUnlinked:
+	default:
+		return nil, fmt.Errorf("%w: unknown sync state '%s'", ErrInvalidInput, state)
+	}
+
+	variantStates, err := s.syncRepo.ListVariantSyncStates(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list variant sync states: %w", err)
+	}
+
+	stripeProducts, err := s.stripeService.ListAllProducts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to list Stripe products: %w", err)
+	}
+	stripeProductsByID := make(map[string]*stripe.Product, len(stripeProducts))
+	for _, stripeProduct := range stripeProducts {
+		stripeProductsByID[stripeProduct.ID] = stripeProduct
+	}
+
+	now := time.Now()
+	report := &dto.SyncStatusReport{
+		GeneratedAt: now,
+		Summary:     make(map[string]int),
+		Variants:    []dto.VariantSyncStatus{},
+	}
+
+	for _, variantState := range variantStates {
+		status := dto.VariantSyncStatus{
+			VariantID:       variantState.VariantID.String(),
+			ProductID:       variantState.ProductID.String(),
+			ProductName:     variantState.ProductName,
+			Options:         variantState.Options,
+			Active:          variantState.Active,
+			StripeProductID: variantState.StripeProductID,
+		}
+		if hash := variantState.SyncHash; hash != nil {
+			syncedAt := hash.UpdatedAt
+			status.LastHash = hash.ContentHash
+			status.LastSyncSource = hash.SyncSource
+			status.LastSyncedAt = &syncedAt
+			status.AgeSeconds = int64(now.Sub(syncedAt).Seconds())
+		}
+
+		var stripeProduct *stripe.Product
+		if variantState.StripeProductID != "" {
+			stripeProduct = stripeProductsByID[variantState.StripeProductID]
+		}
+		status.State, status.CurrentHash = s.syncState(variantState.StripeProductID, stripeProduct, variantState.SyncHash)
+
+		report.Summary[status.State]++
+		if state == "" || status.State == state {
+			report.Variants = append(report.Variants, status)
+		}
+	}
+
+	return report, nil
+}
+
+// History returns a variant's hash timeline with its current sync state
+func (s *syncStatusService) History(ctx context.Context, variantID uuid.UUID, limit int) (*dto.SyncHistoryReport, error) {
+	variant, err := s.variantRepo.GetByID(ctx, variantID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	history, err := s.syncRepo.GetHashHistory(ctx, variantID, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sync hash history: %w", err)
+	}
+
+	report := &dto.SyncHistoryReport{
+		VariantID:       variant.ID.String(),
+		StripeProductID: variant.StripeProductID,
+		History:         history,
+	}
+
+	var stripeProduct *stripe.Product
+	var recorded *model.SyncHash
+	if variant.StripeProductID != "" {
+		stripeProduct, err = s.stripeService.GetProduct(variant.StripeProductID)
+		if err != nil {
+			var stripeErr *stripe.Error
+			if !errors.As(err, &stripeErr) || stripeErr.Code != stripe.ErrorCodeResourceMissing {
+				return nil, fmt.Errorf("failed to retrieve Stripe product: %w", err)
+			}
+			stripeProduct = nil
+		}
+
+		recorded, err = s.syncRepo.GetByVariantAndStripeID(ctx, variant.ID, variant.StripeProductID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to retrieve sync hash: %w", err)
+		}
+	}
+	report.State, report.CurrentHash = s.syncState(variant.StripeProductID, stripeProduct, recorded)
+
+	return report, nil
+}
+
+// syncState classifies a variant from its Stripe product and recorded hash,
+// returning the Stripe product's current hash when it exists
+func (s *syncStatusService) syncState(stripeProductID string, stripeProduct *stripe.Product, recorded *model.SyncHash) (string, string) {
+	if stripeProductID == "" {
+		return dto.SyncStateUnlinked, ""
+	}
+	if stripeProduct == nil {
+		return dto.SyncStateMissingInStripe, ""
+	}
+
+	currentHash, err := sync.ComputeStripeProductHash(*stripeProduct)
+	if err != nil {
+		s.logger.Error().Err(err).Str("stripe_product_id", stripeProductID).Msg("Failed to hash Stripe product")
+		return dto.SyncStateDrifted, ""
+	}
+
+	switch {
+	case recorded == nil:
+		return dto.SyncStateNeverSynced, currentHash
+	case recorded.ContentHash != currentHash:
+		return dto.SyncStateDrifted, currentHash
+	default:
+		return dto.SyncStateInSync, currentHash
+	}
+}
@@ -3,18 +3,20 @@ package service
 
 import (
 	"context"
-	"encoding/json"
 	"errors"
 	"fmt"
 	"math"
+	"sort"
 	"strconv"
 	"strings"
 	"time"
 	"unicode"
 
+	"github.com/dukerupert/coffee-commerce/internal/domain/dto"
 	"github.com/dukerupert/coffee-commerce/internal/domain/model"
 	"github.com/dukerupert/coffee-commerce/internal/events"
 	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/dukerupert/coffee-commerce/internal/repository/postgres"
 	"github.com/google/uuid"
 	"github.com/rs/zerolog"
 	stripeSDK "github.com/stripe/stripe-go/v82"
@@ -22,78 +24,66 @@ import (
 
 // variantService implements VariantService
 type variantService struct {
-	logger        zerolog.Logger
-	eventBus      events.EventBus
-	variantRepo   interfaces.VariantRepository
-	productRepo   interfaces.ProductRepository
-	priceRepo     interfaces.PriceRepository
-	stripeService interfaces.StripeService
+	logger          zerolog.Logger
+	eventBus        events.EventBus
+	variantRepo     interfaces.VariantRepository
+	productRepo     interfaces.ProductRepository
+	priceRepo       interfaces.PriceRepository
+	reservationRepo interfaces.StockReservationRepository
+	ruleRepo        interfaces.PricingRuleRepository
+	stripeService   interfaces.StripeService
 }
 
 // NewVariantService creates a new variant service and subscribes to relevant events
-func NewVariantService(logger *zerolog.Logger, eventBus events.EventBus, variantRepo interfaces.VariantRepository, productRepo interfaces.ProductRepository, priceRepo interfaces.PriceRepository, stripeService interfaces.StripeService) (interfaces.VariantService, error) {
+func NewVariantService(logger *zerolog.Logger, eventBus events.EventBus, variantRepo interfaces.VariantRepository, productRepo interfaces.ProductRepository, priceRepo interfaces.PriceRepository, reservationRepo interfaces.StockReservationRepository, ruleRepo interfaces.PricingRuleRepository, stripeService interfaces.StripeService) (interfaces.VariantService, error) {
 	subLogger := logger.With().Str("component", "variant_service").Logger()
 
 	s := &variantService{
-		logger:        subLogger,
-		eventBus:      eventBus,
-		variantRepo:   variantRepo,
-		productRepo:   productRepo,
-		priceRepo:     priceRepo,
-		stripeService: stripeService,
+		logger:          subLogger,
+		eventBus:        eventBus,
+		variantRepo:     variantRepo,
+		productRepo:     productRepo,
+		priceRepo:       priceRepo,
+		reservationRepo: reservationRepo,
+		ruleRepo:        ruleRepo,
+		stripeService:   stripeService,
 	}
 
-	// Subscribe to product created events
-	_, err := eventBus.Subscribe(events.TopicProductCreated, s.handleProductCreated)
+	// Product and variant events are consumed durably so a Stripe outage
+	// delays variant creation instead of losing it
+	_, err := events.SubscribeDurable(eventBus, events.TopicProductCreated, "variant-service-product-created", s.handleProductCreated)
 	if err != nil {
 		subLogger.Error().Err(err).Msg("Failed to subscribe to product created events")
 		return nil, err
 	}
 	subLogger.Info().Str("topic", events.TopicProductCreated).Msg("Subscribed to product created events")
 
-	// Subscribe to product updated events
-	_, err = eventBus.Subscribe(events.TopicProductUpdated, s.handleProductUpdated)
+	_, err = events.SubscribeDurable(eventBus, events.TopicProductUpdated, "variant-service-product-updated", s.handleProductUpdated)
 	if err != nil {
 		subLogger.Error().Err(err).Msg("Failed to subscribe to product updated events")
 		return nil, err
 	}
-
 	subLogger.Info().Str("topic", events.TopicProductUpdated).Msg("Subscribed to product updated events")
 
-	// Subscribe to variant created events
-	_, err = eventBus.Subscribe(events.TopicVariantQueued, s.handleVariantQueued)
+	_, err = events.SubscribeDurable(eventBus, events.TopicVariantQueued, "variant-service-variant-queued", s.handleVariantQueued)
 	if err != nil {
 		subLogger.Error().Err(err).Msg("Failed to subscribe to variant queued events")
 		return nil, err
 	}
-	subLogger.Info().Str("topic", events.TopicProductCreated).Msg("Subscribed to variant queued events")
+	subLogger.Info().Str("topic", events.TopicVariantQueued).Msg("Subscribed to variant queued events")
 
 	return s, nil
 }
 
 // handleProductCreated is called when a product created event is received
-func (s *variantService) handleProductCreated(data []byte) {
-	s.logger.Info().Str("topic", events.TopicProductCreated).Msg("Received product created event")
-
-	// Parse the event
-	var event events.Event
-	if err := json.Unmarshal(data, &event); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to unmarshal product created event")
-		return
-	}
-
-	// Unmarshal the payload to get product details
-	var payload events.ProductCreatedPayload
-	payloadData, err := json.Marshal(event.Payload)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to marshal payload for unmarshaling")
-		return
-	}
+func (s *variantService) handleProductCreated(event events.TypedEvent[events.ProductCreatedPayload]) error {
+	s.logger.Info().
+		Str("topic", events.TopicProductCreated).
+		Str("event_id", event.ID).
+		Str("correlation_id", event.CorrelationID).
+		Msg("Received product created event")
 
-	if err := json.Unmarshal(payloadData, &payload); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to unmarshal product created payload")
-		return
-	}
+	payload := event.Payload
 
 	// Log the received product details
 	s.logger.Debug().
@@ -109,13 +99,13 @@ func (s *variantService) handleProductCreated(data []byte) {
 			Msg("Product has no options, creating single price")
 
 		// Create a single price for the product via Stripe
-		err := s.createDefaultVariant(payload.ProductID)
+		err := s.createDefaultVariant(payload.ProductID, event.Metadata)
 		if err != nil {
 			s.logger.Error().Err(err).
 				Str("product_id", payload.ProductID).
 				Msg("Failed to create default price for product")
 		}
-		return
+		return err
 	}
 
 	// Product has options, need to create variants for all combinations
@@ -150,13 +140,13 @@ func (s *variantService) handleProductCreated(data []byte) {
 			Msg("No valid options found, creating single price")
 
 		// Create a single price for the product via Stripe
-		err := s.createDefaultVariant(payload.ProductID)
+		err := s.createDefaultVariant(payload.ProductID, event.Metadata)
 		if err != nil {
 			s.logger.Error().Err(err).
 				Str("product_id", payload.ProductID).
 				Msg("Failed to create default price for product")
 		}
-		return
+		return err
 	}
 
 	// Generate all combinations of options
@@ -169,13 +159,13 @@ func (s *variantService) handleProductCreated(data []byte) {
 		Int("total_combinations", len(combinations)).
 		Msg("Found product options - will create variants for all combinations")
 
-		// Save these combinations for later variant creation once prices are available
-		// For now, just queue them for processing
-	s.queueVariantCreation(payload.ProductID, optionKeys, combinations, payload)
+	// Save these combinations for later variant creation once prices are available
+	// For now, just queue them for processing
+	return s.queueVariantCreation(payload.ProductID, optionKeys, combinations, payload, event.Metadata)
 }
 
 // createDefaultVariant creates a default price and variant for a product without options
-func (s *variantService) createDefaultVariant(productID string) error {
+func (s *variantService) createDefaultVariant(productID string, cause events.Metadata) error {
 	ctx := context.Background()
 
 	// Parse product ID
@@ -213,9 +203,7 @@ func (s *variantService) createDefaultVariant(productID string) error {
 		Msg("Creating default variant through queue")
 
 	// Queue the variant creation which will sync with Stripe
-	s.queueVariantCreation(productID, optionKeys, combinations, payload)
-
-	return nil
+	return s.queueVariantCreation(productID, optionKeys, combinations, payload, cause)
 }
 
 // generateOptionCombinations generates all possible combinations of option values
@@ -255,28 +243,14 @@ func (s *variantService) generateOptionCombinations(optionSets [][]string) [][]s
 }
 
 // handleProductUpdated is called when a product updated event is received
-func (s *variantService) handleProductUpdated(data []byte) {
-	s.logger.Info().Str("topic", events.TopicProductUpdated).Msg("Received product updated event")
-
-	// Parse the event
-	var event events.Event
-	if err := json.Unmarshal(data, &event); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to unmarshal product updated event")
-		return
-	}
-
-	// Unmarshal the payload to get product details
-	var payload events.ProductUpdatedPayload
-	payloadData, err := json.Marshal(event.Payload)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to marshal payload for unmarshaling")
-		return
-	}
+func (s *variantService) handleProductUpdated(event events.TypedEvent[events.ProductUpdatedPayload]) error {
+	s.logger.Info().
+		Str("topic", events.TopicProductUpdated).
+		Str("event_id", event.ID).
+		Str("correlation_id", event.CorrelationID).
+		Msg("Received product updated event")
 
-	if err := json.Unmarshal(payloadData, &payload); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to unmarshal product updated payload")
-		return
-	}
+	payload := event.Payload
 
 	// Log the received product details
 	s.logger.Debug().
@@ -288,112 +262,223 @@ func (s *variantService) handleProductUpdated(data []byte) {
 		Bool("should_create_variants", payload.ShouldCreateVariants).
 		Msg("Processing product updated event")
 
-	// Only process if we should create variants
-	if !payload.ShouldCreateVariants {
+	// Only process if the options may have changed
+	if !payload.ShouldCreateVariants && !payload.OptionsChanged {
 		s.logger.Debug().
 			Str("product_id", payload.ProductID).
-			Msg("Product update does not require variant creation")
-		return
+			Msg("Product update does not require variant changes")
+		return nil
 	}
 
-	// Check if product has options
-	if payload.Options == nil || len(payload.Options) == 0 {
-		s.logger.Info().Str("product_id", payload.ProductID).
-			Msg("Product has no options after update, creating single default variant")
+	productID, err := uuid.Parse(payload.ProductID)
+	if err != nil {
+		s.logger.Error().Err(err).Str("product_id", payload.ProductID).Msg("Invalid product ID in product updated event")
+		return fmt.Errorf("%w: %v", events.ErrNoRetry, err)
+	}
 
-		// Create a single default variant
-		err := s.createDefaultVariant(payload.ProductID)
-		if err != nil {
-			s.logger.Error().Err(err).
-				Str("product_id", payload.ProductID).
-				Msg("Failed to create default variant for updated product")
+	// Regeneration only touches combinations that changed, so repeats are harmless
+	if _, err := s.RegenerateVariants(context.Background(), productID, false); err != nil {
+		s.logger.Error().Err(err).
+			Str("product_id", payload.ProductID).
+			Msg("Failed to regenerate variants for updated product")
+		return err
+	}
+
+	return nil
+}
+
+// RegenerateVariants compares a product's variants with every combination of
+// its options. Matching variants are kept (and reactivated), missing
+// combinations are created with their Stripe products and prices, and active
+// variants left over are deactivated with their Stripe products archived.
+// Variants are never deleted because orders keep referring to them
+func (s *variantService) RegenerateVariants(ctx context.Context, productID uuid.UUID, dryRun bool) (*dto.VariantRegenerationPlan, error) {
+	product, err := s.productRepo.GetByID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	existing, err := s.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product variants: %w", err)
+	}
+
+	plan := s.planRegeneration(product, existing)
+	if dryRun {
+		return plan, nil
+	}
+
+	for _, variant := range plan.ToKeep {
+		if !variant.Active {
+			if err := s.setVariantActive(ctx, variant, true); err != nil {
+				return nil, err
+			}
 		}
-		return
 	}
 
-	// Product has options, need to create variants for all combinations
-	// Collect all option sets
-	optionSets := make([][]string, 0, len(payload.Options))
-	optionKeys := make([]string, 0, len(payload.Options))
+	for _, variant := range plan.ToDeactivate {
+		if err := s.setVariantActive(ctx, variant, false); err != nil {
+			return nil, err
+		}
+	}
 
-	// Log all option keys and values
-	for key, values := range payload.Options {
-		if len(values) == 0 {
-			s.logger.Warn().
-				Str("product_id", payload.ProductID).
-				Str("option_key", key).
-				Msg("Option key has no values, skipping")
-			continue
+	rule, err := s.ruleRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve pricing rule: %w", err)
+	}
+
+	for _, optionValues := range plan.ToCreate {
+		payload := newVariantQueuedPayload(product.ID.String(), product.Name, product.Description, product.ImageURL, optionValues, rule)
+		if _, err := s.createQueuedVariant(payload); err != nil {
+			return nil, fmt.Errorf("failed to create variant for %v: %w", optionValues, err)
 		}
+	}
 
-		s.logger.Debug().
-			Str("product_id", payload.ProductID).
-			Str("option_key", key).
-			Strs("option_values", values).
-			Msg("Processing option set from updated product")
+	plan.Applied = true
 
-		optionSets = append(optionSets, values)
-		optionKeys = append(optionKeys, key)
+	s.logger.Info().
+		Str("product_id", productID.String()).
+		Int("created", len(plan.ToCreate)).
+		Int("kept", len(plan.ToKeep)).
+		Int("deactivated", len(plan.ToDeactivate)).
+		Msg("Regenerated product variants")
+
+	return plan, nil
+}
+
+// planRegeneration matches existing variants against the product's option combinations
+func (s *variantService) planRegeneration(product *model.Product, existing []*model.Variant) *dto.VariantRegenerationPlan {
+	optionKeys := make([]string, 0, len(product.Options))
+	for key, values := range product.Options {
+		if len(values) > 0 {
+			optionKeys = append(optionKeys, key)
+		}
 	}
+	sort.Strings(optionKeys)
 
-	// Check if we have any valid option sets
-	if len(optionSets) == 0 {
-		s.logger.Info().
-			Str("product_id", payload.ProductID).
-			Msg("No valid options found in updated product, creating single default variant")
+	optionSets := make([][]string, len(optionKeys))
+	for i, key := range optionKeys {
+		optionSets[i] = product.Options[key]
+	}
 
-		// Create a single default variant
-		err := s.createDefaultVariant(payload.ProductID)
-		if err != nil {
-			s.logger.Error().Err(err).
-				Str("product_id", payload.ProductID).
-				Msg("Failed to create default variant for updated product")
+	// A product without options sells a single default variant
+	combinations := [][]string{{}}
+	if len(optionSets) > 0 {
+		combinations = s.generateOptionCombinations(optionSets)
+	}
+
+	// Prefer an active variant when several share the same options
+	byOptions := make(map[string]*model.Variant, len(existing))
+	for _, variant := range existing {
+		key := optionsKey(variant.Options)
+		if current, ok := byOptions[key]; !ok || (!current.Active && variant.Active) {
+			byOptions[key] = variant
 		}
-		return
 	}
 
-	// Generate all combinations of options
-	combinations := s.generateOptionCombinations(optionSets)
+	plan := &dto.VariantRegenerationPlan{
+		ProductID:    product.ID,
+		ToCreate:     make([]map[string]string, 0),
+		ToKeep:       make([]*model.Variant, 0),
+		ToDeactivate: make([]*model.Variant, 0),
+	}
 
-	// Log the number of variants to be created
-	s.logger.Info().
-		Str("product_id", payload.ProductID).
-		Int("option_sets", len(optionSets)).
-		Int("total_combinations", len(combinations)).
-		Msg("Updated product has options - will create variants for all combinations")
-
-	// Convert the ProductUpdatedPayload to a ProductCreatedPayload format for reuse
-	createdPayload := events.ProductCreatedPayload{
-		ProductID:         payload.ProductID,
-		Name:              payload.Name,
-		Description:       payload.Description,
-		ImageURL:          payload.ImageURL,
-		StockLevel:        payload.StockLevel,
-		Weight:            payload.Weight,
-		Origin:            payload.Origin,
-		RoastLevel:        payload.RoastLevel,
-		FlavorNotes:       payload.FlavorNotes,
-		Options:           payload.Options,
-		AllowSubscription: payload.AllowSubscription,
-		Active:            payload.Active,
-		CreatedAt:         payload.UpdatedAt, // Use updated time as created time for variants
-	}
-
-	// Queue variant creation
-	s.queueVariantCreation(payload.ProductID, optionKeys, combinations, createdPayload)
-}
-
-// queueVariantCreation publishes events for each variant combination to be created
-func (s *variantService) queueVariantCreation(productID string, optionKeys []string, combinations [][]string, payload events.ProductCreatedPayload) {
+	kept := make(map[uuid.UUID]bool)
+	for _, combination := range combinations {
+		optionValues := make(map[string]string, len(combination))
+		for i, value := range combination {
+			optionValues[optionKeys[i]] = value
+		}
+
+		if variant, ok := byOptions[optionsKey(optionValues)]; ok {
+			plan.ToKeep = append(plan.ToKeep, variant)
+			kept[variant.ID] = true
+			continue
+		}
+		plan.ToCreate = append(plan.ToCreate, optionValues)
+	}
+
+	for _, variant := range existing {
+		if variant.Active && !kept[variant.ID] {
+			plan.ToDeactivate = append(plan.ToDeactivate, variant)
+		}
+	}
+
+	return plan
+}
+
+// setVariantActive activates or deactivates a variant. The catalog sync worker
+// updates its Stripe product from the published event
+func (s *variantService) setVariantActive(ctx context.Context, variant *model.Variant, active bool) error {
+	variant.Active = active
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		return fmt.Errorf("failed to update variant %s: %w", variant.ID, err)
+	}
+
+	payload := events.VariantUpdatedPayload{
+		VariantID:       variant.ID.String(),
+		ProductID:       variant.ProductID.String(),
+		PriceID:         variant.PriceID.String(),
+		StripeProductID: variant.StripeProductID,
+		StripePriceID:   variant.StripePriceID,
+		Active:          variant.Active,
+		OptionValues:    variant.Options,
+		UpdatedAt:       variant.UpdatedAt,
+		UpdateSource:    model.SyncSourceSystemSync,
+	}
+	if err := s.eventBus.Publish(events.TopicVariantUpdated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", variant.ID.String()).
+			Msg("Failed to publish variant updated event")
+	}
+
+	return nil
+}
+
+// optionsKey builds a canonical key for a set of option values
+func optionsKey(options map[string]string) string {
+	keys := make([]string, 0, len(options))
+	for key := range options {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	var b strings.Builder
+	for _, key := range keys {
+		b.WriteString(key)
+		b.WriteByte('=')
+		b.WriteString(options[key])
+		b.WriteByte(';')
+	}
+	return b.String()
+}
+
+// queueVariantCreation publishes a variants.queued event per combination. It
+// fails if any event couldn't be published so the product event is redelivered;
+// combinations that already have a variant are skipped when processed. The
+// queued events are recorded as caused by the product event
+func (s *variantService) queueVariantCreation(productID string, optionKeys []string, combinations [][]string, payload events.ProductCreatedPayload, cause events.Metadata) error {
 	s.logger.Info().
 		Str("product_id", productID).
 		Strs("option_keys", optionKeys).
 		Int("combinations", len(combinations)).
 		Msg("Publishing variant creation events to NATS")
 
-	// Get a default price in cents (can be updated later)
-	defaultPrice := int64(1000) // $10.00 by default
-	defaultCurrency := "USD"
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return fmt.Errorf("%w: invalid product ID: %v", events.ErrNoRetry, err)
+	}
+
+	// Without a pricing rule variants are queued at the default price
+	rule, err := s.ruleRepo.GetByProductID(context.Background(), id)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve pricing rule: %w", err)
+	}
+
+	failed := 0
 
 	// For each combination, create a payload and publish an event
 	for i, combination := range combinations {
@@ -405,32 +490,20 @@ func (s *variantService) queueVariantCreation(productID string, optionKeys []str
 			}
 		}
 
-		// Create a variant name that includes the options
-		variantName := payload.Name
-		for key, value := range optionValues {
-			variantName += " - " + key + ": " + value
-		}
-
-		// Create the variant creation payload
-		variantPayload := events.VariantQueuedPayload{
-			ProductID:    productID,
-			ProductName:  variantName,
-			Description:  payload.Description,
-			ImageURL:     payload.ImageURL,
-			OptionValues: optionValues,
-			DefaultPrice: defaultPrice,
-			Currency:     defaultCurrency,
-			QueuedAt:     time.Now(),
-		}
+		variantPayload := newVariantQueuedPayload(productID, payload.Name, payload.Description, payload.ImageURL, optionValues, rule)
 
 		// Publish the event
-		err := s.eventBus.Publish(events.TopicVariantQueued, variantPayload)
+		event, err := events.NewEvent(events.TopicVariantQueued, variantPayload)
+		if err == nil {
+			err = s.eventBus.PublishEvent(event.CausedBy(cause))
+		}
 		if err != nil {
 			s.logger.Error().Err(err).
 				Str("product_id", productID).
 				Int("combination_index", i).
 				Interface("option_values", optionValues).
 				Msg("Failed to publish variant queued event")
+			failed++
 		} else {
 			s.logger.Debug().
 				Str("product_id", productID).
@@ -444,44 +517,78 @@ func (s *variantService) queueVariantCreation(productID string, optionKeys []str
 		Str("product_id", productID).
 		Int("total_variants", len(combinations)).
 		Msg("Completed publishing variant creation events")
-}
 
-// handleVariantQueued processes events for variants that need Stripe products and prices
-func (s *variantService) handleVariantQueued(data []byte) {
-	// Parse the event
-	var event events.Event
-	if err := json.Unmarshal(data, &event); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to unmarshal variant queued event")
-		return
+	if failed > 0 {
+		return fmt.Errorf("failed to publish %d of %d variant queued events", failed, len(combinations))
 	}
+	return nil
+}
 
-	// Unmarshal the payload
-	var payload events.VariantQueuedPayload
-	payloadData, err := json.Marshal(event.Payload)
-	if err != nil {
-		s.logger.Error().Err(err).Msg("Failed to marshal payload for unmarshaling")
-		return
+// newVariantQueuedPayload builds the creation payload for one option combination
+// with its price computed from the product's pricing rule, if any
+func newVariantQueuedPayload(productID, productName, description, imageURL string, optionValues map[string]string, rule *model.PricingRule) events.VariantQueuedPayload {
+	amount, currency := variantPrice(rule, optionValues)
+
+	// Create a variant name that includes the options
+	variantName := productName
+	for key, value := range optionValues {
+		variantName += " - " + key + ": " + value
 	}
 
-	if err := json.Unmarshal(payloadData, &payload); err != nil {
-		s.logger.Error().Err(err).Msg("Failed to unmarshal variant queued payload")
-		return
+	return events.VariantQueuedPayload{
+		ProductID:    productID,
+		ProductName:  variantName,
+		Description:  description,
+		ImageURL:     imageURL,
+		OptionValues: optionValues,
+		DefaultPrice: amount,
+		Currency:     currency,
+		QueuedAt:     time.Now(),
 	}
+}
 
+// handleVariantQueued processes events for variants that need Stripe products and prices
+func (s *variantService) handleVariantQueued(event events.TypedEvent[events.VariantQueuedPayload]) error {
+	s.logger.Debug().
+		Str("event_id", event.ID).
+		Str("correlation_id", event.CorrelationID).
+		Msg("Received variant queued event")
+
+	// Failures are logged by createQueuedVariant; returning them redelivers the event
+	_, err := s.createQueuedVariant(event.Payload)
+	return err
+}
+
+// createQueuedVariant creates the Stripe product and price for a queued
+// variant, saves it and publishes variants.created
+func (s *variantService) createQueuedVariant(payload events.VariantQueuedPayload) (*model.Variant, error) {
 	s.logger.Info().
 		Str("product_id", payload.ProductID).
 		Str("variant_name", payload.ProductName).
 		Interface("option_values", payload.OptionValues).
 		Msg("Processing variant creation with Stripe integration")
 
-		// Create a Stripe product for this variant
+	// Queued events can be redelivered, so skip combinations that already have a variant
+	existing, err := s.findVariantByOptions(payload.ProductID, payload.OptionValues)
+	if err != nil {
+		return nil, err
+	}
+	if existing != nil {
+		s.logger.Info().
+			Str("variant_id", existing.ID.String()).
+			Str("product_id", payload.ProductID).
+			Msg("Variant already exists for option combination, skipping")
+		return existing, nil
+	}
+
+	// Create a Stripe product for this variant
 	stripeProduct, err := s.createStripeProduct(payload)
 	if err != nil {
 		s.logger.Error().Err(err).
 			Str("product_id", payload.ProductID).
 			Interface("option_values", payload.OptionValues).
 			Msg("Failed to create Stripe product, will retry later")
-		return
+		return nil, err
 	}
 
 	// Extract the Stripe product ID
@@ -494,7 +601,7 @@ func (s *variantService) handleVariantQueued(data []byte) {
 			Str("product_id", payload.ProductID).
 			Str("stripe_product_id", stripeProductID).
 			Msg("Failed to create Stripe price, will retry later")
-		return
+		return nil, err
 	}
 
 	// Extract the Stripe price ID
@@ -508,7 +615,7 @@ func (s *variantService) handleVariantQueued(data []byte) {
 			Str("stripe_product_id", stripeProductID).
 			Str("stripe_price_id", stripePriceID).
 			Msg("Failed to create variant in database")
-		return
+		return nil, err
 	}
 
 	// Publish an event that the variant was created
@@ -541,6 +648,30 @@ func (s *variantService) handleVariantQueued(data []byte) {
 		Str("stripe_product_id", stripeProductID).
 		Str("stripe_price_id", stripePriceID).
 		Msg("Successfully created variant with Stripe integration")
+
+	return variant, nil
+}
+
+// findVariantByOptions returns the product's variant with exactly these option values, if any
+func (s *variantService) findVariantByOptions(productID string, optionValues map[string]string) (*model.Variant, error) {
+	id, err := uuid.Parse(productID)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid product ID: %v", events.ErrNoRetry, err)
+	}
+
+	variants, err := s.variantRepo.GetByProductID(context.Background(), id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product variants: %w", err)
+	}
+
+	key := optionsKey(optionValues)
+	for _, variant := range variants {
+		if optionsKey(variant.Options) == key {
+			return variant, nil
+		}
+	}
+
+	return nil, nil
 }
 
 // Helper function to safely get option values with a default fallback
@@ -612,6 +743,7 @@ func (s *variantService) createStripePrice(stripeProductID string, payload event
 		recurring,
 		interval,
 		intervalCount,
+		"",
 	)
 
 	if err != nil {
@@ -899,3 +1031,665 @@ func convertWeightToGrams(weightStr string) int {
 	// Default to 1 gram if we can't parse
 	return 1
 }
+
+// Create creates a variant for one weight and grind of a product along with its
+// Stripe product. The variant is sold at an existing price of the product
+func (s *variantService) Create(ctx context.Context, createDTO *dto.VariantCreateDTO) (*model.Variant, error) {
+	product, err := s.productRepo.GetByID(ctx, createDTO.ProductID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve product: %w", err)
+	}
+	if product == nil {
+		return nil, fmt.Errorf("%w: product %s not found", ErrInvalidInput, createDTO.ProductID)
+	}
+
+	price, err := s.productPrice(ctx, product.ID, createDTO.PriceID)
+	if err != nil {
+		return nil, err
+	}
+
+	options := map[string]string{
+		"weight": createDTO.Weight,
+		"grind":  createDTO.Grind,
+	}
+	if err := s.checkOptionsUnique(ctx, product.ID, uuid.Nil, options); err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	variant := &model.Variant{
+		ID:               uuid.New(),
+		ProductID:        product.ID,
+		PriceID:          price.ID,
+		Weight:           convertWeightToGrams(createDTO.Weight),
+		Options:          options,
+		Active:           createDTO.Active,
+		StockLevel:       createDTO.StockLevel,
+		ReorderThreshold: createDTO.ReorderThreshold,
+		CreatedAt:        now,
+		UpdatedAt:        now,
+	}
+
+	var images []string
+	if product.ImageURL != "" {
+		images = []string{product.ImageURL}
+	}
+
+	stripeProduct, err := s.stripeService.CreateProduct(product.Name, product.Description, images, variantStripeMetadata(variant))
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("product_id", product.ID.String()).
+			Interface("options", options).
+			Msg("Failed to create Stripe product for variant")
+		return nil, fmt.Errorf("failed to create Stripe product: %w", err)
+	}
+	variant.StripeProductID = stripeProduct.ID
+
+	// Checkout resolves line items through the price's Stripe product, so the
+	// variant needs a Stripe price of its own rather than the local price's
+	stripePrice, err := createStripePrice(s.stripeService, stripeProduct.ID, price, "")
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("product_id", product.ID.String()).
+			Str("stripe_product_id", stripeProduct.ID).
+			Msg("Failed to create Stripe price for variant")
+		s.archiveStripeProduct(stripeProduct.ID)
+		return nil, err
+	}
+	variant.StripePriceID = stripePrice.ID
+
+	if err := s.variantRepo.Create(ctx, variant); err != nil {
+		s.logger.Error().Err(err).
+			Str("product_id", product.ID.String()).
+			Str("stripe_product_id", stripeProduct.ID).
+			Msg("Failed to save variant")
+
+		s.archiveStripeProduct(stripeProduct.ID)
+		return nil, fmt.Errorf("failed to create variant: %w", err)
+	}
+
+	payload := events.VariantCreatedPayload{
+		VariantID:       variant.ID.String(),
+		ProductID:       product.ID.String(),
+		PriceID:         price.ID.String(),
+		StripeProductID: variant.StripeProductID,
+		StripePriceID:   variant.StripePriceID,
+		Weight:          createDTO.Weight,
+		Grind:           createDTO.Grind,
+		OptionValues:    options,
+		Amount:          price.Amount,
+		Currency:        price.Currency,
+		Active:          variant.Active,
+		StockLevel:      variant.StockLevel,
+		CreatedAt:       variant.CreatedAt,
+	}
+	if err := s.eventBus.Publish(events.TopicVariantCreated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", variant.ID.String()).
+			Msg("Failed to publish variant created event")
+	}
+
+	s.logger.Info().
+		Str("variant_id", variant.ID.String()).
+		Str("product_id", product.ID.String()).
+		Str("stripe_product_id", variant.StripeProductID).
+		Msg("Created variant")
+
+	return variant, nil
+}
+
+// archiveStripeProduct takes the Stripe product of a variant that couldn't be
+// created off sale, so no orphan is left for sale in Stripe
+func (s *variantService) archiveStripeProduct(stripeProductID string) {
+	if _, err := s.stripeService.UpdateProduct(stripeProductID, false, nil); err != nil {
+		s.logger.Error().Err(err).
+			Str("stripe_product_id", stripeProductID).
+			Msg("Failed to archive Stripe product for unsaved variant")
+	}
+}
+
+// GetByID retrieves a variant by its ID
+func (s *variantService) GetByID(ctx context.Context, id uuid.UUID) (*model.Variant, error) {
+	variant, err := s.variantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	return variant, nil
+}
+
+// Update changes a variant's price, options or active flag. The catalog sync
+// worker pushes the change to its Stripe product from the published event
+func (s *variantService) Update(ctx context.Context, id uuid.UUID, updateDTO *dto.VariantUpdateDTO) (*model.Variant, error) {
+	variant, err := s.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	// A new price gets a Stripe price on the variant's own Stripe product; the
+	// one it replaces is retired once the variant is saved
+	replacedStripePriceID := ""
+	if updateDTO.PriceID != nil && *updateDTO.PriceID != variant.PriceID {
+		price, err := s.productPrice(ctx, variant.ProductID, *updateDTO.PriceID)
+		if err != nil {
+			return nil, err
+		}
+		variant.PriceID = price.ID
+
+		if variant.StripeProductID != "" {
+			stripePrice, err := createStripePrice(s.stripeService, variant.StripeProductID, price, "")
+			if err != nil {
+				s.logger.Error().Err(err).
+					Str("variant_id", id.String()).
+					Str("stripe_product_id", variant.StripeProductID).
+					Msg("Failed to create Stripe price for variant")
+				return nil, err
+			}
+			replacedStripePriceID = variant.StripePriceID
+			variant.StripePriceID = stripePrice.ID
+		}
+	}
+
+	options := make(map[string]string, len(variant.Options))
+	for k, v := range variant.Options {
+		options[k] = v
+	}
+	if updateDTO.Weight != nil {
+		options["weight"] = *updateDTO.Weight
+		variant.Weight = convertWeightToGrams(*updateDTO.Weight)
+	}
+	if updateDTO.Grind != nil {
+		options["grind"] = *updateDTO.Grind
+	}
+	if updateDTO.Weight != nil || updateDTO.Grind != nil {
+		if err := s.checkOptionsUnique(ctx, variant.ProductID, variant.ID, options); err != nil {
+			return nil, err
+		}
+	}
+	variant.Options = options
+
+	if updateDTO.Active != nil {
+		variant.Active = *updateDTO.Active
+	}
+
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", id.String()).
+			Msg("Failed to update variant")
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	if replacedStripePriceID != "" {
+		if _, err := s.stripeService.SetPriceActive(replacedStripePriceID, false); err != nil {
+			// Nothing points at it any more, so reconciliation reports it as an orphan
+			s.logger.Error().Err(err).
+				Str("variant_id", id.String()).
+				Str("stripe_price_id", replacedStripePriceID).
+				Msg("Failed to deactivate replaced Stripe price")
+		}
+	}
+
+	payload := events.VariantUpdatedPayload{
+		VariantID:       variant.ID.String(),
+		ProductID:       variant.ProductID.String(),
+		PriceID:         variant.PriceID.String(),
+		StripeProductID: variant.StripeProductID,
+		StripePriceID:   variant.StripePriceID,
+		Active:          variant.Active,
+		OptionValues:    variant.Options,
+		UpdatedAt:       variant.UpdatedAt,
+		UpdateSource:    model.SyncSourceAPICall,
+	}
+	if price, err := s.priceRepo.GetByID(ctx, variant.PriceID); err == nil && price != nil {
+		payload.Amount = price.Amount
+		payload.Currency = price.Currency
+		payload.PriceType = price.Type
+		payload.Interval = price.Interval
+		payload.IntervalCount = price.IntervalCount
+	}
+	if err := s.eventBus.Publish(events.TopicVariantUpdated, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", variant.ID.String()).
+			Msg("Failed to publish variant updated event")
+	}
+
+	return variant, nil
+}
+
+// Delete removes a variant and archives its Stripe product. Variants that
+// have been ordered can only be deactivated
+func (s *variantService) Delete(ctx context.Context, id uuid.UUID) error {
+	variant, err := s.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	if err := s.variantRepo.Delete(ctx, id); err != nil {
+		if errors.Is(err, postgres.ErrResourceInUse) {
+			return fmt.Errorf("%w: variant has been ordered, deactivate it instead", ErrInvalidState)
+		}
+		return err
+	}
+
+	if variant.StripeProductID != "" {
+		if _, err := s.stripeService.UpdateProduct(variant.StripeProductID, false, nil); err != nil {
+			// The variant is already gone locally, so the next reconciliation picks this up
+			s.logger.Error().Err(err).
+				Str("variant_id", id.String()).
+				Str("stripe_product_id", variant.StripeProductID).
+				Msg("Failed to archive Stripe product for deleted variant")
+		}
+	}
+
+	payload := events.VariantDeletedPayload{
+		VariantID:       variant.ID.String(),
+		ProductID:       variant.ProductID.String(),
+		StripeProductID: variant.StripeProductID,
+		DeletedAt:       time.Now(),
+	}
+	if err := s.eventBus.Publish(events.TopicVariantDeleted, payload); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", id.String()).
+			Msg("Failed to publish variant deleted event")
+	}
+
+	s.logger.Info().
+		Str("variant_id", id.String()).
+		Str("product_id", variant.ProductID.String()).
+		Msg("Deleted variant")
+
+	return nil
+}
+
+// UpdateStockLevel sets a variant's stock level, recording the difference in the ledger
+func (s *variantService) UpdateStockLevel(ctx context.Context, id uuid.UUID, stockLevel int) error {
+	if stockLevel < 0 {
+		return fmt.Errorf("%w: stock level cannot be negative", ErrInvalidInput)
+	}
+
+	if _, err := s.GetByID(ctx, id); err != nil {
+		return err
+	}
+
+	return s.SetStockLevel(ctx, &model.InventoryMovement{
+		ID:        uuid.New(),
+		VariantID: id,
+		Type:      model.MovementTypeAdjustment,
+		Reason:    "stock level set",
+		CreatedAt: time.Now(),
+	}, stockLevel)
+}
+
+// productPrice retrieves a price and checks that it belongs to the product
+func (s *variantService) productPrice(ctx context.Context, productID, priceID uuid.UUID) (*model.Price, error) {
+	price, err := s.priceRepo.GetByID(ctx, priceID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve price: %w", err)
+	}
+	if price == nil {
+		return nil, fmt.Errorf("%w: price %s not found", ErrInvalidInput, priceID)
+	}
+	if price.ProductID != productID {
+		return nil, fmt.Errorf("%w: price %s belongs to a different product", ErrInvalidInput, priceID)
+	}
+
+	return price, nil
+}
+
+// checkOptionsUnique fails when another variant of the product already has
+// the same options. excludeID is the variant being updated, if any
+func (s *variantService) checkOptionsUnique(ctx context.Context, productID, excludeID uuid.UUID, options map[string]string) error {
+	existing, err := s.variantRepo.GetByProductID(ctx, productID)
+	if err != nil {
+		return fmt.Errorf("failed to retrieve product variants: %w", err)
+	}
+
+	for _, other := range existing {
+		if other.ID == excludeID || len(other.Options) != len(options) {
+			continue
+		}
+
+		same := true
+		for k, v := range options {
+			if other.Options[k] != v {
+				same = false
+				break
+			}
+		}
+		if same {
+			return fmt.Errorf("%w: variant %s already has these options", ErrInvalidInput, other.ID)
+		}
+	}
+
+	return nil
+}
+
+// variantStripeMetadata builds the Stripe product metadata for a variant
+func variantStripeMetadata(variant *model.Variant) map[string]string {
+	metadata := make(map[string]string, len(variant.Options)+2)
+	for k, v := range variant.Options {
+		metadata[k] = v
+	}
+	metadata["original_product_id"] = variant.ProductID.String()
+	metadata["variant_id"] = variant.ID.String()
+
+	return metadata
+}
+
+// RecordStockMovement posts a manual movement to a variant's ledger. An
+// adjustment with a counted level records the difference from the current level
+func (s *variantService) RecordStockMovement(ctx context.Context, id uuid.UUID, movementDTO *dto.StockMovementCreateDTO) (*model.InventoryMovement, error) {
+	if movementDTO.Quantity == nil && movementDTO.CountedLevel == nil {
+		return nil, fmt.Errorf("%w: quantity or counted level is required", ErrInvalidInput)
+	}
+
+	variant, err := s.variantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	movement := &model.InventoryMovement{
+		ID:        uuid.New(),
+		VariantID: id,
+		Type:      movementDTO.Type,
+		Reason:    strings.TrimSpace(movementDTO.Reason),
+		Reference: movementDTO.Reference,
+		CreatedAt: time.Now(),
+	}
+
+	if movementDTO.CountedLevel != nil {
+		err = s.SetStockLevel(ctx, movement, *movementDTO.CountedLevel)
+	} else {
+		movement.Quantity = *movementDTO.Quantity
+		err = s.AdjustStock(ctx, movement)
+	}
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", id.String()).
+			Str("type", movement.Type).
+			Msg("Failed to record stock movement")
+		return nil, err
+	}
+
+	s.logger.Info().
+		Str("variant_id", id.String()).
+		Str("type", movement.Type).
+		Int("quantity", movement.Quantity).
+		Int("stock_level", movement.BalanceAfter).
+		Str("reason", movement.Reason).
+		Msg("Recorded stock movement")
+
+	return movement, nil
+}
+
+// GetStockHistory retrieves a variant's inventory movements, newest first
+func (s *variantService) GetStockHistory(ctx context.Context, id uuid.UUID, offset, limit int) ([]*model.InventoryMovement, int, error) {
+	variant, err := s.variantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, 0, postgres.ErrResourceNotFound
+	}
+
+	return s.variantRepo.GetStockHistory(ctx, id, offset, limit)
+}
+
+// AdjustStock applies a movement to a variant's stock and publishes the stock events
+func (s *variantService) AdjustStock(ctx context.Context, movement *model.InventoryMovement) error {
+	if err := s.variantRepo.AdjustStock(ctx, movement); err != nil {
+		return err
+	}
+
+	s.publishStockUpdated(ctx, movement)
+	return nil
+}
+
+// SetStockLevel sets a variant's stock to a counted level and publishes the stock events
+func (s *variantService) SetStockLevel(ctx context.Context, movement *model.InventoryMovement, stockLevel int) error {
+	if err := s.variantRepo.UpdateStockLevel(ctx, movement, stockLevel); err != nil {
+		return err
+	}
+
+	s.publishStockUpdated(ctx, movement)
+	return nil
+}
+
+// PublishStockMovement publishes the stock events for a movement another
+// repository applied, e.g. together with an order
+func (s *variantService) PublishStockMovement(ctx context.Context, movement *model.InventoryMovement) {
+	s.publishStockUpdated(ctx, movement)
+}
+
+// publishStockUpdated publishes products.stock_updated for an applied movement,
+// and products.low_stock when it takes the variant down to its reorder threshold.
+// Publishing failures are logged because the stock change is already saved
+func (s *variantService) publishStockUpdated(ctx context.Context, movement *model.InventoryMovement) {
+	variant, err := s.variantRepo.GetByID(ctx, movement.VariantID)
+	if err != nil || variant == nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", movement.VariantID.String()).
+			Msg("Failed to retrieve variant for stock events")
+		return
+	}
+
+	product, err := s.productRepo.GetByID(ctx, variant.ProductID)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", variant.ID.String()).
+			Str("product_id", variant.ProductID.String()).
+			Msg("Failed to retrieve product for stock events")
+		return
+	}
+
+	threshold := product.ReorderThreshold
+	if variant.ReorderThreshold != nil {
+		threshold = *variant.ReorderThreshold
+	}
+
+	oldLevel := movement.BalanceAfter - movement.Quantity
+	payload := events.ProductStockUpdatedPayload{
+		ProductID:        product.ID.String(),
+		VariantID:        variant.ID.String(),
+		Name:             product.Name,
+		OldStockLevel:    oldLevel,
+		NewStockLevel:    movement.BalanceAfter,
+		ReorderThreshold: threshold,
+		IsLowStock:       isLowStock(movement.BalanceAfter, threshold),
+		UpdatedAt:        movement.CreatedAt,
+	}
+
+	publishStockEvents(s.eventBus, s.logger, payload)
+}
+
+// publishStockEvents publishes products.stock_updated and, when the change
+// crossed the reorder threshold on the way down, products.low_stock
+func publishStockEvents(eventBus events.EventBus, logger zerolog.Logger, payload events.ProductStockUpdatedPayload) {
+	for _, topic := range stockEventTopics(payload) {
+		if err := eventBus.Publish(topic, payload); err != nil {
+			logger.Error().Err(err).
+				Str("topic", topic).
+				Str("product_id", payload.ProductID).
+				Str("variant_id", payload.VariantID).
+				Msg("Failed to publish stock event")
+			continue
+		}
+
+		if topic == events.TopicProductLowStock {
+			logger.Warn().
+				Str("product_id", payload.ProductID).
+				Str("variant_id", payload.VariantID).
+				Int("stock_level", payload.NewStockLevel).
+				Int("reorder_threshold", payload.ReorderThreshold).
+				Msg("Stock fell to reorder threshold")
+		}
+	}
+}
+
+// stockEventTopics returns the topics a stock change is published to:
+// products.stock_updated always, products.low_stock when the level crosses
+// down to the reorder threshold
+func stockEventTopics(payload events.ProductStockUpdatedPayload) []string {
+	topics := []string{events.TopicProductStockUpdated}
+	if payload.IsLowStock && !isLowStock(payload.OldStockLevel, payload.ReorderThreshold) {
+		topics = append(topics, events.TopicProductLowStock)
+	}
+	return topics
+}
+
+// isLowStock reports whether a stock level is at or below an enabled reorder threshold
+func isLowStock(stockLevel, threshold int) bool {
+	return threshold > 0 && stockLevel <= threshold
+}
+
+// SetReorderThreshold sets a variant's own reorder threshold, or clears it so
+// the product's threshold applies
+func (s *variantService) SetReorderThreshold(ctx context.Context, id uuid.UUID, threshold *int) (*model.Variant, error) {
+	if threshold != nil && *threshold < 0 {
+		return nil, fmt.Errorf("%w: reorder threshold cannot be negative", ErrInvalidInput)
+	}
+
+	variant, err := s.variantRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve variant: %w", err)
+	}
+	if variant == nil {
+		return nil, postgres.ErrResourceNotFound
+	}
+
+	variant.ReorderThreshold = threshold
+	if err := s.variantRepo.Update(ctx, variant); err != nil {
+		s.logger.Error().Err(err).
+			Str("variant_id", id.String()).
+			Msg("Failed to update variant reorder threshold")
+		return nil, fmt.Errorf("failed to update variant: %w", err)
+	}
+
+	return variant, nil
+}
+
+// ListLowStock retrieves variants at or below their reorder threshold
+func (s *variantService) ListLowStock(ctx context.Context, offset, limit int) ([]*model.LowStockVariant, int, error) {
+	return s.variantRepo.ListLowStock(ctx, offset, limit)
+}
+
+// CheckStockAvailability checks if enough unreserved stock is available for an order
+func (s *variantService) CheckStockAvailability(ctx context.Context, id uuid.UUID, quantity int) (bool, error) {
+	available, err := s.reservationRepo.GetAvailable(ctx, id)
+	if err != nil {
+		return false, err
+	}
+	return available >= quantity, nil
+}
+
+// ReserveStock holds stock for every variant under one reference until expiresAt
+func (s *variantService) ReserveStock(ctx context.Context, reference string, quantities map[uuid.UUID]int, expiresAt time.Time) error {
+	if reference == "" {
+		return fmt.Errorf("%w: reservation reference is required", ErrInvalidInput)
+	}
+	if len(quantities) == 0 {
+		return fmt.Errorf("%w: nothing to reserve", ErrInvalidInput)
+	}
+
+	now := time.Now()
+	reservations := make([]*model.StockReservation, 0, len(quantities))
+	for variantID, quantity := range quantities {
+		if quantity <= 0 {
+			return fmt.Errorf("%w: quantity for variant %s must be positive", ErrInvalidInput, variantID)
+		}
+		reservations = append(reservations, &model.StockReservation{
+			ID:        uuid.New(),
+			VariantID: variantID,
+			Reference: reference,
+			Quantity:  quantity,
+			Status:    model.ReservationStatusHeld,
+			ExpiresAt: expiresAt,
+			CreatedAt: now,
+			UpdatedAt: now,
+		})
+	}
+
+	if err := s.reservationRepo.Reserve(ctx, reservations); err != nil {
+		return err
+	}
+
+	s.logger.Info().
+		Str("reference", reference).
+		Int("variants", len(reservations)).
+		Time("expires_at", expiresAt).
+		Msg("Reserved stock")
+
+	return nil
+}
+
+// ConfirmStock marks the held stock for a reference as taken by a paid order.
+// The order itself decrements the variant stock level
+func (s *variantService) ConfirmStock(ctx context.Context, reference string) error {
+	return s.setReservationStatus(ctx, reference, model.ReservationStatusConfirmed)
+}
+
+// ReleaseStock releases the held stock for a reference
+func (s *variantService) ReleaseStock(ctx context.Context, reference string) error {
+	return s.setReservationStatus(ctx, reference, model.ReservationStatusReleased)
+}
+
+// setReservationStatus moves the held reservations for a reference to status.
+// Holds that were already settled or swept are left alone, so repeats are harmless
+func (s *variantService) setReservationStatus(ctx context.Context, reference, status string) error {
+	if reference == "" {
+		return fmt.Errorf("%w: reservation reference is required", ErrInvalidInput)
+	}
+
+	count, err := s.reservationRepo.SetStatusByReference(ctx, reference, status)
+	if err != nil {
+		return err
+	}
+
+	s.logger.Info().
+		Str("reference", reference).
+		Str("status", status).
+		Int("reservations", count).
+		Msg("Updated stock reservations")
+
+	return nil
+}
+
+// ReleaseExpiredReservations expires holds that ran past their expiry
+func (s *variantService) ReleaseExpiredReservations(ctx context.Context) (int, error) {
+	count, err := s.reservationRepo.ExpireHeld(ctx, time.Now())
+	if err != nil {
+		return 0, err
+	}
+
+	if count > 0 {
+		s.logger.Info().Int("reservations", count).Msg("Released expired stock reservations")
+	}
+
+	return count, nil
+}
+
+// RunReservationSweeper releases expired holds every interval until ctx is done
+func (s *variantService) RunReservationSweeper(ctx context.Context, interval time.Duration) {
+	s.logger.Info().Dur("interval", interval).Msg("Starting stock reservation sweeper")
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			s.logger.Info().Msg("Stopping stock reservation sweeper")
+			return
+		case <-ticker.C:
+			if _, err := s.ReleaseExpiredReservations(ctx); err != nil {
+				s.logger.Error().Err(err).Msg("Failed to release expired stock reservations")
+			}
+		}
+	}
+}
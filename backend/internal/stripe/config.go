@@ -18,6 +18,14 @@ func InitStripe(cfg *config.StripeConfig, logger *zerolog.Logger) {
 	
 	// Set the API key for all API requests
 	stripeSDK.Key = cfg.SecretKey
+
+	// Send API requests somewhere other than api.stripe.com, such as a stripe-mock server
+	if cfg.APIBaseURL != "" {
+		stripeSDK.SetBackend(stripeSDK.APIBackend, stripeSDK.GetBackendWithConfig(stripeSDK.APIBackend, &stripeSDK.BackendConfig{
+			URL: stripeSDK.String(cfg.APIBaseURL),
+		}))
+		subLogger.Info().Str("api_base_url", cfg.APIBaseURL).Msg("Using custom Stripe API base URL")
+	}
 	
 	subLogger.Info().Msg("Stripe SDK initialized successfully")
 }
\ No newline at end of file
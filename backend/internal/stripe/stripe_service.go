@@ -5,13 +5,17 @@ import (
 	"errors"
 	"fmt"
 	"strings"
+	"time"
 
 	"github.com/dukerupert/coffee-commerce/config"
     "github.com/dukerupert/coffee-commerce/internal/interfaces"
 	"github.com/rs/zerolog"
 	stripe "github.com/stripe/stripe-go/v82"
+	"github.com/stripe/stripe-go/v82/checkout/session"
+	"github.com/stripe/stripe-go/v82/customer"
 	"github.com/stripe/stripe-go/v82/price"
 	"github.com/stripe/stripe-go/v82/product"
+	"github.com/stripe/stripe-go/v82/subscription"
 )
 
 // Common errors
@@ -104,7 +108,7 @@ func (s *service) CreateProduct(name, description string, imageURLs []string, me
 
 // CreatePrice creates a new price in Stripe
 func (s *service) CreatePrice(productID string, unitAmount int64, currency string, recurring bool, 
-    interval string, intervalCount int64) (*stripe.Price, error) {
+    interval string, intervalCount int64, idempotencyKey string) (*stripe.Price, error) {
     
     if s.isDisabled {
         s.logger.Warn().Msg("Stripe is disabled, returning mock price")
@@ -138,6 +142,11 @@ func (s *service) CreatePrice(productID string, unitAmount int64, currency strin
             IntervalCount: stripe.Int64(intervalCount),
         }
     }
+
+    // Stripe returns the price created by the first request with this key
+    if idempotencyKey != "" {
+        params.SetIdempotencyKey(idempotencyKey)
+    }
     
     p, err := price.New(params)
     if err != nil {
@@ -157,6 +166,91 @@ func (s *service) CreatePrice(productID string, unitAmount int64, currency strin
     return p, nil
 }
 
+// SetPriceActive activates or retires a Stripe price. Prices can't be
+// deleted, so retired prices stay attached to past charges
+func (s *service) SetPriceActive(priceID string, active bool) (*stripe.Price, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock price")
+		return &stripe.Price{
+			ID:     priceID,
+			Active: active,
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("price_id", priceID).
+		Bool("active", active).
+		Msg("Updating Stripe price")
+
+	p, err := price.Update(priceID, &stripe.PriceParams{
+		Active: stripe.Bool(active),
+	})
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("price_id", priceID).
+			Msg("Failed to update Stripe price")
+		return nil, fmt.Errorf("failed to update Stripe price: %w", err)
+	}
+
+	s.logger.Info().
+		Str("price_id", p.ID).
+		Bool("active", p.Active).
+		Msg("Successfully updated Stripe price")
+
+	return p, nil
+}
+
+// GetPrice retrieves a price from Stripe by ID
+func (s *service) GetPrice(priceID string) (*stripe.Price, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock price")
+		return &stripe.Price{
+			ID:     priceID,
+			Active: true,
+		}, nil
+	}
+
+	p, err := price.Get(priceID, nil)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("price_id", priceID).
+			Msg("Failed to retrieve Stripe price")
+		return nil, fmt.Errorf("failed to retrieve Stripe price: %w", err)
+	}
+
+	return p, nil
+}
+
+// ListAllPrices retrieves every price from Stripe, active or not
+func (s *service) ListAllPrices() ([]*stripe.Price, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning empty price list")
+		return []*stripe.Price{}, nil
+	}
+
+	s.logger.Debug().Msg("Listing all Stripe prices")
+
+	var allPrices []*stripe.Price
+	params := &stripe.PriceListParams{}
+	params.Filters.AddFilter("limit", "", "100")
+
+	iter := price.List(params)
+	for iter.Next() {
+		allPrices = append(allPrices, iter.Price())
+	}
+
+	if err := iter.Err(); err != nil {
+		s.logger.Error().Err(err).Msg("Failed to list Stripe prices")
+		return nil, fmt.Errorf("failed to list Stripe prices: %w", err)
+	}
+
+	s.logger.Info().
+		Int("price_count", len(allPrices)).
+		Msg("Successfully retrieved Stripe prices")
+
+	return allPrices, nil
+}
+
 // GetProduct retrieves a product from Stripe by ID
 func (s *service) GetProduct(productID string) (*stripe.Product, error) {
 	if s.isDisabled {
@@ -178,6 +272,100 @@ func (s *service) GetProduct(productID string) (*stripe.Product, error) {
 	return p, nil
 }
 
+// UpdateProduct sets a Stripe product's active flag and merges metadata into it.
+// Products with prices can't be deleted in Stripe, so deactivating archives them
+func (s *service) UpdateProduct(productID string, active bool, metadata map[string]string) (*stripe.Product, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock product")
+		return &stripe.Product{
+			ID:       productID,
+			Active:   active,
+			Metadata: metadata,
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("product_id", productID).
+		Bool("active", active).
+		Interface("metadata", metadata).
+		Msg("Updating Stripe product")
+
+	params := &stripe.ProductParams{
+		Active: stripe.Bool(active),
+	}
+	for k, v := range metadata {
+		params.AddMetadata(k, v)
+	}
+
+	p, err := product.Update(productID, params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("product_id", productID).
+			Msg("Failed to update Stripe product")
+		return nil, fmt.Errorf("failed to update Stripe product: %w", err)
+	}
+
+	s.logger.Info().
+		Str("product_id", p.ID).
+		Bool("active", p.Active).
+		Msg("Successfully updated Stripe product")
+
+	return p, nil
+}
+
+// UpdateProductDetails sets everything we manage on a Stripe product in a
+// single update, so Stripe sends one product.updated webhook for it
+func (s *service) UpdateProductDetails(productID, name, description string, imageURLs []string, active bool, metadata map[string]string) (*stripe.Product, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock product")
+		return &stripe.Product{
+			ID:          productID,
+			Name:        name,
+			Description: description,
+			Images:      imageURLs,
+			Active:      active,
+			Metadata:    metadata,
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("product_id", productID).
+		Str("name", name).
+		Bool("active", active).
+		Interface("metadata", metadata).
+		Msg("Updating Stripe product details")
+
+	params := &stripe.ProductParams{
+		Name:        stripe.String(name),
+		Description: stripe.String(description),
+		Images:      stripe.StringSlice(imageURLs),
+		Active:      stripe.Bool(active),
+	}
+	if len(imageURLs) == 0 {
+		// An empty list clears the images
+		params.Images = []*string{}
+	}
+	for k, v := range metadata {
+		params.AddMetadata(k, v)
+	}
+
+	p, err := product.Update(productID, params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("product_id", productID).
+			Msg("Failed to update Stripe product details")
+		return nil, fmt.Errorf("failed to update Stripe product: %w", err)
+	}
+
+	s.logger.Info().
+		Str("product_id", p.ID).
+		Str("name", p.Name).
+		Bool("active", p.Active).
+		Msg("Successfully updated Stripe product details")
+
+	return p, nil
+}
+
 // ListAllProducts retrieves all products from Stripe
 func (s *service) ListAllProducts() ([]*stripe.Product, error) {
 	if s.isDisabled {
@@ -275,4 +463,425 @@ func (s *service) FindProductByMetadata(key, value string) (*stripe.Product, err
 		Msg("No Stripe product found with matching metadata")
 	
 	return nil, nil
-}
\ No newline at end of file
+}
+
+// CreateCustomer creates a new customer in Stripe
+func (s *service) CreateCustomer(email, name, phone string, metadata map[string]string) (*stripe.Customer, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock customer")
+		return &stripe.Customer{
+			ID:       fmt.Sprintf("cus_mock_%s", email),
+			Email:    email,
+			Name:     name,
+			Phone:    phone,
+			Metadata: metadata,
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("email", email).
+		Str("name", name).
+		Msg("Creating Stripe customer")
+
+	params := &stripe.CustomerParams{
+		Email: stripe.String(email),
+		Name:  stripe.String(name),
+	}
+
+	if phone != "" {
+		params.Phone = stripe.String(phone)
+	}
+
+	if len(metadata) > 0 {
+		params.Metadata = make(map[string]string)
+		for k, v := range metadata {
+			params.Metadata[k] = v
+		}
+	}
+
+	c, err := customer.New(params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("email", email).
+			Msg("Failed to create Stripe customer")
+		return nil, fmt.Errorf("failed to create Stripe customer: %w", err)
+	}
+
+	s.logger.Info().
+		Str("customer_id", c.ID).
+		Str("email", c.Email).
+		Msg("Successfully created Stripe customer")
+
+	return c, nil
+}
+
+// UpdateCustomer updates the contact details of an existing Stripe customer
+func (s *service) UpdateCustomer(customerID, email, name, phone string) (*stripe.Customer, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock customer")
+		return &stripe.Customer{
+			ID:    customerID,
+			Email: email,
+			Name:  name,
+			Phone: phone,
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("customer_id", customerID).
+		Str("email", email).
+		Msg("Updating Stripe customer")
+
+	params := &stripe.CustomerParams{
+		Email: stripe.String(email),
+		Name:  stripe.String(name),
+		Phone: stripe.String(phone),
+	}
+
+	c, err := customer.Update(customerID, params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", customerID).
+			Msg("Failed to update Stripe customer")
+		return nil, fmt.Errorf("failed to update Stripe customer: %w", err)
+	}
+
+	s.logger.Info().
+		Str("customer_id", c.ID).
+		Msg("Successfully updated Stripe customer")
+
+	return c, nil
+}
+
+// DeleteCustomer deletes a customer in Stripe
+func (s *service) DeleteCustomer(customerID string) error {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, skipping customer deletion")
+		return nil
+	}
+
+	s.logger.Debug().
+		Str("customer_id", customerID).
+		Msg("Deleting Stripe customer")
+
+	if _, err := customer.Del(customerID, nil); err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", customerID).
+			Msg("Failed to delete Stripe customer")
+		return fmt.Errorf("failed to delete Stripe customer: %w", err)
+	}
+
+	s.logger.Info().
+		Str("customer_id", customerID).
+		Msg("Successfully deleted Stripe customer")
+
+	return nil
+}
+
+// mockSubscription builds a stand-in subscription for disabled mode
+func mockSubscription(subscriptionID, customerID, priceID string, quantity int64) *stripe.Subscription {
+	now := time.Now()
+	return &stripe.Subscription{
+		ID:       subscriptionID,
+		Customer: &stripe.Customer{ID: customerID},
+		Status:   stripe.SubscriptionStatusActive,
+		Items: &stripe.SubscriptionItemList{
+			Data: []*stripe.SubscriptionItem{{
+				ID:                 fmt.Sprintf("si_mock_%s", subscriptionID),
+				Price:              &stripe.Price{ID: priceID},
+				Quantity:           quantity,
+				CurrentPeriodStart: now.Unix(),
+				CurrentPeriodEnd:   now.AddDate(0, 1, 0).Unix(),
+			}},
+		},
+	}
+}
+
+// CreateSubscription creates a new subscription in Stripe for a single price
+func (s *service) CreateSubscription(customerID, priceID string, quantity int64, metadata map[string]string) (*stripe.Subscription, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock subscription")
+		return mockSubscription(fmt.Sprintf("sub_mock_%s_%s", customerID, priceID), customerID, priceID, quantity), nil
+	}
+
+	s.logger.Debug().
+		Str("customer_id", customerID).
+		Str("price_id", priceID).
+		Int64("quantity", quantity).
+		Msg("Creating Stripe subscription")
+
+	params := &stripe.SubscriptionParams{
+		Customer: stripe.String(customerID),
+		Items: []*stripe.SubscriptionItemsParams{{
+			Price:    stripe.String(priceID),
+			Quantity: stripe.Int64(quantity),
+		}},
+		// Leave the subscription incomplete until the first invoice is paid
+		PaymentBehavior: stripe.String("default_incomplete"),
+		PaymentSettings: &stripe.SubscriptionPaymentSettingsParams{
+			SaveDefaultPaymentMethod: stripe.String("on_subscription"),
+		},
+	}
+	// The customer pays the first invoice with its client secret or hosted page
+	params.AddExpand("latest_invoice.confirmation_secret")
+
+	if len(metadata) > 0 {
+		params.Metadata = make(map[string]string)
+		for k, v := range metadata {
+			params.Metadata[k] = v
+		}
+	}
+
+	sub, err := subscription.New(params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("customer_id", customerID).
+			Str("price_id", priceID).
+			Msg("Failed to create Stripe subscription")
+		return nil, fmt.Errorf("failed to create Stripe subscription: %w", err)
+	}
+
+	s.logger.Info().
+		Str("subscription_id", sub.ID).
+		Str("status", string(sub.Status)).
+		Msg("Successfully created Stripe subscription")
+
+	return sub, nil
+}
+
+// PauseSubscription pauses payment collection for a subscription. Invoices
+// raised while paused are voided. A zero resumesAt pauses indefinitely
+func (s *service) PauseSubscription(subscriptionID string, resumesAt time.Time) (*stripe.Subscription, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock subscription")
+		return &stripe.Subscription{ID: subscriptionID, Status: stripe.SubscriptionStatusActive}, nil
+	}
+
+	s.logger.Debug().
+		Str("subscription_id", subscriptionID).
+		Time("resumes_at", resumesAt).
+		Msg("Pausing Stripe subscription collection")
+
+	pause := &stripe.SubscriptionPauseCollectionParams{
+		Behavior: stripe.String(string(stripe.SubscriptionPauseCollectionBehaviorVoid)),
+	}
+	if !resumesAt.IsZero() {
+		pause.ResumesAt = stripe.Int64(resumesAt.Unix())
+	}
+
+	sub, err := subscription.Update(subscriptionID, &stripe.SubscriptionParams{PauseCollection: pause})
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", subscriptionID).
+			Msg("Failed to pause Stripe subscription")
+		return nil, fmt.Errorf("failed to pause Stripe subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// ResumeSubscription clears any paused payment collection on a subscription
+func (s *service) ResumeSubscription(subscriptionID string) (*stripe.Subscription, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock subscription")
+		return &stripe.Subscription{ID: subscriptionID, Status: stripe.SubscriptionStatusActive}, nil
+	}
+
+	s.logger.Debug().
+		Str("subscription_id", subscriptionID).
+		Msg("Resuming Stripe subscription collection")
+
+	params := &stripe.SubscriptionParams{}
+	// Stripe clears pause_collection when it is sent as an empty value
+	params.AddExtra("pause_collection", "")
+
+	sub, err := subscription.Update(subscriptionID, params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", subscriptionID).
+			Msg("Failed to resume Stripe subscription")
+		return nil, fmt.Errorf("failed to resume Stripe subscription: %w", err)
+	}
+
+	return sub, nil
+}
+
+// CancelSubscription cancels a subscription immediately or at the end of the current period
+func (s *service) CancelSubscription(subscriptionID string, atPeriodEnd bool) (*stripe.Subscription, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock subscription")
+		status := stripe.SubscriptionStatusCanceled
+		if atPeriodEnd {
+			status = stripe.SubscriptionStatusActive
+		}
+		return &stripe.Subscription{
+			ID:                subscriptionID,
+			Status:            status,
+			CancelAtPeriodEnd: atPeriodEnd,
+			CanceledAt:        time.Now().Unix(),
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("subscription_id", subscriptionID).
+		Bool("at_period_end", atPeriodEnd).
+		Msg("Canceling Stripe subscription")
+
+	var sub *stripe.Subscription
+	var err error
+	if atPeriodEnd {
+		sub, err = subscription.Update(subscriptionID, &stripe.SubscriptionParams{
+			CancelAtPeriodEnd: stripe.Bool(true),
+		})
+	} else {
+		sub, err = subscription.Cancel(subscriptionID, nil)
+	}
+
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", subscriptionID).
+			Msg("Failed to cancel Stripe subscription")
+		return nil, fmt.Errorf("failed to cancel Stripe subscription: %w", err)
+	}
+
+	s.logger.Info().
+		Str("subscription_id", sub.ID).
+		Str("status", string(sub.Status)).
+		Bool("cancel_at_period_end", sub.CancelAtPeriodEnd).
+		Msg("Successfully canceled Stripe subscription")
+
+	return sub, nil
+}
+
+// CreateCheckoutSession creates a hosted Checkout session in payment or
+// subscription mode. Either customerID (a Stripe customer) or customerEmail
+// may be given; without a customer, payment mode always creates one.
+// A zero expiresAt keeps Stripe's default of 24 hours
+func (s *service) CreateCheckoutSession(mode, customerID, customerEmail string, lineItems []*stripe.CheckoutSessionLineItemParams, successURL, cancelURL string, metadata map[string]string, expiresAt time.Time) (*stripe.CheckoutSession, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning mock checkout session")
+		id := fmt.Sprintf("cs_mock_%d", time.Now().UnixNano())
+		mockExpiresAt := expiresAt
+		if mockExpiresAt.IsZero() {
+			mockExpiresAt = time.Now().Add(24 * time.Hour)
+		}
+		return &stripe.CheckoutSession{
+			ID:        id,
+			Mode:      stripe.CheckoutSessionMode(mode),
+			Status:    stripe.CheckoutSessionStatusOpen,
+			URL:       strings.ReplaceAll(successURL, "{CHECKOUT_SESSION_ID}", id),
+			ExpiresAt: mockExpiresAt.Unix(),
+			Metadata:  metadata,
+		}, nil
+	}
+
+	s.logger.Debug().
+		Str("mode", mode).
+		Str("customer_id", customerID).
+		Int("line_item_count", len(lineItems)).
+		Msg("Creating Stripe checkout session")
+
+	params := &stripe.CheckoutSessionParams{
+		Mode:       stripe.String(mode),
+		LineItems:  lineItems,
+		SuccessURL: stripe.String(successURL),
+		CancelURL:  stripe.String(cancelURL),
+	}
+	params.Metadata = metadata
+	if !expiresAt.IsZero() {
+		params.ExpiresAt = stripe.Int64(expiresAt.Unix())
+	}
+
+	switch {
+	case customerID != "":
+		params.Customer = stripe.String(customerID)
+	case customerEmail != "":
+		params.CustomerEmail = stripe.String(customerEmail)
+	}
+
+	// Subscription mode always creates a customer; payment mode only does when asked
+	if customerID == "" && mode == string(stripe.CheckoutSessionModePayment) {
+		params.CustomerCreation = stripe.String(string(stripe.CheckoutSessionCustomerCreationAlways))
+	}
+
+	sess, err := session.New(params)
+	if err != nil {
+		s.logger.Error().Err(err).
+			Str("mode", mode).
+			Msg("Failed to create Stripe checkout session")
+		return nil, fmt.Errorf("failed to create checkout session: %w", err)
+	}
+
+	s.logger.Info().
+		Str("session_id", sess.ID).
+		Str("mode", mode).
+		Msg("Created Stripe checkout session")
+
+	return sess, nil
+}
+
+// ListCheckoutSessionLineItems retrieves every line item of a Checkout session
+// with the price's product expanded
+func (s *service) ListCheckoutSessionLineItems(sessionID string) ([]*stripe.LineItem, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning empty line item list")
+		return []*stripe.LineItem{}, nil
+	}
+
+	s.logger.Debug().
+		Str("session_id", sessionID).
+		Msg("Listing Stripe checkout session line items")
+
+	params := &stripe.CheckoutSessionListLineItemsParams{
+		Session: stripe.String(sessionID),
+	}
+	params.AddExpand("data.price.product")
+	params.Filters.AddFilter("limit", "", "100")
+
+	var lineItems []*stripe.LineItem
+	iter := session.ListLineItems(params)
+	for iter.Next() {
+		lineItems = append(lineItems, iter.LineItem())
+	}
+
+	if err := iter.Err(); err != nil {
+		s.logger.Error().Err(err).
+			Str("session_id", sessionID).
+			Msg("Failed to list Stripe checkout session line items")
+		return nil, fmt.Errorf("failed to list checkout session line items: %w", err)
+	}
+
+	return lineItems, nil
+}
+
+// GetCheckoutSessionForSubscription retrieves the Checkout session that
+// created a subscription. Returns nil if it was created without Checkout
+func (s *service) GetCheckoutSessionForSubscription(subscriptionID string) (*stripe.CheckoutSession, error) {
+	if s.isDisabled {
+		s.logger.Warn().Msg("Stripe is disabled, returning no checkout session")
+		return nil, nil
+	}
+
+	s.logger.Debug().
+		Str("subscription_id", subscriptionID).
+		Msg("Looking up Stripe checkout session for subscription")
+
+	params := &stripe.CheckoutSessionListParams{
+		Subscription: stripe.String(subscriptionID),
+	}
+	params.Filters.AddFilter("limit", "", "1")
+
+	iter := session.List(params)
+	if iter.Next() {
+		return iter.CheckoutSession(), nil
+	}
+
+	if err := iter.Err(); err != nil {
+		s.logger.Error().Err(err).
+			Str("subscription_id", subscriptionID).
+			Msg("Failed to list Stripe checkout sessions for subscription")
+		return nil, fmt.Errorf("failed to list checkout sessions: %w", err)
+	}
+
+	return nil, nil
+}
@@ -0,0 +1,183 @@
+package stripe
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"strconv"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/dukerupert/coffee-commerce/config"
+	"github.com/dukerupert/coffee-commerce/internal/interfaces"
+	"github.com/rs/zerolog"
+	stripe "github.com/stripe/stripe-go/v82"
+)
+
+// fakeStripe records the Checkout sessions created against it
+type fakeStripe struct {
+	mu       sync.Mutex
+	requests []url.Values
+}
+
+func (f *fakeStripe) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost || r.URL.Path != "/v1/checkout/sessions" {
+		http.NotFound(w, r)
+		return
+	}
+	if err := r.ParseForm(); err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+
+	f.mu.Lock()
+	f.requests = append(f.requests, r.PostForm)
+	f.mu.Unlock()
+
+	expiresAt, _ := strconv.ParseInt(r.PostForm.Get("expires_at"), 10, 64)
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"id":         "cs_test_123",
+		"object":     "checkout.session",
+		"mode":       r.PostForm.Get("mode"),
+		"status":     "open",
+		"url":        "https://checkout.stripe.com/c/pay/cs_test_123",
+		"expires_at": expiresAt,
+		"metadata":   map[string]string{"reservation_ref": r.PostForm.Get("metadata[reservation_ref]")},
+	})
+}
+
+// newTestService creates a Stripe service sending requests to baseURL
+func newTestService(t *testing.T, baseURL string) interfaces.StripeService {
+	t.Helper()
+	logger := zerolog.Nop()
+	return NewStripeService(&logger, &config.StripeConfig{
+		SecretKey:  "sk_test_123",
+		APIBaseURL: baseURL,
+	})
+}
+
+func TestCreateCheckoutSession(t *testing.T) {
+	fake := &fakeStripe{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	service := newTestService(t, server.URL)
+
+	expiresAt := time.Now().Add(31 * time.Minute).Truncate(time.Second)
+	lineItems := []*stripe.CheckoutSessionLineItemParams{
+		{Price: stripe.String("price_12oz"), Quantity: stripe.Int64(2)},
+		{Price: stripe.String("price_5lb"), Quantity: stripe.Int64(1)},
+	}
+
+	sess, err := service.CreateCheckoutSession(
+		string(stripe.CheckoutSessionModePayment),
+		"",
+		"buyer@example.com",
+		lineItems,
+		"https://shop.example.com/success?session_id={CHECKOUT_SESSION_ID}",
+		"https://shop.example.com/cart",
+		map[string]string{"reservation_ref": "ref-1"},
+		expiresAt,
+	)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession() error = %v", err)
+	}
+
+	if sess.ID != "cs_test_123" || sess.URL == "" {
+		t.Errorf("session = %+v, want cs_test_123 with a URL", sess)
+	}
+	if sess.ExpiresAt != expiresAt.Unix() {
+		t.Errorf("session expires at %d, want %d", sess.ExpiresAt, expiresAt.Unix())
+	}
+
+	if len(fake.requests) != 1 {
+		t.Fatalf("fake Stripe received %d requests, want 1", len(fake.requests))
+	}
+	form := fake.requests[0]
+
+	want := map[string]string{
+		"mode":                      "payment",
+		"customer_email":            "buyer@example.com",
+		"customer_creation":         "always",
+		"line_items[0][price]":      "price_12oz",
+		"line_items[0][quantity]":   "2",
+		"line_items[1][price]":      "price_5lb",
+		"line_items[1][quantity]":   "1",
+		"metadata[reservation_ref]": "ref-1",
+		"expires_at":                strconv.FormatInt(expiresAt.Unix(), 10),
+		"cancel_url":                "https://shop.example.com/cart",
+	}
+	for key, value := range want {
+		if got := form.Get(key); got != value {
+			t.Errorf("%s = %q, want %q", key, got, value)
+		}
+	}
+	if form.Has("customer") {
+		t.Errorf("customer = %q, want unset for a guest checkout", form.Get("customer"))
+	}
+}
+
+func TestCreateCheckoutSessionSubscription(t *testing.T) {
+	fake := &fakeStripe{}
+	server := httptest.NewServer(fake)
+	defer server.Close()
+
+	service := newTestService(t, server.URL)
+
+	_, err := service.CreateCheckoutSession(
+		string(stripe.CheckoutSessionModeSubscription),
+		"cus_123",
+		"",
+		[]*stripe.CheckoutSessionLineItemParams{{Price: stripe.String("price_monthly"), Quantity: stripe.Int64(1)}},
+		"https://shop.example.com/success",
+		"https://shop.example.com/cart",
+		nil,
+		time.Time{},
+	)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession() error = %v", err)
+	}
+
+	form := fake.requests[0]
+	if got := form.Get("customer"); got != "cus_123" {
+		t.Errorf("customer = %q, want cus_123", got)
+	}
+	for _, key := range []string{"customer_creation", "customer_email", "expires_at"} {
+		if form.Has(key) {
+			t.Errorf("%s = %q, want unset", key, form.Get(key))
+		}
+	}
+}
+
+// TestCreateCheckoutSessionStripeMock runs against a stripe-mock server, which
+// validates the request against Stripe's API spec. Set STRIPE_MOCK_URL, e.g.
+// http://localhost:12111, to run it
+func TestCreateCheckoutSessionStripeMock(t *testing.T) {
+	baseURL := os.Getenv("STRIPE_MOCK_URL")
+	if baseURL == "" {
+		t.Skip("STRIPE_MOCK_URL not set")
+	}
+
+	service := newTestService(t, baseURL)
+
+	sess, err := service.CreateCheckoutSession(
+		string(stripe.CheckoutSessionModePayment),
+		"",
+		"buyer@example.com",
+		[]*stripe.CheckoutSessionLineItemParams{{Price: stripe.String("price_123"), Quantity: stripe.Int64(1)}},
+		"https://shop.example.com/success",
+		"https://shop.example.com/cart",
+		map[string]string{"reservation_ref": "ref-1"},
+		time.Now().Add(31*time.Minute),
+	)
+	if err != nil {
+		t.Fatalf("CreateCheckoutSession() error = %v", err)
+	}
+	if sess.ID == "" {
+		t.Error("session has no ID")
+	}
+}
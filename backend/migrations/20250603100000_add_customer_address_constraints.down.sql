@@ -0,0 +1,5 @@
+-- Migration: 20250603100000_add_customer_address_constraints.down.sql
+-- Drop the single default address constraint
+
+DROP INDEX IF EXISTS idx_customer_addresses_single_default;
+DROP INDEX IF EXISTS idx_customer_addresses_customer_id;
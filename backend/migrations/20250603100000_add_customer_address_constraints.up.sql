@@ -0,0 +1,16 @@
+-- Migration: 20250603100000_add_customer_address_constraints.up.sql
+-- Enforce at most one default address per customer
+
+-- Keep only the most recently updated default address for any customer that has several
+UPDATE customer_addresses a
+SET is_default = FALSE
+WHERE is_default = TRUE
+  AND EXISTS (
+      SELECT 1 FROM customer_addresses b
+      WHERE b.customer_id = a.customer_id
+        AND b.is_default = TRUE
+        AND (b.updated_at > a.updated_at OR (b.updated_at = a.updated_at AND b.id > a.id))
+  );
+
+CREATE INDEX idx_customer_addresses_customer_id ON customer_addresses(customer_id);
+CREATE UNIQUE INDEX idx_customer_addresses_single_default ON customer_addresses(customer_id) WHERE is_default;
@@ -0,0 +1,7 @@
+-- Migration: 20250604090000_add_subscription_address.down.sql
+-- Remove subscription address link and restore the non-unique Stripe ID index
+
+DROP INDEX IF EXISTS idx_subscriptions_stripe_id;
+CREATE INDEX idx_subscriptions_stripe_id ON subscriptions(stripe_id);
+
+ALTER TABLE subscriptions DROP COLUMN IF EXISTS address_id;
@@ -0,0 +1,8 @@
+-- Migration: 20250604090000_add_subscription_address.up.sql
+-- Link subscriptions to a shipping address and make Stripe IDs unique
+
+ALTER TABLE subscriptions ADD COLUMN address_id UUID REFERENCES customer_addresses(id) ON DELETE SET NULL;
+
+-- A Stripe subscription maps to exactly one local subscription
+DROP INDEX IF EXISTS idx_subscriptions_stripe_id;
+CREATE UNIQUE INDEX idx_subscriptions_stripe_id ON subscriptions(stripe_id);
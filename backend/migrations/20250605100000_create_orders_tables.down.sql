@@ -0,0 +1,6 @@
+-- Migration: 20250605100000_create_orders_tables.down.sql
+-- Drop the order tables
+
+DROP TABLE IF EXISTS order_status_history;
+DROP TABLE IF EXISTS order_items;
+DROP TABLE IF EXISTS orders;
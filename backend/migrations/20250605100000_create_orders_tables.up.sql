@@ -0,0 +1,71 @@
+-- Migration: 20250605100000_create_orders_tables.up.sql
+-- Orders, their line items and the history of status transitions
+
+CREATE TABLE orders (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID NOT NULL REFERENCES customers(id),
+    subscription_id UUID REFERENCES subscriptions(id) ON DELETE SET NULL,
+    address_id UUID REFERENCES customer_addresses(id) ON DELETE SET NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'paid', 'roasting', 'packed', 'shipped', 'delivered', 'canceled', 'refunded')),
+
+    -- Amounts in cents
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    subtotal BIGINT NOT NULL DEFAULT 0,
+    shipping_amount BIGINT NOT NULL DEFAULT 0,
+    tax_amount BIGINT NOT NULL DEFAULT 0,
+    total BIGINT NOT NULL DEFAULT 0,
+
+    -- Stripe references
+    stripe_checkout_session_id VARCHAR(255),
+    stripe_payment_intent_id VARCHAR(255),
+    stripe_invoice_id VARCHAR(255),
+
+    -- Fulfillment
+    carrier VARCHAR(50),
+    tracking_number VARCHAR(100),
+
+    metadata JSONB DEFAULT '{}'::JSONB,
+    paid_at TIMESTAMP WITH TIME ZONE,
+    shipped_at TIMESTAMP WITH TIME ZONE,
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    canceled_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_orders_customer_id ON orders(customer_id);
+CREATE INDEX idx_orders_subscription_id ON orders(subscription_id);
+CREATE INDEX idx_orders_status ON orders(status);
+CREATE INDEX idx_orders_created_at ON orders(created_at);
+CREATE UNIQUE INDEX idx_orders_stripe_checkout_session_id ON orders(stripe_checkout_session_id) WHERE stripe_checkout_session_id IS NOT NULL;
+CREATE UNIQUE INDEX idx_orders_stripe_invoice_id ON orders(stripe_invoice_id) WHERE stripe_invoice_id IS NOT NULL;
+
+CREATE TABLE order_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES variants(id),
+    product_id UUID NOT NULL REFERENCES products(id),
+    price_id UUID NOT NULL REFERENCES prices(id),
+
+    -- Snapshot of the variant at order time
+    product_name VARCHAR(255) NOT NULL,
+    options JSONB NOT NULL DEFAULT '{}'::JSONB,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_amount BIGINT NOT NULL,
+    total_amount BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_items_order_id ON order_items(order_id);
+CREATE INDEX idx_order_items_variant_id ON order_items(variant_id);
+
+CREATE TABLE order_status_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    order_id UUID NOT NULL REFERENCES orders(id) ON DELETE CASCADE,
+    from_status VARCHAR(20),
+    to_status VARCHAR(20) NOT NULL,
+    note TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_order_status_history_order_id ON order_status_history(order_id, created_at);
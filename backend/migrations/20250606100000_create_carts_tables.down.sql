@@ -0,0 +1,5 @@
+-- Migration: 20250606100000_create_carts_tables.down.sql
+-- Drop the cart tables
+
+DROP TABLE IF EXISTS cart_items;
+DROP TABLE IF EXISTS carts;
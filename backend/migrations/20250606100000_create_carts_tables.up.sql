@@ -0,0 +1,28 @@
+-- Migration: 20250606100000_create_carts_tables.up.sql
+-- Server-side shopping carts for guests and customers
+
+CREATE TABLE carts (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    customer_id UUID REFERENCES customers(id) ON DELETE CASCADE,
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'merged', 'converted')),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- A customer has at most one active cart
+CREATE UNIQUE INDEX idx_carts_active_customer ON carts(customer_id) WHERE status = 'active' AND customer_id IS NOT NULL;
+CREATE INDEX idx_carts_updated_at ON carts(updated_at);
+
+CREATE TABLE cart_items (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    cart_id UUID NOT NULL REFERENCES carts(id) ON DELETE CASCADE,
+    variant_id UUID NOT NULL REFERENCES variants(id) ON DELETE CASCADE,
+    price_id UUID NOT NULL REFERENCES prices(id),
+    quantity INT NOT NULL CHECK (quantity > 0),
+    unit_amount BIGINT NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    UNIQUE (cart_id, variant_id)
+);
+
+CREATE INDEX idx_cart_items_cart_id ON cart_items(cart_id);
@@ -0,0 +1,4 @@
+-- Migration: 20250607100000_create_invoices_table.down.sql
+-- Drop the invoices table
+
+DROP TABLE IF EXISTS invoices;
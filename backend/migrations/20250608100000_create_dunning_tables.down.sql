@@ -0,0 +1,5 @@
+-- Migration: 20250608100000_create_dunning_tables.down.sql
+-- Drop the dunning tables
+
+DROP TABLE IF EXISTS dunning_steps;
+DROP TABLE IF EXISTS dunning_cases;
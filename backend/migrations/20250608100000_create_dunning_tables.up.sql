@@ -0,0 +1,33 @@
+-- Migration: 20250608100000_create_dunning_tables.up.sql
+-- Dunning cases for failed subscription payments and their audit trail
+
+CREATE TABLE dunning_cases (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    subscription_id UUID NOT NULL REFERENCES subscriptions(id) ON DELETE CASCADE,
+    customer_id UUID NOT NULL REFERENCES customers(id) ON DELETE CASCADE,
+    invoice_id UUID NOT NULL REFERENCES invoices(id) ON DELETE CASCADE,
+    stripe_invoice_id VARCHAR(255) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'open' CHECK (status IN ('open', 'recovered', 'canceled', 'paused')),
+    attempt_count INT NOT NULL DEFAULT 0,
+    reminders_sent INT NOT NULL DEFAULT 0,
+    held_delivery_date TIMESTAMP WITH TIME ZONE,
+    next_action_at TIMESTAMP WITH TIME ZONE,
+    started_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    resolved_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- A subscription is chased for one invoice at a time
+CREATE UNIQUE INDEX idx_dunning_cases_open_subscription ON dunning_cases(subscription_id) WHERE status = 'open';
+CREATE INDEX idx_dunning_cases_next_action ON dunning_cases(next_action_at) WHERE status = 'open';
+
+CREATE TABLE dunning_steps (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    dunning_case_id UUID NOT NULL REFERENCES dunning_cases(id) ON DELETE CASCADE,
+    step VARCHAR(30) NOT NULL,
+    detail TEXT,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_dunning_steps_case_id ON dunning_steps(dunning_case_id);
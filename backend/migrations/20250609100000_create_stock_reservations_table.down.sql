@@ -0,0 +1,4 @@
+-- Migration: 20250609100000_create_stock_reservations_table.down.sql
+-- Drop the stock reservations table
+
+DROP TABLE IF EXISTS stock_reservations;
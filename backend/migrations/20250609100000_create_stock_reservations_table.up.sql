@@ -0,0 +1,17 @@
+-- Migration: 20250609100000_create_stock_reservations_table.up.sql
+-- Stock held for in-progress checkouts so limited lots are not oversold
+
+CREATE TABLE stock_reservations (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    variant_id UUID NOT NULL REFERENCES variants(id) ON DELETE CASCADE,
+    reference VARCHAR(255) NOT NULL,
+    quantity INT NOT NULL CHECK (quantity > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'held' CHECK (status IN ('held', 'confirmed', 'released', 'expired')),
+    expires_at TIMESTAMP WITH TIME ZONE NOT NULL,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_stock_reservations_reference ON stock_reservations(reference);
+CREATE INDEX idx_stock_reservations_held_variant ON stock_reservations(variant_id) WHERE status = 'held';
+CREATE INDEX idx_stock_reservations_held_expiry ON stock_reservations(expires_at) WHERE status = 'held';
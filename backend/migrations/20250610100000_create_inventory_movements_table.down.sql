@@ -0,0 +1,4 @@
+-- Migration: 20250610100000_create_inventory_movements_table.down.sql
+-- Drop the inventory ledger
+
+DROP TABLE IF EXISTS inventory_movements;
@@ -0,0 +1,22 @@
+-- Migration: 20250610100000_create_inventory_movements_table.up.sql
+-- Append-only stock ledger; variants.stock_level is the running balance of its movements
+
+CREATE TABLE inventory_movements (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    variant_id UUID NOT NULL REFERENCES variants(id) ON DELETE CASCADE,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('receipt', 'sale', 'reservation', 'adjustment', 'spoilage', 'return')),
+    quantity INT NOT NULL,
+    balance_after INT NOT NULL,
+    reason TEXT NOT NULL,
+    reference VARCHAR(255),
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_inventory_movements_variant_id ON inventory_movements(variant_id, created_at);
+CREATE INDEX idx_inventory_movements_reference ON inventory_movements(reference) WHERE reference IS NOT NULL;
+
+-- Open the ledger with the stock each variant already has
+INSERT INTO inventory_movements (variant_id, movement_type, quantity, balance_after, reason)
+SELECT id, 'adjustment', stock_level, stock_level, 'opening balance'
+FROM variants
+WHERE stock_level <> 0;
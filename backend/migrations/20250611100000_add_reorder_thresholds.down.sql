@@ -0,0 +1,5 @@
+-- Migration: 20250611100000_add_reorder_thresholds.down.sql
+-- Drop reorder thresholds
+
+ALTER TABLE variants DROP COLUMN IF EXISTS reorder_threshold;
+ALTER TABLE products DROP COLUMN IF EXISTS reorder_threshold;
@@ -0,0 +1,5 @@
+-- Migration: 20250611100000_add_reorder_thresholds.up.sql
+-- Reorder thresholds for low-stock alerts; a variant without its own threshold uses its product's
+
+ALTER TABLE products ADD COLUMN reorder_threshold INT NOT NULL DEFAULT 0 CHECK (reorder_threshold >= 0);
+ALTER TABLE variants ADD COLUMN reorder_threshold INT CHECK (reorder_threshold >= 0);
@@ -0,0 +1,4 @@
+-- Migration: 20250612100000_create_pricing_rules_table.down.sql
+-- Drop product pricing rules
+
+DROP TABLE IF EXISTS pricing_rules;
@@ -0,0 +1,12 @@
+-- Migration: 20250612100000_create_pricing_rules_table.up.sql
+-- Per-product rules that price each variant from its option values
+
+CREATE TABLE pricing_rules (
+    product_id UUID PRIMARY KEY REFERENCES products(id) ON DELETE CASCADE,
+    currency VARCHAR(3) NOT NULL DEFAULT 'USD',
+    base_price BIGINT NOT NULL CHECK (base_price > 0),
+    weight_prices JSONB NOT NULL DEFAULT '{}',
+    surcharges JSONB NOT NULL DEFAULT '{}',
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
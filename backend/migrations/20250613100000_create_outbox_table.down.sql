@@ -0,0 +1,4 @@
+-- Migration: 20250613100000_create_outbox_table.down.sql
+-- Drop the domain event outbox
+
+DROP TABLE IF EXISTS outbox;
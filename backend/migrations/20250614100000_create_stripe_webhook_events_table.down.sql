@@ -0,0 +1,4 @@
+-- Migration: 20250614100000_create_stripe_webhook_events_table.down.sql
+-- Drop the Stripe webhook event store
+
+DROP TABLE IF EXISTS stripe_webhook_events;
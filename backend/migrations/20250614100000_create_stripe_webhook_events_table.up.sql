@@ -0,0 +1,18 @@
+-- Migration: 20250614100000_create_stripe_webhook_events_table.up.sql
+-- Every verified Stripe webhook event, so duplicates are skipped and failures can be replayed
+
+CREATE TABLE stripe_webhook_events (
+    id VARCHAR(255) PRIMARY KEY, -- Stripe event ID (evt_...)
+    type VARCHAR(255) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    last_error TEXT,
+    received_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(),
+    processed_at TIMESTAMP WITH TIME ZONE,
+    CONSTRAINT chk_stripe_webhook_events_status CHECK (status IN ('pending', 'processing', 'processed', 'failed'))
+);
+
+CREATE INDEX idx_stripe_webhook_events_status ON stripe_webhook_events(status, received_at);
+CREATE INDEX idx_stripe_webhook_events_type ON stripe_webhook_events(type);
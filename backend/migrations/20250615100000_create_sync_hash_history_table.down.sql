@@ -0,0 +1,4 @@
+-- Migration: 20250615100000_create_sync_hash_history_table.down.sql
+-- Drop the sync hash timeline
+
+DROP TABLE IF EXISTS sync_hash_history;
@@ -0,0 +1,22 @@
+-- Migration: 20250615100000_create_sync_hash_history_table.up.sql
+-- Keep every sync hash written for a variant so its sync timeline can be inspected
+
+CREATE TABLE sync_hash_history (
+    id UUID PRIMARY KEY DEFAULT uuid_generate_v4(),
+    variant_id UUID NOT NULL REFERENCES variants(id) ON DELETE CASCADE,
+    stripe_product_id VARCHAR(255) NOT NULL,
+    content_hash VARCHAR(64) NOT NULL,
+    hash_algorithm VARCHAR(20) DEFAULT 'sha256',
+    sync_source VARCHAR(50) NOT NULL,
+    recorded_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX idx_sync_hash_history_variant_recorded ON sync_hash_history(variant_id, recorded_at DESC);
+
+-- Seed the timeline with the hashes already recorded
+INSERT INTO sync_hash_history (variant_id, stripe_product_id, content_hash, hash_algorithm, sync_source, recorded_at)
+SELECT variant_id, stripe_product_id, content_hash, hash_algorithm, sync_source, updated_at
+FROM sync_hashes
+WHERE variant_id IS NOT NULL;
+
+COMMENT ON TABLE sync_hash_history IS 'Append-only log of every sync hash written to sync_hashes';